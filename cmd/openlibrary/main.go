@@ -0,0 +1,88 @@
+// Command openlibrary seeds the catalogue from the Open Library data dumps, https://openlibrary.org/developers/dumps.
+//
+//	openlibrary -authors ol_dump_authors.txt.gz -works ol_dump_works.txt.gz -editions ol_dump_editions.txt.gz
+//
+// The dumps are imported in that order since works and editions refer to their authors. Records imported before,
+// authors with the same name and books with the same ISBN are not imported again. An interrupted import started
+// with the same checkpoint file continues where it stopped.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	_ "github.com/go-sql-driver/mysql"
+
+	"projects/GoLang-Interns-2022/authorbook/driver"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+	"projects/GoLang-Interns-2022/authorbook/service/authorservice"
+	"projects/GoLang-Interns-2022/authorbook/service/bookservice"
+	"projects/GoLang-Interns-2022/authorbook/service/openlibraryservice"
+	"projects/GoLang-Interns-2022/authorbook/service/workservice"
+	"projects/GoLang-Interns-2022/authorbook/store"
+	"projects/GoLang-Interns-2022/authorbook/store/audit"
+	"projects/GoLang-Interns-2022/authorbook/store/author"
+	"projects/GoLang-Interns-2022/authorbook/store/book"
+	"projects/GoLang-Interns-2022/authorbook/store/history"
+	"projects/GoLang-Interns-2022/authorbook/store/openlibrary"
+	"projects/GoLang-Interns-2022/authorbook/store/outbox"
+	"projects/GoLang-Interns-2022/authorbook/store/work"
+)
+
+func main() {
+	authors := flag.String("authors", "", "authors dump, plain or gzip compressed")
+	works := flag.String("works", "", "works dump, plain or gzip compressed")
+	editions := flag.String("editions", "", "editions dump, plain or gzip compressed")
+	checkpoint := flag.String("checkpoint", "openlibrary.checkpoint", "file keeping the progress of the import")
+	flag.Parse()
+
+	var files []string
+
+	for _, file := range []string{*authors, *works, *editions} {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+
+	if len(files) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	DB := driver.Connection()
+	defer DB.Close()
+
+	tx := store.NewTransaction(DB)
+	auditStore := audit.New(DB)
+	historyStore := history.New(DB)
+	// the imported authors and books are published by the relay of the running server
+	outboxStore := outbox.New(DB)
+	authorStore := author.New(DB)
+	workStore := work.New(DB)
+	bookStore := book.New(DB)
+
+	authorService := authorservice.New(authorStore, auditStore, historyStore, outboxStore, tx)
+	s := openlibraryservice.New(openlibrary.New(DB), authorStore, workStore, bookStore, authorService,
+		workservice.New(workStore, authorStore, bookStore),
+		bookservice.New(bookStore, authorStore, auditStore, historyStore, outboxStore, tx), tx)
+
+	// an interrupt stops the import after the current line, the checkpoint is saved on the way out
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	ctx = auth.NewContext(ctx, auth.Identity{Subject: "import:openlibrary"})
+
+	summary, err := s.Run(ctx, files, *checkpoint)
+
+	out, _ := json.MarshalIndent(summary, "", "  ")
+	log.Printf("summary %s", out)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,22 @@
+package entities
+
+// scopes narrowing what an API key may do on top of its role
+const (
+	ScopeReadOnly  = "read-only"
+	ScopeBooksOnly = "books-only"
+)
+
+// APIKey : credential of a machine client, only a salted hash of the secret is stored and Key is filled once on issue
+type APIKey struct {
+	APIKeyID   int      `json:"apiKeyID"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Role       string   `json:"role"`
+	Scopes     []string `json:"scopes"`
+	Key        string   `json:"key,omitempty"`
+	Salt       string   `json:"-"`
+	Hash       string   `json:"-"`
+	CreatedAt  string   `json:"createdAt"`
+	LastUsedAt string   `json:"lastUsedAt,omitempty"`
+	RevokedAt  string   `json:"revokedAt,omitempty"`
+}
@@ -0,0 +1,33 @@
+package entities
+
+import "encoding/json"
+
+// operations recorded in the audit log
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+// AuditEntry : a change of an author or book, Before is empty on create and After on delete
+type AuditEntry struct {
+	AuditID    int             `json:"auditID"`
+	Actor      string          `json:"actor"`
+	Operation  string          `json:"operation"`
+	EntityType string          `json:"entityType"`
+	EntityID   int             `json:"entityID"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	CreatedAt  string          `json:"createdAt"`
+}
+
+// AuditFilter : narrows the audit log, From and To bound the time of the change
+type AuditFilter struct {
+	EntityType string
+	EntityID   int
+	Actor      string
+	From       string
+	To         string
+	Page       int
+	Limit      int
+}
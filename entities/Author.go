@@ -6,4 +6,13 @@ type Author struct {
 	LastName  string `json:"lastName"`
 	DOB       string `json:"DOB"`
 	PenName   string `json:"penName"`
+	UpdatedAt string `json:"updatedAt,omitempty"`
+	Books     []Book `json:"books,omitempty"`
+}
+
+// AuthorFilter : conditions used while listing authors, empty fields are not applied
+type AuthorFilter struct {
+	// After and Limit page the listing in the order of the ids, the authors after the id are read up to the limit
+	After int
+	Limit int
 }
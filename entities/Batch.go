@@ -0,0 +1,50 @@
+package entities
+
+import "encoding/json"
+
+// operations of a batch
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// commit modes of a batch, an atomic batch changes nothing when a single operation fails
+const (
+	BatchAtomic      = "atomic"
+	BatchIndependent = "independent"
+)
+
+// Batch : operations run in order in one request
+type Batch struct {
+	Mode       string           `json:"mode"`
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchOperation : a create, update or delete of an author or book. Ref names the operation so the later ones can
+// use the id it created or changed as {"$ref":"<name>"} in their id or anywhere in their body
+type BatchOperation struct {
+	Ref    string          `json:"ref,omitempty"`
+	Op     string          `json:"op"`
+	Entity string          `json:"entity"`
+	ID     json.RawMessage `json:"id,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// BatchResult : the outcome of an operation, Status is the one the operation would have got as a single request
+type BatchResult struct {
+	Index  int         `json:"index"`
+	Ref    string      `json:"ref,omitempty"`
+	Status int         `json:"status"`
+	ID     int         `json:"id,omitempty"`
+	Body   interface{} `json:"body,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// BatchResponse : the outcomes of the operations of a batch in their order
+type BatchResponse struct {
+	Mode      string        `json:"mode"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Results   []BatchResult `json:"results"`
+}
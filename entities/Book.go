@@ -1,10 +1,35 @@
 package entities
 
 type Book struct {
-	BookID        int     `json:"bookID"`
-	AuthorID      int     `json:"authorID"`
-	Title         string  `json:"title"`
-	Publication   string  `json:"publication"`
-	PublishedDate string  `json:"publishedDate"`
-	Author        *Author `json:",omitempty"`
+	BookID        int          `json:"bookID"`
+	AuthorID      int          `json:"authorID"`
+	Title         string       `json:"title"`
+	Publication   string       `json:"publication"`
+	PublishedDate string       `json:"publishedDate"`
+	UpdatedAt     string       `json:"updatedAt,omitempty"`
+	GenreIDs      []int        `json:"genreIDs,omitempty"`
+	Tags          []string     `json:"tags,omitempty"`
+	Author        *Author      `json:",omitempty"`
+	Publisher     *Publisher   `json:"publisher,omitempty"`
+	Genres        []Genre      `json:"genres,omitempty"`
+	Series        []BookSeries `json:"series,omitempty"`
+	Edition       *Edition     `json:"edition,omitempty"`
+	Editions      []Edition    `json:"editions,omitempty"`
+	Rating        *Rating      `json:"rating,omitempty"`
+}
+
+// Publisher : the publisher of a book with the number of books it published
+type Publisher struct {
+	Name  string `json:"name"`
+	Books int    `json:"books"`
+}
+
+// BookFilter : conditions used while listing books, empty fields are not applied
+type BookFilter struct {
+	Title string
+	Genre string
+	Tags  []string
+	// After and Limit page the listing in the order of the ids, the books after the id are read up to the limit
+	After int
+	Limit int
 }
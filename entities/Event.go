@@ -0,0 +1,61 @@
+package entities
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// types of the events published for changes of authors and books
+const (
+	EventAuthorCreated = "AuthorCreated"
+	EventAuthorUpdated = "AuthorUpdated"
+	EventAuthorDeleted = "AuthorDeleted"
+	EventBookCreated   = "BookCreated"
+	EventBookUpdated   = "BookUpdated"
+	EventBookDeleted   = "BookDeleted"
+)
+
+// Event : a change of an author or book for other systems, the payload is the entity after the change and the last
+// state of the entity for a delete
+type Event struct {
+	EventID    int             `json:"eventID"`
+	Type       string          `json:"type"`
+	EntityType string          `json:"entityType"`
+	EntityID   int             `json:"entityID"`
+	Actor      string          `json:"actor"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	CreatedAt  string          `json:"createdAt"`
+}
+
+// Key : the entity of the event, events with the same key are delivered in the order they happened
+func (e Event) Key() string {
+	return e.EntityType + ":" + strconv.Itoa(e.EntityID)
+}
+
+// LatestEvent : the id a change feed follows after to get only the events written from then on
+const LatestEvent = -1
+
+// EventFilter : narrows a change feed to the events of one entity type and, when given, of some of its entities
+type EventFilter struct {
+	EntityType string
+	EntityIDs  []int
+}
+
+// Matches : checks whether the event passes the filter
+func (f EventFilter) Matches(event Event) bool {
+	if f.EntityType != "" && f.EntityType != event.EntityType {
+		return false
+	}
+
+	if len(f.EntityIDs) == 0 {
+		return true
+	}
+
+	for _, id := range f.EntityIDs {
+		if id == event.EntityID {
+			return true
+		}
+	}
+
+	return false
+}
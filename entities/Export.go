@@ -0,0 +1,9 @@
+package entities
+
+// ExportOptions : what to export and in which format, the filter only applies to books
+type ExportOptions struct {
+	Entity        string
+	Format        string
+	IncludeAuthor bool
+	Filter        BookFilter
+}
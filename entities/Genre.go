@@ -0,0 +1,8 @@
+package entities
+
+type Genre struct {
+	GenreID  int     `json:"genreID"`
+	Name     string  `json:"name"`
+	ParentID int     `json:"parentID,omitempty"`
+	Children []Genre `json:"children,omitempty"`
+}
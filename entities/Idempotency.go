@@ -0,0 +1,12 @@
+package entities
+
+// IdempotentRequest : a request made with an Idempotency-Key, keyed by the caller and the key. Status stays 0
+// while the request is in progress and is the status of the response once it is answered
+type IdempotentRequest struct {
+	Scope       string
+	Key         string
+	Fingerprint string
+	Status      int
+	ContentType string
+	Body        []byte
+}
@@ -0,0 +1,45 @@
+package entities
+
+// states of an import job
+const (
+	ImportPending = "pending"
+	ImportRunning = "running"
+	ImportDone    = "done"
+	ImportFailed  = "failed"
+)
+
+// commit modes of an import, atomic imports nothing when a single row fails
+const (
+	ImportAtomic     = "atomic"
+	ImportBestEffort = "best-effort"
+)
+
+// ImportOptions : what to import and how, Mapping renames source columns to the import fields
+type ImportOptions struct {
+	Entity  string            `json:"entity"`
+	Format  string            `json:"format"`
+	Mode    string            `json:"mode"`
+	DryRun  bool              `json:"dryRun"`
+	Mapping map[string]string `json:"mapping,omitempty"`
+}
+
+// ImportJob : the progress and outcome of an import running in the background
+type ImportJob struct {
+	JobID int `json:"jobID"`
+	ImportOptions
+	Status     string        `json:"status"`
+	Total      int           `json:"total"`
+	Imported   int           `json:"imported"`
+	Skipped    int           `json:"skipped"`
+	Failed     int           `json:"failed"`
+	Errors     []ImportError `json:"errors,omitempty"`
+	Actor      string        `json:"actor"`
+	CreatedAt  string        `json:"createdAt"`
+	FinishedAt string        `json:"finishedAt,omitempty"`
+}
+
+// ImportError : why the row on a line of the source was not imported
+type ImportError struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,36 @@
+package entities
+
+import "strings"
+
+// Projection : the shape of a listing, Fields holds the json names of the fields to return with author.penName for
+// a field of an embedded resource and is empty for every field, Expand names the resources to embed
+type Projection struct {
+	Fields []string
+	Expand []string
+}
+
+// Includes : tells whether a field is asked for, a resource asked for as a whole includes all its fields
+func (p Projection) Includes(field string) bool {
+	if len(p.Fields) == 0 {
+		return true
+	}
+
+	for _, f := range p.Fields {
+		if f == field || strings.HasPrefix(field, f+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Expands : tells whether a resource is embedded
+func (p Projection) Expands(name string) bool {
+	for _, e := range p.Expand {
+		if e == name {
+			return true
+		}
+	}
+
+	return false
+}
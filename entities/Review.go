@@ -0,0 +1,41 @@
+package entities
+
+// moderation states of a review, only approved reviews are public and counted in the rating
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+type Review struct {
+	ReviewID     int    `json:"reviewID"`
+	BookID       int    `json:"bookID"`
+	Reviewer     string `json:"reviewer"`
+	Rating       int    `json:"rating"`
+	Text         string `json:"text"`
+	Status       string `json:"status"`
+	HelpfulCount int    `json:"helpfulCount"`
+	CreatedAt    string `json:"createdAt"`
+}
+
+// ReviewFilter : selects a page of the reviews of a book, Sort is either "date" or "helpful"
+type ReviewFilter struct {
+	Status string
+	Sort   string
+	Page   int
+	Limit  int
+}
+
+// ReviewPage : a page of reviews with the total number of matching reviews
+type ReviewPage struct {
+	Reviews []Review `json:"reviews"`
+	Page    int      `json:"page"`
+	Limit   int      `json:"limit"`
+	Total   int      `json:"total"`
+}
+
+// Rating : aggregate of the approved reviews of a book
+type Rating struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
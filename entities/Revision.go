@@ -0,0 +1,19 @@
+package entities
+
+// AuthorRevision : the author as it was after a change, a delete keeps the last state of the author
+type AuthorRevision struct {
+	Revision  int    `json:"revision"`
+	Operation string `json:"operation"`
+	Actor     string `json:"actor"`
+	ChangedAt string `json:"changedAt"`
+	Author    Author `json:"author"`
+}
+
+// BookRevision : the book row as it was after a change, a delete keeps the last state of the book
+type BookRevision struct {
+	Revision  int    `json:"revision"`
+	Operation string `json:"operation"`
+	Actor     string `json:"actor"`
+	ChangedAt string `json:"changedAt"`
+	Book      Book   `json:"book"`
+}
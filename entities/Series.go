@@ -0,0 +1,24 @@
+package entities
+
+type Series struct {
+	SeriesID    int          `json:"seriesID"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Books       []SeriesBook `json:"books,omitempty"`
+}
+
+// SeriesBook : a book at its reading position within a series, positions may be fractional (2.5 for a novella)
+type SeriesBook struct {
+	BookID   int     `json:"bookID"`
+	Title    string  `json:"title,omitempty"`
+	Position float64 `json:"position"`
+}
+
+// BookSeries : the series a book belongs to, with the books read before and after it
+type BookSeries struct {
+	SeriesID int         `json:"seriesID"`
+	Name     string      `json:"name"`
+	Position float64     `json:"position"`
+	Previous *SeriesBook `json:"previous,omitempty"`
+	Next     *SeriesBook `json:"next,omitempty"`
+}
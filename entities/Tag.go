@@ -0,0 +1,6 @@
+package entities
+
+type Tag struct {
+	TagID int    `json:"tagID"`
+	Name  string `json:"name"`
+}
@@ -0,0 +1,69 @@
+package entities
+
+import "encoding/json"
+
+// states of a webhook delivery, a delivery is dead once its retries are used up
+const (
+	DeliveryPending   = "pending"
+	DeliveryRetrying  = "retrying"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+// Webhook : a subscription of a partner to events, EntityIDs narrows it to the events of those entities and the
+// secret signs the deliveries, it is only given back when the webhook is created
+type Webhook struct {
+	WebhookID  int      `json:"webhookID"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"eventTypes"`
+	EntityIDs  []int    `json:"entityIDs,omitempty"`
+	Secret     string   `json:"secret,omitempty"`
+	Actor      string   `json:"actor"`
+	CreatedAt  string   `json:"createdAt"`
+}
+
+// Matches : checks whether the event is one the webhook subscribed to
+func (w Webhook) Matches(event Event) bool {
+	subscribed := false
+
+	for _, t := range w.EventTypes {
+		if t == event.Type {
+			subscribed = true
+			break
+		}
+	}
+
+	if !subscribed || len(w.EntityIDs) == 0 {
+		return subscribed
+	}
+
+	for _, id := range w.EntityIDs {
+		if id == event.EntityID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Delivery : an event sent to a webhook, Payload is the exact body posted so a redelivery is signed alike
+type Delivery struct {
+	DeliveryID     int             `json:"deliveryID"`
+	WebhookID      int             `json:"webhookID"`
+	EventID        int             `json:"eventID"`
+	EventType      string          `json:"eventType"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  string          `json:"nextAttemptAt,omitempty"`
+	ResponseStatus int             `json:"responseStatus,omitempty"`
+	LastError      string          `json:"lastError,omitempty"`
+	CreatedAt      string          `json:"createdAt"`
+	DeliveredAt    string          `json:"deliveredAt,omitempty"`
+}
+
+// DeliveryFilter : narrows the delivery log of a webhook, an empty status lists every delivery
+type DeliveryFilter struct {
+	Status string
+	Limit  int
+}
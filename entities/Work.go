@@ -0,0 +1,21 @@
+package entities
+
+// Work : a title independent of its physical form, grouping all of its editions
+type Work struct {
+	WorkID   int       `json:"workID"`
+	AuthorID int       `json:"authorID"`
+	Title    string    `json:"title"`
+	Editions []Edition `json:"editions,omitempty"`
+}
+
+// Edition : the published form of a work, each edition is stored as a book
+type Edition struct {
+	BookID        int    `json:"bookID,omitempty"`
+	WorkID        int    `json:"workID"`
+	Title         string `json:"title,omitempty"`
+	ISBN          string `json:"isbn"`
+	Format        string `json:"format"`
+	PageCount     int    `json:"pageCount"`
+	Publisher     string `json:"publisher,omitempty"`
+	PublishedDate string `json:"publishedDate,omitempty"`
+}
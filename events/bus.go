@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+// Handler : takes a published event, an error makes the relay publish the event again later
+type Handler func(ctx context.Context, event entities.Event) error
+
+// Bus : an in-process publisher handing every event to the subscribers of this process
+type Bus struct {
+	mu          sync.RWMutex
+	next        int
+	subscribers map[int]Handler
+}
+
+// NewBus : factory function
+func NewBus() *Bus {
+	return &Bus{subscribers: map[int]Handler{}}
+}
+
+// Subscribe : adds a handler for the events published from now on, cancel removes it again
+func (b *Bus) Subscribe(h Handler) (cancel func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.next
+	b.next++
+	b.subscribers[id] = h
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		delete(b.subscribers, id)
+	}
+}
+
+// Publish : hands the event to every subscriber, the first error is returned so the event is published again and
+// subscribers see an event at least once
+func (b *Bus) Publish(ctx context.Context, event entities.Event) error {
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.subscribers))
+
+	for _, h := range b.subscribers {
+		handlers = append(handlers, h)
+	}
+	b.mu.RUnlock()
+
+	var first error
+
+	for _, h := range handlers {
+		if err := h(ctx, event); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
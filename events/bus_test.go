@@ -0,0 +1,40 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+// TestBus : test that every subscriber gets the event and a failing one makes the publish fail
+func TestBus(t *testing.T) {
+	bus := NewBus()
+	event := entities.Event{EventID: 1, Type: entities.EventBookCreated, EntityType: "book", EntityID: 3}
+
+	var got []int
+
+	bus.Subscribe(func(_ context.Context, e entities.Event) error {
+		got = append(got, e.EventID)
+		return nil
+	})
+	cancel := bus.Subscribe(func(_ context.Context, e entities.Event) error {
+		return errors.New("search down")
+	})
+
+	if err := bus.Publish(context.TODO(), event); !reflect.DeepEqual(err, errors.New("search down")) {
+		t.Errorf("expected the failure of the subscriber, got: %v", err)
+	}
+
+	cancel()
+
+	if err := bus.Publish(context.TODO(), event); err != nil {
+		t.Errorf("unexpected error after cancel: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, []int{1, 1}) {
+		t.Errorf("unexpected deliveries %v", got)
+	}
+}
@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Shopify/sarama"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+// Kafka : publishes the events to a Kafka topic, keyed by their entity so the events of an entity land on one
+// partition in order
+type Kafka struct {
+	producer sarama.SyncProducer
+	topic    string
+}
+
+// NewKafka : connects a producer to the brokers, it waits for every in-sync replica and is idempotent so retries
+// neither lose nor reorder events
+func NewKafka(brokers []string, topic string) (Kafka, error) {
+	config := sarama.NewConfig()
+	config.Version = sarama.V2_0_0_0
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Return.Successes = true
+	config.Producer.Idempotent = true
+	config.Net.MaxOpenRequests = 1
+
+	producer, err := sarama.NewSyncProducer(brokers, config)
+	if err != nil {
+		return Kafka{}, err
+	}
+
+	return NewKafkaProducer(producer, topic), nil
+}
+
+// NewKafkaProducer : publishes with a producer that is already set up
+func NewKafkaProducer(p sarama.SyncProducer, topic string) Kafka {
+	return Kafka{p, topic}
+}
+
+// Publish : sends the event as JSON with its type in the event-type header
+func (k Kafka) Publish(_ context.Context, event entities.Event) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = k.producer.SendMessage(&sarama.ProducerMessage{
+		Topic:   k.topic,
+		Key:     sarama.StringEncoder(event.Key()),
+		Value:   sarama.ByteEncoder(value),
+		Headers: []sarama.RecordHeader{{Key: []byte("event-type"), Value: []byte(event.Type)}},
+	})
+
+	return err
+}
+
+// Close : flushes and closes the producer
+func (k Kafka) Close() error {
+	return k.producer.Close()
+}
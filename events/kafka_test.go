@@ -0,0 +1,44 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+// TestKafka : test that events are keyed by their entity and carry their type
+func TestKafka(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	k := NewKafkaProducer(producer, "authorbook.events")
+
+	event := entities.Event{EventID: 9, Type: entities.EventAuthorUpdated, EntityType: "author", EntityID: 4,
+		Payload: json.RawMessage(`{"authorID":4}`)}
+
+	producer.ExpectSendMessageWithCheckerFunctionAndSucceed(func(value []byte) error {
+		var got entities.Event
+		if err := json.Unmarshal(value, &got); err != nil || got.EventID != 9 {
+			return errors.New("unexpected value " + string(value))
+		}
+
+		return nil
+	})
+	producer.ExpectSendMessageAndFail(sarama.ErrNotEnoughReplicas)
+
+	if err := k.Publish(context.TODO(), event); err != nil {
+		t.Errorf("failed to publish: %v", err)
+	}
+
+	if err := k.Publish(context.TODO(), event); err != sarama.ErrNotEnoughReplicas {
+		t.Errorf("expected the broker error, got: %v", err)
+	}
+
+	if err := k.Close(); err != nil {
+		t.Errorf("failed to close: %v", err)
+	}
+}
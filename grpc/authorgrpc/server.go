@@ -0,0 +1,77 @@
+package authorgrpc
+
+import (
+	"context"
+	"errors"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/grpc/pb"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+// Server : serves the author RPCs with the author service, the errors are mapped to status codes by the interceptors
+type Server struct {
+	pb.UnimplementedAuthorServiceServer
+
+	authorService service.AuthorService
+}
+
+// New : factory function used for injection
+func New(a service.AuthorService) *Server {
+	return &Server{authorService: a}
+}
+
+// GetAuthor : gives the author with the id
+func (s *Server) GetAuthor(ctx context.Context, req *pb.GetAuthorRequest) (*pb.Author, error) {
+	if req.GetId() <= 0 {
+		return nil, errors.New("invalid id")
+	}
+
+	authors, err := s.authorService.GetByIDs(ctx, []int{int(req.GetId())})
+	if err != nil {
+		return nil, err
+	}
+
+	author, ok := authors[int(req.GetId())]
+	if !ok {
+		return nil, errors.New("author does not exist")
+	}
+
+	return pb.FromAuthor(author), nil
+}
+
+// ListAuthors : sends every author as it is read
+func (s *Server) ListAuthors(_ *pb.ListAuthorsRequest, stream pb.AuthorService_ListAuthorsServer) error {
+	return s.authorService.StreamAuthors(stream.Context(), entities.Projection{}, func(author entities.Author) error {
+		return stream.Send(pb.FromAuthor(author))
+	})
+}
+
+// CreateAuthor : creates the author
+func (s *Server) CreateAuthor(ctx context.Context, req *pb.CreateAuthorRequest) (*pb.Author, error) {
+	author, err := s.authorService.Post(ctx, req.GetAuthor().Entity())
+	if err != nil {
+		return nil, err
+	}
+
+	return pb.FromAuthor(author), nil
+}
+
+// UpdateAuthor : replaces the author with the id
+func (s *Server) UpdateAuthor(ctx context.Context, req *pb.UpdateAuthorRequest) (*pb.Author, error) {
+	author, err := s.authorService.Put(ctx, req.GetAuthor().Entity(), int(req.GetId()))
+	if err != nil {
+		return nil, err
+	}
+
+	return pb.FromAuthor(author), nil
+}
+
+// DeleteAuthor : deletes the author with the id
+func (s *Server) DeleteAuthor(ctx context.Context, req *pb.DeleteAuthorRequest) (*pb.DeleteAuthorResponse, error) {
+	if err := s.authorService.Delete(ctx, int(req.GetId())); err != nil {
+		return nil, err
+	}
+
+	return &pb.DeleteAuthorResponse{}, nil
+}
@@ -0,0 +1,104 @@
+package authorgrpc
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/grpc/pb"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+// listStream : collects the authors sent on a ListAuthors stream
+type listStream struct {
+	grpc.ServerStream
+	sent []*pb.Author
+}
+
+func (s *listStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *listStream) Send(author *pb.Author) error {
+	s.sent = append(s.sent, author)
+	return nil
+}
+
+// TestGetAuthor : test that a missing author is reported as not existing
+func TestGetAuthor(t *testing.T) {
+	testcases := []struct {
+		desc    string
+		id      int32
+		authors map[int]entities.Author
+
+		expected    *pb.Author
+		expectedErr error
+	}{
+		{desc: "found", id: 4, authors: map[int]entities.Author{4: {AuthorID: 4, FirstName: "ruskin", DOB: "19-05-1934"}},
+			expected: &pb.Author{AuthorId: 4, FirstName: "ruskin", Dob: "19-05-1934"}},
+		{desc: "missing", id: 5, authors: map[int]entities.Author{}, expectedErr: errors.New("author does not exist")},
+		{desc: "invalid id", id: -1, expectedErr: errors.New("invalid id")},
+	}
+
+	ctrl := gomock.NewController(t)
+	mock := service.NewMockAuthorService(ctrl)
+	s := New(mock)
+
+	for _, tc := range testcases {
+		if tc.id > 0 {
+			mock.EXPECT().GetByIDs(gomock.Any(), []int{int(tc.id)}).Return(tc.authors, nil)
+		}
+
+		author, err := s.GetAuthor(context.Background(), &pb.GetAuthorRequest{Id: tc.id})
+
+		if !proto.Equal(author, tc.expected) || !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v, got: %v %v", tc.desc, author, err)
+		}
+	}
+}
+
+// TestListAuthors : test that every author is sent
+func TestListAuthors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := service.NewMockAuthorService(ctrl)
+
+	mock.EXPECT().StreamAuthors(gomock.Any(), entities.Projection{}, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ entities.Projection, fn func(entities.Author) error) error {
+			if err := fn(entities.Author{AuthorID: 1, FirstName: "ruskin"}); err != nil {
+				return err
+			}
+
+			return fn(entities.Author{AuthorID: 2, FirstName: "paulo"})
+		})
+
+	stream := &listStream{}
+
+	err := New(mock).ListAuthors(&pb.ListAuthorsRequest{}, stream)
+
+	if err != nil || len(stream.sent) != 2 || stream.sent[1].GetFirstName() != "paulo" {
+		t.Errorf("unexpected stream %v %v", stream.sent, err)
+	}
+}
+
+// TestCreateAuthor : test that the message is passed to the service
+func TestCreateAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := service.NewMockAuthorService(ctrl)
+
+	mock.EXPECT().Post(gomock.Any(), entities.Author{FirstName: "ruskin", LastName: "bond", DOB: "19-05-1934",
+		PenName: "rb"}).Return(entities.Author{AuthorID: 9, FirstName: "ruskin", LastName: "bond", DOB: "19-05-1934",
+		PenName: "rb"}, nil)
+
+	author, err := New(mock).CreateAuthor(context.Background(), &pb.CreateAuthorRequest{Author: &pb.Author{
+		FirstName: "ruskin", LastName: "bond", Dob: "19-05-1934", PenName: "rb"}})
+
+	if err != nil || author.GetAuthorId() != 9 {
+		t.Errorf("unexpected author %v %v", author, err)
+	}
+}
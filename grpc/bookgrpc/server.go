@@ -0,0 +1,78 @@
+package bookgrpc
+
+import (
+	"context"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/grpc/pb"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+// Server : serves the book RPCs with the book service, the errors are mapped to status codes by the interceptors
+type Server struct {
+	pb.UnimplementedBookServiceServer
+
+	bookService service.BookService
+}
+
+// New : factory function used for injection
+func New(b service.BookService) *Server {
+	return &Server{bookService: b}
+}
+
+// GetBook : gives the book with the id and its author
+func (s *Server) GetBook(ctx context.Context, req *pb.GetBookRequest) (*pb.Book, error) {
+	book, err := s.bookService.GetBookByID(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, err
+	}
+
+	return pb.FromBook(book), nil
+}
+
+// ListBooks : sends the matching books as they are read, with their author when include_author is set
+func (s *Server) ListBooks(req *pb.ListBooksRequest, stream pb.BookService_ListBooksServer) error {
+	filter := entities.BookFilter{Title: req.GetTitle(), Genre: req.GetGenre(), Tags: req.GetTags()}
+
+	var projection entities.Projection
+	if req.GetIncludeAuthor() {
+		projection.Expand = []string{"author"}
+	}
+
+	return s.bookService.StreamBooks(stream.Context(), filter, projection, func(book entities.Book) error {
+		return stream.Send(pb.FromBook(book))
+	})
+}
+
+// CreateBook : creates the book
+func (s *Server) CreateBook(ctx context.Context, req *pb.CreateBookRequest) (*pb.Book, error) {
+	book := req.GetBook().Entity()
+
+	created, err := s.bookService.Post(ctx, &book)
+	if err != nil {
+		return nil, err
+	}
+
+	return pb.FromBook(created), nil
+}
+
+// UpdateBook : replaces the book with the id
+func (s *Server) UpdateBook(ctx context.Context, req *pb.UpdateBookRequest) (*pb.Book, error) {
+	book := req.GetBook().Entity()
+
+	updated, err := s.bookService.Put(ctx, &book, int(req.GetId()))
+	if err != nil {
+		return nil, err
+	}
+
+	return pb.FromBook(updated), nil
+}
+
+// DeleteBook : deletes the book with the id
+func (s *Server) DeleteBook(ctx context.Context, req *pb.DeleteBookRequest) (*pb.DeleteBookResponse, error) {
+	if err := s.bookService.Delete(ctx, int(req.GetId())); err != nil {
+		return nil, err
+	}
+
+	return &pb.DeleteBookResponse{}, nil
+}
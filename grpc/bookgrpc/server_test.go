@@ -0,0 +1,100 @@
+package bookgrpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/grpc/pb"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+// listStream : collects the books sent on a ListBooks stream
+type listStream struct {
+	grpc.ServerStream
+	sent []*pb.Book
+}
+
+func (s *listStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *listStream) Send(book *pb.Book) error {
+	s.sent = append(s.sent, book)
+	return nil
+}
+
+// TestListBooks : test that the filter is passed on and include_author expands the author
+func TestListBooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := service.NewMockBookService(ctrl)
+
+	mock.EXPECT().StreamBooks(gomock.Any(), entities.BookFilter{Genre: "fiction", Tags: []string{"kids"}},
+		entities.Projection{Expand: []string{"author"}}, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ entities.BookFilter, _ entities.Projection, fn func(entities.Book) error) error {
+			return fn(entities.Book{BookID: 3, AuthorID: 7, Title: "rusty", GenreIDs: []int{2},
+				Author: &entities.Author{AuthorID: 7, PenName: "rb"}})
+		})
+
+	stream := &listStream{}
+
+	err := New(mock).ListBooks(&pb.ListBooksRequest{Genre: "fiction", Tags: []string{"kids"}, IncludeAuthor: true},
+		stream)
+
+	expected := &pb.Book{BookId: 3, AuthorId: 7, Title: "rusty", GenreIds: []int32{2},
+		Author: &pb.Author{AuthorId: 7, PenName: "rb"}}
+
+	if err != nil || len(stream.sent) != 1 || !proto.Equal(stream.sent[0], expected) {
+		t.Errorf("unexpected stream %v %v", stream.sent, err)
+	}
+}
+
+// TestGetBook : test that the genre ids are taken from the genres of the book
+func TestGetBook(t *testing.T) {
+	testcases := []struct {
+		desc string
+		book entities.Book
+		err  error
+
+		expected *pb.Book
+	}{
+		{desc: "found", book: entities.Book{BookID: 3, Title: "rusty", Genres: []entities.Genre{{GenreID: 2},
+			{GenreID: 5}}}, expected: &pb.Book{BookId: 3, Title: "rusty", GenreIds: []int32{2, 5}}},
+		{desc: "missing", err: errors.New("book does not exist")},
+	}
+
+	ctrl := gomock.NewController(t)
+	mock := service.NewMockBookService(ctrl)
+	s := New(mock)
+
+	for _, tc := range testcases {
+		mock.EXPECT().GetBookByID(gomock.Any(), 3).Return(tc.book, tc.err)
+
+		book, err := s.GetBook(context.Background(), &pb.GetBookRequest{Id: 3})
+
+		if !proto.Equal(book, tc.expected) || err != tc.err {
+			t.Errorf("failed for %v, got: %v %v", tc.desc, book, err)
+		}
+	}
+}
+
+// TestUpdateBook : test that the message is passed to the service with the id
+func TestUpdateBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := service.NewMockBookService(ctrl)
+
+	mock.EXPECT().Put(gomock.Any(), &entities.Book{AuthorID: 7, Title: "rusty", GenreIDs: []int{2}}, 3).Return(
+		entities.Book{BookID: 3, AuthorID: 7, Title: "rusty"}, nil)
+
+	book, err := New(mock).UpdateBook(context.Background(), &pb.UpdateBookRequest{Id: 3, Book: &pb.Book{AuthorId: 7,
+		Title: "rusty", GenreIds: []int32{2}}})
+
+	if err != nil || book.GetBookId() != 3 {
+		t.Errorf("unexpected book %v %v", book, err)
+	}
+}
@@ -0,0 +1,159 @@
+package interceptor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+	"projects/GoLang-Interns-2022/authorbook/middleware/authz"
+)
+
+// credentials : the metadata keys carrying the credentials, the same headers the REST API reads
+var credentials = []string{"authorization", "x-api-key"}
+
+// Unary : authenticates the caller, lets the call through only when the policy grants the permission of its
+// method and maps the errors of the services to status codes
+func Unary(a auth.Authenticator, p authz.Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authorize(ctx, a, p, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := handler(ctx, req)
+
+		return resp, Status(err)
+	}
+}
+
+// Stream : does for streaming calls what Unary does for unary ones
+func Stream(a auth.Authenticator, p authz.Policy) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(ss.Context(), a, p, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return Status(handler(srv, serverStream{ss, ctx}))
+	}
+}
+
+// Recover : answers a unary call whose handler panicked with Internal, the panic would stop the whole server
+func Recover() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(info.FullMethod, r)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// RecoverStream : does for streaming calls what Recover does for unary ones
+func RecoverStream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(info.FullMethod, r)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+// recovered : logs the panic of a method with its stack and gives the status the caller gets
+func recovered(method string, r interface{}) error {
+	log.Printf("panic in %v: %v\n%s", method, r, debug.Stack())
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+// serverStream : a server stream with the context carrying the identity of the caller
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// authorize : authenticates the caller with the credentials of the metadata and checks the permission the policy
+// requires for the method, gRPC calls are POSTs to the full method name so the policy routes them like requests
+func authorize(ctx context.Context, a auth.Authenticator, p authz.Policy, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	r := (&http.Request{Method: http.MethodPost, URL: &url.URL{Path: method}, Header: http.Header{}}).WithContext(ctx)
+	for _, key := range credentials {
+		for _, value := range md.Get(key) {
+			r.Header.Add(key, value)
+		}
+	}
+
+	id, err := a.Authenticate(r)
+	if err != nil {
+		log.Print(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	ctx = auth.NewContext(ctx, id)
+
+	permission, ok := p.Permission(http.MethodPost, method)
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, "no permission defined for "+method)
+	}
+
+	if err = p.Check(ctx, permission); err != nil {
+		log.Printf("%v denied %v", id.Subject, permission)
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return ctx, nil
+}
+
+// Status : maps an error of the services to a status, missing rows and entities are NotFound and invalid
+// input is InvalidArgument, errors which already are a status are kept
+func Status(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	msg := err.Error()
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows), strings.HasSuffix(msg, "does not exist"),
+		strings.HasSuffix(msg, "did not exist at that time"):
+		return status.Error(codes.NotFound, msg)
+	case strings.HasPrefix(msg, "invalid "):
+		return status.Error(codes.InvalidArgument, msg)
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, msg)
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, msg)
+	}
+
+	log.Print(err)
+
+	return status.Error(codes.Internal, msg)
+}
@@ -0,0 +1,120 @@
+package interceptor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+	"projects/GoLang-Interns-2022/authorbook/middleware/authz"
+)
+
+// keys : authenticates the API keys of the map
+type keys map[string]auth.Identity
+
+func (k keys) Authenticate(r *http.Request) (auth.Identity, error) {
+	id, ok := k[r.Header.Get("X-API-Key")]
+	if !ok {
+		return auth.Identity{}, auth.ErrNoCredentials
+	}
+
+	return id, nil
+}
+
+// TestUnary : test that calls are authenticated from the metadata and need the permission of their method
+func TestUnary(t *testing.T) {
+	testcases := []struct {
+		desc   string
+		key    string
+		method string
+		err    error
+
+		expectedCode codes.Code
+	}{
+		{desc: "reader gets author", key: "r", method: "/authorbook.v1.AuthorService/GetAuthor",
+			expectedCode: codes.OK},
+		{desc: "reader deletes book", key: "r", method: "/authorbook.v1.BookService/DeleteBook",
+			expectedCode: codes.PermissionDenied},
+		{desc: "admin deletes book", key: "a", method: "/authorbook.v1.BookService/DeleteBook",
+			expectedCode: codes.OK},
+		{desc: "unknown method", key: "a", method: "/authorbook.v1.BookService/Burn",
+			expectedCode: codes.PermissionDenied},
+		{desc: "no key", method: "/authorbook.v1.AuthorService/GetAuthor", expectedCode: codes.Unauthenticated},
+		{desc: "service error", key: "a", method: "/authorbook.v1.BookService/GetBook",
+			err: errors.New("book does not exist"), expectedCode: codes.NotFound},
+	}
+
+	interceptor := Unary(keys{"r": {Subject: "asha", Roles: []string{"reader"}},
+		"a": {Subject: "ravi", Roles: []string{"admin"}}}, authz.DefaultPolicy())
+
+	for _, tc := range testcases {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", tc.key))
+
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tc.method},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				if _, ok := auth.FromContext(ctx); !ok {
+					t.Errorf("%v: missing identity in the handler", tc.desc)
+				}
+
+				return nil, tc.err
+			})
+
+		if status.Code(err) != tc.expectedCode {
+			t.Errorf("failed for %v, got: %v", tc.desc, err)
+		}
+	}
+}
+
+// TestStatus : test the mapping of service errors to status codes
+func TestStatus(t *testing.T) {
+	testcases := []struct {
+		err          error
+		expectedCode codes.Code
+	}{
+		{nil, codes.OK},
+		{sql.ErrNoRows, codes.NotFound},
+		{errors.New("author does not exist"), codes.NotFound},
+		{errors.New("book did not exist at that time"), codes.NotFound},
+		{errors.New("invalid constraints"), codes.InvalidArgument},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{status.Error(codes.Aborted, "aborted"), codes.Aborted},
+		{errors.New("database issue"), codes.Internal},
+	}
+
+	for _, tc := range testcases {
+		if code := status.Code(Status(tc.err)); code != tc.expectedCode {
+			t.Errorf("failed for %v, got: %v", tc.err, code)
+		}
+	}
+}
+
+// TestRecover : test that a panicking handler is answered with Internal
+func TestRecover(t *testing.T) {
+	_, err := Recover()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/authorbook.BookService/CreateBook"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			panic("index out of range")
+		})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("expected Internal, got: %v", err)
+	}
+
+	err = RecoverStream()(nil, nil, &grpc.StreamServerInfo{FullMethod: "/authorbook.BookService/ListBooks"},
+		func(srv interface{}, stream grpc.ServerStream) error {
+			panic("index out of range")
+		})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("expected Internal, got: %v", err)
+	}
+
+	if _, err = Recover()(context.Background(), nil, &grpc.UnaryServerInfo{},
+		func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
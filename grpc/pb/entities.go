@@ -0,0 +1,72 @@
+package pb
+
+import "projects/GoLang-Interns-2022/authorbook/entities"
+
+// FromAuthor : converts an author of the services to its message
+func FromAuthor(a entities.Author) *Author {
+	return &Author{
+		AuthorId:  int32(a.AuthorID),
+		FirstName: a.FirstName,
+		LastName:  a.LastName,
+		Dob:       a.DOB,
+		PenName:   a.PenName,
+	}
+}
+
+// Entity : converts the message to an author of the services, a nil message gives an empty author
+func (x *Author) Entity() entities.Author {
+	return entities.Author{
+		AuthorID:  int(x.GetAuthorId()),
+		FirstName: x.GetFirstName(),
+		LastName:  x.GetLastName(),
+		DOB:       x.GetDob(),
+		PenName:   x.GetPenName(),
+	}
+}
+
+// FromBook : converts a book of the services to its message, the genre ids are taken from the genres when the
+// book was read with them
+func FromBook(b entities.Book) *Book {
+	book := &Book{
+		BookId:        int32(b.BookID),
+		AuthorId:      int32(b.AuthorID),
+		Title:         b.Title,
+		Publication:   b.Publication,
+		PublishedDate: b.PublishedDate,
+		Tags:          b.Tags,
+	}
+
+	for _, id := range b.GenreIDs {
+		book.GenreIds = append(book.GenreIds, int32(id))
+	}
+
+	if len(b.GenreIDs) == 0 {
+		for _, genre := range b.Genres {
+			book.GenreIds = append(book.GenreIds, int32(genre.GenreID))
+		}
+	}
+
+	if b.Author != nil {
+		book.Author = FromAuthor(*b.Author)
+	}
+
+	return book
+}
+
+// Entity : converts the message to a book of the services, a nil message gives an empty book
+func (x *Book) Entity() entities.Book {
+	book := entities.Book{
+		BookID:        int(x.GetBookId()),
+		AuthorID:      int(x.GetAuthorId()),
+		Title:         x.GetTitle(),
+		Publication:   x.GetPublication(),
+		PublishedDate: x.GetPublishedDate(),
+		Tags:          x.GetTags(),
+	}
+
+	for _, id := range x.GetGenreIds() {
+		book.GenreIDs = append(book.GenreIDs, int(id))
+	}
+
+	return book
+}
@@ -0,0 +1,94 @@
+package apikeyhttp
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+}
+
+// New : factory function
+func New(s service.APIKeyService) APIKeyHandler {
+	return APIKeyHandler{s}
+}
+
+// GetAll : handles the request of listing the API keys
+func (h APIKeyHandler) GetAll(ctx *gofr.Context) (interface{}, error) {
+	keys, err := h.apiKeyService.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Post : handles the request of issuing an API key
+func (h APIKeyHandler) Post(ctx *gofr.Context) (interface{}, error) {
+	var key entities.APIKey
+
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(body, &key)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err = h.apiKeyService.Post(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Put : handles the request of changing the role and scopes of an API key
+func (h APIKeyHandler) Put(ctx *gofr.Context) (interface{}, error) {
+	var key entities.APIKey
+
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(body, &key)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	key, err = h.apiKeyService.Put(ctx, key, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Delete : handles the request of revoking an API key
+func (h APIKeyHandler) Delete(ctx *gofr.Context) (interface{}, error) {
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.apiKeyService.Delete(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return "successfully revoked", nil
+}
@@ -0,0 +1,97 @@
+package apikeyhttp
+
+import (
+	"bytes"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+)
+
+// TestPost : to test issuing an API key
+func TestPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockAPIKeyService(ctrl)
+	mock := New(mockService)
+
+	input := entities.APIKey{Name: "importer", Role: "reader", Scopes: []string{"read-only"}}
+	issued := entities.APIKey{APIKeyID: 1, Name: "importer", Prefix: "cafe0001", Role: "reader",
+		Scopes: []string{"read-only"}, Key: "ak_cafe0001.secret"}
+
+	testcases := []struct {
+		desc string
+		body string
+
+		expected interface{}
+	}{
+		{desc: "valid key", body: `{"name":"importer","role":"reader","scopes":["read-only"]}`, expected: issued},
+		{desc: "unmarshalling error", body: "key", expected: nil},
+	}
+
+	k := gofr.New()
+	for _, tc := range testcases {
+		r := httptest.NewRequest("POST", "localhost:8000/apikey", bytes.NewBufferString(tc.body))
+		w := httptest.NewRecorder()
+
+		req := request.NewHTTPRequest(r)
+		res := responder.NewContextualResponder(w, r)
+		ctx := gofr.NewContext(res, req, k)
+
+		mockService.EXPECT().Post(ctx, input).Return(issued, nil).AnyTimes()
+
+		result, _ := mock.Post(ctx)
+
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
+
+// TestDelete : to test revoking an API key
+func TestDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockAPIKeyService(ctrl)
+	mock := New(mockService)
+
+	testcases := []struct {
+		desc string
+		id   string
+
+		svcErr   error
+		expected interface{}
+	}{
+		{desc: "revoked", id: "1", expected: "successfully revoked"},
+		{desc: "unknown key", id: "2", svcErr: errors.New("api key does not exist"), expected: nil},
+		{desc: "invalid id", id: "one", expected: nil},
+	}
+
+	k := gofr.New()
+	for _, tc := range testcases {
+		r := httptest.NewRequest("DELETE", "localhost:8000/apikey/"+tc.id, nil)
+		r = mux.SetURLVars(r, map[string]string{"id": tc.id})
+		w := httptest.NewRecorder()
+
+		req := request.NewHTTPRequest(r)
+		res := responder.NewContextualResponder(w, r)
+		ctx := gofr.NewContext(res, req, k)
+
+		if tc.id != "one" {
+			mockService.EXPECT().Delete(ctx, gomock.Any()).Return(tc.svcErr)
+		}
+
+		result, _ := mock.Delete(ctx)
+
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
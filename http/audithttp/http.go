@@ -0,0 +1,51 @@
+package audithttp
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"strconv"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+// New : factory function
+func New(s service.AuditService) AuditHandler {
+	return AuditHandler{s}
+}
+
+// GetAll : handles the request of reading the audit log
+func (h AuditHandler) GetAll(ctx *gofr.Context) (interface{}, error) {
+	filter := entities.AuditFilter{EntityType: ctx.Param("entity"), Actor: ctx.Param("actor"), From: ctx.Param("from"),
+		To: ctx.Param("to")}
+
+	var err error
+
+	if entityID := ctx.Param("entityID"); entityID != "" {
+		if filter.EntityID, err = strconv.Atoi(entityID); err != nil {
+			return nil, err
+		}
+	}
+
+	if page := ctx.Param("page"); page != "" {
+		if filter.Page, err = strconv.Atoi(page); err != nil {
+			return nil, err
+		}
+	}
+
+	if limit := ctx.Param("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := h.auditService.GetAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
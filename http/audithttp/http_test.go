@@ -0,0 +1,55 @@
+package audithttp
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+
+	"github.com/golang/mock/gomock"
+)
+
+// TestGetAll : to test the query parameters reach the service
+func TestGetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockAuditService(ctrl)
+	mock := New(mockService)
+
+	entries := []entities.AuditEntry{{AuditID: 1, Actor: "asha", Operation: "update", EntityType: "book", EntityID: 3}}
+
+	testcases := []struct {
+		desc  string
+		query string
+
+		filter   entities.AuditFilter
+		expected interface{}
+	}{
+		{desc: "filtered", query: "?entity=book&entityID=3&actor=asha&from=2022-08-01&page=2&limit=5",
+			filter: entities.AuditFilter{EntityType: "book", EntityID: 3, Actor: "asha", From: "2022-08-01", Page: 2,
+				Limit: 5}, expected: entries},
+		{desc: "invalid entity id", query: "?entityID=three", expected: nil},
+	}
+
+	k := gofr.New()
+	for _, tc := range testcases {
+		r := httptest.NewRequest("GET", "localhost:8000/audit"+tc.query, nil)
+		w := httptest.NewRecorder()
+
+		req := request.NewHTTPRequest(r)
+		res := responder.NewContextualResponder(w, r)
+		ctx := gofr.NewContext(res, req, k)
+
+		mockService.EXPECT().GetAll(ctx, tc.filter).Return(entries, nil).AnyTimes()
+
+		result, _ := mock.GetAll(ctx)
+
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
@@ -5,9 +5,13 @@ import (
 	"encoding/json"
 	"io"
 	"log"
+	"net/http"
 	"strconv"
 
 	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/http/fieldset"
+	"projects/GoLang-Interns-2022/authorbook/http/ndjson"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
 	"projects/GoLang-Interns-2022/authorbook/service"
 )
 
@@ -20,6 +24,53 @@ func New(a service.AuthorService) AuthorHandler {
 	return AuthorHandler{a}
 }
 
+// GetAll : handles the request of getting all authors, ?fields= and ?expand= shape the listing
+func (h AuthorHandler) GetAll(c *gofr.Context) (interface{}, error) {
+	projection, err := fieldset.Parse(listing, c.Param("fields"), c.Param("expand"))
+	if err != nil {
+		return nil, err
+	}
+
+	authors, err := h.authorService.GetAll(c, entities.AuthorFilter{}, projection)
+	if err != nil {
+		return nil, err
+	}
+
+	return fieldset.Apply(authors, projection)
+}
+
+// StreamAll : handles the request of getting all authors as NDJSON, the authors are written as they are read
+// so it is a plain http handler
+func (h AuthorHandler) StreamAll(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	projection, err := fieldset.Parse(listing, query.Get("fields"), query.Get("expand"))
+	if err != nil {
+		auth.WriteError(w, http.StatusBadRequest, http.StatusText(http.StatusBadRequest), err.Error())
+		return
+	}
+
+	nw := ndjson.NewWriter(w)
+
+	nw.Finish(h.authorService.StreamAuthors(r.Context(), projection, func(author entities.Author) error {
+		v, err := fieldset.Apply(author, projection)
+		if err != nil {
+			return err
+		}
+
+		return nw.Write(v)
+	}))
+}
+
+// listing : the fields of the author listing and of the books it can embed
+var listing = fieldset.Resource{
+	ID:     "authorID",
+	Fields: []string{"authorID", "firstName", "lastName", "DOB", "penName"},
+	Embedded: map[string][]string{
+		"books": {"bookID", "authorID", "title", "publication", "publishedDate"},
+	},
+}
+
 // Post : handles the request of posting an author
 func (h AuthorHandler) Post(c *gofr.Context) (interface{}, error) {
 	var author entities.Author
@@ -91,3 +142,18 @@ func (h AuthorHandler) Delete(ctx *gofr.Context) (interface{}, error) {
 
 	return "successfully deleted!", nil
 }
+
+// GetHistory : handles the request of listing the revisions of an author
+func (h AuthorHandler) GetHistory(ctx *gofr.Context) (interface{}, error) {
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := h.authorService.GetHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"log"
+	"reflect"
 	"strconv"
 	"testing"
 
@@ -74,7 +75,7 @@ func TestPost(t *testing.T) {
 
 		result, _ := mock.Post(ctx)
 
-		if tc.expected != result {
+		if !reflect.DeepEqual(tc.expected, result) {
 			t.Errorf("failed for %v\n", tc.desc)
 		}
 	}
@@ -184,3 +185,132 @@ func TestDelete(t *testing.T) {
 		}
 	}
 }
+
+// TestGetHistory : to test the history handler
+func TestGetHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockAuthorService(ctrl)
+	mock := New(mockService)
+
+	revisions := []entities.AuthorRevision{{Revision: 1, Operation: "create", Actor: "asha",
+		Author: entities.Author{AuthorID: 4, FirstName: "nilotpal"}}}
+
+	testcases := []struct {
+		desc   string
+		target string
+
+		expected interface{}
+	}{
+		{desc: "revisions", target: "4", expected: revisions},
+		{desc: "invalid id", target: "four", expected: nil},
+	}
+
+	k := gofr.New()
+	for _, tc := range testcases {
+		r := httptest.NewRequest("GET", "localhost:8000/author/"+tc.target+"/history", nil)
+		r = mux.SetURLVars(r, map[string]string{"id": tc.target})
+		w := httptest.NewRecorder()
+
+		req := request.NewHTTPRequest(r)
+		res := responder.NewContextualResponder(w, r)
+		ctx := gofr.NewContext(res, req, k)
+
+		mockService.EXPECT().GetHistory(ctx, 4).Return(revisions, nil).AnyTimes()
+
+		result, _ := mock.GetHistory(ctx)
+
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
+
+// TestGetAll : to test the listing of authors
+func TestGetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockAuthorService(ctrl)
+	mock := New(mockService)
+
+	authors := []entities.Author{{AuthorID: 4, FirstName: "nilotpal", LastName: "mrinal", DOB: "02/11/1971"}}
+	sparse := entities.Projection{Fields: []string{"penName", "authorID"}}
+
+	testcases := []struct {
+		desc       string
+		query      string
+		projection entities.Projection
+		err        error
+
+		expected interface{}
+	}{
+		{desc: "authors", expected: authors},
+		{desc: "sparse fields", query: "?fields=penname", projection: sparse,
+			expected: json.RawMessage(`[{"authorID":4,"penName":""}]`)},
+		{desc: "database failure", err: errors.New("connection lost"), expected: nil},
+	}
+
+	k := gofr.New()
+	for _, tc := range testcases {
+		r := httptest.NewRequest("GET", "localhost:8000/author"+tc.query, nil)
+		w := httptest.NewRecorder()
+
+		req := request.NewHTTPRequest(r)
+		res := responder.NewContextualResponder(w, r)
+		ctx := gofr.NewContext(res, req, k)
+
+		if tc.err != nil {
+			mockService.EXPECT().GetAll(ctx, entities.AuthorFilter{}, tc.projection).Return(nil, tc.err)
+		} else {
+			mockService.EXPECT().GetAll(ctx, entities.AuthorFilter{}, tc.projection).Return(authors, nil)
+		}
+
+		result, err := mock.GetAll(ctx)
+
+		if !reflect.DeepEqual(result, tc.expected) || (err != nil) != (tc.err != nil) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+
+	r := httptest.NewRequest("GET", "localhost:8000/author?expand=reviews", nil)
+	ctx := gofr.NewContext(responder.NewContextualResponder(httptest.NewRecorder(), r), request.NewHTTPRequest(r), k)
+
+	if _, err := mock.GetAll(ctx); err == nil {
+		t.Errorf("expected an error for an unknown expansion")
+	}
+}
+
+// TestStreamAll : to test the NDJSON stream of authors
+func TestStreamAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockAuthorService(ctrl)
+	mock := New(mockService)
+
+	mockService.EXPECT().StreamAuthors(gomock.Any(), entities.Projection{}, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ entities.Projection, fn func(entities.Author) error) error {
+			for _, author := range []entities.Author{{AuthorID: 1, FirstName: "a"}, {AuthorID: 2, FirstName: "b"}} {
+				if err := fn(author); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+
+	w := httptest.NewRecorder()
+
+	mock.StreamAll(w, httptest.NewRequest(http.MethodGet, "/author", nil))
+
+	expected := `{"authorID":1,"firstName":"a","lastName":"","DOB":"","penName":""}` + "\n" +
+		`{"authorID":2,"firstName":"b","lastName":"","DOB":"","penName":""}` + "\n"
+
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/x-ndjson" || w.Body.String() != expected {
+		t.Errorf("unexpected stream %v %v %q", w.Code, w.Header(), w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+
+	mock.StreamAll(w, httptest.NewRequest(http.MethodGet, "/author?fields=isbn", nil))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected %v for an unknown field, got %v", http.StatusBadRequest, w.Code)
+	}
+}
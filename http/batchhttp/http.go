@@ -0,0 +1,49 @@
+package batchhttp
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+// maxBatchSize bounds the body of a batch, the service bounds the operations
+const maxBatchSize = 8 << 20
+
+type BatchHandler struct {
+	batchService service.BatchService
+}
+
+// New : factory function
+func New(s service.BatchService) BatchHandler {
+	return BatchHandler{s}
+}
+
+// Post : handles the request of running a batch of operations, the outcome of every operation is in the response
+func (h BatchHandler) Post(ctx *gofr.Context) (interface{}, error) {
+	body, err := io.ReadAll(io.LimitReader(ctx.Request().Body, maxBatchSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) > maxBatchSize {
+		return nil, errors.New("batch too large")
+	}
+
+	var batch entities.Batch
+
+	err = json.Unmarshal(body, &batch)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := h.batchService.Run(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
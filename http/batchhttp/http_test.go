@@ -0,0 +1,66 @@
+package batchhttp
+
+import (
+	"bytes"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+
+	"github.com/golang/mock/gomock"
+)
+
+// TestPost : to test the batch of the body reaches the service
+func TestPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockBatchService(ctrl)
+	mock := New(mockService)
+
+	batch := entities.Batch{Mode: entities.BatchIndependent, Operations: []entities.BatchOperation{
+		{Ref: "a", Op: entities.BatchCreate, Entity: "author", Body: json.RawMessage(`{"firstName":"Frank"}`)},
+		{Op: entities.BatchDelete, Entity: "book", ID: json.RawMessage(`{"$ref":"a"}`)},
+	}}
+	response := entities.BatchResponse{Mode: entities.BatchIndependent, Succeeded: 2}
+
+	testcases := []struct {
+		desc string
+		body string
+		err  error
+
+		expected interface{}
+	}{
+		{desc: "batch", body: `{"mode":"independent","operations":[{"ref":"a","op":"create","entity":"author",` +
+			`"body":{"firstName":"Frank"}},{"op":"delete","entity":"book","id":{"$ref":"a"}}]}`, expected: response},
+		{desc: "invalid batch", body: `{"mode":"independent","operations":[{"ref":"a","op":"create","entity":"author",` +
+			`"body":{"firstName":"Frank"}},{"op":"delete","entity":"book","id":{"$ref":"a"}}]}`,
+			err: errors.New("invalid mode")},
+		{desc: "malformed body", body: `{"operations":`},
+	}
+
+	k := gofr.New()
+	for _, tc := range testcases {
+		r := httptest.NewRequest("POST", "localhost:8000/batch", bytes.NewBufferString(tc.body))
+		w := httptest.NewRecorder()
+
+		req := request.NewHTTPRequest(r)
+		res := responder.NewContextualResponder(w, r)
+		ctx := gofr.NewContext(res, req, k)
+
+		if tc.expected != nil || tc.err != nil {
+			mockService.EXPECT().Run(ctx, batch).Return(response, tc.err)
+		}
+
+		result, err := mock.Post(ctx)
+
+		if !reflect.DeepEqual(result, tc.expected) || (err == nil) != (tc.expected != nil) {
+			t.Errorf("failed for %v: %v %v", tc.desc, result, err)
+		}
+	}
+}
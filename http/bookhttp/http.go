@@ -4,9 +4,14 @@ import (
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
 	"encoding/json"
 	"io"
+	"net/http"
 	"strconv"
+	"strings"
 
 	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/http/fieldset"
+	"projects/GoLang-Interns-2022/authorbook/http/ndjson"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
 	"projects/GoLang-Interns-2022/authorbook/service"
 )
 
@@ -19,17 +24,77 @@ func New(bookS service.BookService) BookHandler {
 	return BookHandler{bookS}
 }
 
-// GetAllBook : handles the request of getting all books
+// GetAllBook : handles the request of getting all books, ?fields= and ?expand= shape the listing
 func (h BookHandler) GetAllBook(ctx *gofr.Context) (interface{}, error) {
-	includeAuthor := ctx.Param("includeAuthor")
-	title := ctx.Param("title")
+	projection, err := listProjection(ctx.Param)
+	if err != nil {
+		return nil, err
+	}
 
-	books, err := h.bookH.GetAllBook(ctx, title, includeAuthor)
+	books, err := h.bookH.GetAllBook(ctx, listFilter(ctx.Param), projection)
 	if err != nil {
 		return nil, err
 	}
 
-	return books, nil
+	return fieldset.Apply(books, projection)
+}
+
+// StreamAllBook : handles the request of getting all books as NDJSON, the books are written as they are read
+// so it is a plain http handler
+func (h BookHandler) StreamAllBook(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	projection, err := listProjection(query.Get)
+	if err != nil {
+		auth.WriteError(w, http.StatusBadRequest, http.StatusText(http.StatusBadRequest), err.Error())
+		return
+	}
+
+	nw := ndjson.NewWriter(w)
+
+	err = h.bookH.StreamBooks(r.Context(), listFilter(query.Get), projection, func(book entities.Book) error {
+		v, err := fieldset.Apply(book, projection)
+		if err != nil {
+			return err
+		}
+
+		return nw.Write(v)
+	})
+
+	nw.Finish(err)
+}
+
+// listing : the fields of the book listing and of the resources it can embed
+var listing = fieldset.Resource{
+	ID:     "bookID",
+	Fields: []string{"bookID", "authorID", "title", "publication", "publishedDate", "updatedAt", "rating"},
+	Embedded: map[string][]string{
+		"author":    {"authorID", "firstName", "lastName", "DOB", "penName", "updatedAt"},
+		"publisher": {"name", "books"},
+		"series":    {"seriesID", "name", "position"},
+	},
+}
+
+// listProjection : the projection of the book listing read from the query parameters, includeAuthor=true
+// expands the author
+func listProjection(param func(key string) string) (entities.Projection, error) {
+	expand := param("expand")
+	if param("includeAuthor") == "true" {
+		expand += ",author"
+	}
+
+	return fieldset.Parse(listing, param("fields"), expand)
+}
+
+// listFilter : the filter of the book listing read from the query parameters
+func listFilter(param func(key string) string) entities.BookFilter {
+	filter := entities.BookFilter{Title: param("title"), Genre: param("genre")}
+
+	if tags := param("tag"); tags != "" {
+		filter.Tags = strings.Split(tags, ",")
+	}
+
+	return filter
 }
 
 // GetBookByID : handles the request of getting a book
@@ -41,6 +106,15 @@ func (h BookHandler) GetBookByID(ctx *gofr.Context) (interface{}, error) {
 		return nil, err
 	}
 
+	if asOf := ctx.Param("asOf"); asOf != "" {
+		book, err := h.bookH.GetBookAsOf(ctx, id, asOf)
+		if err != nil {
+			return nil, err
+		}
+
+		return book, nil
+	}
+
 	book, err := h.bookH.GetBookByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -120,3 +194,38 @@ func (h BookHandler) Delete(ctx *gofr.Context) (interface{}, error) {
 
 	return "successfully deleted", nil
 }
+
+// GetHistory : handles the request of listing the revisions of a book
+func (h BookHandler) GetHistory(ctx *gofr.Context) (interface{}, error) {
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := h.bookH.GetHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// Revert : handles the request of restoring a revision of a book
+func (h BookHandler) Revert(ctx *gofr.Context) (interface{}, error) {
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := strconv.Atoi(ctx.PathParam("revision"))
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := h.bookH.Revert(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return book, nil
+}
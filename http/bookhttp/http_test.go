@@ -2,6 +2,9 @@ package bookhttp
 
 import (
 	"bytes"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
 	"encoding/json"
 	"errors"
 	"log"
@@ -270,3 +273,77 @@ func TestDelete(t *testing.T) {
 		}
 	}
 }
+
+// TestRevert : test the revert handler reads both path parameters
+func TestRevert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockBookService(ctrl)
+	mock := New(mockService)
+
+	book := entities.Book{BookID: 3, AuthorID: 1, Title: "old title", Publication: "penguin",
+		PublishedDate: "20/03/2010"}
+
+	testcases := []struct {
+		desc     string
+		id       string
+		revision string
+
+		expected interface{}
+	}{
+		{desc: "valid revision", id: "3", revision: "1", expected: book},
+		{desc: "invalid revision", id: "3", revision: "first", expected: nil},
+	}
+
+	k := gofr.New()
+	for _, tc := range testcases {
+		r := httptest.NewRequest("POST", "localhost:8000/book/"+tc.id+"/revert/"+tc.revision, nil)
+		r = mux.SetURLVars(r, map[string]string{"id": tc.id, "revision": tc.revision})
+		w := httptest.NewRecorder()
+
+		req := request.NewHTTPRequest(r)
+		res := responder.NewContextualResponder(w, r)
+		ctx := gofr.NewContext(res, req, k)
+
+		mockService.EXPECT().Revert(ctx, 3, 1).Return(book, nil).AnyTimes()
+
+		result, _ := mock.Revert(ctx)
+
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("failed for %s\n", tc.desc)
+		}
+	}
+}
+
+// TestStreamAllBook : test the NDJSON stream of the book listing
+func TestStreamAllBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockBookService(ctrl)
+	mock := New(mockService)
+
+	filter := entities.BookFilter{Title: "rusty", Tags: []string{"kids", "classic"}}
+	projection := entities.Projection{Fields: []string{"title", "author.penName", "bookID"}, Expand: []string{"author"}}
+
+	mockService.EXPECT().StreamBooks(gomock.Any(), filter, projection, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ entities.BookFilter, _ entities.Projection, fn func(entities.Book) error) error {
+			return fn(entities.Book{BookID: 1, AuthorID: 2, Title: "rusty", Author: &entities.Author{PenName: "rb"}})
+		})
+
+	w := httptest.NewRecorder()
+
+	mock.StreamAllBook(w, httptest.NewRequest(http.MethodGet,
+		"/book?title=rusty&tag=kids,classic&includeAuthor=true&fields=title,author.penName", nil))
+
+	expected := `{"bookID":1,"title":"rusty","Author":{"penName":"rb"}}` + "\n"
+
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/x-ndjson" || w.Body.String() != expected {
+		t.Errorf("unexpected stream %v %v %q", w.Code, w.Header(), w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+
+	mock.StreamAllBook(w, httptest.NewRequest(http.MethodGet, "/book?expand=reviews", nil))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected %v for an unknown expansion, got %v", http.StatusBadRequest, w.Code)
+	}
+}
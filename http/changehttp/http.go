@@ -0,0 +1,173 @@
+package changehttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+// MediaType : the media type of a Server-Sent Events stream
+const MediaType = "text/event-stream"
+
+// retryMillis : how long a client waits before it reconnects after the stream ended
+const retryMillis = 3000
+
+// ChangeHandler : streams the changes as Server-Sent Events, so its handler is a plain http handler which keeps
+// the response open and not a gofr handler
+type ChangeHandler struct {
+	changeService service.ChangeService
+	heartbeat     time.Duration
+}
+
+// New : factory function, a comment is sent every heartbeat so idle connections are not closed by proxies
+func New(s service.ChangeService, heartbeat time.Duration) ChangeHandler {
+	return ChangeHandler{s, heartbeat}
+}
+
+// Stream : handles the request of following the changes of authors and books. The id of every event is its
+// sequence number in the outbox, a client resumes after the one in the Last-Event-ID header or, for the first
+// connection, in the lastEventID parameter; without either only the new changes are sent and lastEventID=0 sends
+// every change kept
+func (h ChangeHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	after, filter, err := parse(r)
+	if err != nil {
+		auth.WriteError(w, http.StatusBadRequest, "Invalid Parameter", err.Error())
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		auth.WriteError(w, http.StatusInternalServerError, "Internal Server Error", "streaming is not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", MediaType)
+	w.Header().Set("Cache-Control", "no-cache")
+	// proxies like nginx would hold the events back in their buffer
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	sw := &streamWriter{w: w, flusher: flusher}
+	if err := sw.write(fmt.Sprintf("retry: %d\n\n", retryMillis)); err != nil {
+		return
+	}
+
+	// the heartbeat has to stop before the handler returns, the response may not be written after
+	var wg sync.WaitGroup
+
+	done := make(chan struct{})
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		sw.heartbeat(h.heartbeat, done)
+	}()
+
+	defer wg.Wait()
+	defer close(done)
+
+	err = h.changeService.Follow(r.Context(), after, filter, func(event entities.Event) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		return sw.write(fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", event.EventID, event.Type, data))
+	})
+	if err != nil {
+		// the headers are sent already, the client reconnects and resumes from the last event it got
+		log.Printf("change feed stopped: %v", err)
+	}
+}
+
+// parse : reads the event to resume after and the filter, the entity ids only make sense with an entity type
+func parse(r *http.Request) (int, entities.EventFilter, error) {
+	query := r.URL.Query()
+
+	var (
+		after  = entities.LatestEvent
+		filter entities.EventFilter
+		err    error
+	)
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = query.Get("lastEventID")
+	}
+
+	if lastEventID != "" {
+		after, err = strconv.Atoi(lastEventID)
+		if err != nil || after < 0 {
+			return 0, filter, errors.New("invalid last event id " + lastEventID)
+		}
+	}
+
+	filter.EntityType = query.Get("entityType")
+	if filter.EntityType != "" && filter.EntityType != "author" && filter.EntityType != "book" {
+		return 0, filter, errors.New("invalid entity type " + filter.EntityType)
+	}
+
+	if ids := query.Get("entityID"); ids != "" {
+		if filter.EntityType == "" {
+			return 0, filter, errors.New("entityID needs an entityType")
+		}
+
+		for _, s := range strings.Split(ids, ",") {
+			id, err := strconv.Atoi(s)
+			if err != nil || id <= 0 {
+				return 0, filter, errors.New("invalid entity id " + s)
+			}
+
+			filter.EntityIDs = append(filter.EntityIDs, id)
+		}
+	}
+
+	return after, filter, nil
+}
+
+// streamWriter : flushes every write to the client, the heartbeat writes between the events
+type streamWriter struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+func (sw *streamWriter) write(s string) error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	if _, err := sw.w.Write([]byte(s)); err != nil {
+		return err
+	}
+
+	sw.flusher.Flush()
+
+	return nil
+}
+
+func (sw *streamWriter) heartbeat(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := sw.write(": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
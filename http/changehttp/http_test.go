@@ -0,0 +1,119 @@
+package changehttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+
+	"github.com/golang/mock/gomock"
+)
+
+// TestStream : test the resumption point and filter read from the request and the events written as SSE
+func TestStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockChangeService(ctrl)
+	handler := New(mockService, time.Hour)
+
+	event := entities.Event{EventID: 12, Type: entities.EventBookUpdated, EntityType: "book", EntityID: 3,
+		Actor: "asha", Payload: json.RawMessage(`{"bookID":3}`), CreatedAt: "2022-08-01 10:00:00"}
+
+	testcases := []struct {
+		desc        string
+		target      string
+		lastEventID string
+		after       int
+		filter      entities.EventFilter
+		followErr   error
+	}{
+		{desc: "new changes only", target: "/changes", after: entities.LatestEvent},
+		{desc: "every change kept", target: "/changes?lastEventID=0"},
+		{desc: "header wins over the parameter", target: "/changes?lastEventID=3", lastEventID: "11", after: 11},
+		{desc: "first connection", target: "/changes?lastEventID=3", after: 3},
+		{desc: "filtered", target: "/changes?entityType=book&entityID=3,4", after: entities.LatestEvent,
+			filter: entities.EventFilter{EntityType: "book", EntityIDs: []int{3, 4}}},
+		{desc: "stopped after the event", target: "/changes", after: entities.LatestEvent, followErr: errors.New("broken pipe")},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+
+		mockService.EXPECT().Follow(gomock.Any(), tc.after, tc.filter, gomock.Any()).DoAndReturn(
+			func(_ interface{}, _ int, _ entities.EventFilter, send func(entities.Event) error) error {
+				if err := send(event); err != nil {
+					return err
+				}
+
+				return tc.followErr
+			})
+
+		r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+		if tc.lastEventID != "" {
+			r.Header.Set("Last-Event-ID", tc.lastEventID)
+		}
+
+		w := httptest.NewRecorder()
+
+		handler.Stream(w, r)
+
+		expected := "retry: 3000\n\nid: 12\nevent: BookUpdated\ndata: {\"eventID\":12,\"type\":\"BookUpdated\"," +
+			"\"entityType\":\"book\",\"entityID\":3,\"actor\":\"asha\",\"payload\":{\"bookID\":3}," +
+			"\"createdAt\":\"2022-08-01 10:00:00\"}\n\n"
+
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != MediaType || w.Body.String() != expected {
+			t.Errorf("failed for %v, got %v %v %q", tc.desc, w.Code, w.Header(), w.Body.String())
+		}
+	}
+}
+
+// TestStreamInvalid : test that invalid parameters are rejected before the stream starts
+func TestStreamInvalid(t *testing.T) {
+	handler := New(service.NewMockChangeService(gomock.NewController(t)), time.Hour)
+
+	testcases := []struct {
+		desc   string
+		target string
+	}{
+		{desc: "negative last event id", target: "/changes?lastEventID=-1"},
+		{desc: "last event id not a number", target: "/changes?lastEventID=abc"},
+		{desc: "unknown entity type", target: "/changes?entityType=genre"},
+		{desc: "entity id without type", target: "/changes?entityID=3"},
+		{desc: "entity id not a number", target: "/changes?entityType=book&entityID=3,x"},
+	}
+
+	for _, tc := range testcases {
+		w := httptest.NewRecorder()
+
+		handler.Stream(w, httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("failed for %v, got %v %q", tc.desc, w.Code, w.Body.String())
+		}
+	}
+}
+
+// TestHeartbeat : test that an idle stream gets comments
+func TestHeartbeat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockChangeService(ctrl)
+
+	mockService.EXPECT().Follow(gomock.Any(), entities.LatestEvent, entities.EventFilter{}, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ int, _ entities.EventFilter, _ func(entities.Event) error) error {
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		})
+
+	w := httptest.NewRecorder()
+
+	New(mockService, 10*time.Millisecond).Stream(w, httptest.NewRequest(http.MethodGet, "/changes", nil))
+
+	if body := w.Body.String(); len(body) <= len("retry: 3000\n\n") ||
+		body[len("retry: 3000\n\n"):len("retry: 3000\n\n: heartbeat\n\n")] != ": heartbeat\n\n" {
+		t.Errorf("no heartbeat in %q", body)
+	}
+}
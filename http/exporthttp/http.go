@@ -0,0 +1,175 @@
+package exporthttp
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+	"projects/GoLang-Interns-2022/authorbook/middleware/negotiate"
+	"projects/GoLang-Interns-2022/authorbook/middleware/route"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+// format : an export format with the media type it is negotiated by and the one it is sent with
+type format struct {
+	name        string
+	mediaType   string
+	contentType string
+	extension   string
+}
+
+// formats : the export formats, the first one is sent when the request accepts anything
+var formats = []format{
+	{"csv", "text/csv", "text/csv; charset=utf-8", "csv"},
+	{"ndjson", "application/x-ndjson", "application/x-ndjson", "ndjson"},
+	{"xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "xlsx"},
+	{"marcxml", "application/marcxml+xml", "application/marcxml+xml; charset=utf-8", "xml"},
+	{"marc", "application/marc", "application/marc", "mrc"},
+	{"bibtex", "application/x-bibtex", "application/x-bibtex; charset=utf-8", "bib"},
+	{"dc", "application/oai_dc+xml", "application/oai_dc+xml; charset=utf-8", "xml"},
+}
+
+// ExportHandler : writes exports to the response as they are produced, so its handlers are plain http handlers
+// and not gofr handlers which could only return the whole result
+type ExportHandler struct {
+	exportService service.ExportService
+}
+
+// New : factory function
+func New(s service.ExportService) ExportHandler {
+	return ExportHandler{s}
+}
+
+// Export : handles the request of exporting books or authors, books take the filters of the book listing
+func (h ExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	f, ok := formatOf(r)
+	if !ok {
+		auth.WriteError(w, http.StatusNotAcceptable, "Not Acceptable", "no export format matches the Accept header")
+		return
+	}
+
+	opts := entities.ExportOptions{Entity: query.Get("entity"), Format: f.name,
+		IncludeAuthor: query.Get("includeAuthor") == "true",
+		Filter:        entities.BookFilter{Title: query.Get("title"), Genre: query.Get("genre")}}
+
+	if tags := query.Get("tag"); tags != "" {
+		opts.Filter.Tags = strings.Split(tags, ",")
+	}
+
+	if opts.Entity == "" {
+		opts.Entity = "book"
+	}
+
+	sw := &streamWriter{w: w, contentType: f.contentType, filename: opts.Entity + "s." + f.extension}
+
+	finish(w, sw, h.exportService.Export(r.Context(), sw, opts))
+}
+
+// ExportBook : handles the request of exporting a single book, mostly in one of the library formats
+func (h ExportHandler) ExportBook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(route.Param(r, "id"))
+	if err != nil {
+		auth.WriteError(w, http.StatusBadRequest, "Invalid Parameter", "invalid id")
+		return
+	}
+
+	f, ok := formatOf(r)
+	if !ok {
+		auth.WriteError(w, http.StatusNotAcceptable, "Not Acceptable", "no export format matches the Accept header")
+		return
+	}
+
+	sw := &streamWriter{w: w, contentType: f.contentType, filename: "book-" + strconv.Itoa(id) + "." + f.extension}
+
+	finish(w, sw, h.exportService.ExportBook(r.Context(), sw, id, f.name))
+}
+
+// formatOf : the format named by the format parameter or else the one the Accept header prefers, an unknown name
+// is passed on for the service to reject; false when the header accepts none of the formats
+func formatOf(r *http.Request) (format, bool) {
+	if name := r.URL.Query().Get("format"); name != "" {
+		for _, f := range formats {
+			if f.name == name {
+				return f, true
+			}
+		}
+
+		return format{name: name}, true
+	}
+
+	offered := make([]string, len(formats))
+	for i := range formats {
+		offered[i] = formats[i].mediaType
+	}
+
+	mediaType, ok := negotiate.Select(r.Header.Get("Accept"), offered)
+
+	for _, f := range formats {
+		if ok && f.mediaType == mediaType {
+			return f, true
+		}
+	}
+
+	return format{}, false
+}
+
+// finish : reports the error of an export, once the first row is sent the status can not change any more
+// and the client gets a truncated export; only the errors of the request are told to the client
+func finish(w http.ResponseWriter, sw *streamWriter, err error) {
+	switch {
+	case err != nil && !sw.started:
+		msg := err.Error()
+
+		switch {
+		case strings.HasPrefix(msg, "invalid ") || strings.HasPrefix(msg, "unsupported format "):
+			auth.WriteError(w, http.StatusBadRequest, http.StatusText(http.StatusBadRequest), msg)
+		case strings.HasSuffix(msg, "does not exist"):
+			auth.WriteError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound), msg)
+		default:
+			log.Printf("export %v failed: %v", sw.filename, err)
+			auth.WriteError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError),
+				"the export could not be read")
+		}
+	case err != nil:
+		log.Printf("export %v stopped: %v", sw.filename, err)
+	case !sw.started:
+		sw.start()
+	}
+}
+
+// streamWriter : sends the headers with the first write and flushes every write to the client,
+// the formats buffer their rows so a write carries a few kilobytes
+type streamWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (sw *streamWriter) start() {
+	sw.started = true
+
+	sw.w.Header().Set("Content-Type", sw.contentType)
+	sw.w.Header().Set("Content-Disposition", `attachment; filename="`+sw.filename+`"`)
+	sw.w.WriteHeader(http.StatusOK)
+}
+
+func (sw *streamWriter) Write(p []byte) (int, error) {
+	if !sw.started {
+		sw.start()
+	}
+
+	n, err := sw.w.Write(p)
+
+	if f, ok := sw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+
+	return n, err
+}
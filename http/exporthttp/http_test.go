@@ -0,0 +1,153 @@
+package exporthttp
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/middleware/route"
+	"projects/GoLang-Interns-2022/authorbook/service"
+
+	"github.com/golang/mock/gomock"
+)
+
+// response : the status, headers and body a test expects
+type response struct {
+	status      int
+	contentType string
+	disposition string
+	body        string
+}
+
+// check : compares the recorded response with the expected one
+func check(t *testing.T, desc string, w *httptest.ResponseRecorder, expected response) {
+	if w.Code != expected.status || w.Header().Get("Content-Type") != expected.contentType ||
+		w.Header().Get("Content-Disposition") != expected.disposition || w.Body.String() != expected.body {
+		t.Errorf("failed for %v, got %v %v %q", desc, w.Code, w.Header(), w.Body.String())
+	}
+}
+
+// writing : a service stub which writes the content and returns err
+func writing(content string, err error) func(interface{}, io.Writer, interface{}) error {
+	return func(_ interface{}, w io.Writer, _ interface{}) error {
+		if content != "" {
+			_, _ = io.WriteString(w, content)
+		}
+
+		return err
+	}
+}
+
+// TestExport : test the options read from the query, the headers and the errors before and after the first row
+func TestExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockExportService(ctrl)
+	handler := New(mockService)
+
+	testcases := []struct {
+		desc   string
+		target string
+		accept string
+		opts   *entities.ExportOptions
+		write  func(interface{}, io.Writer, interface{}) error
+
+		expected response
+	}{
+		{desc: "book csv", target: "/export?title=x&genre=fiction&tag=a,b&includeAuthor=true",
+			opts: &entities.ExportOptions{Entity: "book", Format: "csv", IncludeAuthor: true,
+				Filter: entities.BookFilter{Title: "x", Genre: "fiction", Tags: []string{"a", "b"}}},
+			write: writing("book_id\n1\n", nil), expected: response{http.StatusOK, "text/csv; charset=utf-8",
+				`attachment; filename="books.csv"`, "book_id\n1\n"}},
+		{desc: "empty ndjson", target: "/export?entity=author&format=ndjson",
+			opts:  &entities.ExportOptions{Entity: "author", Format: "ndjson"},
+			write: writing("", nil), expected: response{http.StatusOK, "application/x-ndjson",
+				`attachment; filename="authors.ndjson"`, ""}},
+		{desc: "accept header", target: "/export", accept: "application/x-bibtex, */*;q=0.1",
+			opts:  &entities.ExportOptions{Entity: "book", Format: "bibtex"},
+			write: writing("@book{book1,\n}\n", nil), expected: response{http.StatusOK,
+				"application/x-bibtex; charset=utf-8", `attachment; filename="books.bib"`, "@book{book1,\n}\n"}},
+		{desc: "nothing acceptable", target: "/export", accept: "application/pdf",
+			expected: response{status: http.StatusNotAcceptable, contentType: "application/json",
+				body: `{"errors":[{"code":"Not Acceptable","reason":"no export format matches the Accept header"}]}` + "\n"}},
+		{desc: "invalid options", target: "/export?format=pdf", opts: &entities.ExportOptions{Entity: "book", Format: "pdf"},
+			write: writing("", errors.New("invalid constraints")), expected: response{status: http.StatusBadRequest,
+				contentType: "application/json",
+				body:        `{"errors":[{"code":"Bad Request","reason":"invalid constraints"}]}` + "\n"}},
+		{desc: "failure before the first row", target: "/export?format=ndjson",
+			opts:  &entities.ExportOptions{Entity: "book", Format: "ndjson"},
+			write: writing("", errors.New("connection lost")), expected: response{status: http.StatusInternalServerError,
+				contentType: "application/json", body: `{"errors":[{"code":"Internal Server Error",` +
+					`"reason":"the export could not be read"}]}` + "\n"}},
+		{desc: "failure after the first row", target: "/export?format=ndjson",
+			opts:  &entities.ExportOptions{Entity: "book", Format: "ndjson"},
+			write: writing("{}\n", errors.New("connection lost")), expected: response{http.StatusOK,
+				"application/x-ndjson", `attachment; filename="books.ndjson"`, "{}\n"}},
+	}
+
+	for _, tc := range testcases {
+		if tc.opts != nil {
+			mockService.EXPECT().Export(gomock.Any(), gomock.Any(), *tc.opts).DoAndReturn(tc.write)
+		}
+
+		r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+		r.Header.Set("Accept", tc.accept)
+
+		w := httptest.NewRecorder()
+
+		handler.Export(w, r)
+
+		check(t, tc.desc, w, tc.expected)
+	}
+}
+
+// TestExportBook : test the format of a single book and the status of its errors
+func TestExportBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockExportService(ctrl)
+	handler := route.Handle(http.MethodGet, "/book/{id}/export", http.HandlerFunc(New(mockService).ExportBook))(
+		http.NotFoundHandler())
+
+	testcases := []struct {
+		desc   string
+		target string
+		accept string
+		id     int
+		format string
+		write  func(interface{}, io.Writer, interface{}) error
+
+		expected response
+	}{
+		{desc: "marcxml by accept header", target: "/book/7/export", accept: "application/marcxml+xml", id: 7,
+			format: "marcxml", write: writing("<record/>", nil), expected: response{http.StatusOK,
+				"application/marcxml+xml; charset=utf-8", `attachment; filename="book-7.xml"`, "<record/>"}},
+		{desc: "marc by parameter", target: "/book/7/export?format=marc", accept: "text/csv", id: 7, format: "marc",
+			write: writing("00000", nil), expected: response{http.StatusOK, "application/marc",
+				`attachment; filename="book-7.mrc"`, "00000"}},
+		{desc: "missing book", target: "/book/8/export?format=dc", id: 8, format: "dc",
+			write: writing("", errors.New("book does not exist")), expected: response{status: http.StatusNotFound,
+				contentType: "application/json",
+				body:        `{"errors":[{"code":"Not Found","reason":"book does not exist"}]}` + "\n"}},
+		{desc: "invalid id", target: "/book/x/export", expected: response{status: http.StatusBadRequest,
+			contentType: "application/json",
+			body:        `{"errors":[{"code":"Invalid Parameter","reason":"invalid id"}]}` + "\n"}},
+	}
+
+	for _, tc := range testcases {
+		if tc.write != nil {
+			mockService.EXPECT().ExportBook(gomock.Any(), gomock.Any(), tc.id, tc.format).DoAndReturn(
+				func(ctx interface{}, w io.Writer, _ int, format string) error { return tc.write(ctx, w, format) })
+		}
+
+		r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+		r.Header.Set("Accept", tc.accept)
+
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, r)
+
+		check(t, tc.desc, w, tc.expected)
+	}
+}
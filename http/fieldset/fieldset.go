@@ -0,0 +1,255 @@
+package fieldset
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+// Resource : the fields of a listing and of the resources it can embed by their json name, the ID field is always
+// returned so the entries of a sparse listing can still be told apart
+type Resource struct {
+	ID       string
+	Fields   []string
+	Embedded map[string][]string
+}
+
+// Parse : reads the comma separated fields and expand parameters of a listing, names are matched regardless of
+// case; a field of an embedded resource like author.penName expands it and an expanded resource missing from a
+// field list is returned whole
+func Parse(r Resource, fields, expand string) (entities.Projection, error) {
+	var projection entities.Projection
+
+	for _, name := range split(expand) {
+		embedded, ok := lookup(keys(r.Embedded), name)
+		if !ok {
+			return entities.Projection{}, errors.New("invalid expand")
+		}
+
+		projection.Expand = appendOnce(projection.Expand, embedded)
+	}
+
+	for _, name := range split(fields) {
+		field, ok := r.field(name)
+		if !ok {
+			return entities.Projection{}, errors.New("invalid fields")
+		}
+
+		projection.Fields = appendOnce(projection.Fields, field)
+
+		if embedded, _, _ := strings.Cut(field, "."); r.Embedded[embedded] != nil {
+			projection.Expand = appendOnce(projection.Expand, embedded)
+		}
+	}
+
+	if len(projection.Fields) == 0 {
+		return projection, nil
+	}
+
+	projection.Fields = appendOnce(projection.Fields, r.ID)
+
+	for _, embedded := range projection.Expand {
+		if !projection.Includes(embedded) && !hasPrefix(projection.Fields, embedded+".") {
+			projection.Fields = append(projection.Fields, embedded)
+		}
+	}
+
+	return projection, nil
+}
+
+// Apply : gives the value as JSON with only the fields of the projection, in the order they are encoded
+func Apply(v interface{}, projection entities.Projection) (interface{}, error) {
+	if len(projection.Fields) == 0 {
+		return v, nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	if err = filter(&buf, raw, projection.Fields); err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(buf.Bytes()), nil
+}
+
+// field : gives the canonical name of a field of the resource or of one of its embedded resources
+func (r Resource) field(name string) (string, bool) {
+	head, tail, dotted := strings.Cut(name, ".")
+	if !dotted {
+		if field, ok := lookup(r.Fields, name); ok {
+			return field, true
+		}
+
+		return lookup(keys(r.Embedded), name)
+	}
+
+	embedded, ok := lookup(keys(r.Embedded), head)
+	if !ok {
+		return "", false
+	}
+
+	field, ok := lookup(r.Embedded[embedded], tail)
+
+	return embedded + "." + field, ok
+}
+
+// filter : writes the members of the objects in raw whose names lead to one of the fields, arrays are filtered
+// element by element and other values are written as they are
+func filter(buf *bytes.Buffer, raw json.RawMessage, fields []string) error {
+	switch trimmed := bytes.TrimSpace(raw); {
+	case len(trimmed) > 0 && trimmed[0] == '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return err
+		}
+
+		buf.WriteByte('[')
+
+		for i := range items {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			if err := filter(buf, items[i], fields); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte(']')
+
+		return nil
+	case len(trimmed) > 0 && trimmed[0] == '{':
+		return filterObject(buf, trimmed, fields)
+	default:
+		buf.Write(trimmed)
+		return nil
+	}
+}
+
+// filterObject : writes the members of an object which are asked for as a whole or lead to a nested field
+func filterObject(buf *bytes.Buffer, raw json.RawMessage, fields []string) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	buf.WriteByte('{')
+
+	written := 0
+
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		var value json.RawMessage
+		if err = dec.Decode(&value); err != nil {
+			return err
+		}
+
+		name, _ := token.(string)
+
+		whole, nested := false, []string(nil)
+
+		for _, field := range fields {
+			head, tail, dotted := strings.Cut(field, ".")
+			if !strings.EqualFold(head, name) {
+				continue
+			}
+
+			if !dotted {
+				whole = true
+				break
+			}
+
+			nested = append(nested, tail)
+		}
+
+		if !whole && nested == nil {
+			continue
+		}
+
+		if written++; written > 1 {
+			buf.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+
+		if whole {
+			buf.Write(value)
+			continue
+		}
+
+		if err = filter(buf, value, nested); err != nil {
+			return err
+		}
+	}
+
+	buf.WriteByte('}')
+
+	return nil
+}
+
+// split : gives the trimmed non empty names of a comma separated list
+func split(list string) []string {
+	var names []string
+
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// lookup : finds a name among the candidates regardless of case
+func lookup(candidates []string, name string) (string, bool) {
+	for _, candidate := range candidates {
+		if strings.EqualFold(candidate, name) {
+			return candidate, true
+		}
+	}
+
+	return "", false
+}
+
+func keys(m map[string][]string) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func appendOnce(names []string, name string) []string {
+	for _, n := range names {
+		if n == name {
+			return names
+		}
+	}
+
+	return append(names, name)
+}
+
+func hasPrefix(names []string, prefix string) bool {
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,93 @@
+package fieldset
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+var book = Resource{
+	ID:     "bookID",
+	Fields: []string{"bookID", "authorID", "title", "publishedDate"},
+	Embedded: map[string][]string{
+		"author": {"authorID", "firstName", "penName"},
+		"series": {"seriesID", "name", "position"},
+	},
+}
+
+// TestParse : test reading the fields and expand parameters
+func TestParse(t *testing.T) {
+	testcases := []struct {
+		desc   string
+		fields string
+		expand string
+
+		expected    entities.Projection
+		expectedErr bool
+	}{
+		{desc: "every field", expected: entities.Projection{}},
+		{desc: "expansion", expand: "Author, series,author",
+			expected: entities.Projection{Expand: []string{"author", "series"}}},
+		{desc: "embedded field", fields: "title,publisheddate,author.penname",
+			expected: entities.Projection{Fields: []string{"title", "publishedDate", "author.penName", "bookID"},
+				Expand: []string{"author"}}},
+		{desc: "expansion outside the fields", fields: "title", expand: "series",
+			expected: entities.Projection{Fields: []string{"title", "bookID", "series"}, Expand: []string{"series"}}},
+		{desc: "whole embedded resource", fields: "author",
+			expected: entities.Projection{Fields: []string{"author", "bookID"}, Expand: []string{"author"}}},
+		{desc: "unknown field", fields: "isbn", expectedErr: true},
+		{desc: "unknown embedded field", fields: "author.dob", expectedErr: true},
+		{desc: "unknown expansion", expand: "publisher", expectedErr: true},
+	}
+
+	for _, tc := range testcases {
+		projection, err := Parse(book, tc.fields, tc.expand)
+
+		if (err != nil) != tc.expectedErr || !reflect.DeepEqual(projection, tc.expected) {
+			t.Errorf("failed for %v, got %v %v", tc.desc, projection, err)
+		}
+	}
+}
+
+// TestApply : test that only the fields of the projection are returned in their encoded order
+func TestApply(t *testing.T) {
+	books := []entities.Book{
+		{BookID: 1, AuthorID: 2, Title: "rusty", PublishedDate: "11/03/1980",
+			Author: &entities.Author{AuthorID: 2, FirstName: "ruskin", PenName: "rb"},
+			Series: []entities.BookSeries{{SeriesID: 3, Name: "rusty", Position: 1}}},
+		{BookID: 4, Title: "vagrants"},
+	}
+
+	testcases := []struct {
+		desc       string
+		projection entities.Projection
+
+		expected string
+	}{
+		{"sparse", entities.Projection{Fields: []string{"title", "author.penName", "bookID"}},
+			`[{"bookID":1,"title":"rusty","Author":{"penName":"rb"}},{"bookID":4,"title":"vagrants"}]`},
+		{"embedded list", entities.Projection{Fields: []string{"bookID", "series.name"}},
+			`[{"bookID":1,"series":[{"name":"rusty"}]},{"bookID":4}]`},
+		{"whole embedded resource", entities.Projection{Fields: []string{"bookID", "author"}},
+			`[{"bookID":1,"Author":{"authorID":2,"firstName":"ruskin","lastName":"","DOB":"","penName":"rb"}},` +
+				`{"bookID":4}]`},
+	}
+
+	for _, tc := range testcases {
+		v, err := Apply(books, tc.projection)
+		if err != nil {
+			t.Fatalf("failed for %v: %v", tc.desc, err)
+		}
+
+		raw, _ := json.Marshal(v)
+		if string(raw) != tc.expected {
+			t.Errorf("failed for %v, got %s", tc.desc, raw)
+		}
+	}
+
+	if v, _ := Apply(books, entities.Projection{}); !reflect.DeepEqual(v, books) {
+		t.Errorf("expected the books untouched, got %v", v)
+	}
+}
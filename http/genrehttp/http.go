@@ -0,0 +1,109 @@
+package genrehttp
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+type GenreHandler struct {
+	genreService service.GenreService
+}
+
+// New : factory function
+func New(g service.GenreService) GenreHandler {
+	return GenreHandler{g}
+}
+
+// GetAll : handles the request of getting the genre tree
+func (h GenreHandler) GetAll(ctx *gofr.Context) (interface{}, error) {
+	genres, err := h.genreService.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return genres, nil
+}
+
+// GetByID : handles the request of getting a genre with its sub-genres
+func (h GenreHandler) GetByID(ctx *gofr.Context) (interface{}, error) {
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	genre, err := h.genreService.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return genre, nil
+}
+
+// Post : handles the request of posting a genre
+func (h GenreHandler) Post(ctx *gofr.Context) (interface{}, error) {
+	var genre entities.Genre
+
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(body, &genre)
+	if err != nil {
+		return nil, err
+	}
+
+	genre, err = h.genreService.Post(ctx, genre)
+	if err != nil {
+		return nil, err
+	}
+
+	return genre, nil
+}
+
+// Put : handles the request of renaming or moving a genre
+func (h GenreHandler) Put(ctx *gofr.Context) (interface{}, error) {
+	var genre entities.Genre
+
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(body, &genre)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	genre, err = h.genreService.Put(ctx, genre, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return genre, nil
+}
+
+// Delete : handles the request of deleting a genre
+func (h GenreHandler) Delete(ctx *gofr.Context) (interface{}, error) {
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.genreService.Delete(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return "successfully deleted", nil
+}
@@ -0,0 +1,96 @@
+package genrehttp
+
+import (
+	"bytes"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+)
+
+// TestPost : to test Post handler
+func TestPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockGenreService(ctrl)
+	mock := New(mockService)
+
+	testcases := []struct {
+		desc string
+		body []byte
+
+		expected    interface{}
+		expectedErr error
+	}{
+		{desc: "valid genre", body: []byte(`{"name":"Epic","parentID":2}`),
+			expected: entities.Genre{GenreID: 3, Name: "Epic", ParentID: 2}},
+		{desc: "unmarshalling error", body: []byte("hello"), expected: nil},
+	}
+
+	k := gofr.New()
+	for _, tc := range testcases {
+		r := httptest.NewRequest("POST", "localhost:8000/genre", bytes.NewReader(tc.body))
+		w := httptest.NewRecorder()
+
+		req := request.NewHTTPRequest(r)
+		res := responder.NewContextualResponder(w, r)
+		ctx := gofr.NewContext(res, req, k)
+
+		var genre entities.Genre
+		if json.Unmarshal(tc.body, &genre) == nil {
+			mockService.EXPECT().Post(ctx, genre).Return(tc.expected, tc.expectedErr)
+		}
+
+		result, _ := mock.Post(ctx)
+
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
+
+// TestDelete : to test the delete handler
+func TestDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockGenreService(ctrl)
+	mock := New(mockService)
+
+	testcases := []struct {
+		desc   string
+		target string
+
+		serviceErr  error
+		expectedErr bool
+	}{
+		{desc: "leaf genre", target: "3"},
+		{desc: "genre with sub-genres", target: "1", serviceErr: errors.New("genre has sub-genres"), expectedErr: true},
+		{desc: "invalid id", target: "abc", expectedErr: true},
+	}
+
+	k := gofr.New()
+	for _, tc := range testcases {
+		r := httptest.NewRequest("DELETE", "localhost:8000/genre/"+tc.target, nil)
+		r = mux.SetURLVars(r, map[string]string{"id": tc.target})
+		w := httptest.NewRecorder()
+
+		req := request.NewHTTPRequest(r)
+		res := responder.NewContextualResponder(w, r)
+		ctx := gofr.NewContext(res, req, k)
+
+		mockService.EXPECT().Delete(ctx, gomock.Any()).Return(tc.serviceErr).AnyTimes()
+
+		_, err := mock.Delete(ctx)
+		if (err != nil) != tc.expectedErr {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
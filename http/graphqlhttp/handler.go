@@ -0,0 +1,63 @@
+package graphqlhttp
+
+import (
+	_ "embed"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+	"projects/GoLang-Interns-2022/authorbook/middleware/authz"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// maxDepth : the deepest nesting of fields a query may select
+const maxDepth = 8
+
+// Handler : serves GraphQL requests posted as a JSON body of query, operationName and variables; the answer carries
+// its errors next to the data so it is a plain http handler
+type Handler struct {
+	schema  *graphql.Schema
+	authors service.AuthorService
+}
+
+// request : the body of a GraphQL request
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// New : factory function, the policy is checked for every operation and nested field as every query shares one route
+func New(authors service.AuthorService, books service.BookService, policy authz.Policy) Handler {
+	r := &resolver{authors: authors, books: books, policy: policy}
+
+	return Handler{graphql.MustParseSchema(schema, r, graphql.MaxDepth(maxDepth)), authors}
+}
+
+// ServeHTTP : runs the query with the loaders of the request
+func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Query == "" {
+		auth.WriteError(w, http.StatusBadRequest, http.StatusText(http.StatusBadRequest),
+			"body has to be a JSON object with a query")
+
+		return
+	}
+
+	ctx := withLoaders(r.Context(), newLoaders(h.authors))
+
+	res := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		log.Print(err)
+	}
+}
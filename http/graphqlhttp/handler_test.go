@@ -0,0 +1,129 @@
+package graphqlhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+	"projects/GoLang-Interns-2022/authorbook/middleware/authz"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+// serve : posts the body as the caller with the roles
+func serve(h Handler, body string, roles ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	r = r.WithContext(auth.NewContext(r.Context(), auth.Identity{Subject: "asha", Roles: roles}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	return w
+}
+
+// TestBooks : test that the authors of a page of books are read in one query
+func TestBooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	authors := service.NewMockAuthorService(ctrl)
+	books := service.NewMockBookService(ctrl)
+	h := New(authors, books, authz.DefaultPolicy())
+
+	books.EXPECT().GetAllBook(gomock.Any(), entities.BookFilter{Tags: []string{"kids"}, After: 1, Limit: 3},
+		entities.Projection{}).Return([]entities.Book{{BookID: 2, AuthorID: 8, Title: "vagrants"},
+		{BookID: 3, AuthorID: 7, Title: "blue umbrella"}, {BookID: 4, AuthorID: 7}}, nil)
+	authors.EXPECT().GetByIDs(gomock.Any(), []int{8, 7}).Return(map[int]entities.Author{
+		7: {AuthorID: 7, PenName: "rb"}, 8: {AuthorID: 8, PenName: "pc"}}, nil)
+
+	w := serve(h, `{"query":"query($after: String) { books(first: 2, after: $after, tag: \"kids\") { edges { node `+
+		`{ title author { penName } } } pageInfo { hasNextPage endCursor } } }","variables":{"after":"Ym9vazox"}}`,
+		"reader")
+
+	expected := `{"data":{"books":{"edges":[{"node":{"title":"vagrants","author":{"penName":"pc"}}},` +
+		`{"node":{"title":"blue umbrella","author":{"penName":"rb"}}}],` +
+		`"pageInfo":{"hasNextPage":true,"endCursor":"Ym9vazoz"}}}}` + "\n"
+
+	if w.Code != http.StatusOK || w.Body.String() != expected {
+		t.Errorf("unexpected response %v %v", w.Code, w.Body.String())
+	}
+}
+
+// TestAuthorsBooks : test that the books of a page of authors and the authors of those books take one query each
+func TestAuthorsBooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	authors := service.NewMockAuthorService(ctrl)
+	books := service.NewMockBookService(ctrl)
+	h := New(authors, books, authz.DefaultPolicy())
+
+	authors.EXPECT().GetAll(gomock.Any(), entities.AuthorFilter{Limit: defaultFirst + 1}, entities.Projection{}).Return(
+		[]entities.Author{{AuthorID: 1, FirstName: "ruskin"}, {AuthorID: 2, FirstName: "paulo"}}, nil)
+	authors.EXPECT().GetBooksByAuthors(gomock.Any(), []int{1, 2}).Return(map[int][]entities.Book{
+		1: {{BookID: 5, AuthorID: 1, Title: "rusty"}}, 2: {{BookID: 6, AuthorID: 2, Title: "alchemist"}}}, nil)
+	authors.EXPECT().GetByIDs(gomock.Any(), gomock.Any()).Return(map[int]entities.Author{
+		1: {AuthorID: 1, FirstName: "ruskin"}, 2: {AuthorID: 2, FirstName: "paulo"}}, nil)
+
+	w := serve(h, `{"query":"{ authors { edges { node { firstName books { edges { node { title author `+
+		`{ firstName } } } } } } } }"}`, "reader")
+
+	expected := `{"data":{"authors":{"edges":[` +
+		`{"node":{"firstName":"ruskin","books":{"edges":[{"node":{"title":"rusty","author":{"firstName":"ruskin"}}}]}}},` +
+		`{"node":{"firstName":"paulo","books":{"edges":[{"node":{"title":"alchemist","author":{"firstName":"paulo"}}}]}}}` +
+		`]}}}` + "\n"
+
+	if w.Code != http.StatusOK || w.Body.String() != expected {
+		t.Errorf("unexpected response %v %v", w.Code, w.Body.String())
+	}
+}
+
+// TestMutations : test that mutations need the permission of the matching REST route
+func TestMutations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	authors := service.NewMockAuthorService(ctrl)
+	books := service.NewMockBookService(ctrl)
+	h := New(authors, books, authz.DefaultPolicy())
+
+	query := `{"query":"mutation { createAuthor(input: {firstName: \"ruskin\", lastName: \"bond\", ` +
+		`dob: \"19/05/1934\", penName: \"rb\"}) { id penName } }"}`
+
+	w := serve(h, query, "reader")
+
+	if !strings.Contains(w.Body.String(), "missing permission author:create") {
+		t.Errorf("expected the reader to be denied, got %v", w.Body.String())
+	}
+
+	authors.EXPECT().Post(gomock.Any(), entities.Author{FirstName: "ruskin", LastName: "bond", DOB: "19/05/1934",
+		PenName: "rb"}).Return(entities.Author{AuthorID: 3, PenName: "rb"}, nil)
+
+	w = serve(h, query, "editor")
+
+	if expected := `{"data":{"createAuthor":{"id":3,"penName":"rb"}}}` + "\n"; w.Body.String() != expected {
+		t.Errorf("unexpected response %v", w.Body.String())
+	}
+
+	books.EXPECT().Delete(gomock.Any(), 4).Return(nil)
+
+	if w = serve(h, `{"query":"mutation { deleteBook(id: 4) }"}`, "admin"); !strings.Contains(w.Body.String(),
+		`"deleteBook":true`) {
+		t.Errorf("unexpected response %v", w.Body.String())
+	}
+}
+
+// TestBadRequest : test that a body without a query is rejected
+func TestBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	h := New(service.NewMockAuthorService(ctrl), service.NewMockBookService(ctrl), authz.DefaultPolicy())
+
+	for _, body := range []string{`{"variables":{}}`, `query`} {
+		if w := serve(h, body, "reader"); w.Code != http.StatusBadRequest {
+			t.Errorf("expected %v for %v, got %v", http.StatusBadRequest, body, w.Code)
+		}
+	}
+
+	if w := serve(h, `{"query":"{ authors(after: \"bm9wZQ==\") { edges { cursor } } }"}`, "reader"); !strings.Contains(
+		w.Body.String(), "invalid cursor") {
+		t.Errorf("expected an invalid cursor, got %v", w.Body.String())
+	}
+}
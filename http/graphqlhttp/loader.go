@@ -0,0 +1,158 @@
+package graphqlhttp
+
+import (
+	"context"
+	"sync"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+// loader : batches the lookups by id of one request, a list primes the ids of its items before they are resolved
+// so the first lookup reads them all in one query and the others are served from the cache. Lookups made at the
+// same time wait for the one reading, which takes every id asked for until then along
+type loader struct {
+	fetch func(ctx context.Context, ids []int) (map[int]interface{}, error)
+
+	mu      sync.Mutex
+	pending []int
+	done    map[int]bool
+	values  map[int]interface{}
+}
+
+func newLoader(fetch func(ctx context.Context, ids []int) (map[int]interface{}, error)) *loader {
+	return &loader{fetch: fetch, done: map[int]bool{}, values: map[int]interface{}{}}
+}
+
+// prime : announces ids which are about to be looked up
+func (l *loader) prime(ids ...int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, id := range ids {
+		l.queue(id)
+	}
+}
+
+// load : gives the value of the id, false when there is none
+func (l *loader) load(ctx context.Context, id int) (interface{}, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.done[id] {
+		l.queue(id)
+
+		values, err := l.fetch(ctx, l.pending)
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, pending := range l.pending {
+			l.done[pending] = true
+		}
+
+		for key, value := range values {
+			l.values[key] = value
+		}
+
+		l.pending = nil
+	}
+
+	value, ok := l.values[id]
+
+	return value, ok, nil
+}
+
+func (l *loader) queue(id int) {
+	if l.done[id] {
+		return
+	}
+
+	for _, pending := range l.pending {
+		if pending == id {
+			return
+		}
+	}
+
+	l.pending = append(l.pending, id)
+}
+
+// loaders : the loaders of one request
+type loaders struct {
+	authors       *loader
+	booksByAuthor *loader
+}
+
+func newLoaders(authors service.AuthorService) *loaders {
+	l := &loaders{
+		authors: newLoader(func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			found, err := authors.GetByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			values := make(map[int]interface{}, len(found))
+			for id, author := range found {
+				values[id] = author
+			}
+
+			return values, nil
+		}),
+	}
+
+	// the books of a batch of authors are listed under every one of them, so their authors are primed together
+	// instead of by each list
+	l.booksByAuthor = newLoader(func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+		found, err := authors.GetBooksByAuthors(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		values := make(map[int]interface{}, len(found))
+		for id, books := range found {
+			values[id] = books
+
+			for i := range books {
+				l.authors.prime(books[i].AuthorID)
+			}
+		}
+
+		return values, nil
+	})
+
+	return l
+}
+
+// author : gives the author with the id, nil when there is none
+func (l *loaders) author(ctx context.Context, id int) (*entities.Author, error) {
+	value, ok, err := l.authors.load(ctx, id)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	author := value.(entities.Author)
+
+	return &author, nil
+}
+
+// books : gives the books of the author with the id
+func (l *loaders) books(ctx context.Context, authorID int) ([]entities.Book, error) {
+	value, ok, err := l.booksByAuthor.load(ctx, authorID)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return value.([]entities.Book), nil
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	l, _ := ctx.Value(loadersKey{}).(*loaders)
+
+	return l
+}
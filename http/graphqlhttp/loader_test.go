@@ -0,0 +1,66 @@
+package graphqlhttp
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// TestLoader : test that primed and concurrent lookups are read in one batch and failures are not cached
+func TestLoader(t *testing.T) {
+	var (
+		batches [][]int
+		fail    = true
+	)
+
+	l := newLoader(func(_ context.Context, ids []int) (map[int]interface{}, error) {
+		if fail {
+			fail = false
+			return nil, errors.New("connection lost")
+		}
+
+		batches = append(batches, append([]int(nil), ids...))
+
+		values := map[int]interface{}{}
+		for _, id := range ids {
+			if id%2 == 0 {
+				values[id] = id * 10
+			}
+		}
+
+		return values, nil
+	})
+
+	if _, _, err := l.load(context.TODO(), 2); err == nil {
+		t.Fatalf("expected the failure of the first batch")
+	}
+
+	l.prime(4, 5, 2)
+
+	var wg sync.WaitGroup
+
+	for _, id := range []int{2, 4, 5} {
+		wg.Add(1)
+
+		go func(id int) {
+			defer wg.Done()
+
+			value, ok, err := l.load(context.TODO(), id)
+			if err != nil || ok != (id%2 == 0) || (ok && value != id*10) {
+				t.Errorf("unexpected value for %v: %v %v %v", id, value, ok, err)
+			}
+		}(id)
+	}
+
+	wg.Wait()
+
+	if _, _, err := l.load(context.TODO(), 6); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := [][]int{{2, 4, 5}, {6}}; !reflect.DeepEqual(batches, expected) {
+		t.Errorf("expected batches %v, got %v", expected, batches)
+	}
+}
@@ -0,0 +1,237 @@
+package graphqlhttp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/middleware/authz"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+// resolver : resolves the queries and mutations of the schema with the author and book services, the permissions
+// the REST routes require are checked for every operation and nested field
+type resolver struct {
+	authors service.AuthorService
+	books   service.BookService
+	policy  authz.Policy
+}
+
+type authorInput struct {
+	FirstName string
+	LastName  string
+	DOB       string
+	PenName   string
+}
+
+func (i authorInput) author() entities.Author {
+	return entities.Author{FirstName: i.FirstName, LastName: i.LastName, DOB: i.DOB, PenName: i.PenName}
+}
+
+type bookInput struct {
+	AuthorID      int32
+	Title         string
+	Publication   string
+	PublishedDate string
+}
+
+func (i bookInput) book() entities.Book {
+	return entities.Book{AuthorID: int(i.AuthorID), Title: i.Title, Publication: i.Publication,
+		PublishedDate: i.PublishedDate}
+}
+
+// Book : the book with the id, null when there is none
+func (r *resolver) Book(ctx context.Context, args struct{ ID int32 }) (*bookResolver, error) {
+	if err := r.policy.Check(ctx, "book:read"); err != nil {
+		return nil, err
+	}
+
+	book, err := r.books.GetBookByID(ctx, int(args.ID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &bookResolver{book, r}, nil
+}
+
+// Books : a page of the books in the order of their id, the page and the book telling whether there is a next one
+// are read by the query
+func (r *resolver) Books(ctx context.Context, args struct {
+	pageArgs
+	Title *string
+	Genre *string
+	Tag   *string
+}) (*bookConnection, error) {
+	if err := r.policy.Check(ctx, "book:read"); err != nil {
+		return nil, err
+	}
+
+	first, after, err := args.page("book")
+	if err != nil {
+		return nil, err
+	}
+
+	var filter entities.BookFilter
+
+	if args.Title != nil {
+		filter.Title = *args.Title
+	}
+
+	if args.Genre != nil {
+		filter.Genre = *args.Genre
+	}
+
+	if args.Tag != nil && *args.Tag != "" {
+		filter.Tags = strings.Split(*args.Tag, ",")
+	}
+
+	filter.After, filter.Limit = after, first+1
+
+	books, err := r.books.GetAllBook(ctx, filter, entities.Projection{})
+	if err != nil {
+		return nil, err
+	}
+
+	hasNext := len(books) > first
+	if hasNext {
+		books = books[:first]
+	}
+
+	return r.bookConnection(ctx, books, hasNext), nil
+}
+
+// Author : the author with the id, null when there is none
+func (r *resolver) Author(ctx context.Context, args struct{ ID int32 }) (*authorResolver, error) {
+	if err := r.policy.Check(ctx, "author:read"); err != nil {
+		return nil, err
+	}
+
+	author, err := loadersFrom(ctx).author(ctx, int(args.ID))
+	if err != nil || author == nil {
+		return nil, err
+	}
+
+	return &authorResolver{*author, r}, nil
+}
+
+// Authors : a page of the authors in the order of their id
+func (r *resolver) Authors(ctx context.Context, args pageArgs) (*authorConnection, error) {
+	if err := r.policy.Check(ctx, "author:read"); err != nil {
+		return nil, err
+	}
+
+	first, after, err := args.page("author")
+	if err != nil {
+		return nil, err
+	}
+
+	authors, err := r.authors.GetAll(ctx, entities.AuthorFilter{After: after, Limit: first + 1}, entities.Projection{})
+	if err != nil {
+		return nil, err
+	}
+
+	hasNext := len(authors) > first
+	if hasNext {
+		authors = authors[:first]
+	}
+
+	return r.authorConnection(ctx, authors, hasNext), nil
+}
+
+// CreateAuthor : adds an author
+func (r *resolver) CreateAuthor(ctx context.Context, args struct{ Input authorInput }) (*authorResolver, error) {
+	if err := r.policy.Check(ctx, "author:create"); err != nil {
+		return nil, err
+	}
+
+	author, err := r.authors.Post(ctx, args.Input.author())
+	if err != nil {
+		return nil, err
+	}
+
+	return &authorResolver{author, r}, nil
+}
+
+// UpdateAuthor : replaces the details of an author
+func (r *resolver) UpdateAuthor(ctx context.Context, args struct {
+	ID    int32
+	Input authorInput
+}) (*authorResolver, error) {
+	if err := r.policy.Check(ctx, "author:update"); err != nil {
+		return nil, err
+	}
+
+	author, err := r.authors.Put(ctx, args.Input.author(), int(args.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &authorResolver{author, r}, nil
+}
+
+// DeleteAuthor : removes an author
+func (r *resolver) DeleteAuthor(ctx context.Context, args struct{ ID int32 }) (bool, error) {
+	if err := r.policy.Check(ctx, "author:delete"); err != nil {
+		return false, err
+	}
+
+	if err := r.authors.Delete(ctx, int(args.ID)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CreateBook : adds a book
+func (r *resolver) CreateBook(ctx context.Context, args struct{ Input bookInput }) (*bookResolver, error) {
+	if err := r.policy.Check(ctx, "book:create"); err != nil {
+		return nil, err
+	}
+
+	book := args.Input.book()
+
+	created, err := r.books.Post(ctx, &book)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bookResolver{created, r}, nil
+}
+
+// UpdateBook : replaces the details of a book
+func (r *resolver) UpdateBook(ctx context.Context, args struct {
+	ID    int32
+	Input bookInput
+}) (*bookResolver, error) {
+	if err := r.policy.Check(ctx, "book:update"); err != nil {
+		return nil, err
+	}
+
+	book := args.Input.book()
+
+	updated, err := r.books.Put(ctx, &book, int(args.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &bookResolver{updated, r}, nil
+}
+
+// DeleteBook : removes a book
+func (r *resolver) DeleteBook(ctx context.Context, args struct{ ID int32 }) (bool, error) {
+	if err := r.policy.Check(ctx, "book:delete"); err != nil {
+		return false, err
+	}
+
+	if err := r.books.Delete(ctx, int(args.ID)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
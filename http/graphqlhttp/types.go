@@ -0,0 +1,235 @@
+package graphqlhttp
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+// page sizes of a connection when first is not given and at most
+const (
+	defaultFirst = 20
+	maxFirst     = 100
+)
+
+// pageArgs : the arguments selecting a page of a connection
+type pageArgs struct {
+	First *int32
+	After *string
+}
+
+// page : reads the page arguments as a number of items and the id the page starts after
+func (a pageArgs) page(kind string) (first, after int, err error) {
+	first = defaultFirst
+
+	if a.First != nil {
+		if first = int(*a.First); first < 0 || first > maxFirst {
+			return 0, 0, errors.New("first has to be between 0 and " + strconv.Itoa(maxFirst))
+		}
+	}
+
+	if a.After != nil {
+		if after, err = decodeCursor(kind, *a.After); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return first, after, nil
+}
+
+// encodeCursor : gives the opaque cursor of an item
+func encodeCursor(kind string, id int) string {
+	return base64.StdEncoding.EncodeToString([]byte(kind + ":" + strconv.Itoa(id)))
+}
+
+func decodeCursor(kind, cursor string) (int, error) {
+	raw, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errors.New("invalid cursor")
+	}
+
+	prefix, id, ok := strings.Cut(string(raw), ":")
+	if !ok || prefix != kind {
+		return 0, errors.New("invalid cursor")
+	}
+
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, errors.New("invalid cursor")
+	}
+
+	return n, nil
+}
+
+type authorResolver struct {
+	author entities.Author
+	r      *resolver
+}
+
+func (a *authorResolver) ID() int32         { return int32(a.author.AuthorID) }
+func (a *authorResolver) FirstName() string { return a.author.FirstName }
+func (a *authorResolver) LastName() string  { return a.author.LastName }
+func (a *authorResolver) DOB() string       { return a.author.DOB }
+func (a *authorResolver) PenName() string   { return a.author.PenName }
+
+// Books : the books of the author, the books of every author of a listing are read together
+func (a *authorResolver) Books(ctx context.Context, args pageArgs) (*bookConnection, error) {
+	if err := a.r.policy.Check(ctx, "book:read"); err != nil {
+		return nil, err
+	}
+
+	first, after, err := args.page("book")
+	if err != nil {
+		return nil, err
+	}
+
+	books, err := loadersFrom(ctx).books(ctx, a.author.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	var page []entities.Book
+
+	for i := range books {
+		if books[i].BookID > after {
+			page = append(page, books[i])
+		}
+	}
+
+	hasNext := len(page) > first
+	if hasNext {
+		page = page[:first]
+	}
+
+	return a.r.bookConnection(ctx, page, hasNext), nil
+}
+
+type bookResolver struct {
+	book entities.Book
+	r    *resolver
+}
+
+func (b *bookResolver) ID() int32             { return int32(b.book.BookID) }
+func (b *bookResolver) Title() string         { return b.book.Title }
+func (b *bookResolver) Publication() string   { return b.book.Publication }
+func (b *bookResolver) PublishedDate() string { return b.book.PublishedDate }
+
+// Author : the author of the book, the authors of every book of a listing are read together
+func (b *bookResolver) Author(ctx context.Context) (*authorResolver, error) {
+	if err := b.r.policy.Check(ctx, "author:read"); err != nil {
+		return nil, err
+	}
+
+	author := b.book.Author
+	if author == nil {
+		var err error
+
+		if author, err = loadersFrom(ctx).author(ctx, b.book.AuthorID); err != nil || author == nil {
+			return nil, err
+		}
+	}
+
+	return &authorResolver{*author, b.r}, nil
+}
+
+func (b *bookResolver) Rating() *ratingResolver {
+	if b.book.Rating == nil {
+		return nil
+	}
+
+	return &ratingResolver{*b.book.Rating}
+}
+
+type ratingResolver struct {
+	rating entities.Rating
+}
+
+func (r *ratingResolver) Average() float64 { return r.rating.Average }
+func (r *ratingResolver) Count() int32     { return int32(r.rating.Count) }
+
+type pageInfo struct {
+	hasNext bool
+	end     *string
+}
+
+func (p *pageInfo) HasNextPage() bool  { return p.hasNext }
+func (p *pageInfo) EndCursor() *string { return p.end }
+
+type bookConnection struct {
+	edges []*bookEdge
+	info  *pageInfo
+}
+
+func (c *bookConnection) Edges() []*bookEdge  { return c.edges }
+func (c *bookConnection) PageInfo() *pageInfo { return c.info }
+
+type bookEdge struct {
+	cursor string
+	node   *bookResolver
+}
+
+func (e *bookEdge) Cursor() string      { return e.cursor }
+func (e *bookEdge) Node() *bookResolver { return e.node }
+
+type authorConnection struct {
+	edges []*authorEdge
+	info  *pageInfo
+}
+
+func (c *authorConnection) Edges() []*authorEdge { return c.edges }
+func (c *authorConnection) PageInfo() *pageInfo  { return c.info }
+
+type authorEdge struct {
+	cursor string
+	node   *authorResolver
+}
+
+func (e *authorEdge) Cursor() string        { return e.cursor }
+func (e *authorEdge) Node() *authorResolver { return e.node }
+
+// bookConnection : gives the connection of a page of books and primes the lookup of their authors
+func (r *resolver) bookConnection(ctx context.Context, books []entities.Book, hasNext bool) *bookConnection {
+	c := &bookConnection{edges: make([]*bookEdge, len(books)), info: &pageInfo{hasNext: hasNext}}
+
+	ids := make([]int, 0, len(books))
+
+	for i := range books {
+		c.edges[i] = &bookEdge{encodeCursor("book", books[i].BookID), &bookResolver{books[i], r}}
+
+		if books[i].Author == nil {
+			ids = append(ids, books[i].AuthorID)
+		}
+	}
+
+	if len(c.edges) > 0 {
+		c.info.end = &c.edges[len(c.edges)-1].cursor
+	}
+
+	loadersFrom(ctx).authors.prime(ids...)
+
+	return c
+}
+
+// authorConnection : gives the connection of a page of authors and primes the lookup of their books
+func (r *resolver) authorConnection(ctx context.Context, authors []entities.Author, hasNext bool) *authorConnection {
+	c := &authorConnection{edges: make([]*authorEdge, len(authors)), info: &pageInfo{hasNext: hasNext}}
+
+	ids := make([]int, len(authors))
+
+	for i := range authors {
+		c.edges[i] = &authorEdge{encodeCursor("author", authors[i].AuthorID), &authorResolver{authors[i], r}}
+		ids[i] = authors[i].AuthorID
+	}
+
+	if len(c.edges) > 0 {
+		c.info.end = &c.edges[len(c.edges)-1].cursor
+	}
+
+	loadersFrom(ctx).booksByAuthor.prime(ids...)
+
+	return c
+}
@@ -0,0 +1,111 @@
+package importhttp
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+// maxImportSize bounds the body of an import, larger catalogues have to be split
+const maxImportSize = 64 << 20
+
+type ImportHandler struct {
+	importService service.ImportService
+}
+
+// New : factory function
+func New(s service.ImportService) ImportHandler {
+	return ImportHandler{s}
+}
+
+// Post : handles the request of importing a CSV or NDJSON body, the import runs in the background
+func (h ImportHandler) Post(ctx *gofr.Context) (interface{}, error) {
+	opts := entities.ImportOptions{Entity: ctx.Param("entity"), Format: ctx.Param("format"), Mode: ctx.Param("mode")}
+
+	if opts.Format == "" {
+		opts.Format = formatOf(ctx.Request().Header.Get("Content-Type"))
+	}
+
+	if dryRun := ctx.Param("dryRun"); dryRun != "" {
+		var err error
+
+		if opts.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			return nil, err
+		}
+	}
+
+	mapping, err := parseMapping(ctx.Param("mapping"))
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Mapping = mapping
+
+	body, err := io.ReadAll(io.LimitReader(ctx.Request().Body, maxImportSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) > maxImportSize {
+		return nil, errors.New("import too large")
+	}
+
+	job, err := h.importService.Post(ctx, opts, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// GetByID : handles the request of reading the progress of an import
+func (h ImportHandler) GetByID(ctx *gofr.Context) (interface{}, error) {
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := h.importService.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// formatOf : gives the import format of a content type
+func formatOf(contentType string) string {
+	switch strings.TrimSpace(strings.Split(contentType, ";")[0]) {
+	case "text/csv":
+		return "csv"
+	case "application/x-ndjson", "application/ndjson":
+		return "ndjson"
+	}
+
+	return ""
+}
+
+// parseMapping : reads a column mapping written as "Source Column:field,Other Column:field"
+func parseMapping(value string) (map[string]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	mapping := make(map[string]string)
+
+	for _, pair := range strings.Split(value, ",") {
+		column, field, ok := strings.Cut(pair, ":")
+		if !ok || strings.TrimSpace(column) == "" || strings.TrimSpace(field) == "" {
+			return nil, errors.New("invalid mapping " + pair)
+		}
+
+		mapping[strings.TrimSpace(column)] = strings.TrimSpace(field)
+	}
+
+	return mapping, nil
+}
@@ -0,0 +1,74 @@
+package importhttp
+
+import (
+	"bytes"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+
+	"github.com/golang/mock/gomock"
+)
+
+// TestPost : to test the options of an import reach the service
+func TestPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockImportService(ctrl)
+	mock := New(mockService)
+
+	body := "Book Title,author\nthe road,Cormac McCarthy\n"
+	job := entities.ImportJob{JobID: 1, Status: entities.ImportPending}
+
+	testcases := []struct {
+		desc        string
+		query       string
+		contentType string
+
+		opts     entities.ImportOptions
+		expected interface{}
+	}{
+		{desc: "csv dry run with mapping", query: "?mode=atomic&dryRun=true&mapping=Book%20Title:title",
+			contentType: "text/csv; charset=utf-8", opts: entities.ImportOptions{Format: "csv", Mode: "atomic",
+				DryRun: true, Mapping: map[string]string{"Book Title": "title"}}, expected: job},
+		{desc: "format parameter", query: "?format=ndjson&entity=author",
+			opts: entities.ImportOptions{Entity: "author", Format: "ndjson"}, expected: job},
+		{desc: "invalid mapping", query: "?format=csv&mapping=title", expected: nil},
+		{desc: "invalid dry run", query: "?format=csv&dryRun=maybe", expected: nil},
+	}
+
+	k := gofr.New()
+	for _, tc := range testcases {
+		r := httptest.NewRequest("POST", "localhost:8000/import"+tc.query, bytes.NewBufferString(body))
+		r.Header.Set("Content-Type", tc.contentType)
+		w := httptest.NewRecorder()
+
+		req := request.NewHTTPRequest(r)
+		res := responder.NewContextualResponder(w, r)
+		ctx := gofr.NewContext(res, req, k)
+
+		mockService.EXPECT().Post(ctx, tc.opts, []byte(body)).Return(job, nil).AnyTimes()
+
+		result, _ := mock.Post(ctx)
+
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
+
+// TestParseMapping : to test reading the column mapping
+func TestParseMapping(t *testing.T) {
+	mapping, err := parseMapping(" Book Title : title,Writer:author")
+	if err != nil || !reflect.DeepEqual(mapping, map[string]string{"Book Title": "title", "Writer": "author"}) {
+		t.Errorf("unexpected mapping %v %v", mapping, err)
+	}
+
+	if _, err = parseMapping("title,"); err == nil {
+		t.Errorf("expected error for a pair without field")
+	}
+}
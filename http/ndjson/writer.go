@@ -0,0 +1,62 @@
+package ndjson
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+)
+
+// MediaType : the media type of newline delimited JSON
+const MediaType = "application/x-ndjson"
+
+// Writer : writes a response as one JSON value per line, the headers are sent with the first line
+// and every line is flushed so the client sees the rows as they are read
+type Writer struct {
+	w       http.ResponseWriter
+	enc     *json.Encoder
+	started bool
+}
+
+// NewWriter : factory function
+func NewWriter(w http.ResponseWriter) *Writer {
+	return &Writer{w: w, enc: json.NewEncoder(w)}
+}
+
+// Write : writes a value as a line, an error means the client is gone and the stream has to stop
+func (nw *Writer) Write(value interface{}) error {
+	if !nw.started {
+		nw.start()
+	}
+
+	if err := nw.enc.Encode(value); err != nil {
+		return err
+	}
+
+	if f, ok := nw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+
+	return nil
+}
+
+// Finish : ends the stream, an error before the first line is sent as an error response while an error after it
+// can only end the stream early
+func (nw *Writer) Finish(err error) {
+	switch {
+	case err != nil && !nw.started:
+		auth.WriteError(nw.w, http.StatusInternalServerError, "Internal Server Error", err.Error())
+	case err != nil:
+		log.Printf("stream stopped: %v", err)
+	case !nw.started:
+		nw.start()
+	}
+}
+
+func (nw *Writer) start() {
+	nw.started = true
+
+	nw.w.Header().Set("Content-Type", MediaType)
+	nw.w.WriteHeader(http.StatusOK)
+}
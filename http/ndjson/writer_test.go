@@ -0,0 +1,47 @@
+package ndjson
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestWriter : test the lines, the headers and the errors before and after the first line
+func TestWriter(t *testing.T) {
+	testcases := []struct {
+		desc   string
+		values []interface{}
+		err    error
+
+		expectedStatus int
+		expectedType   string
+		expectedBody   string
+	}{
+		{"lines", []interface{}{map[string]int{"id": 1}, map[string]int{"id": 2}}, nil, http.StatusOK, MediaType,
+			"{\"id\":1}\n{\"id\":2}\n"},
+		{"empty", nil, nil, http.StatusOK, MediaType, ""},
+		{"failure before the first line", nil, errors.New("connection lost"), http.StatusInternalServerError,
+			"application/json", `{"errors":[{"code":"Internal Server Error","reason":"connection lost"}]}` + "\n"},
+		{"failure after the first line", []interface{}{1}, errors.New("connection lost"), http.StatusOK, MediaType,
+			"1\n"},
+	}
+
+	for _, tc := range testcases {
+		w := httptest.NewRecorder()
+		nw := NewWriter(w)
+
+		for _, value := range tc.values {
+			if err := nw.Write(value); err != nil {
+				t.Fatalf("%v: %v", tc.desc, err)
+			}
+		}
+
+		nw.Finish(tc.err)
+
+		if w.Code != tc.expectedStatus || w.Header().Get("Content-Type") != tc.expectedType ||
+			w.Body.String() != tc.expectedBody || (len(tc.values) > 0 && !w.Flushed) {
+			t.Errorf("failed for %v, got %v %v %q", tc.desc, w.Code, w.Header(), w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package reviewhttp
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+type ReviewHandler struct {
+	reviewService service.ReviewService
+}
+
+// New : factory function
+func New(r service.ReviewService) ReviewHandler {
+	return ReviewHandler{r}
+}
+
+// Post : handles the request of reviewing a book
+func (h ReviewHandler) Post(ctx *gofr.Context) (interface{}, error) {
+	var review entities.Review
+
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(body, &review)
+	if err != nil {
+		return nil, err
+	}
+
+	bookID, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	review, err = h.reviewService.Post(ctx, bookID, review)
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// GetByBook : handles the request of listing the reviews of a book
+func (h ReviewHandler) GetByBook(ctx *gofr.Context) (interface{}, error) {
+	filter := entities.ReviewFilter{Status: ctx.Param("status"), Sort: ctx.Param("sort")}
+
+	bookID, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	if page := ctx.Param("page"); page != "" {
+		if filter.Page, err = strconv.Atoi(page); err != nil {
+			return nil, err
+		}
+	}
+
+	if limit := ctx.Param("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			return nil, err
+		}
+	}
+
+	reviews, err := h.reviewService.GetByBook(ctx, bookID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// UpdateStatus : handles the request of moderating a review
+func (h ReviewHandler) UpdateStatus(ctx *gofr.Context) (interface{}, error) {
+	var review entities.Review
+
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(body, &review)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	review, err = h.reviewService.UpdateStatus(ctx, id, review.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// MarkHelpful : handles the request of voting a review helpful
+func (h ReviewHandler) MarkHelpful(ctx *gofr.Context) (interface{}, error) {
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := h.reviewService.MarkHelpful(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// Delete : handles the request of deleting a review
+func (h ReviewHandler) Delete(ctx *gofr.Context) (interface{}, error) {
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.reviewService.Delete(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return "successfully deleted", nil
+}
@@ -0,0 +1,58 @@
+package reviewhttp
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+)
+
+// TestGetByBook : to test the query parameters reach the service
+func TestGetByBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockReviewService(ctrl)
+	mock := New(mockService)
+
+	page := entities.ReviewPage{Reviews: []entities.Review{{ReviewID: 1}}, Page: 2, Limit: 5, Total: 6}
+
+	testcases := []struct {
+		desc  string
+		query string
+
+		filter   entities.ReviewFilter
+		expected interface{}
+	}{
+		{desc: "paged by helpfulness", query: "?sort=helpful&page=2&limit=5",
+			filter: entities.ReviewFilter{Sort: "helpful", Page: 2, Limit: 5}, expected: page},
+		{desc: "pending reviews", query: "?status=pending", filter: entities.ReviewFilter{Status: "pending"},
+			expected: page},
+		{desc: "invalid page", query: "?page=two", expected: nil},
+	}
+
+	k := gofr.New()
+	for _, tc := range testcases {
+		r := httptest.NewRequest("GET", "localhost:8000/book/1/review"+tc.query, nil)
+		r = mux.SetURLVars(r, map[string]string{"id": "1"})
+		w := httptest.NewRecorder()
+
+		req := request.NewHTTPRequest(r)
+		res := responder.NewContextualResponder(w, r)
+		ctx := gofr.NewContext(res, req, k)
+
+		mockService.EXPECT().GetByBook(ctx, 1, tc.filter).Return(page, nil).AnyTimes()
+
+		result, _ := mock.GetByBook(ctx)
+
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package serieshttp
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+type SeriesHandler struct {
+	seriesService service.SeriesService
+}
+
+// New : factory function
+func New(s service.SeriesService) SeriesHandler {
+	return SeriesHandler{s}
+}
+
+// GetByID : handles the request of getting a series with its books in reading order
+func (h SeriesHandler) GetByID(ctx *gofr.Context) (interface{}, error) {
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := h.seriesService.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+// Post : handles the request of posting a series
+func (h SeriesHandler) Post(ctx *gofr.Context) (interface{}, error) {
+	var series entities.Series
+
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(body, &series)
+	if err != nil {
+		return nil, err
+	}
+
+	series, err = h.seriesService.Post(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+// Put : handles the request of updating a series
+func (h SeriesHandler) Put(ctx *gofr.Context) (interface{}, error) {
+	var series entities.Series
+
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(body, &series)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	series, err = h.seriesService.Put(ctx, series, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+// Delete : handles the request of deleting a series
+func (h SeriesHandler) Delete(ctx *gofr.Context) (interface{}, error) {
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.seriesService.Delete(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return "successfully deleted", nil
+}
+
+// AddBook : handles the request of placing a book at a position of the series
+func (h SeriesHandler) AddBook(ctx *gofr.Context) (interface{}, error) {
+	var book entities.SeriesBook
+
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(body, &book)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	book.BookID, err = strconv.Atoi(ctx.PathParam("bookID"))
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := h.seriesService.AddBook(ctx, id, book)
+	if err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+// RemoveBook : handles the request of taking a book out of the series
+func (h SeriesHandler) RemoveBook(ctx *gofr.Context) (interface{}, error) {
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	bookID, err := strconv.Atoi(ctx.PathParam("bookID"))
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.seriesService.RemoveBook(ctx, id, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	return "successfully removed", nil
+}
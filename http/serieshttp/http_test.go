@@ -0,0 +1,83 @@
+package serieshttp
+
+import (
+	"bytes"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+)
+
+// TestGetByID : to test the GetByID handler
+func TestGetByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockSeriesService(ctrl)
+	mock := New(mockService)
+
+	series := entities.Series{SeriesID: 1, Name: "Discworld", Books: []entities.SeriesBook{{BookID: 4, Position: 1},
+		{BookID: 9, Position: 1.5}}}
+
+	testcases := []struct {
+		desc   string
+		target string
+
+		serviceErr error
+		expected   interface{}
+	}{
+		{desc: "existing series", target: "1", expected: series},
+		{desc: "not existing", target: "2", serviceErr: errors.New("series does not exist"), expected: nil},
+		{desc: "invalid id", target: "abc", expected: nil},
+	}
+
+	k := gofr.New()
+	for _, tc := range testcases {
+		r := httptest.NewRequest("GET", "localhost:8000/series/"+tc.target, nil)
+		r = mux.SetURLVars(r, map[string]string{"id": tc.target})
+		w := httptest.NewRecorder()
+
+		req := request.NewHTTPRequest(r)
+		res := responder.NewContextualResponder(w, r)
+		ctx := gofr.NewContext(res, req, k)
+
+		mockService.EXPECT().GetByID(ctx, gomock.Any()).Return(series, tc.serviceErr).AnyTimes()
+
+		result, _ := mock.GetByID(ctx)
+
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
+
+// TestAddBook : to test the AddBook handler
+func TestAddBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockSeriesService(ctrl)
+	mock := New(mockService)
+
+	k := gofr.New()
+	r := httptest.NewRequest("PUT", "localhost:8000/series/1/book/9", bytes.NewReader([]byte(`{"position":2.5}`)))
+	r = mux.SetURLVars(r, map[string]string{"id": "1", "bookID": "9"})
+	w := httptest.NewRecorder()
+
+	req := request.NewHTTPRequest(r)
+	res := responder.NewContextualResponder(w, r)
+	ctx := gofr.NewContext(res, req, k)
+
+	mockService.EXPECT().AddBook(ctx, 1, entities.SeriesBook{BookID: 9, Position: 2.5}).
+		Return(entities.Series{SeriesID: 1}, nil)
+
+	_, err := mock.AddBook(ctx)
+	if err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+}
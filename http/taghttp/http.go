@@ -0,0 +1,67 @@
+package taghttp
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+type TagHandler struct {
+	tagService service.TagService
+}
+
+// New : factory function
+func New(t service.TagService) TagHandler {
+	return TagHandler{t}
+}
+
+// GetAll : handles the request of getting all tags
+func (h TagHandler) GetAll(ctx *gofr.Context) (interface{}, error) {
+	tags, err := h.tagService.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// Post : handles the request of posting a tag
+func (h TagHandler) Post(ctx *gofr.Context) (interface{}, error) {
+	var tag entities.Tag
+
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(body, &tag)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err = h.tagService.Post(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// Delete : handles the request of deleting a tag
+func (h TagHandler) Delete(ctx *gofr.Context) (interface{}, error) {
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.tagService.Delete(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return "successfully deleted", nil
+}
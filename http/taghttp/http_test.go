@@ -0,0 +1,58 @@
+package taghttp
+
+import (
+	"bytes"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+
+	"github.com/golang/mock/gomock"
+)
+
+// TestPost : to test Post handler
+func TestPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockTagService(ctrl)
+	mock := New(mockService)
+
+	testcases := []struct {
+		desc string
+		body []byte
+
+		serviceErr error
+		expected   interface{}
+	}{
+		{desc: "valid tag", body: []byte(`{"name":"dragons"}`), expected: entities.Tag{TagID: 1, Name: "dragons"}},
+		{desc: "existing tag", body: []byte(`{"name":"dragons"}`), serviceErr: errors.New("tag already exists"),
+			expected: nil},
+		{desc: "unmarshalling error", body: []byte("hello"), expected: nil},
+	}
+
+	k := gofr.New()
+	for _, tc := range testcases {
+		r := httptest.NewRequest("POST", "localhost:8000/tag", bytes.NewReader(tc.body))
+		w := httptest.NewRecorder()
+
+		req := request.NewHTTPRequest(r)
+		res := responder.NewContextualResponder(w, r)
+		ctx := gofr.NewContext(res, req, k)
+
+		if tc.desc != "unmarshalling error" {
+			mockService.EXPECT().Post(ctx, entities.Tag{Name: "dragons"}).
+				Return(entities.Tag{TagID: 1, Name: "dragons"}, tc.serviceErr)
+		}
+
+		result, _ := mock.Post(ctx)
+
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package webhookhttp
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+// New : factory function
+func New(s service.WebhookService) WebhookHandler {
+	return WebhookHandler{s}
+}
+
+// GetAll : handles the request of listing the webhooks
+func (h WebhookHandler) GetAll(ctx *gofr.Context) (interface{}, error) {
+	webhooks, err := h.webhookService.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// GetByID : handles the request of getting a webhook
+func (h WebhookHandler) GetByID(ctx *gofr.Context) (interface{}, error) {
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := h.webhookService.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// Post : handles the request of creating a webhook
+func (h WebhookHandler) Post(ctx *gofr.Context) (interface{}, error) {
+	var webhook entities.Webhook
+
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(body, &webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err = h.webhookService.Post(ctx, webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// Put : handles the request of changing a webhook
+func (h WebhookHandler) Put(ctx *gofr.Context) (interface{}, error) {
+	var webhook entities.Webhook
+
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(body, &webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err = h.webhookService.Put(ctx, webhook, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// Delete : handles the request of deleting a webhook
+func (h WebhookHandler) Delete(ctx *gofr.Context) (interface{}, error) {
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.webhookService.Delete(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return "successfully deleted", nil
+}
+
+// GetDeliveries : handles the request of reading the delivery log of a webhook
+func (h WebhookHandler) GetDeliveries(ctx *gofr.Context) (interface{}, error) {
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	filter := entities.DeliveryFilter{Status: ctx.Param("status")}
+
+	if limit := ctx.Param("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			return nil, err
+		}
+	}
+
+	deliveries, err := h.webhookService.GetDeliveries(ctx, id, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// Redeliver : handles the request of sending a delivery again
+func (h WebhookHandler) Redeliver(ctx *gofr.Context) (interface{}, error) {
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	deliveryID, err := strconv.Atoi(ctx.PathParam("deliveryID"))
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := h.webhookService.Redeliver(ctx, id, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
@@ -0,0 +1,97 @@
+package webhookhttp
+
+import (
+	"bytes"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+)
+
+// TestPost : to test creating a webhook
+func TestPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockWebhookService(ctrl)
+	mock := New(mockService)
+
+	input := entities.Webhook{URL: "https://partner.example/hook", EventTypes: []string{"BookUpdated"}}
+	created := entities.Webhook{WebhookID: 3, URL: "https://partner.example/hook", EventTypes: []string{"BookUpdated"},
+		Secret: "whsec_abc"}
+
+	testcases := []struct {
+		desc string
+		body string
+
+		expected interface{}
+	}{
+		{desc: "valid webhook", body: `{"url":"https://partner.example/hook","eventTypes":["BookUpdated"]}`,
+			expected: created},
+		{desc: "unmarshalling error", body: "hook", expected: nil},
+	}
+
+	k := gofr.New()
+	for _, tc := range testcases {
+		r := httptest.NewRequest("POST", "localhost:8000/webhook", bytes.NewBufferString(tc.body))
+		w := httptest.NewRecorder()
+
+		req := request.NewHTTPRequest(r)
+		res := responder.NewContextualResponder(w, r)
+		ctx := gofr.NewContext(res, req, k)
+
+		mockService.EXPECT().Post(ctx, input).Return(created, nil).AnyTimes()
+
+		result, _ := mock.Post(ctx)
+
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
+
+// TestRedeliver : to test sending a delivery again
+func TestRedeliver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockWebhookService(ctrl)
+	mock := New(mockService)
+
+	delivery := entities.Delivery{DeliveryID: 7, WebhookID: 3, Status: entities.DeliveryPending}
+
+	testcases := []struct {
+		desc       string
+		id         string
+		deliveryID string
+
+		expected interface{}
+	}{
+		{desc: "redelivered", id: "3", deliveryID: "7", expected: delivery},
+		{desc: "invalid delivery id", id: "3", deliveryID: "x", expected: nil},
+	}
+
+	k := gofr.New()
+	for _, tc := range testcases {
+		r := httptest.NewRequest("POST", "localhost:8000/webhook/"+tc.id+"/delivery/"+tc.deliveryID+"/redeliver",
+			nil)
+		r = mux.SetURLVars(r, map[string]string{"id": tc.id, "deliveryID": tc.deliveryID})
+		w := httptest.NewRecorder()
+
+		req := request.NewHTTPRequest(r)
+		res := responder.NewContextualResponder(w, r)
+		ctx := gofr.NewContext(res, req, k)
+
+		mockService.EXPECT().Redeliver(ctx, 3, 7).Return(delivery, nil).AnyTimes()
+
+		result, _ := mock.Redeliver(ctx)
+
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("failed for %v, got: %v\n", tc.desc, result)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package workhttp
+
+import (
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+)
+
+type WorkHandler struct {
+	workService service.WorkService
+}
+
+// New : factory function
+func New(s service.WorkService) WorkHandler {
+	return WorkHandler{s}
+}
+
+// GetByID : handles the request of getting a work with all of its editions
+func (h WorkHandler) GetByID(ctx *gofr.Context) (interface{}, error) {
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	work, err := h.workService.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return work, nil
+}
+
+// Post : handles the request of posting a work
+func (h WorkHandler) Post(ctx *gofr.Context) (interface{}, error) {
+	var work entities.Work
+
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(body, &work)
+	if err != nil {
+		return nil, err
+	}
+
+	work, err = h.workService.Post(ctx, work)
+	if err != nil {
+		return nil, err
+	}
+
+	return work, nil
+}
+
+// Put : handles the request of updating a work
+func (h WorkHandler) Put(ctx *gofr.Context) (interface{}, error) {
+	var work entities.Work
+
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(body, &work)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	work, err = h.workService.Put(ctx, work, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return work, nil
+}
+
+// Delete : handles the request of deleting a work
+func (h WorkHandler) Delete(ctx *gofr.Context) (interface{}, error) {
+	id, err := strconv.Atoi(ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.workService.Delete(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return "successfully deleted", nil
+}
@@ -0,0 +1,62 @@
+package workhttp
+
+import (
+	"bytes"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+)
+
+// TestPut : to test the Put handler
+func TestPut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockWorkService(ctrl)
+	mock := New(mockService)
+
+	work := entities.Work{WorkID: 1, AuthorID: 3, Title: "deciding decade"}
+
+	testcases := []struct {
+		desc   string
+		target string
+		body   []byte
+
+		serviceErr error
+		expected   interface{}
+	}{
+		{desc: "valid work", target: "1", body: []byte(`{"authorID":3,"title":"deciding decade"}`), expected: work},
+		{desc: "error from svc layer", target: "1", body: []byte(`{"authorID":3,"title":"deciding decade"}`),
+			serviceErr: errors.New("author does not exist"), expected: nil},
+		{desc: "unmarshalling error", target: "1", body: []byte("hello"), expected: nil},
+		{desc: "strconv error", target: "abc", body: []byte(`{}`), expected: nil},
+	}
+
+	k := gofr.New()
+	for _, tc := range testcases {
+		r := httptest.NewRequest("PUT", "localhost:8000/work/"+tc.target, bytes.NewReader(tc.body))
+		r = mux.SetURLVars(r, map[string]string{"id": tc.target})
+		w := httptest.NewRecorder()
+
+		req := request.NewHTTPRequest(r)
+		res := responder.NewContextualResponder(w, r)
+		ctx := gofr.NewContext(res, req, k)
+
+		mockService.EXPECT().Put(ctx, entities.Work{AuthorID: 3, Title: "deciding decade"}, 1).
+			Return(work, tc.serviceErr).AnyTimes()
+
+		result, _ := mock.Put(ctx)
+
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
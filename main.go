@@ -1,15 +1,79 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/go-redis/redis/v8"
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"log"
+	"net"
+	"net/http"
 	"projects/GoLang-Interns-2022/authorbook/driver"
+	"projects/GoLang-Interns-2022/authorbook/events"
+	"projects/GoLang-Interns-2022/authorbook/grpc/authorgrpc"
+	"projects/GoLang-Interns-2022/authorbook/grpc/bookgrpc"
+	"projects/GoLang-Interns-2022/authorbook/grpc/interceptor"
+	"projects/GoLang-Interns-2022/authorbook/grpc/pb"
+	"projects/GoLang-Interns-2022/authorbook/http/apikeyhttp"
+	"projects/GoLang-Interns-2022/authorbook/http/audithttp"
 	"projects/GoLang-Interns-2022/authorbook/http/authorhttp"
+	"projects/GoLang-Interns-2022/authorbook/http/batchhttp"
 	"projects/GoLang-Interns-2022/authorbook/http/bookhttp"
+	"projects/GoLang-Interns-2022/authorbook/http/changehttp"
+	"projects/GoLang-Interns-2022/authorbook/http/exporthttp"
+	"projects/GoLang-Interns-2022/authorbook/http/genrehttp"
+	"projects/GoLang-Interns-2022/authorbook/http/graphqlhttp"
+	"projects/GoLang-Interns-2022/authorbook/http/importhttp"
+	"projects/GoLang-Interns-2022/authorbook/http/ndjson"
+	"projects/GoLang-Interns-2022/authorbook/http/reviewhttp"
+	"projects/GoLang-Interns-2022/authorbook/http/serieshttp"
+	"projects/GoLang-Interns-2022/authorbook/http/taghttp"
+	"projects/GoLang-Interns-2022/authorbook/http/webhookhttp"
+	"projects/GoLang-Interns-2022/authorbook/http/workhttp"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+	"projects/GoLang-Interns-2022/authorbook/middleware/authz"
+	"projects/GoLang-Interns-2022/authorbook/middleware/conditional"
+	idempotencymiddleware "projects/GoLang-Interns-2022/authorbook/middleware/idempotency"
+	"projects/GoLang-Interns-2022/authorbook/middleware/negotiate"
+	"projects/GoLang-Interns-2022/authorbook/middleware/route"
+	"projects/GoLang-Interns-2022/authorbook/service/apikeyservice"
+	"projects/GoLang-Interns-2022/authorbook/service/auditservice"
 	"projects/GoLang-Interns-2022/authorbook/service/authorservice"
+	"projects/GoLang-Interns-2022/authorbook/service/batchservice"
 	"projects/GoLang-Interns-2022/authorbook/service/bookservice"
+	"projects/GoLang-Interns-2022/authorbook/service/changeservice"
+	"projects/GoLang-Interns-2022/authorbook/service/exportservice"
+	"projects/GoLang-Interns-2022/authorbook/service/genreservice"
+	"projects/GoLang-Interns-2022/authorbook/service/idempotencyservice"
+	"projects/GoLang-Interns-2022/authorbook/service/importservice"
+	"projects/GoLang-Interns-2022/authorbook/service/outboxservice"
+	"projects/GoLang-Interns-2022/authorbook/service/reviewservice"
+	"projects/GoLang-Interns-2022/authorbook/service/seriesservice"
+	"projects/GoLang-Interns-2022/authorbook/service/tagservice"
+	"projects/GoLang-Interns-2022/authorbook/service/webhookservice"
+	"projects/GoLang-Interns-2022/authorbook/service/workservice"
+	"projects/GoLang-Interns-2022/authorbook/store"
+	"projects/GoLang-Interns-2022/authorbook/store/apikey"
+	"projects/GoLang-Interns-2022/authorbook/store/audit"
 	"projects/GoLang-Interns-2022/authorbook/store/author"
 	"projects/GoLang-Interns-2022/authorbook/store/book"
+	"projects/GoLang-Interns-2022/authorbook/store/cache"
+	"projects/GoLang-Interns-2022/authorbook/store/genre"
+	"projects/GoLang-Interns-2022/authorbook/store/history"
+	"projects/GoLang-Interns-2022/authorbook/store/idempotency"
+	"projects/GoLang-Interns-2022/authorbook/store/importjob"
+	"projects/GoLang-Interns-2022/authorbook/store/outbox"
+	"projects/GoLang-Interns-2022/authorbook/store/review"
+	"projects/GoLang-Interns-2022/authorbook/store/series"
+	"projects/GoLang-Interns-2022/authorbook/store/tag"
+	"projects/GoLang-Interns-2022/authorbook/store/webhook"
+	"projects/GoLang-Interns-2022/authorbook/store/work"
+	"strconv"
+	"strings"
+	"time"
 )
 
 func main() {
@@ -20,23 +84,285 @@ func main() {
 
 	app := gofr.New()
 
-	authorStore := author.New(DB)
-	authorService := authorservice.New(authorStore)
+	// every endpoint needs a bearer token or an API key, the token keys are read from the files named in the config
+	jwtAuthenticator, err := auth.NewJWT(auth.Config{
+		HMACSecretFile:   app.Config.Get("JWT_HMAC_SECRET_FILE"),
+		RSAPublicKeyFile: app.Config.Get("JWT_RSA_PUBLIC_KEY_FILE"),
+		JWKSFile:         app.Config.Get("JWT_JWKS_FILE"),
+		Issuer:           app.Config.Get("JWT_ISSUER"),
+		Audience:         app.Config.Get("JWT_AUDIENCE"),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	apiKeyService := apikeyservice.New(apikey.New(DB))
+	authenticator := auth.Chain{auth.NewAPIKey(apiKeyService), jwtAuthenticator}
+
+	// the roles of the caller have to grant the permission of the route, the policy file replaces the default matrix
+	policy := authz.DefaultPolicy()
+	if file := app.Config.Get("AUTHZ_POLICY_FILE"); file != "" {
+		policy, err = authz.LoadPolicy(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	authorStore, bookStore, closeCache := newStores(app, DB)
+	defer closeCache()
+
+	// author and book changes are written together with their audit entry in one transaction
+	tx := store.NewTransaction(DB)
+	auditStore := audit.New(DB)
+	historyStore := history.New(DB)
+
+	// the changes are written to the outbox with their audit entry and relayed to the bus of this process,
+	// Kafka subscribes to the bus when brokers are configured
+	outboxStore := outbox.New(DB)
+	bus := events.NewBus()
+
+	if brokers := app.Config.Get("EVENTS_KAFKA_BROKERS"); brokers != "" {
+		topic := app.Config.Get("EVENTS_KAFKA_TOPIC")
+		if topic == "" {
+			topic = "authorbook.events"
+		}
+
+		kafka, err := events.NewKafka(strings.Split(brokers, ","), topic)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer kafka.Close()
+
+		bus.Subscribe(kafka.Publish)
+	}
+
+	authorService := authorservice.New(authorStore, auditStore, historyStore, outboxStore, tx)
 	authorHandler := authorhttp.New(authorService)
+
+	bookService := bookservice.New(bookStore, authorStore, auditStore, historyStore, outboxStore, tx)
+	bookHandler := bookhttp.New(bookService)
+
+	idempotencyService := newIdempotencyService(app, DB)
+
+	// middlewares : auth first, streamed and GraphQL routes beside the router, then negotiation, caching, idempotency
+	exportHandler := exporthttp.New(exportservice.New(bookStore, authorStore))
+	changeHandler := newChangeHandler(app, outboxStore, bus)
+	app.Server.UseMiddleware(auth.Middleware(authenticator, "/.well-known/"), authz.Middleware(policy, "/.well-known/"),
+		route.Handle("GET", "/export", http.HandlerFunc(exportHandler.Export)),
+		route.Handle("GET", "/book/{id}/export", http.HandlerFunc(exportHandler.ExportBook)),
+		route.HandleAccept("GET", "/book", ndjson.MediaType, http.HandlerFunc(bookHandler.StreamAllBook)),
+		route.HandleAccept("GET", "/author", ndjson.MediaType, http.HandlerFunc(authorHandler.StreamAll)),
+		route.Handle("POST", "/graphql", graphqlhttp.New(authorService, bookService, policy)),
+		route.Handle("GET", "/changes", http.HandlerFunc(changeHandler.Stream)),
+		negotiate.Middleware("/author", "/book"),
+		conditional.Middleware(cacheControl(app), "/book"),
+		idempotencymiddleware.Middleware(idempotencyService, "/author", "/book", "/batch"))
+
 	// author endpoints
+	app.GET("/author", authorHandler.GetAll)
 	app.POST("/author", authorHandler.Post)
 	app.DELETE("/author/{id}", authorHandler.Delete)
 	app.PUT("/author/{id}", authorHandler.Put)
+	app.GET("/author/{id}/history", authorHandler.GetHistory)
 
-	bookStore := book.New(DB)
-	bookService := bookservice.New(bookStore, authorStore)
-	bookHandler := bookhttp.New(bookService)
 	//book  endpoints
 	app.GET("/book", bookHandler.GetAllBook)
 	app.GET("/book/{id}", bookHandler.GetBookByID)
 	app.POST("/book", bookHandler.Post)
 	app.PUT("/book/{id}", bookHandler.Put)
 	app.DELETE("/book/{id}", bookHandler.Delete)
+	app.GET("/book/{id}/history", bookHandler.GetHistory)
+	app.POST("/book/{id}/revert/{revision}", bookHandler.Revert)
+
+	// batch endpoint : ordered author and book operations, each authorized on its own
+	batchHandler := batchhttp.New(batchservice.New(authorService, bookService, tx, policy))
+	app.POST("/batch", batchHandler.Post)
+
+	genreHandler := genrehttp.New(genreservice.New(genre.New(DB), bookStore))
+	// genre endpoints
+	app.GET("/genre", genreHandler.GetAll)
+	app.GET("/genre/{id}", genreHandler.GetByID)
+	app.POST("/genre", genreHandler.Post)
+	app.PUT("/genre/{id}", genreHandler.Put)
+	app.DELETE("/genre/{id}", genreHandler.Delete)
+
+	tagHandler := taghttp.New(tagservice.New(tag.New(DB), bookStore))
+	// tag endpoints
+	app.GET("/tag", tagHandler.GetAll)
+	app.POST("/tag", tagHandler.Post)
+	app.DELETE("/tag/{id}", tagHandler.Delete)
+
+	seriesHandler := serieshttp.New(seriesservice.New(series.New(DB), bookStore))
+	// series endpoints
+	app.GET("/series/{id}", seriesHandler.GetByID)
+	app.POST("/series", seriesHandler.Post)
+	app.PUT("/series/{id}", seriesHandler.Put)
+	app.DELETE("/series/{id}", seriesHandler.Delete)
+	app.PUT("/series/{id}/book/{bookID}", seriesHandler.AddBook)
+	app.DELETE("/series/{id}/book/{bookID}", seriesHandler.RemoveBook)
+
+	workHandler := workhttp.New(workservice.New(work.New(DB), authorStore, bookStore))
+	// work endpoints
+	app.GET("/work/{id}", workHandler.GetByID)
+	app.POST("/work", workHandler.Post)
+	app.PUT("/work/{id}", workHandler.Put)
+	app.DELETE("/work/{id}", workHandler.Delete)
+
+	reviewHandler := reviewhttp.New(reviewservice.New(review.New(DB), bookStore, policy))
+	// review endpoints
+	app.POST("/book/{id}/review", reviewHandler.Post)
+	app.GET("/book/{id}/review", reviewHandler.GetByBook)
+	app.PUT("/review/{id}/status", reviewHandler.UpdateStatus)
+	app.POST("/review/{id}/helpful", reviewHandler.MarkHelpful)
+	app.DELETE("/review/{id}", reviewHandler.Delete)
+
+	apiKeyHandler := apikeyhttp.New(apiKeyService)
+	// api key endpoints
+	app.GET("/apikey", apiKeyHandler.GetAll)
+	app.POST("/apikey", apiKeyHandler.Post)
+	app.PUT("/apikey/{id}", apiKeyHandler.Put)
+	app.DELETE("/apikey/{id}", apiKeyHandler.Delete)
+
+	importService := importservice.New(importjob.New(DB), authorStore, authorService, bookService, tx)
+	importHandler := importhttp.New(importService)
+	// import endpoints
+	app.POST("/import", importHandler.Post)
+	app.GET("/import/{id}", importHandler.GetByID)
+
+	auditHandler := audithttp.New(auditservice.New(auditStore))
+	// audit endpoint
+	app.GET("/audit", auditHandler.GetAll)
+
+	// partners get the events they subscribed to as signed deliveries, queued from the bus and sent in the background
+	webhookStore := webhook.New(DB)
+	webhookService := webhookservice.New(webhookStore)
+	bus.Subscribe(webhookService.Enqueue)
+
+	go webhookservice.NewDispatcher(webhookStore, &http.Client{Timeout: 10 * time.Second}).Run(context.Background(),
+		5*time.Second)
+
+	webhookHandler := webhookhttp.New(webhookService)
+	// webhook endpoints
+	app.GET("/webhook", webhookHandler.GetAll)
+	app.POST("/webhook", webhookHandler.Post)
+	app.GET("/webhook/{id}", webhookHandler.GetByID)
+	app.PUT("/webhook/{id}", webhookHandler.Put)
+	app.DELETE("/webhook/{id}", webhookHandler.Delete)
+	app.GET("/webhook/{id}/delivery", webhookHandler.GetDeliveries)
+	app.POST("/webhook/{id}/delivery/{deliveryID}/redeliver", webhookHandler.Redeliver)
+
+	// the relay starts once every subscriber is on the bus, an event published before would be missed by them
+	go outboxservice.New(outboxStore, bus).Run(context.Background(), duration(app, "OUTBOX_RELAY_INTERVAL",
+		time.Second))
+	go newOutboxPurger(app, outboxStore).Run(context.Background(), time.Hour)
+
+	// internal services reach the author and book services over gRPC on a port of their own, the calls are
+	// authenticated and authorized like the REST requests
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptor.Recover(), interceptor.Unary(authenticator, policy)),
+		grpc.ChainStreamInterceptor(interceptor.RecoverStream(), interceptor.Stream(authenticator, policy)))
+	pb.RegisterAuthorServiceServer(grpcServer, authorgrpc.New(authorService))
+	pb.RegisterBookServiceServer(grpcServer, bookgrpc.New(bookService))
+
+	grpcPort := app.Config.Get("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "9000"
+	}
+
+	listener, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Fatal(err)
+		}
+	}()
 
 	app.Start()
 }
+
+// duration : the duration of the config key, the fallback when it is not set or invalid
+func duration(app *gofr.Gofr, key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(app.Config.Get(key))
+	if err != nil {
+		return fallback
+	}
+
+	return d
+}
+
+// newChangeHandler : streams the changes read from the outbox every CHANGES_POLL_INTERVAL, a second by default
+func newChangeHandler(app *gofr.Gofr, outboxStore store.OutboxStorer, bus *events.Bus) changehttp.ChangeHandler {
+	interval := duration(app, "CHANGES_POLL_INTERVAL", time.Second)
+
+	return changehttp.New(changeservice.New(outboxStore, bus, interval), 15*time.Second)
+}
+
+// newIdempotencyService : replays the response of a key for IDEMPOTENCY_WINDOW, a day by default, purged hourly
+func newIdempotencyService(app *gofr.Gofr, db *sql.DB) idempotencyservice.IdempotencyService {
+	s := idempotencyservice.New(idempotency.New(db), duration(app, "IDEMPOTENCY_WINDOW", 24*time.Hour))
+	go s.Run(context.Background(), time.Hour)
+
+	return s
+}
+
+// newOutboxPurger : keeps the published events for OUTBOX_RETENTION, a week by default
+func newOutboxPurger(app *gofr.Gofr, outboxStore store.OutboxStorer) outboxservice.Purger {
+	return outboxservice.NewPurger(outboxStore, duration(app, "OUTBOX_RETENTION", 7*24*time.Hour))
+}
+
+// newStores : the author and book stores read through the CACHE_BACKEND cache, memory or redis, when one is set;
+// closeCache releases its connection
+func newStores(app *gofr.Gofr, db *sql.DB) (authorStore store.AuthorStorer, bookStore store.BookStorer,
+	closeCache func()) {
+	authorStore, bookStore, closeCache = author.New(db), book.New(db), func() {}
+
+	backend := app.Config.Get("CACHE_BACKEND")
+	if backend == "" {
+		return authorStore, bookStore, closeCache
+	}
+
+	var storeCache cache.Cache
+
+	switch backend {
+	case "memory":
+		size, err := strconv.Atoi(app.Config.Get("CACHE_SIZE"))
+		if err != nil || size <= 0 {
+			size = 10000
+		}
+
+		storeCache = cache.NewLRU(size)
+	case "redis":
+		port := app.Config.Get("REDIS_PORT")
+		if port == "" {
+			port = "6379"
+		}
+
+		client := redis.NewClient(&redis.Options{Addr: app.Config.Get("REDIS_HOST") + ":" + port})
+		storeCache, closeCache = cache.NewRedis(client, "authorbook:"), func() { _ = client.Close() }
+	default:
+		log.Fatalf("unknown CACHE_BACKEND %v", backend)
+	}
+
+	metrics, err := cache.NewMetrics(prometheus.DefaultRegisterer)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ttl := duration(app, "CACHE_TTL", 5*time.Minute)
+	authorStore = cache.NewAuthorStore(authorStore, storeCache, ttl, metrics)
+	bookStore = cache.NewBookStore(bookStore, storeCache, ttl, metrics)
+
+	return authorStore, bookStore, closeCache
+}
+
+// cacheControl : HTTP_CACHE_CONTROL of the config, private by default as every response depends on the caller
+func cacheControl(app *gofr.Gofr) string {
+	if value := app.Config.Get("HTTP_CACHE_CONTROL"); value != "" {
+		return value
+	}
+
+	return "private, no-cache"
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrNoCredentials : the request carries no credentials of the kind an authenticator understands
+var ErrNoCredentials = errors.New("missing credentials")
+
+// KeyVerifier : resolves an API key to the identity it was issued to
+type KeyVerifier interface {
+	Verify(ctx context.Context, key string) (Identity, error)
+}
+
+// APIKey : authenticates requests carrying an API key in the X-API-Key header
+type APIKey struct {
+	verifier KeyVerifier
+}
+
+// NewAPIKey : factory function
+func NewAPIKey(v KeyVerifier) APIKey {
+	return APIKey{v}
+}
+
+// Authenticate : verifies the API key of the request
+func (a APIKey) Authenticate(r *http.Request) (Identity, error) {
+	key := r.Header.Get("X-API-Key")
+	if key == "" {
+		return Identity{}, ErrNoCredentials
+	}
+
+	return a.verifier.Verify(r.Context(), key)
+}
+
+// Chain : tries the authenticators in order, the first one finding its credentials in the request decides
+type Chain []Authenticator
+
+// Authenticate : gives the identity of the first authenticator which finds credentials in the request
+func (c Chain) Authenticate(r *http.Request) (Identity, error) {
+	for _, a := range c {
+		id, err := a.Authenticate(r)
+		if !errors.Is(err, ErrNoCredentials) {
+			return id, err
+		}
+	}
+
+	return Identity{}, ErrNoCredentials
+}
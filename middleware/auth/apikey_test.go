@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+type verifier map[string]Identity
+
+func (v verifier) Verify(_ context.Context, key string) (Identity, error) {
+	id, ok := v[key]
+	if !ok {
+		return Identity{}, errors.New("invalid api key")
+	}
+
+	return id, nil
+}
+
+// TestChain : test that the first authenticator finding credentials decides
+func TestChain(t *testing.T) {
+	keys := NewAPIKey(verifier{"ak_1234.secret": {Subject: "apikey:1", Roles: []string{"reader"}}})
+	chain := Chain{keys, mockAuthenticator{}}
+
+	testcases := []struct {
+		desc   string
+		key    string
+		bearer string
+
+		expectedSubject string
+		expectedErr     bool
+	}{
+		{desc: "valid key", key: "ak_1234.secret", expectedSubject: "apikey:1"},
+		{desc: "invalid key is not retried as bearer", key: "ak_1234.wrong", bearer: "Bearer good", expectedErr: true},
+		{desc: "bearer token", bearer: "Bearer good", expectedSubject: "asha"},
+		{desc: "no credentials", expectedErr: true},
+	}
+
+	for _, tc := range testcases {
+		r := httptest.NewRequest("GET", "/book", nil)
+
+		if tc.key != "" {
+			r.Header.Set("X-API-Key", tc.key)
+		}
+
+		if tc.bearer != "" {
+			r.Header.Set("Authorization", tc.bearer)
+		}
+
+		id, err := chain.Authenticate(r)
+
+		if (err != nil) != tc.expectedErr || id.Subject != tc.expectedSubject {
+			t.Errorf("failed for %v, got: %v %v\n", tc.desc, id, err)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package auth
+
+import "context"
+
+// Identity : the authenticated caller of a request
+type Identity struct {
+	Subject string
+	Roles   []string
+	Scopes  []string
+}
+
+type identityKey struct{}
+
+// NewContext : gives a copy of the context carrying the identity
+func NewContext(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext : gives the identity of the caller, false when the request was not authenticated
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+
+	return id, ok
+}
+
+// Actor : gives the subject of the caller for the records of who changed what, "anonymous" when unknown
+func Actor(ctx context.Context) string {
+	if id, ok := FromContext(ctx); ok && id.Subject != "" {
+		return id.Subject
+	}
+
+	return "anonymous"
+}
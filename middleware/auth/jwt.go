@@ -0,0 +1,212 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Config : locations of the verification keys, at least one of them has to be set
+type Config struct {
+	HMACSecretFile   string
+	RSAPublicKeyFile string
+	JWKSFile         string
+	Issuer           string
+	Audience         string
+}
+
+// JWT : authenticates requests carrying an HS256 or RS256 signed bearer token
+type JWT struct {
+	secret   []byte
+	rsaKey   *rsa.PublicKey
+	jwks     map[string]interface{}
+	issuer   string
+	audience string
+}
+
+// NewJWT : factory function, reads the keys from the files of the config
+func NewJWT(c Config) (JWT, error) {
+	j := JWT{issuer: c.Issuer, audience: c.Audience, jwks: make(map[string]interface{})}
+
+	if c.HMACSecretFile != "" {
+		secret, err := os.ReadFile(c.HMACSecretFile)
+		if err != nil {
+			return JWT{}, err
+		}
+
+		j.secret = []byte(strings.TrimSpace(string(secret)))
+	}
+
+	if c.RSAPublicKeyFile != "" {
+		pem, err := os.ReadFile(c.RSAPublicKeyFile)
+		if err != nil {
+			return JWT{}, err
+		}
+
+		if j.rsaKey, err = jwt.ParseRSAPublicKeyFromPEM(pem); err != nil {
+			return JWT{}, err
+		}
+	}
+
+	if c.JWKSFile != "" {
+		data, err := os.ReadFile(c.JWKSFile)
+		if err != nil {
+			return JWT{}, err
+		}
+
+		if j.jwks, err = parseJWKS(data); err != nil {
+			return JWT{}, err
+		}
+	}
+
+	if len(j.secret) == 0 && j.rsaKey == nil && len(j.jwks) == 0 {
+		return JWT{}, errors.New("no verification key configured")
+	}
+
+	return j, nil
+}
+
+// Authenticate : verifies the bearer token of the request and gives the identity it was issued to
+func (j JWT) Authenticate(r *http.Request) (Identity, error) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return Identity{}, ErrNoCredentials
+	}
+
+	claims := jwt.MapClaims{}
+
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"HS256", "RS256"}))
+
+	_, err := parser.ParseWithClaims(strings.TrimPrefix(header, "Bearer "), claims, j.key)
+	if err != nil {
+		return Identity{}, errors.New("invalid token")
+	}
+
+	if j.issuer != "" && !claims.VerifyIssuer(j.issuer, true) {
+		return Identity{}, errors.New("invalid token issuer")
+	}
+
+	if j.audience != "" && !claims.VerifyAudience(j.audience, true) {
+		return Identity{}, errors.New("invalid token audience")
+	}
+
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return Identity{}, errors.New("token has no subject")
+	}
+
+	id := Identity{Subject: sub}
+
+	if roles, ok := claims["roles"].([]interface{}); ok {
+		for _, role := range roles {
+			if role, ok := role.(string); ok {
+				id.Roles = append(id.Roles, role)
+			}
+		}
+	}
+
+	if scope, ok := claims["scope"].(string); ok {
+		id.Scopes = strings.Fields(scope)
+	}
+
+	return id, nil
+}
+
+// key : picks the verification key matching the algorithm and key id of the token
+func (j JWT) key(token *jwt.Token) (interface{}, error) {
+	if kid, ok := token.Header["kid"].(string); ok {
+		if key, ok := j.jwks[kid]; ok {
+			return matchAlg(token, key)
+		}
+	}
+
+	switch token.Method.Alg() {
+	case "HS256":
+		if len(j.secret) > 0 {
+			return j.secret, nil
+		}
+	case "RS256":
+		if j.rsaKey != nil {
+			return j.rsaKey, nil
+		}
+	}
+
+	// a key set holding a single key also verifies tokens without a key id
+	if len(j.jwks) == 1 {
+		for _, key := range j.jwks {
+			return matchAlg(token, key)
+		}
+	}
+
+	return nil, errors.New("no key for token")
+}
+
+// matchAlg : makes sure a key is only used with the algorithm of its type
+func matchAlg(token *jwt.Token, key interface{}) (interface{}, error) {
+	switch key.(type) {
+	case []byte:
+		if token.Method.Alg() == "HS256" {
+			return key, nil
+		}
+	case *rsa.PublicKey:
+		if token.Method.Alg() == "RS256" {
+			return key, nil
+		}
+	}
+
+	return nil, errors.New("key does not match the token algorithm")
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+// parseJWKS : reads the RSA and symmetric keys of a JSON Web Key Set, other key types are skipped
+func parseJWKS(data []byte) (map[string]interface{}, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]interface{})
+
+	for _, k := range set.Keys {
+		switch k.Kty {
+		case "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(k.N)
+			if err != nil {
+				return nil, err
+			}
+
+			e, err := base64.RawURLEncoding.DecodeString(k.E)
+			if err != nil {
+				return nil, err
+			}
+
+			keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(k.K)
+			if err != nil {
+				return nil, err
+			}
+
+			keys[k.Kid] = secret
+		}
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// writeFile : writes the content to a file of the temporary directory of the test
+func writeFile(t *testing.T, name string, content []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("error writing %v: %v", name, err)
+	}
+
+	return path
+}
+
+// sign : gives a signed token for the claims
+func sign(t *testing.T, method jwt.SigningMethod, kid string, claims jwt.MapClaims, key interface{}) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("error signing token: %v", err)
+	}
+
+	return signed
+}
+
+// TestAuthenticate : to test the verification of HS256 and RS256 tokens
+func TestAuthenticate(t *testing.T) {
+	secret := []byte("s3cr3t")
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+
+	pubDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatalf("error encoding key: %v", err)
+	}
+
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+
+	jwks := fmt.Sprintf(`{"keys":[{"kty":"RSA","kid":"k1","n":%q,"e":%q}]}`,
+		base64.RawURLEncoding.EncodeToString(rsaKey.PublicKey.N.Bytes()),
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.PublicKey.E)).Bytes()))
+
+	hmacAuth, err := NewJWT(Config{HMACSecretFile: writeFile(t, "secret", secret), Issuer: "authorbook"})
+	if err != nil {
+		t.Fatalf("error creating authenticator: %v", err)
+	}
+
+	rsaAuth, err := NewJWT(Config{RSAPublicKeyFile: writeFile(t, "key.pem", pubPEM)})
+	if err != nil {
+		t.Fatalf("error creating authenticator: %v", err)
+	}
+
+	jwksAuth, err := NewJWT(Config{JWKSFile: writeFile(t, "jwks.json", []byte(jwks))})
+	if err != nil {
+		t.Fatalf("error creating authenticator: %v", err)
+	}
+
+	valid := jwt.MapClaims{"sub": "asha", "iss": "authorbook", "roles": []string{"editor"}, "scope": "read books",
+		"exp": time.Now().Add(time.Hour).Unix()}
+	expired := jwt.MapClaims{"sub": "asha", "iss": "authorbook", "exp": time.Now().Add(-time.Hour).Unix()}
+	otherIssuer := jwt.MapClaims{"sub": "asha", "iss": "someone", "exp": time.Now().Add(time.Hour).Unix()}
+
+	testcases := []struct {
+		desc   string
+		auth   JWT
+		header string
+
+		expected    Identity
+		expectedErr bool
+	}{
+		{desc: "HS256 token", auth: hmacAuth, header: "Bearer " + sign(t, jwt.SigningMethodHS256, "", valid, secret),
+			expected: Identity{Subject: "asha", Roles: []string{"editor"}, Scopes: []string{"read", "books"}}},
+		{desc: "RS256 token", auth: rsaAuth, header: "Bearer " + sign(t, jwt.SigningMethodRS256, "", valid, rsaKey),
+			expected: Identity{Subject: "asha", Roles: []string{"editor"}, Scopes: []string{"read", "books"}}},
+		{desc: "RS256 token from key set", auth: jwksAuth,
+			header:   "Bearer " + sign(t, jwt.SigningMethodRS256, "k1", valid, rsaKey),
+			expected: Identity{Subject: "asha", Roles: []string{"editor"}, Scopes: []string{"read", "books"}}},
+		{desc: "public key used as HMAC secret", auth: rsaAuth,
+			header: "Bearer " + sign(t, jwt.SigningMethodHS256, "", valid, pubPEM), expectedErr: true},
+		{desc: "expired token", auth: hmacAuth,
+			header: "Bearer " + sign(t, jwt.SigningMethodHS256, "", expired, secret), expectedErr: true},
+		{desc: "other issuer", auth: hmacAuth,
+			header: "Bearer " + sign(t, jwt.SigningMethodHS256, "", otherIssuer, secret), expectedErr: true},
+		{desc: "wrong secret", auth: hmacAuth,
+			header: "Bearer " + sign(t, jwt.SigningMethodHS256, "", valid, []byte("guess")), expectedErr: true},
+		{desc: "missing token", auth: hmacAuth, header: "", expectedErr: true},
+		{desc: "basic credentials", auth: hmacAuth, header: "Basic YXNoYTpwdw==", expectedErr: true},
+	}
+
+	for _, tc := range testcases {
+		r := httptest.NewRequest("GET", "localhost:8000/book", nil)
+		if tc.header != "" {
+			r.Header.Set("Authorization", tc.header)
+		}
+
+		id, err := tc.auth.Authenticate(r)
+
+		if !reflect.DeepEqual(id, tc.expected) || (err != nil) != tc.expectedErr {
+			t.Errorf("failed for %v, got: %v %v", tc.desc, id, err)
+		}
+	}
+}
+
+// TestNewJWT : to test an authenticator needs at least one key
+func TestNewJWT(t *testing.T) {
+	if _, err := NewJWT(Config{}); err == nil {
+		t.Errorf("expected error for missing keys")
+	}
+
+	if _, err := NewJWT(Config{HMACSecretFile: filepath.Join(t.TempDir(), "missing")}); err == nil {
+		t.Errorf("expected error for missing secret file")
+	}
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Authenticator : verifies the credentials of a request, implementations are plugged into Middleware
+type Authenticator interface {
+	Authenticate(r *http.Request) (Identity, error)
+}
+
+// Middleware : rejects requests which can not be authenticated with 401 and stores the identity of the others
+// in the request context, paths starting with one of the public prefixes are let through untouched
+func Middleware(a Authenticator, public ...string) func(http.Handler) http.Handler {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, prefix := range public {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					inner.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			id, err := a.Authenticate(r)
+			if err != nil {
+				log.Print(err)
+				w.Header().Set("WWW-Authenticate", `Bearer realm="authorbook"`)
+				WriteError(w, http.StatusUnauthorized, "Unauthorized", err.Error())
+
+				return
+			}
+
+			inner.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+		})
+	}
+}
+
+// WriteError : writes an error response in the format used by the handlers
+func WriteError(w http.ResponseWriter, status int, code, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{{"code": code, "reason": reason}},
+	})
+}
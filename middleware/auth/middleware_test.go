@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type mockAuthenticator struct{}
+
+func (mockAuthenticator) Authenticate(r *http.Request) (Identity, error) {
+	if r.Header.Get("Authorization") != "Bearer good" {
+		return Identity{}, errors.New("invalid token")
+	}
+
+	return Identity{Subject: "asha"}, nil
+}
+
+// TestMiddleware : to test unauthenticated requests are rejected and the identity reaches the handler
+func TestMiddleware(t *testing.T) {
+	testcases := []struct {
+		desc   string
+		path   string
+		header string
+
+		expectedStatus  int
+		expectedSubject string
+	}{
+		{desc: "valid token", path: "/book", header: "Bearer good", expectedStatus: http.StatusOK,
+			expectedSubject: "asha"},
+		{desc: "invalid token", path: "/book", header: "Bearer bad", expectedStatus: http.StatusUnauthorized},
+		{desc: "missing token", path: "/author/1", expectedStatus: http.StatusUnauthorized},
+		{desc: "public path", path: "/.well-known/health-check", expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range testcases {
+		var subject string
+
+		handler := Middleware(mockAuthenticator{}, "/.well-known/")(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				id, _ := FromContext(r.Context())
+				subject = id.Subject
+			}))
+
+		r := httptest.NewRequest("GET", tc.path, nil)
+		r.Header.Set("Authorization", tc.header)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, r)
+
+		if w.Code != tc.expectedStatus || subject != tc.expectedSubject {
+			t.Errorf("failed for %v, got: %v %v", tc.desc, w.Code, subject)
+		}
+
+		if tc.expectedStatus == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("failed for %v, missing WWW-Authenticate header", tc.desc)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package authz
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+)
+
+// Middleware : lets a request through only when the roles of the caller grant the permission of its route,
+// it has to run after the authentication middleware; paths starting with one of the public prefixes are let through
+func Middleware(p Policy, public ...string) func(http.Handler) http.Handler {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, prefix := range public {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					inner.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			id, ok := auth.FromContext(r.Context())
+			if !ok {
+				auth.WriteError(w, http.StatusUnauthorized, "Unauthorized", "missing identity")
+				return
+			}
+
+			permission, ok := p.Permission(r.Method, r.URL.Path)
+			if !ok {
+				auth.WriteError(w, http.StatusForbidden, "Forbidden", "no permission defined for "+r.Method+" "+r.URL.Path)
+				return
+			}
+
+			if !p.Allowed(id.Roles, permission) {
+				log.Printf("%v denied %v", id.Subject, permission)
+				auth.WriteError(w, http.StatusForbidden, "Forbidden", "missing permission "+permission)
+
+				return
+			}
+
+			if !ScopesAllow(id.Scopes, permission) {
+				log.Printf("%v denied %v by scope", id.Subject, permission)
+				auth.WriteError(w, http.StatusForbidden, "Forbidden", "permission "+permission+" is outside the key scope")
+
+				return
+			}
+
+			inner.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package authz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+)
+
+// TestMiddleware : to test requests without the permission of their route are rejected with 403
+func TestMiddleware(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		method   string
+		path     string
+		identity *auth.Identity
+
+		expectedStatus int
+		expectedReason string
+	}{
+		{desc: "reader lists books", method: "GET", path: "/book", identity: &auth.Identity{Roles: []string{"reader"}},
+			expectedStatus: http.StatusOK},
+		{desc: "reader deletes author", method: "DELETE", path: "/author/4",
+			identity: &auth.Identity{Roles: []string{"reader"}}, expectedStatus: http.StatusForbidden,
+			expectedReason: "missing permission author:delete"},
+		{desc: "editor deletes book", method: "DELETE", path: "/book/4",
+			identity: &auth.Identity{Roles: []string{"editor"}}, expectedStatus: http.StatusForbidden,
+			expectedReason: "missing permission book:delete"},
+		{desc: "admin deletes book", method: "DELETE", path: "/book/4",
+			identity: &auth.Identity{Roles: []string{"admin"}}, expectedStatus: http.StatusOK},
+		{desc: "read-only key updates book", method: "PUT", path: "/book/4",
+			identity:       &auth.Identity{Roles: []string{"editor"}, Scopes: []string{"read-only"}},
+			expectedStatus: http.StatusForbidden, expectedReason: "outside the key scope"},
+		{desc: "editor manages api keys", method: "GET", path: "/apikey",
+			identity: &auth.Identity{Roles: []string{"editor"}}, expectedStatus: http.StatusForbidden,
+			expectedReason: "missing permission apikey:manage"},
+		{desc: "unauthenticated", method: "GET", path: "/book", expectedStatus: http.StatusUnauthorized},
+		{desc: "public path", method: "GET", path: "/.well-known/heartbeat", expectedStatus: http.StatusOK},
+	}
+
+	handler := Middleware(DefaultPolicy(), "/.well-known/")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, tc := range testcases {
+		r := httptest.NewRequest(tc.method, tc.path, nil)
+		if tc.identity != nil {
+			r = r.WithContext(auth.NewContext(r.Context(), *tc.identity))
+		}
+
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, r)
+
+		if w.Code != tc.expectedStatus || !strings.Contains(w.Body.String(), tc.expectedReason) {
+			t.Errorf("failed for %v, got: %v %v", tc.desc, w.Code, w.Body.String())
+		}
+	}
+}
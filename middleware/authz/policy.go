@@ -0,0 +1,230 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+)
+
+// Policy : the permissions granted to each role and the permission each route requires
+type Policy struct {
+	Roles  map[string][]string `json:"roles"`
+	Routes []Route             `json:"routes"`
+}
+
+// Route : a method and path pattern, path parameters are written as {name}
+type Route struct {
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	Permission string `json:"permission"`
+}
+
+// DefaultPolicy : readers browse the catalogue and review books, editors maintain it and admins may also delete
+func DefaultPolicy() Policy {
+	return Policy{
+		Roles: map[string][]string{
+			"reader": {"author:read", "book:read", "catalogue:read", "review:read", "review:create", "review:vote"},
+			"editor": {"author:read", "author:create", "author:update", "book:read", "book:create", "book:update",
+				"catalogue:read", "catalogue:write", "catalogue:import", "review:read", "review:create", "review:vote",
+				"review:moderate"},
+			"admin": {"*"},
+		},
+		Routes: []Route{
+			{"GET", "/author", "author:read"},
+			{"POST", "/author", "author:create"},
+			{"PUT", "/author/{id}", "author:update"},
+			{"DELETE", "/author/{id}", "author:delete"},
+			{"GET", "/author/{id}/history", "author:read"},
+
+			{"GET", "/book", "book:read"},
+			{"GET", "/book/{id}", "book:read"},
+			{"POST", "/book", "book:create"},
+			{"PUT", "/book/{id}", "book:update"},
+			{"DELETE", "/book/{id}", "book:delete"},
+			{"GET", "/book/{id}/history", "book:read"},
+			{"POST", "/book/{id}/revert/{revision}", "book:update"},
+			{"GET", "/book/{id}/export", "book:read"},
+
+			{"GET", "/genre", "catalogue:read"},
+			{"GET", "/genre/{id}", "catalogue:read"},
+			{"POST", "/genre", "catalogue:write"},
+			{"PUT", "/genre/{id}", "catalogue:write"},
+			{"DELETE", "/genre/{id}", "catalogue:delete"},
+			{"GET", "/tag", "catalogue:read"},
+			{"POST", "/tag", "catalogue:write"},
+			{"DELETE", "/tag/{id}", "catalogue:delete"},
+			{"GET", "/series/{id}", "catalogue:read"},
+			{"POST", "/series", "catalogue:write"},
+			{"PUT", "/series/{id}", "catalogue:write"},
+			{"DELETE", "/series/{id}", "catalogue:delete"},
+			{"PUT", "/series/{id}/book/{bookID}", "catalogue:write"},
+			{"DELETE", "/series/{id}/book/{bookID}", "catalogue:write"},
+			{"GET", "/work/{id}", "catalogue:read"},
+			{"POST", "/work", "catalogue:write"},
+			{"PUT", "/work/{id}", "catalogue:write"},
+			{"DELETE", "/work/{id}", "catalogue:delete"},
+
+			{"GET", "/book/{id}/review", "review:read"},
+			{"POST", "/book/{id}/review", "review:create"},
+			{"POST", "/review/{id}/helpful", "review:vote"},
+			{"PUT", "/review/{id}/status", "review:moderate"},
+			{"DELETE", "/review/{id}", "review:delete"},
+
+			{"POST", "/import", "catalogue:import"},
+			{"GET", "/import/{id}", "catalogue:import"},
+			{"GET", "/export", "catalogue:read"},
+
+			// every GraphQL operation shares the route, the resolvers check the permission of each operation
+			{"POST", "/graphql", "catalogue:read"},
+			// likewise for the operations of a batch
+			{"POST", "/batch", "catalogue:read"},
+
+			// gRPC calls are HTTP/2 POSTs to /package.Service/Method
+			{"POST", "/authorbook.v1.AuthorService/GetAuthor", "author:read"},
+			{"POST", "/authorbook.v1.AuthorService/ListAuthors", "author:read"},
+			{"POST", "/authorbook.v1.AuthorService/CreateAuthor", "author:create"},
+			{"POST", "/authorbook.v1.AuthorService/UpdateAuthor", "author:update"},
+			{"POST", "/authorbook.v1.AuthorService/DeleteAuthor", "author:delete"},
+			{"POST", "/authorbook.v1.BookService/GetBook", "book:read"},
+			{"POST", "/authorbook.v1.BookService/ListBooks", "book:read"},
+			{"POST", "/authorbook.v1.BookService/CreateBook", "book:create"},
+			{"POST", "/authorbook.v1.BookService/UpdateBook", "book:update"},
+			{"POST", "/authorbook.v1.BookService/DeleteBook", "book:delete"},
+
+			{"GET", "/audit", "audit:read"},
+			// the feed shows who changed what like the audit log
+			{"GET", "/changes", "changes:read"},
+
+			{"GET", "/webhook", "webhook:manage"},
+			{"POST", "/webhook", "webhook:manage"},
+			{"GET", "/webhook/{id}", "webhook:manage"},
+			{"PUT", "/webhook/{id}", "webhook:manage"},
+			{"DELETE", "/webhook/{id}", "webhook:manage"},
+			{"GET", "/webhook/{id}/delivery", "webhook:manage"},
+			{"POST", "/webhook/{id}/delivery/{deliveryID}/redeliver", "webhook:manage"},
+
+			{"GET", "/apikey", "apikey:manage"},
+			{"POST", "/apikey", "apikey:manage"},
+			{"PUT", "/apikey/{id}", "apikey:manage"},
+			{"DELETE", "/apikey/{id}", "apikey:manage"},
+		},
+	}
+}
+
+// LoadPolicy : reads a policy from a JSON file
+func LoadPolicy(file string) (Policy, error) {
+	var p Policy
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	if err = json.Unmarshal(data, &p); err != nil {
+		return Policy{}, err
+	}
+
+	if len(p.Roles) == 0 || len(p.Routes) == 0 {
+		return Policy{}, errors.New("policy needs roles and routes")
+	}
+
+	return p, nil
+}
+
+// Permission : gives the permission required by the request, false when no route matches
+func (p Policy) Permission(method, path string) (string, bool) {
+	for _, route := range p.Routes {
+		if strings.EqualFold(route.Method, method) && matchPath(route.Path, path) {
+			return route.Permission, true
+		}
+	}
+
+	return "", false
+}
+
+// Allowed : checks whether any of the roles grants the permission, "book:*" grants every book permission and
+// "*" grants everything
+func (p Policy) Allowed(roles []string, permission string) bool {
+	resource := strings.SplitN(permission, ":", 2)[0]
+
+	for _, role := range roles {
+		for _, granted := range p.Roles[role] {
+			if granted == "*" || granted == permission || granted == resource+":*" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Check : checks that the caller of the request is granted the permission, for endpoints serving operations which
+// need different permissions behind one route
+func (p Policy) Check(ctx context.Context, permission string) error {
+	id, ok := auth.FromContext(ctx)
+	if !ok {
+		return errors.New("missing identity")
+	}
+
+	if !p.Allowed(id.Roles, permission) {
+		return errors.New("missing permission " + permission)
+	}
+
+	if !ScopesAllow(id.Scopes, permission) {
+		return errors.New("permission " + permission + " is outside the key scope")
+	}
+
+	return nil
+}
+
+// ScopesAllow : checks the permission against the scopes narrowing an API key, "read-only" keeps to read
+// permissions and "books-only" to book permissions
+func ScopesAllow(scopes []string, permission string) bool {
+	parts := strings.SplitN(permission, ":", 2)
+
+	for _, scope := range scopes {
+		switch scope {
+		case entities.ScopeReadOnly:
+			if len(parts) < 2 || parts[1] != "read" {
+				return false
+			}
+		case entities.ScopeBooksOnly:
+			if parts[0] != "book" {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// matchPath : matches a path against a pattern segment by segment
+func matchPath(pattern, path string) bool {
+	patternParts := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathParts := strings.Split(strings.Trim(path, "/"), "/")
+
+	if len(patternParts) != len(pathParts) {
+		return false
+	}
+
+	for i := range patternParts {
+		if strings.HasPrefix(patternParts[i], "{") && strings.HasSuffix(patternParts[i], "}") {
+			if pathParts[i] == "" {
+				return false
+			}
+
+			continue
+		}
+
+		if patternParts[i] != pathParts[i] {
+			return false
+		}
+	}
+
+	return true
+}
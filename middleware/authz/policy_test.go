@@ -0,0 +1,153 @@
+package authz
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+)
+
+// TestPermission : to test requests are matched to the permission of their route
+func TestPermission(t *testing.T) {
+	p := DefaultPolicy()
+
+	testcases := []struct {
+		desc   string
+		method string
+		path   string
+
+		expected   string
+		expectedOK bool
+	}{
+		{"list books", "GET", "/book", "book:read", true},
+		{"delete author", "DELETE", "/author/4", "author:delete", true},
+		{"series membership", "PUT", "/series/1/book/9", "catalogue:write", true},
+		{"helpful vote", "POST", "/review/3/helpful", "review:vote", true},
+		{"trailing slash", "GET", "/book/4/", "book:read", true},
+		{"unknown route", "PATCH", "/book/4", "", false},
+		{"missing id", "DELETE", "/author/", "", false},
+	}
+
+	for _, tc := range testcases {
+		permission, ok := p.Permission(tc.method, tc.path)
+
+		if permission != tc.expected || ok != tc.expectedOK {
+			t.Errorf("failed for %v, got: %v %v", tc.desc, permission, ok)
+		}
+	}
+}
+
+// TestAllowed : to test the permission matrix of the default roles
+func TestAllowed(t *testing.T) {
+	p := DefaultPolicy()
+	p.Roles["librarian"] = []string{"book:*"}
+
+	testcases := []struct {
+		desc       string
+		roles      []string
+		permission string
+
+		expected bool
+	}{
+		{"reader reads books", []string{"reader"}, "book:read", true},
+		{"reader creates book", []string{"reader"}, "book:create", false},
+		{"editor updates author", []string{"editor"}, "author:update", true},
+		{"editor deletes book", []string{"editor"}, "book:delete", false},
+		{"admin deletes author", []string{"admin"}, "author:delete", true},
+		{"any of the roles", []string{"reader", "editor"}, "book:create", true},
+		{"resource wildcard", []string{"librarian"}, "book:delete", true},
+		{"resource wildcard other resource", []string{"librarian"}, "author:delete", false},
+		{"unknown role", []string{"guest"}, "book:read", false},
+		{"no roles", nil, "book:read", false},
+	}
+
+	for _, tc := range testcases {
+		if p.Allowed(tc.roles, tc.permission) != tc.expected {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
+
+// TestScopesAllow : test that the scopes of an API key narrow its permissions
+func TestScopesAllow(t *testing.T) {
+	testcases := []struct {
+		desc       string
+		scopes     []string
+		permission string
+
+		expected bool
+	}{
+		{"no scopes", nil, "author:delete", true},
+		{"read-only reads", []string{"read-only"}, "author:read", true},
+		{"read-only writes", []string{"read-only"}, "book:create", false},
+		{"read-only votes", []string{"read-only"}, "review:vote", false},
+		{"books-only on books", []string{"books-only"}, "book:update", true},
+		{"books-only on authors", []string{"books-only"}, "author:read", false},
+		{"both scopes", []string{"read-only", "books-only"}, "book:read", true},
+		{"both scopes writing books", []string{"read-only", "books-only"}, "book:create", false},
+		{"unknown scope", []string{"profile"}, "author:create", true},
+	}
+
+	for _, tc := range testcases {
+		if ScopesAllow(tc.scopes, tc.permission) != tc.expected {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
+
+// TestCheck : test the permission check of the caller carried by a context
+func TestCheck(t *testing.T) {
+	p := DefaultPolicy()
+
+	testcases := []struct {
+		desc       string
+		identity   *auth.Identity
+		permission string
+
+		expectedErr string
+	}{
+		{"reader reads", &auth.Identity{Roles: []string{"reader"}}, "author:read", ""},
+		{"reader creates", &auth.Identity{Roles: []string{"reader"}}, "author:create", "missing permission author:create"},
+		{"scoped key", &auth.Identity{Roles: []string{"editor"}, Scopes: []string{"books-only"}}, "author:update",
+			"permission author:update is outside the key scope"},
+		{"unauthenticated", nil, "book:read", "missing identity"},
+	}
+
+	for _, tc := range testcases {
+		ctx := context.TODO()
+		if tc.identity != nil {
+			ctx = auth.NewContext(ctx, *tc.identity)
+		}
+
+		err := p.Check(ctx, tc.permission)
+		if (err == nil && tc.expectedErr != "") || (err != nil && err.Error() != tc.expectedErr) {
+			t.Errorf("failed for %v, got %v", tc.desc, err)
+		}
+	}
+}
+
+// TestLoadPolicy : to test a policy is read from a file
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "policy.json")
+	empty := filepath.Join(dir, "empty.json")
+
+	_ = os.WriteFile(valid, []byte(`{"roles":{"reader":["book:read"]},`+
+		`"routes":[{"method":"GET","path":"/book","permission":"book:read"}]}`), 0o600)
+	_ = os.WriteFile(empty, []byte(`{}`), 0o600)
+
+	p, err := LoadPolicy(valid)
+	if err != nil || !p.Allowed([]string{"reader"}, "book:read") {
+		t.Errorf("expected policy to be loaded, got: %v %v", p, err)
+	}
+
+	if _, err = LoadPolicy(empty); err == nil {
+		t.Errorf("expected error for empty policy")
+	}
+
+	if _, err = LoadPolicy(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("expected error for missing policy")
+	}
+}
@@ -0,0 +1,186 @@
+package conditional
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Middleware : adds the Cache-Control to the successful GET responses below the prefixes and answers 304 when the
+// client has the response already, a response is validated with a weak ETag of its body and a single resource also
+// with the newest updatedAt in it as Last-Modified; a listing gets no Last-Modified as removing an item does not move it
+func Middleware(cacheControl string, prefixes ...string) func(http.Handler) http.Handler {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || !below(r.URL.Path, prefixes) {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			rec := &recorder{header: http.Header{}, status: http.StatusOK}
+			inner.ServeHTTP(rec, r)
+
+			for key, values := range rec.header {
+				w.Header()[key] = values
+			}
+
+			if rec.status != http.StatusOK {
+				w.WriteHeader(rec.status)
+				_, _ = w.Write(rec.body.Bytes())
+
+				return
+			}
+
+			w.Header().Set("Cache-Control", cacheControl)
+
+			etag, lastModified := validators(rec.body.Bytes(), r.Header.Get("Accept"))
+			if etag != "" {
+				w.Header().Set("ETag", etag)
+			}
+
+			if !lastModified.IsZero() {
+				w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+			}
+
+			if notModified(r, etag, lastModified) {
+				w.Header().Del("Content-Type")
+				w.Header().Del("Content-Length")
+				w.WriteHeader(http.StatusNotModified)
+
+				return
+			}
+
+			w.WriteHeader(rec.status)
+			_, _ = w.Write(rec.body.Bytes())
+		})
+	}
+}
+
+// validators : the weak ETag of a response with data and the Last-Modified of a single resource read from the data,
+// the ETag covers the Accept header as the body is re-encoded for it afterwards
+func validators(body []byte, accept string) (etag string, lastModified time.Time) {
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return "", time.Time{}
+	}
+
+	data := bytes.TrimSpace(envelope.Data)
+	if len(data) == 0 {
+		return "", time.Time{}
+	}
+
+	sum := sha256.Sum256(append([]byte(accept+"\n"), body...))
+	etag = `W/"` + hex.EncodeToString(sum[:16]) + `"`
+
+	if data[0] == '[' {
+		return etag, time.Time{}
+	}
+
+	var resource map[string]interface{}
+	if err := json.Unmarshal(data, &resource); err != nil {
+		return etag, time.Time{}
+	}
+
+	// a resource which is not tracked itself, like a past revision, is not validated by the resources it embeds
+	if _, ok := resource["updatedAt"]; !ok {
+		return etag, time.Time{}
+	}
+
+	return etag, newest(resource)
+}
+
+// newest : the latest updatedAt of the value and of the resources embedded in it
+func newest(value interface{}) time.Time {
+	var latest time.Time
+
+	later := func(t time.Time) {
+		if t.After(latest) {
+			latest = t
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if s, ok := field.(string); ok && key == "updatedAt" {
+				if t, err := time.Parse(time.RFC3339, s); err == nil {
+					later(t)
+				}
+
+				continue
+			}
+
+			later(newest(field))
+		}
+	case []interface{}:
+		for _, item := range v {
+			later(newest(item))
+		}
+	}
+
+	return latest
+}
+
+// notModified : evaluates If-None-Match, or If-Modified-Since when it is not sent, against the validators of the
+// response
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if match := strings.Join(r.Header.Values("If-None-Match"), ","); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || etag != "" && opaque(candidate) == opaque(etag) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || lastModified.IsZero() {
+		return false
+	}
+
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// opaque : the entity tag without its weak indicator, GET compares the tags weakly
+func opaque(tag string) string {
+	return strings.TrimPrefix(tag, "W/")
+}
+
+// below : whether the path is one of the prefixes or below one of them
+func below(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// recorder : keeps the response of the handler so its validators can be computed before it is sent
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *recorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	rec.status = status
+}
+
+func (rec *recorder) Write(p []byte) (int, error) {
+	return rec.body.Write(p)
+}
@@ -0,0 +1,109 @@
+package conditional
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestMiddleware : test the caching headers of the responses and the requests answered with 304
+func TestMiddleware(t *testing.T) {
+	list := `{"data":[{"bookID":1,"title":"Dune","updatedAt":"2022-05-01T10:00:00Z"}]}`
+	single := `{"data":{"bookID":1,"title":"Dune","updatedAt":"2022-05-01T10:00:00Z",` +
+		`"Author":{"authorID":2,"updatedAt":"2022-05-03T08:30:00Z"}}}`
+	revision := `{"data":{"bookID":2,"Author":{"updatedAt":"2022-05-03T08:30:00Z"}}}`
+
+	handler := Middleware("private, max-age=60", "/book")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/book":
+			_, _ = w.Write([]byte(list))
+		case "/book/1":
+			_, _ = w.Write([]byte(single))
+		case "/book/2":
+			_, _ = w.Write([]byte(revision))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+
+	// the tags are read from a first response
+	tag := func(target, accept string) string {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		r.Header.Set("Accept", accept)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		return w.Header().Get("ETag")
+	}
+
+	etag, csvTag, resourceTag, revisionTag := tag("/book", ""), tag("/book", "text/csv"), tag("/book/1", ""),
+		tag("/book/2", "")
+	if len(etag) != 36 || etag[:3] != `W/"` || csvTag == etag || len(resourceTag) != 36 || resourceTag == etag {
+		t.Fatalf("unexpected ETags %v %v %v", etag, csvTag, resourceTag)
+	}
+
+	lastModified := "Tue, 03 May 2022 08:30:00 GMT"
+
+	testcases := []struct {
+		desc   string
+		method string
+		target string
+		header map[string]string
+
+		expectedStatus       int
+		expectedCacheControl string
+		expectedETag         string
+		expectedLastModified string
+		expectedBody         string
+	}{
+		{"listing", http.MethodGet, "/book", nil, http.StatusOK, "private, max-age=60", etag, "", list},
+		{"listing not modified", http.MethodGet, "/book", map[string]string{"If-None-Match": `"x", ` + etag},
+			http.StatusNotModified, "private, max-age=60", etag, "", ""},
+		{"listing matched strongly", http.MethodGet, "/book", map[string]string{"If-None-Match": etag[2:]},
+			http.StatusNotModified, "private, max-age=60", etag, "", ""},
+		{"listing in another media type", http.MethodGet, "/book",
+			map[string]string{"If-None-Match": etag, "Accept": "text/csv"}, http.StatusOK, "private, max-age=60", csvTag,
+			"", list},
+		{"listing modified since", http.MethodGet, "/book",
+			map[string]string{"If-Modified-Since": "Sun, 01 May 2022 10:00:00 GMT"}, http.StatusOK, "private, max-age=60",
+			etag, "", list},
+		{"resource", http.MethodGet, "/book/1", nil, http.StatusOK, "private, max-age=60", resourceTag, lastModified,
+			single},
+		{"resource not modified", http.MethodGet, "/book/1", map[string]string{"If-Modified-Since": lastModified},
+			http.StatusNotModified, "private, max-age=60", resourceTag, lastModified, ""},
+		{"resource modified", http.MethodGet, "/book/1",
+			map[string]string{"If-Modified-Since": "Mon, 02 May 2022 00:00:00 GMT"}, http.StatusOK,
+			"private, max-age=60", resourceTag, lastModified, single},
+		{"resource matched", http.MethodGet, "/book/1", map[string]string{"If-None-Match": resourceTag},
+			http.StatusNotModified, "private, max-age=60", resourceTag, lastModified, ""},
+		{"If-None-Match wins", http.MethodGet, "/book/1", map[string]string{"If-None-Match": etag,
+			"If-Modified-Since": lastModified}, http.StatusOK, "private, max-age=60", resourceTag, lastModified, single},
+		{"resource not tracked", http.MethodGet, "/book/2", map[string]string{"If-Modified-Since": lastModified},
+			http.StatusOK, "private, max-age=60", revisionTag, "", revision},
+		{"error", http.MethodGet, "/book/3", nil, http.StatusNotFound, "", "", "", `{"errors":[]}`},
+		{"other methods", http.MethodPut, "/book/1", nil, http.StatusOK, "", "", "", single},
+		{"other paths", http.MethodGet, "/author", nil, http.StatusNotFound, "", "", "", `{"errors":[]}`},
+	}
+
+	for _, tc := range testcases {
+		r := httptest.NewRequest(tc.method, tc.target, nil)
+		for key, value := range tc.header {
+			r.Header.Set(key, value)
+		}
+
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, r)
+
+		if w.Code != tc.expectedStatus || w.Header().Get("Cache-Control") != tc.expectedCacheControl ||
+			w.Header().Get("ETag") != tc.expectedETag || w.Header().Get("Last-Modified") != tc.expectedLastModified ||
+			w.Body.String() != tc.expectedBody {
+			t.Errorf("failed for %v, got %v %q %q %q %v", tc.desc, w.Code, w.Header().Get("Cache-Control"),
+				w.Header().Get("ETag"), w.Header().Get("Last-Modified"), w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+	"projects/GoLang-Interns-2022/authorbook/service"
+	"projects/GoLang-Interns-2022/authorbook/service/idempotencyservice"
+)
+
+// Header : the request header carrying the key a client picks for a request it may retry
+const Header = "Idempotency-Key"
+
+// ReplayedHeader : set on a response which was recorded for an earlier request with the key
+const ReplayedHeader = "Idempotent-Replayed"
+
+// maxKeyLength : the longest key the store keeps
+const maxKeyLength = 255
+
+// Middleware : runs the POST requests to the paths which carry an Idempotency-Key once per caller and key, a retry
+// gets the response of the first request, a reuse of the key with another body gets 422 and a retry while the first
+// request still runs gets 409. Requests without the header are passed on untouched
+func Middleware(s service.IdempotencyService, paths ...string) func(http.Handler) http.Handler {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(Header)
+			if key == "" || r.Method != http.MethodPost || !contains(paths, r.URL.Path) {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > maxKeyLength {
+				auth.WriteError(w, http.StatusBadRequest, "Invalid Parameter",
+					"the idempotency key is longer than "+strconv.Itoa(maxKeyLength)+" characters")
+
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				auth.WriteError(w, http.StatusBadRequest, "Bad Request", err.Error())
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			request := entities.IdempotentRequest{Scope: auth.Actor(r.Context()), Key: key,
+				Fingerprint: fingerprint(r, body)}
+
+			replay, err := s.Begin(r.Context(), request)
+
+			switch {
+			case err == idempotencyservice.ErrKeyReused:
+				auth.WriteError(w, http.StatusUnprocessableEntity, "Unprocessable Entity", err.Error())
+			case err == idempotencyservice.ErrInProgress:
+				w.Header().Set("Retry-After", "1")
+				auth.WriteError(w, http.StatusConflict, "Conflict", err.Error())
+			case err != nil:
+				auth.WriteError(w, http.StatusInternalServerError, "Internal Server Error", err.Error())
+			case replay != nil:
+				if replay.ContentType != "" {
+					w.Header().Set("Content-Type", replay.ContentType)
+				}
+
+				w.Header().Set(ReplayedHeader, "true")
+				w.WriteHeader(replay.Status)
+				_, _ = w.Write(replay.Body)
+			default:
+				serve(w, r, inner, s, request)
+			}
+		})
+	}
+}
+
+// serve : runs the request and records its response, a panicking handler frees the key like a server error
+func serve(w http.ResponseWriter, r *http.Request, inner http.Handler, s service.IdempotencyService,
+	request entities.IdempotentRequest) {
+	rec := &recorder{header: w.Header(), status: http.StatusInternalServerError}
+
+	defer func() {
+		request.Status = rec.status
+		request.ContentType = rec.header.Get("Content-Type")
+		request.Body = rec.body.Bytes()
+
+		// the response is recorded even when the client is gone, that is when it retries
+		if err := s.Finish(context.Background(), request); err != nil {
+			log.Printf("idempotency key %v not recorded: %v", request.Key, err)
+		}
+	}()
+
+	inner.ServeHTTP(rec, r)
+
+	if !rec.wroteHeader {
+		rec.status = http.StatusOK
+	}
+
+	w.WriteHeader(rec.status)
+	_, _ = w.Write(rec.body.Bytes())
+}
+
+// fingerprint : identifies the request by its method, path and body
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	_, _ = io.WriteString(h, r.Method+" "+r.URL.Path+"\n")
+	_, _ = h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func contains(paths []string, path string) bool {
+	for _, p := range paths {
+		if p == path {
+			return true
+		}
+	}
+
+	return false
+}
+
+// recorder : keeps the response of the handler so it can be recorded before it is sent
+type recorder struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *recorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status = status
+		rec.wroteHeader = true
+	}
+}
+
+func (rec *recorder) Write(p []byte) (int, error) {
+	if !rec.wroteHeader {
+		rec.WriteHeader(http.StatusOK)
+	}
+
+	return rec.body.Write(p)
+}
@@ -0,0 +1,146 @@
+package idempotency
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+	"projects/GoLang-Interns-2022/authorbook/service"
+	"projects/GoLang-Interns-2022/authorbook/service/idempotencyservice"
+)
+
+// TestMiddleware : test that the first request is run and recorded and the later ones with the key are answered
+// from the record
+func TestMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockIdempotencyService(ctrl)
+
+	body := `{"title":"Dune"}`
+	request := entities.IdempotentRequest{Scope: "asha", Key: "k1",
+		Fingerprint: fingerprint(httptest.NewRequest(http.MethodPost, "/book", nil), []byte(body))}
+	answered := entities.IdempotentRequest{Scope: "asha", Key: "k1", Fingerprint: request.Fingerprint,
+		Status: http.StatusCreated, ContentType: "application/json", Body: []byte(`{"bookID":3}`)}
+
+	runs := 0
+	handler := Middleware(mockService, "/book", "/author")(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+		runs++
+
+		if got, _ := io.ReadAll(r.Body); string(got) != body {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"bookID":3}`))
+	}))
+
+	testcases := []struct {
+		desc   string
+		method string
+		target string
+		key    string
+		expect func()
+
+		expectedStatus   int
+		expectedBody     string
+		expectedReplayed string
+		expectedRuns     int
+	}{
+		{desc: "no key", method: http.MethodPost, target: "/book", expectedStatus: http.StatusCreated,
+			expectedBody: `{"bookID":3}`, expectedRuns: 1},
+		{desc: "other path", method: http.MethodPost, target: "/genre", key: "k1", expectedStatus: http.StatusCreated,
+			expectedBody: `{"bookID":3}`, expectedRuns: 1},
+		{desc: "first request", method: http.MethodPost, target: "/book", key: "k1", expect: func() {
+			mockService.EXPECT().Begin(gomock.Any(), request).Return(nil, nil)
+			mockService.EXPECT().Finish(gomock.Any(), answered).Return(nil)
+		}, expectedStatus: http.StatusCreated, expectedBody: `{"bookID":3}`, expectedRuns: 1},
+		{desc: "retry", method: http.MethodPost, target: "/book", key: "k1", expect: func() {
+			mockService.EXPECT().Begin(gomock.Any(), request).Return(&answered, nil)
+		}, expectedStatus: http.StatusCreated, expectedBody: `{"bookID":3}`, expectedReplayed: "true"},
+		{desc: "other body", method: http.MethodPost, target: "/book", key: "k1", expect: func() {
+			mockService.EXPECT().Begin(gomock.Any(), request).Return(nil, idempotencyservice.ErrKeyReused)
+		}, expectedStatus: http.StatusUnprocessableEntity, expectedBody: `{"errors":[{"code":"Unprocessable Entity",` +
+			`"reason":"idempotency key reused with a different request"}]}` + "\n"},
+		{desc: "in progress", method: http.MethodPost, target: "/book", key: "k1", expect: func() {
+			mockService.EXPECT().Begin(gomock.Any(), request).Return(nil, idempotencyservice.ErrInProgress)
+		}, expectedStatus: http.StatusConflict, expectedBody: `{"errors":[{"code":"Conflict",` +
+			`"reason":"a request with the idempotency key is in progress"}]}` + "\n"},
+		{desc: "key too long", method: http.MethodPost, target: "/book", key: strings.Repeat("k", 256),
+			expectedStatus: http.StatusBadRequest, expectedBody: `{"errors":[{"code":"Invalid Parameter",` +
+				`"reason":"the idempotency key is longer than 255 characters"}]}` + "\n"},
+	}
+
+	for _, tc := range testcases {
+		if tc.expect != nil {
+			tc.expect()
+		}
+
+		runs = 0
+
+		r := httptest.NewRequest(tc.method, tc.target, strings.NewReader(body))
+		r = r.WithContext(auth.NewContext(r.Context(), auth.Identity{Subject: "asha"}))
+
+		if tc.key != "" {
+			r.Header.Set(Header, tc.key)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != tc.expectedStatus || w.Body.String() != tc.expectedBody ||
+			w.Header().Get(ReplayedHeader) != tc.expectedReplayed || runs != tc.expectedRuns {
+			t.Errorf("%v: unexpected response %v %q %v after %v runs", tc.desc, w.Code, w.Body.String(), w.Header(),
+				runs)
+		}
+	}
+}
+
+// TestMiddlewareServerError : test that a failed or panicking handler is finished with a server error so the key
+// is freed
+func TestMiddlewareServerError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := service.NewMockIdempotencyService(ctrl)
+
+	for _, panics := range []bool{false, true} {
+		panics := panics
+
+		handler := Middleware(mockService, "/author")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if panics {
+				panic("boom")
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+
+		mockService.EXPECT().Begin(gomock.Any(), gomock.Any()).Return(nil, nil)
+		mockService.EXPECT().Finish(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ interface{}, request entities.IdempotentRequest) error {
+				if request.Status != http.StatusInternalServerError {
+					t.Errorf("finished with %v", request.Status)
+				}
+
+				return nil
+			})
+
+		r := httptest.NewRequest(http.MethodPost, "/author", strings.NewReader(`{}`))
+		r.Header.Set(Header, "k1")
+
+		func() {
+			defer func() {
+				if recovered := recover(); (recovered != nil) != panics {
+					t.Errorf("unexpected panic %v", recovered)
+				}
+			}()
+
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+		}()
+	}
+}
@@ -0,0 +1,151 @@
+package negotiate
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+)
+
+// encodeCSV : writes a decoded document as rows, the data or errors of the response envelope are unwrapped.
+// Nested objects become columns named by their path like Author.firstName, lists of scalars are joined with ";"
+// and lists of objects are kept as JSON
+func encodeCSV(value interface{}) ([]byte, error) {
+	if o, ok := value.(object); ok {
+		if data, ok := o.get("data"); ok {
+			value = data
+		} else if errs, ok := o.get("errors"); ok {
+			value = errs
+		}
+	}
+
+	var items []interface{}
+
+	switch v := value.(type) {
+	case []interface{}:
+		items = v
+	case nil:
+	default:
+		items = []interface{}{v}
+	}
+
+	var (
+		columns []string
+		index   = map[string]int{}
+		rows    = make([]map[string]string, 0, len(items))
+	)
+
+	for _, item := range items {
+		row := map[string]string{}
+
+		o, ok := item.(object)
+		if !ok {
+			o = object{{"value", item}}
+		}
+
+		flatten(row, "", o)
+
+		for _, m := range columnsOf("", o) {
+			if _, ok := index[m]; !ok {
+				index[m] = len(columns)
+				columns = append(columns, m)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+
+	if len(columns) > 0 {
+		if err := w.Write(columns); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, c := range columns {
+			record[i] = row[c]
+		}
+
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
+
+// flatten : collects the cells of an object, nested objects are named by their path
+func flatten(row map[string]string, prefix string, o object) {
+	for _, m := range o {
+		switch v := m.value.(type) {
+		case object:
+			flatten(row, prefix+m.key+".", v)
+		case []interface{}:
+			row[prefix+m.key] = cell(v)
+		default:
+			row[prefix+m.key] = scalar(v)
+		}
+	}
+}
+
+// columnsOf : the column names of an object in the order of its keys
+func columnsOf(prefix string, o object) []string {
+	var columns []string
+
+	for _, m := range o {
+		if nested, ok := m.value.(object); ok {
+			columns = append(columns, columnsOf(prefix+m.key+".", nested)...)
+			continue
+		}
+
+		columns = append(columns, prefix+m.key)
+	}
+
+	return columns
+}
+
+// cell : a list in a single cell
+func cell(list []interface{}) string {
+	parts := make([]string, len(list))
+
+	for i, item := range list {
+		switch item.(type) {
+		case object, []interface{}:
+			data, _ := json.Marshal(plain(list))
+			return string(data)
+		}
+
+		parts[i] = scalar(item)
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// plain : converts ordered objects back into values encoding/json can write, the key order is lost
+func plain(value interface{}) interface{} {
+	switch v := value.(type) {
+	case object:
+		m := make(map[string]interface{}, len(v))
+		for _, member := range v {
+			m[member.key] = plain(member.value)
+		}
+
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i := range v {
+			list[i] = plain(v[i])
+		}
+
+		return list
+	}
+
+	return value
+}
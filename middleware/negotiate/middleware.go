@@ -0,0 +1,140 @@
+package negotiate
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+)
+
+// the media types the handlers can answer in, JSON first as it is the default
+const (
+	mediaJSON    = "application/json"
+	mediaXML     = "application/xml"
+	mediaTextXML = "text/xml"
+	mediaCSV     = "text/csv"
+	mediaMsgpack = "application/msgpack"
+)
+
+var errNotJSON = errors.New("not a JSON response")
+
+// offered : the media types of the responses, the last two are the older names of MessagePack
+var offered = []string{mediaJSON, mediaXML, mediaTextXML, mediaCSV, mediaMsgpack, "application/x-msgpack",
+	"application/vnd.msgpack"}
+
+// Middleware : answers the requests below the prefixes in the media type their Accept header prefers, the JSON
+// of the handlers is re-encoded as XML, CSV or MessagePack and requests accepting none of them get 406
+func Middleware(prefixes ...string) func(http.Handler) http.Handler {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !below(r.URL.Path, prefixes) {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Accept")
+
+			mediaType, ok := Select(r.Header.Get("Accept"), offered)
+			if !ok {
+				auth.WriteError(w, http.StatusNotAcceptable, "Not Acceptable",
+					"the response can be sent as "+strings.Join(offered, ", "))
+
+				return
+			}
+
+			if mediaType == mediaJSON {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			rec := &recorder{header: http.Header{}, status: http.StatusOK}
+			inner.ServeHTTP(rec, r)
+
+			for key, values := range rec.header {
+				w.Header()[key] = values
+			}
+
+			body, err := transcode(rec, mediaType)
+			if err != nil {
+				// the handler did not answer in JSON, it is passed on as it is
+				log.Printf("response of %v %v not re-encoded: %v", r.Method, r.URL.Path, err)
+				w.WriteHeader(rec.status)
+				_, _ = w.Write(rec.body.Bytes())
+
+				return
+			}
+
+			contentType := mediaType
+			if strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "xml") {
+				contentType += "; charset=utf-8"
+			}
+
+			w.Header().Set("Content-Type", contentType)
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			w.WriteHeader(rec.status)
+			_, _ = w.Write(body)
+		})
+	}
+}
+
+// transcode : re-encodes the JSON body of a response
+func transcode(rec *recorder, mediaType string) ([]byte, error) {
+	if contentType := rec.header.Get("Content-Type"); contentType != "" {
+		if parsed, _, err := mime.ParseMediaType(contentType); err != nil || parsed != mediaJSON {
+			return nil, errNotJSON
+		}
+	}
+
+	if rec.body.Len() == 0 {
+		return nil, errNotJSON
+	}
+
+	value, err := decode(rec.body.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	switch mediaType {
+	case mediaXML, mediaTextXML:
+		return encodeXML(value), nil
+	case mediaCSV:
+		return encodeCSV(value)
+	}
+
+	return encodeMsgpack(value), nil
+}
+
+// below : whether the path is one of the prefixes or below one of them
+func below(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// recorder : keeps the response of the handler so it can be re-encoded
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *recorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	rec.status = status
+}
+
+func (rec *recorder) Write(p []byte) (int, error) {
+	return rec.body.Write(p)
+}
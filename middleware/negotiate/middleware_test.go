@@ -0,0 +1,129 @@
+package negotiate
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestMiddleware : test the media types the responses are encoded in
+func TestMiddleware(t *testing.T) {
+	body := `{"data":[{"bookID":1,"title":"Tom & Jerry","Author":{"firstName":"Ruskin","DOB":null},` +
+		`"tags":["classic","kids"],"rating":4.5}]}`
+
+	handler := Middleware("/book", "/author")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/author/plain" {
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("pong"))
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(body))
+	}))
+
+	testcases := []struct {
+		desc   string
+		target string
+		accept string
+
+		expectedStatus int
+		expectedType   string
+		expectedBody   string
+	}{
+		{"json by default", "/book", "", http.StatusCreated, "application/json", body},
+		{"xml", "/book", "application/xml", http.StatusCreated, "application/xml; charset=utf-8",
+			`<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<response><data><item><bookID>1</bookID>` +
+				`<title>Tom &amp; Jerry</title><Author><firstName>Ruskin</firstName><DOB/></Author>` +
+				`<tags><item>classic</item><item>kids</item></tags><rating>4.5</rating></item></data></response>`},
+		{"csv", "/book/1", "text/csv, application/json;q=0.5", http.StatusCreated, "text/csv; charset=utf-8",
+			"bookID,title,Author.firstName,Author.DOB,tags,rating\n1,Tom & Jerry,Ruskin,,classic;kids,4.5\n"},
+		{"msgpack", "/author", "application/x-msgpack", http.StatusCreated, "application/x-msgpack",
+			"\x81\xa4data\x91\x85\xa6bookID\x01\xa5title\xabTom & Jerry\xa6Author\x82\xa9firstName\xa6Ruskin" +
+				"\xa3DOB\xc0\xa4tags\x92\xa7classic\xa4kids\xa6rating\xcb\x40\x12\x00\x00\x00\x00\x00\x00"},
+		{"not acceptable", "/book", "application/pdf", http.StatusNotAcceptable, "application/json",
+			`{"errors":[{"code":"Not Acceptable","reason":"the response can be sent as application/json, ` +
+				`application/xml, text/xml, text/csv, application/msgpack, application/x-msgpack, ` +
+				`application/vnd.msgpack"}]}` + "\n"},
+		{"other paths", "/bookmarks", "application/pdf", http.StatusCreated, "application/json", body},
+		{"not json", "/author/plain", "text/csv", http.StatusOK, "text/plain", "pong"},
+	}
+
+	for _, tc := range testcases {
+		r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+		r.Header.Set("Accept", tc.accept)
+
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, r)
+
+		if w.Code != tc.expectedStatus || w.Header().Get("Content-Type") != tc.expectedType ||
+			w.Body.String() != tc.expectedBody {
+			t.Errorf("failed for %v, got %v %v %q", tc.desc, w.Code, w.Header().Get("Content-Type"), w.Body.String())
+		}
+	}
+}
+
+// TestEncodeMsgpack : test the integer and length forms of MessagePack
+func TestEncodeMsgpack(t *testing.T) {
+	long := make([]byte, 40)
+	for i := range long {
+		long[i] = 'a'
+	}
+
+	testcases := []struct {
+		json     string
+		expected string
+	}{
+		{`-1`, "\xff"},
+		{`-100`, "\xd0\x9c"},
+		{`200`, "\xcc\xc8"},
+		{`70000`, "\xce\x00\x01\x11\x70"},
+		{`-40000`, "\xd2\xff\xff\x63\xc0"},
+		{`true`, "\xc3"},
+		{`"` + string(long) + `"`, "\xd9\x28" + string(long)},
+		{`[1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16]`, "\xdc\x00\x10\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0a\x0b\x0c" +
+			"\x0d\x0e\x0f\x10"},
+	}
+
+	for _, tc := range testcases {
+		value, err := decode([]byte(tc.json))
+		if err != nil {
+			t.Fatalf("%v: %v", tc.json, err)
+		}
+
+		if got := string(encodeMsgpack(value)); got != tc.expected {
+			t.Errorf("%v: expected %q got %q", tc.json, tc.expected, got)
+		}
+	}
+}
+
+// TestEncodeCSV : test the rows of single objects and errors
+func TestEncodeCSV(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		json     string
+		expected string
+	}{
+		{"single object", `{"data":{"authorID":1,"firstName":"Ruskin"}}`, "authorID,firstName\n1,Ruskin\n"},
+		{"errors", `{"errors":[{"code":"Not Found","reason":"book does not exist"}]}`,
+			"code,reason\nNot Found,book does not exist\n"},
+		{"lists of objects", `{"data":[{"bookID":1,"genres":[{"genreID":2}]},{"bookID":2,"extra":true}]}`,
+			"bookID,genres,extra\n1,\"[{\"\"genreID\"\":2}]\",\n2,,true\n"},
+		{"empty list", `{"data":[]}`, ""},
+	}
+
+	for _, tc := range testcases {
+		value, err := decode([]byte(tc.json))
+		if err != nil {
+			t.Fatalf("%v: %v", tc.desc, err)
+		}
+
+		got, err := encodeCSV(value)
+		if err != nil || string(got) != tc.expected {
+			t.Errorf("failed for %v, got %q %v", tc.desc, got, err)
+		}
+	}
+}
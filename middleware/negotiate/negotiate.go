@@ -0,0 +1,93 @@
+package negotiate
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// mediaRange : an entry of an Accept header
+type mediaRange struct {
+	typ     string
+	subtype string
+	q       float64
+}
+
+// Select : gives the offered media type the Accept header prefers, offers earlier in the list win ties so the first
+// one is the default for a missing header or */*; false when the header accepts none of the offers
+func Select(accept string, offered []string) (string, bool) {
+	if len(offered) == 0 {
+		return "", false
+	}
+
+	if strings.TrimSpace(accept) == "" {
+		return offered[0], true
+	}
+
+	ranges := parse(accept)
+
+	best, bestQ := "", 0.0
+
+	for _, offer := range offered {
+		if q := quality(ranges, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best, bestQ > 0
+}
+
+// parse : reads the media ranges of an Accept header, malformed entries are left out
+func parse(accept string) []mediaRange {
+	var ranges []mediaRange
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		typ, subtype, ok := strings.Cut(mediaType, "/")
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+
+		ranges = append(ranges, mediaRange{typ, subtype, q})
+	}
+
+	return ranges
+}
+
+// quality : the q value of the most specific range matching the offer, 0 when no range matches
+func quality(ranges []mediaRange, offer string) float64 {
+	typ, subtype, _ := strings.Cut(offer, "/")
+
+	q, specificity := 0.0, -1
+
+	for _, r := range ranges {
+		s := -1
+
+		switch {
+		case r.typ == typ && r.subtype == subtype:
+			s = 2
+		case r.typ == typ && r.subtype == "*":
+			s = 1
+		case r.typ == "*" && r.subtype == "*":
+			s = 0
+		}
+
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+
+	return q
+}
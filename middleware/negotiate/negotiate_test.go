@@ -0,0 +1,35 @@
+package negotiate
+
+import "testing"
+
+// TestSelect : test the preference of the Accept header over the offered media types
+func TestSelect(t *testing.T) {
+	offered := []string{"application/json", "application/xml", "text/csv"}
+
+	testcases := []struct {
+		desc   string
+		accept string
+
+		expected   string
+		expectedOK bool
+	}{
+		{"missing header", "", "application/json", true},
+		{"anything", "*/*", "application/json", true},
+		{"exact", "text/csv", "text/csv", true},
+		{"quality", "application/json;q=0.5, application/xml", "application/xml", true},
+		{"type wildcard", "text/*", "text/csv", true},
+		{"specific range wins over wildcard", "*/*;q=0.9, application/json;q=0.1", "application/xml", true},
+		{"excluded", "application/json;q=0, */*;q=0.1", "application/xml", true},
+		{"parameters", "text/csv; charset=utf-8", "text/csv", true},
+		{"no match", "application/pdf", "", false},
+		{"malformed", "text", "", false},
+	}
+
+	for _, tc := range testcases {
+		got, ok := Select(tc.accept, offered)
+
+		if got != tc.expected || ok != tc.expectedOK {
+			t.Errorf("failed for %v, expected %v %v got %v %v", tc.desc, tc.expected, tc.expectedOK, got, ok)
+		}
+	}
+}
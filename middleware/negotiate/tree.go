@@ -0,0 +1,92 @@
+package negotiate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// member : a key of a JSON object with its value
+type member struct {
+	key   string
+	value interface{}
+}
+
+// object : a JSON object keeping the order of its keys, the encoders write the fields as the handlers declared them
+type object []member
+
+// get : the value of a key, false when the object does not have it
+func (o object) get(key string) (interface{}, bool) {
+	for _, m := range o {
+		if m.key == key {
+			return m.value, true
+		}
+	}
+
+	return nil, false
+}
+
+// decode : reads a JSON document into nil, bool, json.Number, string, []interface{} and object values
+func decode(data []byte) (interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	value, err := decodeValue(d)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = d.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("trailing data after the JSON document")
+	}
+
+	return value, nil
+}
+
+func decodeValue(d *json.Decoder) (interface{}, error) {
+	token, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		o := object{}
+
+		for d.More() {
+			key, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := decodeValue(d)
+			if err != nil {
+				return nil, err
+			}
+
+			o = append(o, member{key.(string), value})
+		}
+
+		_, err = d.Token()
+
+		return o, err
+	case json.Delim('['):
+		list := []interface{}{}
+
+		for d.More() {
+			value, err := decodeValue(d)
+			if err != nil {
+				return nil, err
+			}
+
+			list = append(list, value)
+		}
+
+		_, err = d.Token()
+
+		return list, err
+	}
+
+	return token, nil
+}
@@ -0,0 +1,89 @@
+package negotiate
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"unicode"
+)
+
+// encodeXML : writes a decoded document below a response element, object keys become elements and the entries
+// of lists are item elements
+func encodeXML(value interface{}) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString(xml.Header)
+	writeXML(&buf, "response", value)
+
+	return buf.Bytes()
+}
+
+func writeXML(buf *bytes.Buffer, name string, value interface{}) {
+	name = elementName(name)
+
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("<" + name + "/>")
+	case object:
+		buf.WriteString("<" + name + ">")
+
+		for _, m := range v {
+			writeXML(buf, m.key, m.value)
+		}
+
+		buf.WriteString("</" + name + ">")
+	case []interface{}:
+		buf.WriteString("<" + name + ">")
+
+		for _, item := range v {
+			writeXML(buf, "item", item)
+		}
+
+		buf.WriteString("</" + name + ">")
+	default:
+		buf.WriteString("<" + name + ">")
+		_ = xml.EscapeText(buf, []byte(scalar(v)))
+		buf.WriteString("</" + name + ">")
+	}
+}
+
+// elementName : a JSON key as an XML name, characters a name can not hold become underscores
+func elementName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.' {
+			return r
+		}
+
+		return '_'
+	}, key)
+
+	if name == "" || !(unicode.IsLetter(rune(name[0])) || name[0] == '_') ||
+		strings.HasPrefix(strings.ToLower(name), "xml") {
+		name = "_" + name
+	}
+
+	return name
+}
+
+// scalar : the text of a JSON scalar
+func scalar(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case bool:
+		if v {
+			return "true"
+		}
+
+		return "false"
+	case json.Number:
+		return v.String()
+	case string:
+		return v
+	}
+
+	data, _ := json.Marshal(value)
+
+	return string(data)
+}
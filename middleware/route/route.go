@@ -0,0 +1,80 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/middleware/negotiate"
+)
+
+type paramsKey struct{}
+
+// Handle : serves the requests for method and path with h instead of passing them on to the router, for endpoints
+// which write their response themselves like streams; path parameters are written as {name} and read with Param.
+// It has to run after the authentication and authorization middleware so those still apply
+func Handle(method, path string, h http.Handler) func(http.Handler) http.Handler {
+	pattern := strings.Split(strings.Trim(path, "/"), "/")
+
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == method {
+				if params, ok := match(pattern, r.URL.Path); ok {
+					h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), paramsKey{}, params)))
+					return
+				}
+			}
+
+			inner.ServeHTTP(w, r)
+		})
+	}
+}
+
+// HandleAccept : like Handle but only serves the requests whose Accept header prefers mediaType over JSON, the
+// others are passed on so the router still answers them
+func HandleAccept(method, path, mediaType string, h http.Handler) func(http.Handler) http.Handler {
+	offered := []string{"application/json", mediaType}
+
+	return func(inner http.Handler) http.Handler {
+		handler := Handle(method, path, h)(inner)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if selected, ok := negotiate.Select(r.Header.Get("Accept"), offered); ok && selected == mediaType {
+				handler.ServeHTTP(w, r)
+				return
+			}
+
+			inner.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Param : gives the value of a path parameter of the route serving the request
+func Param(r *http.Request, name string) string {
+	params, _ := r.Context().Value(paramsKey{}).(map[string]string)
+
+	return params[name]
+}
+
+// match : matches a path against a pattern segment by segment and collects the parameters
+func match(pattern []string, path string) (map[string]string, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != len(pattern) {
+		return nil, false
+	}
+
+	params := map[string]string{}
+
+	for i, segment := range pattern {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params[segment[1:len(segment)-1]] = parts[i]
+			continue
+		}
+
+		if segment != parts[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
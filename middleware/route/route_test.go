@@ -0,0 +1,90 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestHandle : test that only the method and path of the route reach its handler
+func TestHandle(t *testing.T) {
+	mounted := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTeapot) })
+	router := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	handler := Handle(http.MethodGet, "/export", mounted)(router)
+
+	testcases := []struct {
+		desc   string
+		method string
+		target string
+
+		expected int
+	}{
+		{"route", http.MethodGet, "/export?entity=book", http.StatusTeapot},
+		{"trailing slash", http.MethodGet, "/export/", http.StatusTeapot},
+		{"other method", http.MethodPost, "/export", http.StatusOK},
+		{"other path", http.MethodGet, "/export/1", http.StatusOK},
+	}
+
+	for _, tc := range testcases {
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, httptest.NewRequest(tc.method, tc.target, nil))
+
+		if w.Code != tc.expected {
+			t.Errorf("failed for %v, expected %v got %v", tc.desc, tc.expected, w.Code)
+		}
+	}
+}
+
+// TestParam : test that the path parameters of the route reach its handler
+func TestParam(t *testing.T) {
+	var id string
+
+	mounted := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { id = Param(r, "id") })
+	router := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	handler := Handle(http.MethodGet, "/book/{id}/export", mounted)(router)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/book/7/export", nil))
+
+	if id != "7" {
+		t.Errorf("expected id 7 got %q", id)
+	}
+
+	if Param(httptest.NewRequest(http.MethodGet, "/book/7", nil), "id") != "" {
+		t.Errorf("a request outside a route has no parameters")
+	}
+}
+
+// TestHandleAccept : test that only the requests preferring the media type reach the handler
+func TestHandleAccept(t *testing.T) {
+	mounted := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTeapot) })
+	router := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	handler := HandleAccept(http.MethodGet, "/book", "application/x-ndjson", mounted)(router)
+
+	testcases := []struct {
+		desc   string
+		method string
+		accept string
+
+		expected int
+	}{
+		{"ndjson", http.MethodGet, "application/x-ndjson", http.StatusTeapot},
+		{"ndjson preferred", http.MethodGet, "application/json;q=0.5, application/x-ndjson", http.StatusTeapot},
+		{"json preferred", http.MethodGet, "application/json, application/x-ndjson;q=0.5", http.StatusOK},
+		{"no accept", http.MethodGet, "", http.StatusOK},
+		{"any", http.MethodGet, "*/*", http.StatusOK},
+		{"other method", http.MethodPost, "application/x-ndjson", http.StatusOK},
+	}
+
+	for _, tc := range testcases {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(tc.method, "/book", nil)
+		r.Header.Set("Accept", tc.accept)
+
+		handler.ServeHTTP(w, r)
+
+		if w.Code != tc.expected {
+			t.Errorf("failed for %v, expected %v got %v", tc.desc, tc.expected, w.Code)
+		}
+	}
+}
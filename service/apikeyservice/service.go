@@ -0,0 +1,202 @@
+package apikeyservice
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+const keyPrefix = "ak_"
+
+type APIKeyService struct {
+	apiKeyStore store.APIKeyStorer
+}
+
+// New : factory function
+func New(s store.APIKeyStorer) APIKeyService {
+	return APIKeyService{s}
+}
+
+// GetAll : gives every issued API key without its secret
+func (s APIKeyService) GetAll(ctx context.Context) ([]entities.APIKey, error) {
+	keys, err := s.apiKeyStore.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if keys == nil {
+		keys = []entities.APIKey{}
+	}
+
+	return keys, nil
+}
+
+// Post : issues a new API key, the plain key is only part of this response and can not be recovered later
+func (s APIKeyService) Post(ctx context.Context, key entities.APIKey) (entities.APIKey, error) {
+	if !checkKey(&key) {
+		return entities.APIKey{}, errors.New("invalid constraints")
+	}
+
+	prefix, err := random(4)
+	if err != nil {
+		return entities.APIKey{}, err
+	}
+
+	secret, err := random(32)
+	if err != nil {
+		return entities.APIKey{}, err
+	}
+
+	salt, err := random(16)
+	if err != nil {
+		return entities.APIKey{}, err
+	}
+
+	prefix = hex.EncodeToString([]byte(prefix))
+	secret = base64.RawURLEncoding.EncodeToString([]byte(secret))
+	salt = hex.EncodeToString([]byte(salt))
+
+	key.Prefix = prefix
+	key.Salt = salt
+	key.Hash = hash(salt, secret)
+
+	id, err := s.apiKeyStore.Post(ctx, key)
+	if err != nil || id <= 0 {
+		return entities.APIKey{}, errors.New("api key could not be issued")
+	}
+
+	issued, err := s.apiKeyStore.GetByID(ctx, id)
+	if err != nil {
+		return entities.APIKey{}, err
+	}
+
+	issued.Key = keyPrefix + prefix + "." + secret
+
+	return issued, nil
+}
+
+// Put : changes the name, role and scopes of an API key
+func (s APIKeyService) Put(ctx context.Context, key entities.APIKey, id int) (entities.APIKey, error) {
+	if id <= 0 {
+		return entities.APIKey{}, errors.New("invalid id")
+	}
+
+	if !checkKey(&key) {
+		return entities.APIKey{}, errors.New("invalid constraints")
+	}
+
+	ra, err := s.apiKeyStore.Put(ctx, key, id)
+	if err != nil {
+		return entities.APIKey{}, err
+	}
+
+	if ra <= 0 {
+		return entities.APIKey{}, errors.New("api key does not exist")
+	}
+
+	return s.apiKeyStore.GetByID(ctx, id)
+}
+
+// Delete : revokes an API key
+func (s APIKeyService) Delete(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid id")
+	}
+
+	ra, err := s.apiKeyStore.Revoke(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if ra <= 0 {
+		return errors.New("api key does not exist")
+	}
+
+	return nil
+}
+
+// Verify : resolves a plain API key to the identity of its machine client and records its use
+func (s APIKeyService) Verify(ctx context.Context, plain string) (auth.Identity, error) {
+	prefix, secret, ok := strings.Cut(strings.TrimPrefix(plain, keyPrefix), ".")
+	if !ok || !strings.HasPrefix(plain, keyPrefix) {
+		return auth.Identity{}, errors.New("invalid api key")
+	}
+
+	key, err := s.apiKeyStore.GetByPrefix(ctx, prefix)
+	if err != nil || key.RevokedAt != "" {
+		return auth.Identity{}, errors.New("invalid api key")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hash(key.Salt, secret)), []byte(key.Hash)) != 1 {
+		return auth.Identity{}, errors.New("invalid api key")
+	}
+
+	// a failed bookkeeping write should not lock the client out
+	if err = s.apiKeyStore.TouchLastUsed(ctx, key.APIKeyID); err != nil {
+		log.Print(err)
+	}
+
+	return auth.Identity{Subject: "apikey:" + strconv.Itoa(key.APIKeyID), Roles: []string{key.Role},
+		Scopes: key.Scopes}, nil
+}
+
+// checkKey : validates the name, role and scopes of a key, duplicate scopes are dropped
+func checkKey(key *entities.APIKey) bool {
+	key.Name = strings.TrimSpace(key.Name)
+	if key.Name == "" {
+		return false
+	}
+
+	// machine clients never get the admin role, so a leaked key can not issue new keys
+	if key.Role != "reader" && key.Role != "editor" {
+		return false
+	}
+
+	var scopes []string
+
+	seen := make(map[string]bool)
+
+	for _, scope := range key.Scopes {
+		if scope != entities.ScopeReadOnly && scope != entities.ScopeBooksOnly {
+			return false
+		}
+
+		if !seen[scope] {
+			seen[scope] = true
+
+			scopes = append(scopes, scope)
+		}
+	}
+
+	key.Scopes = scopes
+
+	return true
+}
+
+// hash : salted SHA-256 of the secret part of a key
+func hash(salt, secret string) string {
+	sum := sha256.Sum256([]byte(salt + secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// random : n bytes from the system's secure random source
+func random(n int) (string, error) {
+	b := make([]byte, n)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,140 @@
+package apikeyservice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+
+	"github.com/golang/mock/gomock"
+)
+
+// TestPostAndVerify : test that an issued key verifies and only its hash is stored
+func TestPostAndVerify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockAPIKeyStorer(ctrl)
+	mock := New(mockStore)
+
+	var stored entities.APIKey
+
+	mockStore.EXPECT().Post(context.TODO(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, key entities.APIKey) (int, error) {
+			stored = key
+			stored.APIKeyID = 3
+
+			return 3, nil
+		})
+	mockStore.EXPECT().GetByID(context.TODO(), 3).DoAndReturn(
+		func(_ context.Context, _ int) (entities.APIKey, error) { return stored, nil })
+
+	issued, err := mock.Post(context.TODO(), entities.APIKey{Name: " importer ", Role: "editor",
+		Scopes: []string{"books-only", "books-only"}})
+	if err != nil {
+		t.Fatalf("failed to issue key: %v", err)
+	}
+
+	if !strings.HasPrefix(issued.Key, "ak_"+stored.Prefix+".") || strings.Contains(stored.Hash, issued.Key) {
+		t.Errorf("unexpected key %v for stored %v", issued.Key, stored)
+	}
+
+	if stored.Name != "importer" || !reflect.DeepEqual(stored.Scopes, []string{"books-only"}) {
+		t.Errorf("key not normalised: %v", stored)
+	}
+
+	mockStore.EXPECT().GetByPrefix(context.TODO(), stored.Prefix).Return(stored, nil).Times(2)
+	mockStore.EXPECT().TouchLastUsed(context.TODO(), 3).Return(nil)
+
+	id, err := mock.Verify(context.TODO(), issued.Key)
+	if err != nil || id.Subject != "apikey:3" || !reflect.DeepEqual(id.Roles, []string{"editor"}) {
+		t.Errorf("failed to verify issued key: %v %v", id, err)
+	}
+
+	if _, err = mock.Verify(context.TODO(), issued.Key+"x"); err == nil {
+		t.Errorf("expected error for tampered key")
+	}
+}
+
+// TestPost : test the validation of new keys
+func TestPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := New(store.NewMockAPIKeyStorer(ctrl))
+
+	testcases := []struct {
+		desc  string
+		input entities.APIKey
+	}{
+		{desc: "missing name", input: entities.APIKey{Role: "reader"}},
+		{desc: "admin role", input: entities.APIKey{Name: "ci", Role: "admin"}},
+		{desc: "unknown scope", input: entities.APIKey{Name: "ci", Role: "reader", Scopes: []string{"write-only"}}},
+	}
+
+	for _, tc := range testcases {
+		_, err := mock.Post(context.TODO(), tc.input)
+
+		if !reflect.DeepEqual(err, errors.New("invalid constraints")) {
+			t.Errorf("failed for %v, got: %v\n", tc.desc, err)
+		}
+	}
+}
+
+// TestVerify : test that malformed, unknown and revoked keys are rejected
+func TestVerify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockAPIKeyStorer(ctrl)
+	mock := New(mockStore)
+
+	revoked := entities.APIKey{APIKeyID: 2, Prefix: "cafe0001", Salt: "aa", Hash: hash("aa", "secret"),
+		RevokedAt: "2022-08-01 10:00:00"}
+
+	mockStore.EXPECT().GetByPrefix(context.TODO(), "cafe0001").Return(revoked, nil)
+	mockStore.EXPECT().GetByPrefix(context.TODO(), "beef0002").Return(entities.APIKey{}, errors.New("no rows"))
+
+	testcases := []struct {
+		desc string
+		key  string
+	}{
+		{desc: "malformed", key: "secret"},
+		{desc: "missing prefix", key: "cafe0001.secret"},
+		{desc: "revoked", key: "ak_cafe0001.secret"},
+		{desc: "unknown", key: "ak_beef0002.secret"},
+	}
+
+	for _, tc := range testcases {
+		if _, err := mock.Verify(context.TODO(), tc.key); err == nil {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
+
+// TestDelete : test revoking a key
+func TestDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockAPIKeyStorer(ctrl)
+	mock := New(mockStore)
+
+	testcases := []struct {
+		desc string
+		id   int
+		ra   int
+
+		expectedErr error
+	}{
+		{desc: "revoked", id: 1, ra: 1},
+		{desc: "already revoked", id: 2, ra: 0, expectedErr: errors.New("api key does not exist")},
+		{desc: "invalid id", id: -1, expectedErr: errors.New("invalid id")},
+	}
+
+	for _, tc := range testcases {
+		mockStore.EXPECT().Revoke(context.TODO(), tc.id).Return(tc.ra, nil).AnyTimes()
+
+		err := mock.Delete(context.TODO(), tc.id)
+
+		if !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v, got: %v\n", tc.desc, err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package auditservice
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 500
+	dbTimeLayout = "2006-01-02 15:04:05"
+)
+
+type AuditService struct {
+	auditStore store.AuditStorer
+}
+
+// New : factory function
+func New(s store.AuditStorer) AuditService {
+	return AuditService{s}
+}
+
+// GetAll : checks the filter and gives the matching audit entries, newest first
+func (s AuditService) GetAll(ctx context.Context, filter entities.AuditFilter) ([]entities.AuditEntry, error) {
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultLimit
+	}
+
+	if (filter.EntityType != "" && filter.EntityType != "author" && filter.EntityType != "book") ||
+		filter.EntityID < 0 || filter.Page < 0 || filter.Limit < 0 || filter.Limit > maxLimit {
+		return nil, errors.New("invalid constraints")
+	}
+
+	var err error
+
+	if filter.From, err = toDBTime(filter.From); err != nil {
+		return nil, errors.New("invalid from")
+	}
+
+	if filter.To, err = toDBTime(filter.To); err != nil {
+		return nil, errors.New("invalid to")
+	}
+
+	entries, err := s.auditStore.GetAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if entries == nil {
+		entries = []entities.AuditEntry{}
+	}
+
+	return entries, nil
+}
+
+// toDBTime : converts an RFC 3339 timestamp or a plain date to a UTC datetime, the store converts it to the time
+// zone of the session
+func toDBTime(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		if t, err = time.Parse("2006-01-02", value); err != nil {
+			return "", err
+		}
+	}
+
+	return t.UTC().Format(dbTimeLayout), nil
+}
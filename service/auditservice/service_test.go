@@ -0,0 +1,49 @@
+package auditservice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+
+	"github.com/golang/mock/gomock"
+)
+
+// TestGetAll : test the defaults and the validation of the audit filter
+func TestGetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockAuditStorer(ctrl)
+	mock := New(mockStore)
+
+	testcases := []struct {
+		desc   string
+		filter entities.AuditFilter
+
+		storeFilter entities.AuditFilter
+		expectedErr error
+	}{
+		{desc: "defaults", storeFilter: entities.AuditFilter{Page: 1, Limit: 50}},
+		{desc: "time range", filter: entities.AuditFilter{EntityType: "book", EntityID: 3, Actor: "asha",
+			From: "2022-08-01", To: "2022-08-02T12:30:00+05:30"}, storeFilter: entities.AuditFilter{EntityType: "book",
+			EntityID: 3, Actor: "asha", From: "2022-08-01 00:00:00", To: "2022-08-02 07:00:00", Page: 1, Limit: 50}},
+		{desc: "unknown entity", filter: entities.AuditFilter{EntityType: "genre"},
+			expectedErr: errors.New("invalid constraints")},
+		{desc: "limit too high", filter: entities.AuditFilter{Limit: 1000}, expectedErr: errors.New("invalid constraints")},
+		{desc: "invalid from", filter: entities.AuditFilter{From: "yesterday"}, expectedErr: errors.New("invalid from")},
+	}
+
+	for _, tc := range testcases {
+		if tc.expectedErr == nil {
+			mockStore.EXPECT().GetAll(context.TODO(), tc.storeFilter).Return(nil, nil)
+		}
+
+		entries, err := mock.GetAll(context.TODO(), tc.filter)
+
+		if !reflect.DeepEqual(err, tc.expectedErr) || (err == nil && entries == nil) {
+			t.Errorf("failed for %v, got: %v\n", tc.desc, err)
+		}
+	}
+}
@@ -2,21 +2,125 @@ package authorservice
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"strconv"
 	"strings"
 
 	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
 	"projects/GoLang-Interns-2022/authorbook/store"
 )
 
+// streamBatch : the authors of a stream whose books are read together
+const streamBatch = 100
+
 type AuthorService struct {
-	datastore store.AuthorStorer
+	datastore    store.AuthorStorer
+	auditStore   store.AuditStorer
+	historyStore store.HistoryStorer
+	outbox       store.OutboxStorer
+	tx           store.Transactor
 }
 
 // New : factory function , use for dependency injection
-func New(s store.AuthorStorer) AuthorService {
-	return AuthorService{s}
+func New(s store.AuthorStorer, audit store.AuditStorer, history store.HistoryStorer, outbox store.OutboxStorer,
+	tx store.Transactor) AuthorService {
+	return AuthorService{s, audit, history, outbox, tx}
+}
+
+// GetAll : gives the authors of the filter with only the fields and embedded resources of the projection
+func (s AuthorService) GetAll(ctx context.Context, filter entities.AuthorFilter,
+	projection entities.Projection) ([]entities.Author, error) {
+	authors := []entities.Author{}
+
+	err := s.datastore.EachAuthor(ctx, filter, projection, func(author entities.Author) error {
+		authors = append(authors, author)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.includeBooks(ctx, authors, projection); err != nil {
+		return nil, err
+	}
+
+	return authors, nil
+}
+
+// StreamAuthors : calls fn with every author one at a time as they are read, the books of an expansion are read
+// for every batch of authors so the stream does not query once per author
+func (s AuthorService) StreamAuthors(ctx context.Context, projection entities.Projection,
+	fn func(author entities.Author) error) error {
+	batch := make([]entities.Author, 0, streamBatch)
+
+	flush := func() error {
+		if err := s.includeBooks(ctx, batch, projection); err != nil {
+			return err
+		}
+
+		for i := range batch {
+			if err := fn(batch[i]); err != nil {
+				return err
+			}
+		}
+
+		batch = batch[:0]
+
+		return ctx.Err()
+	}
+
+	err := s.datastore.EachAuthor(ctx, entities.AuthorFilter{}, projection, func(author entities.Author) error {
+		if batch = append(batch, author); len(batch) < streamBatch {
+			return nil
+		}
+
+		return flush()
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	return flush()
+}
+
+// GetByIDs : gives the authors with the ids by author id, read together for a batch of lookups
+func (s AuthorService) GetByIDs(ctx context.Context, ids []int) (map[int]entities.Author, error) {
+	return s.datastore.GetAuthorsByIDs(ctx, ids)
+}
+
+// GetBooksByAuthors : gives the books of each of the authors by author id, read together for a batch of lookups
+func (s AuthorService) GetBooksByAuthors(ctx context.Context, ids []int) (map[int][]entities.Book, error) {
+	return s.datastore.GetBooksByAuthors(ctx, ids)
+}
+
+// includeBooks : reads the books of a listing of authors when the projection expands them
+func (s AuthorService) includeBooks(ctx context.Context, authors []entities.Author,
+	projection entities.Projection) error {
+	if !projection.Expands("books") {
+		return nil
+	}
+
+	ids := make([]int, len(authors))
+	for i := range authors {
+		ids[i] = authors[i].AuthorID
+	}
+
+	books, err := s.GetBooksByAuthors(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range authors {
+		authors[i].Books = books[authors[i].AuthorID]
+	}
+
+	return nil
 }
 
 // Post : checks the author before posting
@@ -25,13 +129,24 @@ func (s AuthorService) Post(ctx context.Context, a entities.Author) (entities.Au
 		return entities.Author{}, errors.New("invalid constraints")
 	}
 
-	id, err := s.datastore.Post(ctx, a)
-	if err != nil || id <= 0 {
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		id, err := s.datastore.Post(ctx, a)
+		if err != nil {
+			return err
+		}
+
+		if id <= 0 {
+			return errors.New("database issue")
+		}
+
+		a.AuthorID = id
+
+		return s.audit(ctx, entities.AuditCreate, id, nil, &a)
+	})
+	if err != nil {
 		return entities.Author{}, err
 	}
 
-	a.AuthorID = id
-
 	return a, nil
 }
 
@@ -41,18 +156,29 @@ func (s AuthorService) Put(ctx context.Context, a entities.Author, id int) (enti
 		return entities.Author{}, errors.New("invalid constraints")
 	}
 
-	existAuthor, err := s.datastore.IncludeAuthor(ctx, id)
-	if err != nil || existAuthor.AuthorID != id {
-		return entities.Author{}, errors.New("author does not exist")
-	}
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		existAuthor, err := s.datastore.IncludeAuthor(ctx, id)
+		if err != nil || existAuthor.AuthorID != id {
+			return errors.New("author does not exist")
+		}
+
+		i, err := s.datastore.Put(ctx, a, id)
+		if err != nil {
+			return err
+		}
 
-	i, err := s.datastore.Put(ctx, a, id)
+		if i == 0 {
+			return errors.New("author does not exist")
+		}
+
+		a.AuthorID = id
+
+		return s.audit(ctx, entities.AuditUpdate, id, &existAuthor, &a)
+	})
 	if err != nil {
 		return entities.Author{}, err
 	}
 
-	a.AuthorID = i
-
 	return a, nil
 }
 
@@ -62,21 +188,107 @@ func (s AuthorService) Delete(ctx context.Context, id int) error {
 		return errors.New("invalid id")
 	}
 
-	count, err := s.datastore.Delete(ctx, id)
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		// a missing author shows up as nothing deleted below
+		existAuthor, _ := s.datastore.IncludeAuthor(ctx, id)
+
+		count, err := s.datastore.Delete(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if count <= 0 {
+			return errors.New("author does not exist")
+		}
+
+		return s.audit(ctx, entities.AuditDelete, id, &existAuthor, nil)
+	})
+}
+
+// GetHistory : gives the revisions of the author, newest first
+func (s AuthorService) GetHistory(ctx context.Context, id int) ([]entities.AuthorRevision, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid id")
+	}
+
+	revisions, err := s.historyStore.GetAuthorHistory(ctx, id)
 	if err != nil {
+		return nil, err
+	}
+
+	if len(revisions) == 0 {
+		// authors added before the history was kept have no revisions yet
+		if _, err = s.datastore.IncludeAuthor(ctx, id); err != nil {
+			return nil, errors.New("author does not exist")
+		}
+
+		revisions = []entities.AuthorRevision{}
+	}
+
+	return revisions, nil
+}
+
+// events : the event type of each audited operation
+var events = map[string]string{
+	entities.AuditCreate: entities.EventAuthorCreated,
+	entities.AuditUpdate: entities.EventAuthorUpdated,
+	entities.AuditDelete: entities.EventAuthorDeleted,
+}
+
+// audit : records the change in the audit log, the author history and the outbox, within the transaction
+// of the change
+func (s AuthorService) audit(ctx context.Context, operation string, id int, before, after *entities.Author) error {
+	entry := entities.AuditEntry{Actor: auth.Actor(ctx), Operation: operation, EntityType: "author", EntityID: id}
+	rev := entities.AuthorRevision{Operation: operation, Actor: entry.Actor}
+
+	var err error
+
+	if before != nil {
+		// the time of the change is recorded by the entry, the before and after values are compared field by field
+		record := *before
+		record.UpdatedAt = ""
+
+		if entry.Before, err = json.Marshal(record); err != nil {
+			return err
+		}
+
+		rev.Author = record
+	}
+
+	if after != nil {
+		if entry.After, err = json.Marshal(after); err != nil {
+			return err
+		}
+
+		rev.Author = *after
+	}
+
+	if err = s.auditStore.Post(ctx, entry); err != nil {
 		return err
 	}
 
-	if count <= 0 {
-		return errors.New("author does not exist")
+	rev.Author.AuthorID = id
+
+	if err = s.historyStore.PostAuthor(ctx, rev); err != nil {
+		return err
 	}
 
-	return nil
+	event := entities.Event{Type: events[operation], EntityType: "author", EntityID: id, Actor: entry.Actor,
+		Payload: entry.After}
+	if after == nil {
+		event.Payload = entry.Before
+	}
+
+	return s.outbox.Post(ctx, event)
 }
 
 // checkDob : validates the DOB
 func checkDob(dob string) bool {
 	Dob := strings.Split(dob, "/")
+	if len(Dob) != 3 {
+		return false
+	}
+
 	day, _ := strconv.Atoi(Dob[0])
 	month, _ := strconv.Atoi(Dob[1])
 	year, _ := strconv.Atoi(Dob[2])
@@ -2,10 +2,13 @@ package authorservice
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"reflect"
 	"testing"
 
 	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
 	"projects/GoLang-Interns-2022/authorbook/store"
 
 	"github.com/golang/mock/gomock"
@@ -15,7 +18,7 @@ import (
 func TestPost(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockStore := store.NewMockAuthorStorer(ctrl)
-	mock := New(mockStore) // defining the type of interface
+	mock := New(mockStore, mockAudit(ctrl), mockHistory(ctrl), mockOutbox(ctrl), mockTx(ctrl)) // defining the type of interface
 
 	testcases := []struct {
 		desc string
@@ -52,7 +55,7 @@ func TestPost(t *testing.T) {
 
 		a, _ := mock.Post(context.TODO(), tc.body)
 
-		if a != tc.expectedAuthor {
+		if !reflect.DeepEqual(a, tc.expectedAuthor) {
 			t.Errorf("failed for %v\n", tc.desc)
 		}
 	}
@@ -62,7 +65,7 @@ func TestPost(t *testing.T) {
 func TestPut(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockStore := store.NewMockAuthorStorer(ctrl)
-	mock := New(mockStore)
+	mock := New(mockStore, mockAudit(ctrl), mockHistory(ctrl), mockOutbox(ctrl), mockTx(ctrl))
 
 	testcases := []struct {
 		desc     string
@@ -74,7 +77,7 @@ func TestPut(t *testing.T) {
 	}{
 		{desc: "existing author", input: entities.Author{
 			AuthorID: 4, FirstName: "nilotpal", LastName: "mrinal", DOB: "20/05/1990", PenName: "Dark horse"},
-			targetID: 5, expected: entities.Author{AuthorID: 4, FirstName: "nilotpal", LastName: "mrinal",
+			targetID: 5, expected: entities.Author{AuthorID: 5, FirstName: "nilotpal", LastName: "mrinal",
 				DOB: "20/05/1990", PenName: "Dark horse"}, expectedErr: nil,
 		},
 		{desc: "not existing author", input: entities.Author{
@@ -108,7 +111,7 @@ func TestPut(t *testing.T) {
 
 		author1, _ := mock.Put(context.TODO(), tc.input, tc.targetID)
 
-		if author1 != tc.expected {
+		if !reflect.DeepEqual(author1, tc.expected) {
 			t.Errorf("failed for %v\n", tc.desc)
 		}
 	}
@@ -118,28 +121,306 @@ func TestPut(t *testing.T) {
 func TestDelete(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockStore := store.NewMockAuthorStorer(ctrl)
-	mock := New(mockStore)
+	mock := New(mockStore, mockAudit(ctrl), mockHistory(ctrl), mockOutbox(ctrl), mockTx(ctrl))
 
 	testcases := []struct {
 		desc     string
 		targetID int
 
-		expectedRowsAffected int
-		expectedErr          error
+		rowsAffected int
+		storeErr     error
+		expectedErr  error
 	}{
-		{"valid authorId", 4, 1, nil},
-		{"invalid authorId", -1, 0, errors.New("invalid id")},
-		{"error case", 4, 0, errors.New("invalid id")},
+		{desc: "valid authorId", targetID: 4, rowsAffected: 1},
+		{desc: "invalid authorId", targetID: -1, expectedErr: errors.New("invalid id")},
+		{desc: "missing author", targetID: 6, expectedErr: errors.New("author does not exist")},
+		{desc: "error case", targetID: 7, storeErr: errors.New("connection lost"),
+			expectedErr: errors.New("connection lost")},
 	}
 
 	for _, tc := range testcases {
-		if tc.targetID == 4 {
-			mockStore.EXPECT().Delete(context.TODO(), tc.targetID).Return(tc.expectedRowsAffected, tc.expectedErr)
+		if tc.targetID > 0 {
+			mockStore.EXPECT().IncludeAuthor(context.TODO(), tc.targetID).Return(entities.Author{AuthorID: tc.targetID}, nil)
+			mockStore.EXPECT().Delete(context.TODO(), tc.targetID).Return(tc.rowsAffected, tc.storeErr)
 		}
 
-		id, _ := mock.Delete(context.TODO(), tc.targetID)
-		if id != tc.expectedRowsAffected {
-			t.Errorf("failed for %v\n", tc.desc)
+		err := mock.Delete(context.TODO(), tc.targetID)
+		if !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v, got: %v\n", tc.desc, err)
 		}
 	}
 }
+
+// TestAudit : test that each change is recorded with its actor inside the transaction of the change
+func TestAudit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockAuthorStorer(ctrl)
+	mockAuditStore := store.NewMockAuditStorer(ctrl)
+	mock := New(mockStore, mockAuditStore, mockHistory(ctrl), mockOutbox(ctrl), mockTx(ctrl))
+
+	ctx := auth.NewContext(context.TODO(), auth.Identity{Subject: "asha"})
+	before := entities.Author{AuthorID: 5, FirstName: "nilotpal", LastName: "mrinal", DOB: "20/05/1990", PenName: "Dh"}
+	after := entities.Author{AuthorID: 5, FirstName: "nilotpal", LastName: "mrinal", DOB: "21/05/1990", PenName: "Dh"}
+	beforeJSON, _ := json.Marshal(before)
+	afterJSON, _ := json.Marshal(after)
+
+	mockStore.EXPECT().IncludeAuthor(ctx, 5).Return(before, nil).Times(2)
+	mockStore.EXPECT().Put(ctx, after, 5).Return(5, nil)
+	mockStore.EXPECT().Delete(ctx, 5).Return(1, nil)
+
+	var entries []entities.AuditEntry
+
+	mockAuditStore.EXPECT().Post(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, entry entities.AuditEntry) error {
+			entries = append(entries, entry)
+			return nil
+		}).Times(2)
+
+	if _, err := mock.Put(ctx, after, 5); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+
+	if err := mock.Delete(ctx, 5); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+
+	expected := []entities.AuditEntry{
+		{Actor: "asha", Operation: entities.AuditUpdate, EntityType: "author", EntityID: 5, Before: beforeJSON,
+			After: afterJSON},
+		{Actor: "asha", Operation: entities.AuditDelete, EntityType: "author", EntityID: 5, Before: beforeJSON},
+	}
+
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("unexpected audit entries %+v", entries)
+	}
+}
+
+// TestEvents : test that each change is published through the outbox, a delete with the last state of the author
+func TestEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockAuthorStorer(ctrl)
+	mockOutboxStore := store.NewMockOutboxStorer(ctrl)
+	mock := New(mockStore, mockAudit(ctrl), mockHistory(ctrl), mockOutboxStore, mockTx(ctrl))
+
+	ctx := auth.NewContext(context.TODO(), auth.Identity{Subject: "asha"})
+	author := entities.Author{FirstName: "nilotpal", LastName: "mrinal", DOB: "20/05/1990", PenName: "Dh"}
+	created := entities.Author{AuthorID: 5, FirstName: "nilotpal", LastName: "mrinal", DOB: "20/05/1990",
+		PenName: "Dh"}
+	payload, _ := json.Marshal(created)
+
+	mockStore.EXPECT().Post(ctx, author).Return(5, nil)
+	mockStore.EXPECT().IncludeAuthor(ctx, 5).Return(created, nil)
+	mockStore.EXPECT().Delete(ctx, 5).Return(1, nil)
+
+	var events []entities.Event
+
+	mockOutboxStore.EXPECT().Post(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, event entities.Event) error {
+		events = append(events, event)
+		return nil
+	}).Times(2)
+
+	if _, err := mock.Post(ctx, author); err != nil {
+		t.Fatalf("failed to post: %v", err)
+	}
+
+	if err := mock.Delete(ctx, 5); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+
+	expected := []entities.Event{
+		{Type: entities.EventAuthorCreated, EntityType: "author", EntityID: 5, Actor: "asha", Payload: payload},
+		{Type: entities.EventAuthorDeleted, EntityType: "author", EntityID: 5, Actor: "asha", Payload: payload},
+	}
+
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
+
+// TestPutID : test that an update is audited and published with the id of the author and not with the result of
+// the store
+func TestPutID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockAuthorStorer(ctrl)
+	mockAuditStore := store.NewMockAuditStorer(ctrl)
+	mockOutboxStore := store.NewMockOutboxStorer(ctrl)
+	mock := New(mockStore, mockAuditStore, mockHistory(ctrl), mockOutboxStore, mockTx(ctrl))
+
+	ctx := auth.NewContext(context.TODO(), auth.Identity{Subject: "asha"})
+	input := entities.Author{FirstName: "nilotpal", LastName: "mrinal", DOB: "21/05/1990", PenName: "Dh"}
+	updated := entities.Author{AuthorID: 7, FirstName: "nilotpal", LastName: "mrinal", DOB: "21/05/1990",
+		PenName: "Dh"}
+	payload, _ := json.Marshal(updated)
+
+	mockStore.EXPECT().IncludeAuthor(ctx, 7).Return(entities.Author{AuthorID: 7, FirstName: "nilotpal"}, nil)
+	mockStore.EXPECT().Put(ctx, input, 7).Return(1, nil)
+
+	var entry entities.AuditEntry
+
+	mockAuditStore.EXPECT().Post(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e entities.AuditEntry) error {
+		entry = e
+		return nil
+	})
+
+	var event entities.Event
+
+	mockOutboxStore.EXPECT().Post(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e entities.Event) error {
+		event = e
+		return nil
+	})
+
+	author, err := mock.Put(ctx, input, 7)
+	if err != nil || !reflect.DeepEqual(author, updated) {
+		t.Fatalf("unexpected update %+v %v", author, err)
+	}
+
+	if entry.EntityID != 7 || string(entry.After) != string(payload) {
+		t.Errorf("unexpected audit entry %+v", entry)
+	}
+
+	if event.EntityID != 7 || string(event.Payload) != string(payload) {
+		t.Errorf("unexpected event %+v", event)
+	}
+}
+
+// TestGetHistory : test the revisions of an author are listed
+func TestGetHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockAuthorStorer(ctrl)
+	mockHistoryStore := store.NewMockHistoryStorer(ctrl)
+	mock := New(mockStore, mockAudit(ctrl), mockHistoryStore, mockOutbox(ctrl), mockTx(ctrl))
+
+	revisions := []entities.AuthorRevision{{Revision: 1, Operation: entities.AuditCreate, Actor: "asha",
+		Author: entities.Author{AuthorID: 4, FirstName: "nilotpal"}}}
+
+	testcases := []struct {
+		desc string
+		id   int
+
+		revisions   []entities.AuthorRevision
+		authorErr   error
+		expected    []entities.AuthorRevision
+		expectedErr error
+	}{
+		{desc: "revisions", id: 4, revisions: revisions, expected: revisions},
+		{desc: "author from before the history", id: 5, expected: []entities.AuthorRevision{}},
+		{desc: "missing author", id: 6, authorErr: errors.New("no rows"), expectedErr: errors.New("author does not exist")},
+		{desc: "invalid id", id: -1, expectedErr: errors.New("invalid id")},
+	}
+
+	for _, tc := range testcases {
+		mockHistoryStore.EXPECT().GetAuthorHistory(context.TODO(), tc.id).Return(tc.revisions, nil).AnyTimes()
+		mockStore.EXPECT().IncludeAuthor(context.TODO(), tc.id).Return(entities.Author{AuthorID: tc.id},
+			tc.authorErr).AnyTimes()
+
+		result, err := mock.GetHistory(context.TODO(), tc.id)
+
+		if !reflect.DeepEqual(err, tc.expectedErr) || !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("failed for %v, got: %v\n", tc.desc, err)
+		}
+	}
+}
+
+// mockAudit : an audit store accepting every entry
+func mockAudit(ctrl *gomock.Controller) *store.MockAuditStorer {
+	m := store.NewMockAuditStorer(ctrl)
+	m.EXPECT().Post(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	return m
+}
+
+// mockHistory : a history store accepting every revision
+func mockHistory(ctrl *gomock.Controller) *store.MockHistoryStorer {
+	m := store.NewMockHistoryStorer(ctrl)
+	m.EXPECT().PostAuthor(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	m.EXPECT().PostBook(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	return m
+}
+
+// mockOutbox : an outbox accepting every event
+func mockOutbox(ctrl *gomock.Controller) *store.MockOutboxStorer {
+	m := store.NewMockOutboxStorer(ctrl)
+	m.EXPECT().Post(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	return m
+}
+
+// mockTx : a transactor running the function on the given context
+func mockTx(ctrl *gomock.Controller) *store.MockTransactor {
+	m := store.NewMockTransactor(ctrl)
+	m.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) }).AnyTimes()
+
+	return m
+}
+
+// TestGetAll : test the listing and the stream of authors
+func TestGetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockAuthorStorer(ctrl)
+	mock := New(mockStore, mockAudit(ctrl), mockHistory(ctrl), mockOutbox(ctrl), mockTx(ctrl))
+
+	author := entities.Author{AuthorID: 1, FirstName: "nilotpal", DOB: "20/05/1990"}
+
+	each := func(_ context.Context, _ entities.AuthorFilter, _ entities.Projection,
+		fn func(entities.Author) error) error {
+		return fn(author)
+	}
+
+	filter := entities.AuthorFilter{After: 4, Limit: 3}
+
+	mockStore.EXPECT().EachAuthor(gomock.Any(), filter, entities.Projection{}, gomock.Any()).DoAndReturn(each)
+	mockStore.EXPECT().EachAuthor(gomock.Any(), entities.AuthorFilter{}, entities.Projection{}, gomock.Any()).
+		DoAndReturn(each)
+
+	authors, err := mock.GetAll(context.TODO(), filter, entities.Projection{})
+	if err != nil || !reflect.DeepEqual(authors, []entities.Author{author}) {
+		t.Errorf("unexpected authors %v %v", authors, err)
+	}
+
+	var streamed []entities.Author
+
+	err = mock.StreamAuthors(context.TODO(), entities.Projection{}, func(a entities.Author) error {
+		streamed = append(streamed, a)
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(streamed, []entities.Author{author}) {
+		t.Errorf("unexpected stream %v %v", streamed, err)
+	}
+
+	mockStore.EXPECT().EachAuthor(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(errors.New("connection lost"))
+
+	if authors, err = mock.GetAll(context.TODO(), entities.AuthorFilter{}, entities.Projection{}); err == nil || authors != nil {
+		t.Errorf("expected an error, got %v", authors)
+	}
+}
+
+// TestGetAllExpand : test that the books of the authors are read together when they are expanded
+func TestGetAllExpand(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockAuthorStorer(ctrl)
+	mock := New(mockStore, mockAudit(ctrl), mockHistory(ctrl), mockOutbox(ctrl), mockTx(ctrl))
+
+	projection := entities.Projection{Fields: []string{"authorID", "penName", "books"}, Expand: []string{"books"}}
+	books := []entities.Book{{BookID: 3, AuthorID: 1, Title: "rusty"}}
+
+	mockStore.EXPECT().EachAuthor(gomock.Any(), entities.AuthorFilter{}, projection, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ entities.AuthorFilter, _ entities.Projection, fn func(entities.Author) error) error {
+			if err := fn(entities.Author{AuthorID: 1, PenName: "rb"}); err != nil {
+				return err
+			}
+
+			return fn(entities.Author{AuthorID: 2, PenName: "pc"})
+		})
+	mockStore.EXPECT().GetBooksByAuthors(gomock.Any(), []int{1, 2}).Return(map[int][]entities.Book{1: books}, nil)
+
+	authors, err := mock.GetAll(context.TODO(), entities.AuthorFilter{}, projection)
+
+	expected := []entities.Author{{AuthorID: 1, PenName: "rb", Books: books}, {AuthorID: 2, PenName: "pc"}}
+
+	if err != nil || !reflect.DeepEqual(authors, expected) {
+		t.Errorf("unexpected authors %v %v", authors, err)
+	}
+}
@@ -0,0 +1,381 @@
+package batchservice
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+// maxOperations : the most operations a batch may carry
+const maxOperations = 1000
+
+var errFailed = errors.New("operation failed")
+
+// Authorizer : checks the permission of each operation as every batch shares one route
+type Authorizer interface {
+	Check(ctx context.Context, permission string) error
+}
+
+type BatchService struct {
+	authorService service.AuthorService
+	bookService   service.BookService
+	tx            store.Transactor
+	policy        Authorizer
+}
+
+// New : factory function, operations go through the author and book services so they get the same checks, audit
+// and events as single requests
+func New(authors service.AuthorService, books service.BookService, tx store.Transactor,
+	policy Authorizer) BatchService {
+	return BatchService{authors, books, tx, policy}
+}
+
+// Run : runs the operations in order. An atomic batch runs them in one transaction and stops at the first failure,
+// rolling back the ones before; the operations of an independent batch succeed or fail on their own and only the
+// ones referring to a failed operation fail with it
+func (s BatchService) Run(ctx context.Context, batch entities.Batch) (entities.BatchResponse, error) {
+	if err := check(&batch); err != nil {
+		return entities.BatchResponse{}, err
+	}
+
+	results := make([]entities.BatchResult, len(batch.Operations))
+	ids := make(map[string]int)
+	failed := make(map[string]bool)
+
+	run := func(ctx context.Context) error {
+		for i, op := range batch.Operations {
+			results[i] = s.apply(ctx, i, op, ids, failed)
+
+			if results[i].Error != "" && batch.Mode == entities.BatchAtomic {
+				return errFailed
+			}
+
+			if op.Ref == "" {
+				continue
+			}
+
+			if results[i].Error == "" {
+				ids[op.Ref] = results[i].ID
+			} else {
+				failed[op.Ref] = true
+			}
+		}
+
+		return nil
+	}
+
+	var err error
+
+	if batch.Mode == entities.BatchAtomic {
+		err = s.tx.WithTx(ctx, run)
+	} else {
+		err = run(ctx)
+	}
+
+	if err != nil {
+		abort(batch.Operations, results, err)
+	}
+
+	response := entities.BatchResponse{Mode: batch.Mode, Results: results}
+
+	for i := range results {
+		if results[i].Error == "" {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+
+	return response, nil
+}
+
+// apply : runs an operation once the references in its id and body are replaced by the ids they name
+func (s BatchService) apply(ctx context.Context, index int, op entities.BatchOperation, ids map[string]int,
+	failed map[string]bool) entities.BatchResult {
+	result := entities.BatchResult{Index: index, Ref: op.Ref}
+
+	fail := func(status int, err error) entities.BatchResult {
+		result.Status, result.Error = status, err.Error()
+		return result
+	}
+
+	if err := s.policy.Check(ctx, op.Entity+":"+op.Op); err != nil {
+		return fail(http.StatusForbidden, err)
+	}
+
+	rawID, err := resolve(op.ID, ids, failed)
+	if err != nil {
+		return fail(refStatus(err), err)
+	}
+
+	body, err := resolve(op.Body, ids, failed)
+	if err != nil {
+		return fail(refStatus(err), err)
+	}
+
+	if op.Op != entities.BatchCreate {
+		if len(rawID) == 0 || json.Unmarshal(rawID, &result.ID) != nil {
+			return fail(http.StatusBadRequest, errors.New("invalid id"))
+		}
+	}
+
+	if op.Op == entities.BatchDelete {
+		if op.Entity == "author" {
+			err = s.authorService.Delete(ctx, result.ID)
+		} else {
+			err = s.bookService.Delete(ctx, result.ID)
+		}
+
+		if err != nil {
+			return fail(status(err), err)
+		}
+
+		result.Status = http.StatusNoContent
+
+		return result
+	}
+
+	if op.Entity == "author" {
+		result.Body, err = s.writeAuthor(ctx, op.Op, body, &result.ID)
+	} else {
+		result.Body, err = s.writeBook(ctx, op.Op, body, &result.ID)
+	}
+
+	if err != nil {
+		result.Body = nil
+		return fail(status(err), err)
+	}
+
+	result.Status = http.StatusOK
+	if op.Op == entities.BatchCreate {
+		result.Status = http.StatusCreated
+	}
+
+	return result
+}
+
+// writeAuthor : creates or updates an author, the id of a created author is set
+func (s BatchService) writeAuthor(ctx context.Context, op string, body []byte, id *int) (interface{}, error) {
+	var author entities.Author
+
+	if err := decode(body, &author); err != nil {
+		return nil, err
+	}
+
+	var err error
+
+	if op == entities.BatchCreate {
+		author, err = s.authorService.Post(ctx, author)
+		*id = author.AuthorID
+	} else {
+		author, err = s.authorService.Put(ctx, author, *id)
+	}
+
+	return author, err
+}
+
+// writeBook : creates or updates a book, the id of a created book is set
+func (s BatchService) writeBook(ctx context.Context, op string, body []byte, id *int) (interface{}, error) {
+	var book entities.Book
+
+	if err := decode(body, &book); err != nil {
+		return nil, err
+	}
+
+	var err error
+
+	if op == entities.BatchCreate {
+		book, err = s.bookService.Post(ctx, &book)
+		*id = book.BookID
+	} else {
+		book, err = s.bookService.Put(ctx, &book, *id)
+	}
+
+	return book, err
+}
+
+// check : validates the batch as a whole, filling in the default mode
+func check(batch *entities.Batch) error {
+	if batch.Mode == "" {
+		batch.Mode = entities.BatchAtomic
+	}
+
+	if batch.Mode != entities.BatchAtomic && batch.Mode != entities.BatchIndependent {
+		return errors.New("invalid mode")
+	}
+
+	if len(batch.Operations) == 0 {
+		return errors.New("invalid batch, no operations")
+	}
+
+	if len(batch.Operations) > maxOperations {
+		return errors.New("invalid batch, more than " + strconv.Itoa(maxOperations) + " operations")
+	}
+
+	refs := make(map[string]bool)
+
+	for i, op := range batch.Operations {
+		if op.Entity != "author" && op.Entity != "book" {
+			return errors.New("invalid entity in operation " + strconv.Itoa(i))
+		}
+
+		if op.Op != entities.BatchCreate && op.Op != entities.BatchUpdate && op.Op != entities.BatchDelete {
+			return errors.New("invalid op in operation " + strconv.Itoa(i))
+		}
+
+		if op.Ref == "" {
+			continue
+		}
+
+		if refs[op.Ref] {
+			return errors.New("invalid ref " + op.Ref + ", it names two operations")
+		}
+
+		refs[op.Ref] = true
+	}
+
+	return nil
+}
+
+// abort : reports the operations of an atomic batch which was rolled back, the failed operation keeps its error and
+// the others fail with it; when the transaction itself failed every operation failed with the server
+func abort(ops []entities.BatchOperation, results []entities.BatchResult, err error) {
+	failedAt, status, reason := -1, http.StatusInternalServerError, err.Error()
+
+	for i := range results {
+		if err == errFailed && results[i].Error != "" {
+			failedAt, status, reason = i, http.StatusFailedDependency, "operation "+strconv.Itoa(i)+" failed"
+			break
+		}
+	}
+
+	for i := range results {
+		switch {
+		case i == failedAt:
+			continue
+		case results[i].Status == 0:
+			results[i].Error = "not run, " + reason
+		default:
+			results[i].Error = "rolled back, " + reason
+		}
+
+		results[i] = entities.BatchResult{Index: i, Ref: ops[i].Ref, Status: status, Error: results[i].Error}
+	}
+}
+
+// decode : reads the body of an operation, unknown fields are refused as a misspelled one would be lost silently
+func decode(body []byte, v interface{}) error {
+	if len(body) == 0 {
+		return errors.New("invalid body, it is missing")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		return errors.New("invalid body, " + err.Error())
+	}
+
+	return nil
+}
+
+// errRefFailed : the reference names an operation which failed
+type errRefFailed string
+
+func (e errRefFailed) Error() string {
+	return "ref " + string(e) + " failed"
+}
+
+func refStatus(err error) int {
+	var refFailed errRefFailed
+	if errors.As(err, &refFailed) {
+		return http.StatusFailedDependency
+	}
+
+	return http.StatusBadRequest
+}
+
+// resolve : replaces every {"$ref":"<name>"} in the raw JSON by the id of the named operation
+func resolve(raw json.RawMessage, ids map[string]int, failed map[string]bool) (json.RawMessage, error) {
+	if len(raw) == 0 || !bytes.Contains(raw, []byte(`"$ref"`)) {
+		return raw, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, errors.New("invalid body, " + err.Error())
+	}
+
+	value, err := replace(value, ids, failed)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(value)
+}
+
+func replace(value interface{}, ids map[string]int, failed map[string]bool) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok && len(v) == 1 {
+			if failed[ref] {
+				return nil, errRefFailed(ref)
+			}
+
+			id, ok := ids[ref]
+			if !ok {
+				return nil, errors.New("invalid ref " + ref + ", it names no earlier operation")
+			}
+
+			return id, nil
+		}
+
+		for key := range v {
+			replaced, err := replace(v[key], ids, failed)
+			if err != nil {
+				return nil, err
+			}
+
+			v[key] = replaced
+		}
+	case []interface{}:
+		for i := range v {
+			replaced, err := replace(v[i], ids, failed)
+			if err != nil {
+				return nil, err
+			}
+
+			v[i] = replaced
+		}
+	}
+
+	return value, nil
+}
+
+// status : the status a single request would have answered the error of an operation with
+func status(err error) int {
+	msg := err.Error()
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows), strings.HasSuffix(msg, "does not exist"):
+		return http.StatusNotFound
+	case strings.HasPrefix(msg, "already exist"):
+		return http.StatusConflict
+	case strings.HasPrefix(msg, "invalid "), msg == "empty":
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
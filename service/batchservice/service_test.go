@@ -0,0 +1,206 @@
+package batchservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+// denying : a policy refusing the permissions it holds
+type denying map[string]bool
+
+func (d denying) Check(_ context.Context, permission string) error {
+	if d[permission] {
+		return errors.New("missing permission " + permission)
+	}
+
+	return nil
+}
+
+// operation : builds an operation with the id and body given as JSON
+func operation(ref, op, entity, id, body string) entities.BatchOperation {
+	operation := entities.BatchOperation{Ref: ref, Op: op, Entity: entity}
+	if id != "" {
+		operation.ID = json.RawMessage(id)
+	}
+
+	if body != "" {
+		operation.Body = json.RawMessage(body)
+	}
+
+	return operation
+}
+
+// TestRunIndependent : test that references carry the created ids and that only the operations depending on a
+// failed one fail with it
+func TestRunIndependent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockAuthorService := service.NewMockAuthorService(ctrl)
+	mockBookService := service.NewMockBookService(ctrl)
+	s := New(mockAuthorService, mockBookService, store.NewMockTransactor(ctrl), denying{"author:delete": true})
+
+	author := entities.Author{AuthorID: 7, FirstName: "Frank", LastName: "Herbert", DOB: "08/10/1920"}
+	created := entities.Book{BookID: 9, AuthorID: 7, Title: "Dune"}
+	updated := entities.Book{BookID: 9, AuthorID: 7, Title: "Dune Messiah"}
+
+	gomock.InOrder(
+		mockAuthorService.EXPECT().Post(gomock.Any(), entities.Author{FirstName: "Frank", LastName: "Herbert",
+			DOB: "08/10/1920"}).Return(author, nil),
+		mockBookService.EXPECT().Post(gomock.Any(), &entities.Book{AuthorID: 7, Title: "Dune"}).Return(created, nil),
+		mockBookService.EXPECT().Put(gomock.Any(), &entities.Book{AuthorID: 7, Title: "Dune Messiah"}, 9).
+			Return(updated, nil),
+		mockAuthorService.EXPECT().Post(gomock.Any(), entities.Author{}).
+			Return(entities.Author{}, errors.New("invalid constraints")),
+		mockBookService.EXPECT().Delete(gomock.Any(), 99).Return(errors.New("book does not exist")),
+	)
+
+	response, err := s.Run(context.TODO(), entities.Batch{Mode: entities.BatchIndependent,
+		Operations: []entities.BatchOperation{
+			operation("a", "create", "author", "", `{"firstName":"Frank","lastName":"Herbert","dob":"08/10/1920"}`),
+			operation("b", "create", "book", "", `{"title":"Dune","authorID":{"$ref":"a"}}`),
+			operation("", "update", "book", `{"$ref":"b"}`, `{"title":"Dune Messiah","authorID":{"$ref":"a"}}`),
+			operation("c", "create", "author", "", `{}`),
+			operation("d", "create", "book", "", `{"title":"Children of Dune","authorID":{"$ref":"c"}}`),
+			operation("", "delete", "book", "99", ""),
+			operation("", "delete", "author", `{"$ref":"a"}`, ""),
+			operation("", "update", "book", `{"$ref":"e"}`, `{}`),
+			operation("", "update", "book", "9", `{"titel":"Dune"}`),
+		}})
+
+	expected := entities.BatchResponse{Mode: entities.BatchIndependent, Succeeded: 3, Failed: 6,
+		Results: []entities.BatchResult{
+			{Index: 0, Ref: "a", Status: 201, ID: 7, Body: author},
+			{Index: 1, Ref: "b", Status: 201, ID: 9, Body: created},
+			{Index: 2, Status: 200, ID: 9, Body: updated},
+			{Index: 3, Ref: "c", Status: 400, Error: "invalid constraints"},
+			{Index: 4, Ref: "d", Status: 424, Error: "ref c failed"},
+			{Index: 5, Status: 404, ID: 99, Error: "book does not exist"},
+			{Index: 6, Status: 403, Error: "missing permission author:delete"},
+			{Index: 7, Status: 400, Error: "invalid ref e, it names no earlier operation"},
+			{Index: 8, Status: 400, ID: 9, Error: `invalid body, json: unknown field "titel"`},
+		}}
+
+	if err != nil || !reflect.DeepEqual(response, expected) {
+		t.Errorf("unexpected response %+v %v", response, err)
+	}
+}
+
+// TestRunAtomic : test that a failed operation rolls back the batch and the other operations are reported with it
+func TestRunAtomic(t *testing.T) {
+	operations := []entities.BatchOperation{
+		operation("a", "create", "author", "", `{"firstName":"Frank","lastName":"Herbert","dob":"08/10/1920"}`),
+		operation("b", "create", "book", "", `{"title":"Dune","authorID":{"$ref":"a"}}`),
+		operation("", "delete", "author", "3", ""),
+	}
+
+	testcases := []struct {
+		desc    string
+		bookErr error
+		txErr   error
+
+		expected entities.BatchResponse
+	}{
+		{desc: "committed", expected: entities.BatchResponse{Mode: entities.BatchAtomic, Succeeded: 3,
+			Results: []entities.BatchResult{
+				{Index: 0, Ref: "a", Status: 201, ID: 7, Body: entities.Author{AuthorID: 7}},
+				{Index: 1, Ref: "b", Status: 201, ID: 9, Body: entities.Book{BookID: 9, AuthorID: 7}},
+				{Index: 2, Status: 204, ID: 3},
+			}}},
+		{desc: "operation failed", bookErr: errors.New("already exists"), expected: entities.BatchResponse{
+			Mode: entities.BatchAtomic, Failed: 3, Results: []entities.BatchResult{
+				{Index: 0, Ref: "a", Status: 424, Error: "rolled back, operation 1 failed"},
+				{Index: 1, Ref: "b", Status: 409, Error: "already exists"},
+				{Index: 2, Status: 424, Error: "not run, operation 1 failed"},
+			}}},
+		{desc: "commit failed", txErr: errors.New("connection lost"), expected: entities.BatchResponse{
+			Mode: entities.BatchAtomic, Failed: 3, Results: []entities.BatchResult{
+				{Index: 0, Ref: "a", Status: 500, Error: "rolled back, connection lost"},
+				{Index: 1, Ref: "b", Status: 500, Error: "rolled back, connection lost"},
+				{Index: 2, Status: 500, Error: "rolled back, connection lost"},
+			}}},
+	}
+
+	for _, tc := range testcases {
+		ctrl := gomock.NewController(t)
+		mockAuthorService := service.NewMockAuthorService(ctrl)
+		mockBookService := service.NewMockBookService(ctrl)
+		mockTx := store.NewMockTransactor(ctrl)
+
+		mockTx.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(ctx context.Context) error) error {
+				if err := fn(ctx); err != nil {
+					return err
+				}
+
+				return tc.txErr
+			})
+
+		mockAuthorService.EXPECT().Post(gomock.Any(), gomock.Any()).Return(entities.Author{AuthorID: 7}, nil)
+
+		if tc.bookErr != nil {
+			mockBookService.EXPECT().Post(gomock.Any(), gomock.Any()).Return(entities.Book{}, tc.bookErr)
+		} else {
+			mockBookService.EXPECT().Post(gomock.Any(), &entities.Book{AuthorID: 7, Title: "Dune"}).
+				Return(entities.Book{BookID: 9, AuthorID: 7}, nil)
+			mockAuthorService.EXPECT().Delete(gomock.Any(), 3).Return(nil)
+		}
+
+		response, err := New(mockAuthorService, mockBookService, mockTx, denying{}).Run(context.TODO(),
+			entities.Batch{Operations: operations})
+
+		if err != nil || !reflect.DeepEqual(response, tc.expected) {
+			t.Errorf("%v: unexpected response %+v %v", tc.desc, response, err)
+		}
+
+		ctrl.Finish()
+	}
+}
+
+// TestRunInvalid : test that a malformed batch is refused as a whole
+func TestRunInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s := New(service.NewMockAuthorService(ctrl), service.NewMockBookService(ctrl), store.NewMockTransactor(ctrl),
+		denying{})
+
+	tooMany := make([]entities.BatchOperation, maxOperations+1)
+	for i := range tooMany {
+		tooMany[i] = operation("", "delete", "book", "1", "")
+	}
+
+	testcases := []struct {
+		desc  string
+		batch entities.Batch
+
+		expectedErr string
+	}{
+		{desc: "mode", batch: entities.Batch{Mode: "eventual", Operations: tooMany[:1]},
+			expectedErr: "invalid mode"},
+		{desc: "empty", batch: entities.Batch{}, expectedErr: "invalid batch, no operations"},
+		{desc: "too many", batch: entities.Batch{Operations: tooMany},
+			expectedErr: "invalid batch, more than 1000 operations"},
+		{desc: "entity", batch: entities.Batch{Operations: []entities.BatchOperation{
+			operation("", "delete", "genre", "1", "")}}, expectedErr: "invalid entity in operation 0"},
+		{desc: "op", batch: entities.Batch{Operations: []entities.BatchOperation{
+			operation("", "delete", "book", "1", ""), operation("", "patch", "book", "1", "")}},
+			expectedErr: "invalid op in operation 1"},
+		{desc: "ref", batch: entities.Batch{Operations: []entities.BatchOperation{
+			operation("x", "delete", "book", "1", ""), operation("x", "delete", "book", "2", "")}},
+			expectedErr: "invalid ref x, it names two operations"},
+	}
+
+	for _, tc := range testcases {
+		_, err := s.Run(context.TODO(), tc.batch)
+		if err == nil || !strings.EqualFold(err.Error(), tc.expectedErr) {
+			t.Errorf("%v: expected %v, got %v", tc.desc, tc.expectedErr, err)
+		}
+	}
+}
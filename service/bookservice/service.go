@@ -2,59 +2,111 @@ package bookservice
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
 	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+	"projects/GoLang-Interns-2022/authorbook/service/tagservice"
 	"projects/GoLang-Interns-2022/authorbook/store"
 )
 
+// streamBatch : the books of a stream whose ratings and series are read together
+const streamBatch = 100
+
 type BookService struct {
 	bookService   store.BookStorer
 	authorService store.AuthorStorer
+	auditStore    store.AuditStorer
+	historyStore  store.HistoryStorer
+	outbox        store.OutboxStorer
+	tx            store.Transactor
 }
 
 // New : factory function
-func New(bs store.BookStorer, as store.AuthorStorer) BookService {
-	return BookService{bs, as}
+func New(bs store.BookStorer, as store.AuthorStorer, audit store.AuditStorer, history store.HistoryStorer,
+	outbox store.OutboxStorer, tx store.Transactor) BookService {
+	return BookService{bs, as, audit, history, outbox, tx}
+}
+
+// GetAllBook : implements the logic of getting all book, only the fields and embedded resources of the projection
+// are read
+func (b BookService) GetAllBook(ctx context.Context, filter entities.BookFilter,
+	projection entities.Projection) ([]entities.Book, error) {
+	books := []entities.Book{}
+
+	for i := range filter.Tags {
+		filter.Tags[i] = tagservice.NormalizeName(filter.Tags[i])
+	}
+
+	err := b.bookService.EachBook(ctx, filter, projection, func(book entities.Book) error {
+		books = append(books, book)
+		return nil
+	})
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+
+	if err = b.include(ctx, books, projection); err != nil {
+		log.Print(err)
+		return nil, err
+	}
+
+	return books, nil
 }
 
-// GetAllBook : implements the logic of getting all book
-func (b BookService) GetAllBook(ctx context.Context, title, includeAuthor string) ([]entities.Book, error) {
-	var (
-		books []entities.Book
-		err   error
-	)
+// StreamBooks : calls fn with the books of the listing one at a time, the books are read a batch at a time and the
+// ratings and series of a batch once its rows are closed, so a stream holds a single connection
+func (b BookService) StreamBooks(ctx context.Context, filter entities.BookFilter, projection entities.Projection,
+	fn func(book entities.Book) error) error {
+	for i := range filter.Tags {
+		filter.Tags[i] = tagservice.NormalizeName(filter.Tags[i])
+	}
+
+	filter.Limit = streamBatch
+	batch := make([]entities.Book, 0, streamBatch)
 
-	if title != "" {
-		books, err = b.bookService.GetBooksByTitle(ctx, title)
+	for {
+		batch = batch[:0]
+
+		err := b.bookService.EachBook(ctx, filter, projection, func(book entities.Book) error {
+			batch = append(batch, book)
+			return nil
+		})
 		if err != nil {
 			log.Print(err)
-			return nil, err
+			return err
 		}
-	} else {
-		books, err = b.bookService.GetAllBook(ctx)
-		if err != nil {
-			log.Print(err)
-			return nil, err
+
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err = b.include(ctx, batch, projection); err != nil {
+			return err
 		}
-	}
 
-	if includeAuthor == "true" {
-		for i := range books {
-			author, err := b.authorService.IncludeAuthor(ctx, books[i].AuthorID)
-			if err != nil {
-				log.Print(err)
-				return nil, err
+		for i := range batch {
+			if err = fn(batch[i]); err != nil {
+				return err
 			}
+		}
 
-			books[i].Author = &author
+		if len(batch) < streamBatch {
+			return nil
 		}
-	}
 
-	return books, nil
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		filter.After = batch[len(batch)-1].BookID
+	}
 }
 
 // GetBookByID : implements the logic of getting a single by
@@ -72,28 +124,94 @@ func (b BookService) GetBookByID(ctx context.Context, id int) (entities.Book, er
 	author, err := b.authorService.IncludeAuthor(ctx, book.AuthorID)
 	book.Author = &author
 
+	book.Genres, err = b.bookService.GetGenres(ctx, id)
+	if err != nil {
+		log.Print(err)
+		return entities.Book{}, err
+	}
+
+	book.Tags, err = b.bookService.GetTags(ctx, id)
+	if err != nil {
+		log.Print(err)
+		return entities.Book{}, err
+	}
+
+	book.Series, err = b.bookService.GetSeries(ctx, id)
+	if err != nil {
+		log.Print(err)
+		return entities.Book{}, err
+	}
+
+	if err = b.includeEditions(ctx, &book); err != nil {
+		log.Print(err)
+		return entities.Book{}, err
+	}
+
+	ratings, err := b.bookService.GetRatings(ctx, []int{id})
+	if err != nil {
+		log.Print(err)
+		return entities.Book{}, err
+	}
+
+	if rating, ok := ratings[id]; ok {
+		book.Rating = &rating
+	}
+
 	return book, nil
 }
 
 // Post : checks the book before posting
 func (b BookService) Post(ctx context.Context, book *entities.Book) (entities.Book, error) {
-	if book.Title == "" || book.AuthorID < 0 || checkPublication(book.Publication) ||
-		!checkPublishedDate(book.PublishedDate) {
+	if checkPublication(book.Publication) || !checkPublishedDate(book.PublishedDate) {
 		return entities.Book{}, errors.New("invalid constraints")
 	}
 
-	existAuthor, err := b.authorService.IncludeAuthor(ctx, book.AuthorID)
-	if err != nil {
-		return entities.Book{}, err
+	return b.post(ctx, book)
+}
+
+// Import : checks a book of a catalogue import before posting, it may come from any publisher and year so only the
+// form of its date is checked besides the checks every book goes through
+func (b BookService) Import(ctx context.Context, book *entities.Book) (entities.Book, error) {
+	if _, ok := checkDate(book.PublishedDate); !ok {
+		return entities.Book{}, errors.New("invalid constraints")
 	}
 
-	id, err := b.bookService.Post(ctx, book)
-	if err != nil || id == -1 {
-		return entities.Book{}, errors.New("database issue")
+	return b.post(ctx, book)
+}
+
+// post : checks the title, author and edition of the book and posts it with its categories
+func (b BookService) post(ctx context.Context, book *entities.Book) (entities.Book, error) {
+	if book.Title == "" || book.AuthorID < 0 {
+		return entities.Book{}, errors.New("invalid constraints")
+	}
+
+	if book.Edition != nil && !checkEdition(book.Edition) {
+		return entities.Book{}, errors.New("invalid edition")
 	}
 
-	book.Author = &existAuthor
-	book.BookID = id
+	err := b.tx.WithTx(ctx, func(ctx context.Context) error {
+		existAuthor, err := b.authorService.IncludeAuthor(ctx, book.AuthorID)
+		if err != nil {
+			return err
+		}
+
+		id, err := b.bookService.Post(ctx, book)
+		if err != nil || id == -1 {
+			return errors.New("database issue")
+		}
+
+		book.Author = &existAuthor
+		book.BookID = id
+
+		if err = b.setCategories(ctx, book); err != nil {
+			return err
+		}
+
+		return b.audit(ctx, entities.AuditCreate, id, nil, book)
+	})
+	if err != nil {
+		return entities.Book{}, err
+	}
 
 	return *book, nil
 }
@@ -105,18 +223,38 @@ func (b BookService) Put(ctx context.Context, book *entities.Book, id int) (enti
 		return entities.Book{}, errors.New("invalid constraints")
 	}
 
-	author, err := b.authorService.IncludeAuthor(ctx, book.AuthorID)
-	if err != nil {
-		return entities.Book{}, errors.New("author does not exist")
+	if book.Edition != nil && !checkEdition(book.Edition) {
+		return entities.Book{}, errors.New("invalid edition")
 	}
 
-	count, err := b.bookService.Put(ctx, book, id)
-	if err != nil || count <= 0 {
-		return entities.Book{}, errors.New("book does not exist")
-	}
+	err := b.tx.WithTx(ctx, func(ctx context.Context) error {
+		author, err := b.authorService.IncludeAuthor(ctx, book.AuthorID)
+		if err != nil {
+			return errors.New("author does not exist")
+		}
 
-	book.Author = &author
-	book.BookID = id
+		existBook, err := b.bookService.GetBookByID(ctx, id)
+		if err != nil {
+			return errors.New("book does not exist")
+		}
+
+		count, err := b.bookService.Put(ctx, book, id)
+		if err != nil || count <= 0 {
+			return errors.New("book does not exist")
+		}
+
+		book.Author = &author
+		book.BookID = id
+
+		if err = b.setCategories(ctx, book); err != nil {
+			return err
+		}
+
+		return b.audit(ctx, entities.AuditUpdate, id, &existBook, book)
+	})
+	if err != nil {
+		return entities.Book{}, err
+	}
 
 	return *book, nil
 }
@@ -127,13 +265,258 @@ func (b BookService) Delete(ctx context.Context, id int) error {
 		return errors.New("invalid id")
 	}
 
-	count, err := b.bookService.Delete(ctx, id)
+	return b.tx.WithTx(ctx, func(ctx context.Context) error {
+		// a missing book shows up as nothing deleted below
+		existBook, _ := b.bookService.GetBookByID(ctx, id)
+
+		count, err := b.bookService.Delete(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if count <= 0 {
+			return errors.New("book does not exist")
+		}
+
+		return b.audit(ctx, entities.AuditDelete, id, &existBook, nil)
+	})
+}
+
+// GetBookAsOf : gives the book row as it was at the time, along with its current author
+func (b BookService) GetBookAsOf(ctx context.Context, id int, asOf string) (entities.Book, error) {
+	if id <= 0 {
+		return entities.Book{}, errors.New("invalid id")
+	}
+
+	t, err := time.Parse(time.RFC3339, asOf)
+	if err != nil {
+		return entities.Book{}, errors.New("invalid asOf")
+	}
+
+	rev, err := b.historyStore.GetBookAsOf(ctx, id, t.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil || rev.Operation == entities.AuditDelete {
+		return entities.Book{}, errors.New("book did not exist at that time")
+	}
+
+	book := rev.Book
+
+	if author, err := b.authorService.IncludeAuthor(ctx, book.AuthorID); err == nil {
+		book.Author = &author
+	}
+
+	return book, nil
+}
+
+// GetHistory : gives the revisions of the book, newest first
+func (b BookService) GetHistory(ctx context.Context, id int) ([]entities.BookRevision, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid id")
+	}
+
+	revisions, err := b.historyStore.GetBookHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(revisions) == 0 {
+		// books added before the history was kept have no revisions yet
+		if _, err = b.bookService.GetBookByID(ctx, id); err != nil {
+			return nil, errors.New("book does not exist")
+		}
+
+		revisions = []entities.BookRevision{}
+	}
+
+	return revisions, nil
+}
+
+// Revert : restores the book row of a revision, the restored book goes through the checks of Put
+// and becomes a new revision itself
+func (b BookService) Revert(ctx context.Context, id, revision int) (entities.Book, error) {
+	if id <= 0 || revision <= 0 {
+		return entities.Book{}, errors.New("invalid id")
+	}
+
+	rev, err := b.historyStore.GetBookRevision(ctx, id, revision)
 	if err != nil {
+		return entities.Book{}, errors.New("revision does not exist")
+	}
+
+	book := bookRecord(rev.Book)
+
+	return b.Put(ctx, &book, id)
+}
+
+// events : the event type of each audited operation
+var events = map[string]string{
+	entities.AuditCreate: entities.EventBookCreated,
+	entities.AuditUpdate: entities.EventBookUpdated,
+	entities.AuditDelete: entities.EventBookDeleted,
+}
+
+// audit : records the change of the book row in the audit log, the book history and the outbox,
+// within the transaction of the change
+func (b BookService) audit(ctx context.Context, operation string, id int, before, after *entities.Book) error {
+	entry := entities.AuditEntry{Actor: auth.Actor(ctx), Operation: operation, EntityType: "book", EntityID: id}
+	rev := entities.BookRevision{Operation: operation, Actor: entry.Actor}
+
+	var err error
+
+	if before != nil {
+		rev.Book = bookRecord(*before)
+		if entry.Before, err = json.Marshal(rev.Book); err != nil {
+			return err
+		}
+	}
+
+	if after != nil {
+		rev.Book = bookRecord(*after)
+		if entry.After, err = json.Marshal(rev.Book); err != nil {
+			return err
+		}
+	}
+
+	if err = b.auditStore.Post(ctx, entry); err != nil {
 		return err
 	}
 
-	if count <= 0 {
-		return errors.New("book does not exist")
+	rev.Book.BookID = id
+
+	if err = b.historyStore.PostBook(ctx, rev); err != nil {
+		return err
+	}
+
+	event := entities.Event{Type: events[operation], EntityType: "book", EntityID: id, Actor: entry.Actor,
+		Payload: entry.After}
+	if after == nil {
+		event.Payload = entry.Before
+	}
+
+	return b.outbox.Post(ctx, event)
+}
+
+// bookRecord : the columns of the book row, without the details joined from other tables
+func bookRecord(book entities.Book) entities.Book {
+	return entities.Book{BookID: book.BookID, AuthorID: book.AuthorID, Title: book.Title, Publication: book.Publication,
+		PublishedDate: book.PublishedDate}
+}
+
+// setCategories : attaches the requested genres, tags and edition to the book, nil leaves the existing ones untouched
+func (b BookService) setCategories(ctx context.Context, book *entities.Book) error {
+	if book.GenreIDs != nil {
+		if err := b.bookService.SetGenres(ctx, book.BookID, book.GenreIDs); err != nil {
+			return errors.New("invalid genre")
+		}
+
+		genres, err := b.bookService.GetGenres(ctx, book.BookID)
+		if err != nil {
+			return err
+		}
+
+		book.Genres = genres
+	}
+
+	if book.Tags != nil {
+		tags := make([]string, 0, len(book.Tags))
+
+		for _, tag := range book.Tags {
+			if tag = tagservice.NormalizeName(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+
+		if err := b.bookService.SetTags(ctx, book.BookID, tags); err != nil {
+			return err
+		}
+
+		book.Tags = tags
+	}
+
+	if book.Edition != nil {
+		if err := b.bookService.SetEdition(ctx, book.BookID, *book.Edition); err != nil {
+			return errors.New("invalid edition")
+		}
+
+		return b.includeEditions(ctx, book)
+	}
+
+	return nil
+}
+
+// includeEditions : attaches the edition details of the book and the other editions of the same work
+func (b BookService) includeEditions(ctx context.Context, book *entities.Book) error {
+	edition, err := b.bookService.GetEdition(ctx, book.BookID)
+	if err != nil {
+		return err
+	}
+
+	if edition.WorkID == 0 {
+		book.Edition = nil
+		return nil
+	}
+
+	editions, err := b.bookService.GetEditionsByWork(ctx, edition.WorkID)
+	if err != nil {
+		return err
+	}
+
+	book.Edition = &edition
+	book.Editions = nil
+
+	for i := range editions {
+		if editions[i].BookID != book.BookID {
+			book.Editions = append(book.Editions, editions[i])
+		}
+	}
+
+	return nil
+}
+
+// include : reads the ratings and the series of a listing of books when the projection asks for them
+func (b BookService) include(ctx context.Context, books []entities.Book, projection entities.Projection) error {
+	if projection.Includes("rating") {
+		if err := b.includeRatings(ctx, books); err != nil {
+			return err
+		}
+	}
+
+	if !projection.Expands("series") {
+		return nil
+	}
+
+	ids := make([]int, len(books))
+	for i := range books {
+		ids[i] = books[i].BookID
+	}
+
+	series, err := b.bookService.GetSeriesByBooks(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range books {
+		books[i].Series = series[books[i].BookID]
+	}
+
+	return nil
+}
+
+// includeRatings : attaches the aggregate rating to the books which have approved reviews
+func (b BookService) includeRatings(ctx context.Context, books []entities.Book) error {
+	ids := make([]int, len(books))
+	for i := range books {
+		ids[i] = books[i].BookID
+	}
+
+	ratings, err := b.bookService.GetRatings(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range books {
+		if rating, ok := ratings[books[i].BookID]; ok {
+			books[i].Rating = &rating
+		}
 	}
 
 	return nil
@@ -148,19 +531,83 @@ func checkPublication(publication string) bool {
 
 // checkPublishedDate : validates the published date
 func checkPublishedDate(publishedDate string) bool {
-	Dob := strings.Split(publishedDate, "/")
+	year, ok := checkDate(publishedDate)
+
+	return ok && year >= 1870 && year <= 2022
+}
+
+// checkDate : validates a day/month/year date and gives its year
+func checkDate(date string) (int, bool) {
+	Dob := strings.Split(date, "/")
+	if len(Dob) != 3 {
+		return 0, false
+	}
+
 	day, _ := strconv.Atoi(Dob[0])
 	month, _ := strconv.Atoi(Dob[1])
 	year, _ := strconv.Atoi(Dob[2])
 
 	switch {
 	case day <= 0 || day > 31:
-		return false
+		return 0, false
 	case month <= 0 || month > 12:
+		return 0, false
+	case year <= 0:
+		return 0, false
+	}
+
+	return year, true
+}
+
+// checkEdition : validates the format, page count and ISBN of an edition, the ISBN is stored without separators
+func checkEdition(edition *entities.Edition) bool {
+	edition.Format = strings.ToLower(strings.TrimSpace(edition.Format))
+	edition.ISBN = strings.NewReplacer("-", "", " ", "").Replace(edition.ISBN)
+
+	switch edition.Format {
+	case "hardcover", "paperback", "ebook", "audiobook":
+	default:
 		return false
-	case year < 1870 || year > 2022:
-		return false
 	}
 
-	return true
+	return edition.WorkID > 0 && edition.PageCount >= 0 && checkISBN(edition.ISBN)
+}
+
+// checkISBN : validates the check digit of an ISBN-10 or ISBN-13
+func checkISBN(isbn string) bool {
+	sum := 0
+
+	switch len(isbn) {
+	case 10:
+		for i, c := range isbn {
+			digit := int(c - '0')
+
+			switch {
+			case i == 9 && (c == 'X' || c == 'x'):
+				digit = 10
+			case c < '0' || c > '9':
+				return false
+			}
+
+			sum += (10 - i) * digit
+		}
+
+		return sum%11 == 0
+	case 13:
+		for i, c := range isbn {
+			if c < '0' || c > '9' {
+				return false
+			}
+
+			if i%2 == 1 {
+				sum += 3 * int(c-'0')
+			} else {
+				sum += int(c - '0')
+			}
+		}
+
+		return sum%10 == 0
+	}
+
+	return false
 }
@@ -2,11 +2,13 @@ package bookservice
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"reflect"
 	"testing"
 
 	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
 	"projects/GoLang-Interns-2022/authorbook/store"
 
 	"github.com/golang/mock/gomock"
@@ -17,33 +19,47 @@ func TestGetAllBook(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockAuthorStore := store.NewMockAuthorStorer(ctrl)
 	mockBookStore := store.NewMockBookStorer(ctrl)
-	mock := New(mockBookStore, mockAuthorStore)
+	mock := New(mockBookStore, mockAuthorStore, mockAudit(ctrl), mockHistory(ctrl), mockOutbox(ctrl), mockTx(ctrl))
+
+	book := entities.Book{BookID: 1, Title: "book two"}
+	rating := entities.Rating{Average: 4, Count: 2}
+	series := []entities.BookSeries{{SeriesID: 3, Name: "the rusty books", Position: 1}}
 
 	Testcases := []struct {
-		desc          string
-		title         string
-		includeAuthor string
+		desc       string
+		title      string
+		projection entities.Projection
+		calls      func()
 
 		expected    []entities.Book
 		expectedErr error
 	}{
-		{desc: "getting all books", title: "", includeAuthor: "", expected: []entities.Book{},
-			expectedErr: errors.New("empty")},
-		{desc: "getting book with author and particular", title: "book two", includeAuthor: "",
-			expected: []entities.Book{}, expectedErr: errors.New("empty"),
-		},
-		{desc: "getting book with author and particular title", title: "book", includeAuthor: "true",
-			expected: []entities.Book{}, expectedErr: errors.New("empty"),
-		},
+		{desc: "getting all books", calls: func() {
+			mockBookStore.EXPECT().GetRatings(context.TODO(), []int{1}).Return(map[int]entities.Rating{1: rating}, nil)
+		}, expected: []entities.Book{{BookID: 1, Title: "book two", Rating: &rating}}},
+		{desc: "getting titles with their series", title: "book two",
+			projection: entities.Projection{Fields: []string{"bookID", "title", "series"}, Expand: []string{"series"}},
+			calls: func() {
+				mockBookStore.EXPECT().GetSeriesByBooks(context.TODO(), []int{1}).Return(map[int][]entities.BookSeries{
+					1: series}, nil)
+			}, expected: []entities.Book{{BookID: 1, Title: "book two", Series: series}}},
+		{desc: "failing ratings", calls: func() {
+			mockBookStore.EXPECT().GetRatings(context.TODO(), []int{1}).Return(nil, errors.New("empty"))
+		}, expectedErr: errors.New("empty")},
 	}
 
 	for _, tc := range Testcases {
-		mockBookStore.EXPECT().GetAllBook(context.TODO()).Return(tc.expected, tc.expectedErr).AnyTimes()
-		mockBookStore.EXPECT().GetBooksByTitle(context.TODO(), tc.title).Return(tc.expected, tc.expectedErr).AnyTimes()
+		filter := entities.BookFilter{Title: tc.title}
 
-		books, _ := mock.GetAllBook(context.TODO(), tc.title, tc.includeAuthor)
+		mockBookStore.EXPECT().EachBook(context.TODO(), filter, tc.projection, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ entities.BookFilter, _ entities.Projection, fn func(entities.Book) error) error {
+				return fn(book)
+			})
+		tc.calls()
 
-		if !reflect.DeepEqual(books, tc.expected) {
+		books, err := mock.GetAllBook(context.TODO(), filter, tc.projection)
+
+		if !reflect.DeepEqual(books, tc.expected) || !reflect.DeepEqual(err, tc.expectedErr) {
 			t.Errorf("failed for %v\n", tc.desc)
 		}
 	}
@@ -54,7 +70,7 @@ func TestGetBookByID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockAuthorStore := store.NewMockAuthorStorer(ctrl)
 	mockBookStore := store.NewMockBookStorer(ctrl)
-	mock := New(mockBookStore, mockAuthorStore)
+	mock := New(mockBookStore, mockAuthorStore, mockAudit(ctrl), mockHistory(ctrl), mockOutbox(ctrl), mockTx(ctrl))
 
 	Testcases := []struct {
 		desc     string
@@ -84,41 +100,84 @@ func TestPost(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockAuthorStore := store.NewMockAuthorStorer(ctrl)
 	mockBookStore := store.NewMockBookStorer(ctrl)
-	mock := New(mockBookStore, mockAuthorStore)
+	mock := New(mockBookStore, mockAuthorStore, mockAudit(ctrl), mockHistory(ctrl), mockOutbox(ctrl), mockTx(ctrl))
+
+	author := entities.Author{AuthorID: 1, FirstName: "shani", LastName: "kumar", DOB: "30/05/1999", PenName: "sk"}
 
 	testcases := []struct {
 		desc  string
 		input entities.Book
+		calls func(book *entities.Book)
 
-		expected     entities.Book
-		expectedErr  error
-		expectedErr1 error
+		expected    entities.Book
+		expectedErr error
 	}{
-		{desc: "success case", input: entities.Book{BookID: 0, AuthorID: 1, Title: "deciding decade",
-			Publication: "penguin", PublishedDate: "20/03/2010", Author: &entities.Author{AuthorID: 1, FirstName: "shani",
-				LastName: "kumar", DOB: "30/05/1999", PenName: "sk"}},
-			expected: entities.Book{BookID: 12, AuthorID: 1, Title: "deciding decade", Publication: "penguin",
-				PublishedDate: "20/03/2010", Author: &entities.Author{AuthorID: 1, FirstName: "shani",
-					LastName: "kumar", DOB: "30/05/1999", PenName: "sk"}}, expectedErr: nil, expectedErr1: nil,
-		},
+		{desc: "success case", input: entities.Book{AuthorID: 1, Title: "deciding decade", Publication: "penguin",
+			PublishedDate: "20/03/2010"}, calls: func(book *entities.Book) {
+			mockAuthorStore.EXPECT().IncludeAuthor(context.TODO(), 1).Return(author, nil)
+			mockBookStore.EXPECT().Post(context.TODO(), book).Return(12, nil)
+		}, expected: entities.Book{BookID: 12, AuthorID: 1, Title: "deciding decade", Publication: "penguin",
+			PublishedDate: "20/03/2010", Author: &author}},
+		{desc: "author does not exist", input: entities.Book{AuthorID: 3, Title: "deciding decade",
+			Publication: "penguin", PublishedDate: "20/03/2010"}, calls: func(*entities.Book) {
+			mockAuthorStore.EXPECT().IncludeAuthor(context.TODO(), 3).Return(entities.Author{},
+				errors.New("author does not exist"))
+		}, expectedErr: errors.New("author does not exist")},
+		{desc: "database issue", input: entities.Book{AuthorID: 1, Title: "deciding decade", Publication: "penguin",
+			PublishedDate: "20/03/2010"}, calls: func(book *entities.Book) {
+			mockAuthorStore.EXPECT().IncludeAuthor(context.TODO(), 1).Return(author, nil)
+			mockBookStore.EXPECT().Post(context.TODO(), book).Return(-1, errors.New("connection lost"))
+		}, expectedErr: errors.New("database issue")},
+		{desc: "invalid publication", input: entities.Book{AuthorID: 3, Title: "deciding decade", Publication: "pen",
+			PublishedDate: "20/03/2010"}, calls: func(*entities.Book) {},
+			expectedErr: errors.New("invalid constraints")},
+	}
 
-		{desc: "author does not exist", input: entities.Book{BookID: 1, AuthorID: 3, Title: "deciding decade",
-			Publication: "penguin", PublishedDate: "20/03/2010", Author: &entities.Author{}},
-			expected: entities.Book{}, expectedErr: errors.New("issue"), expectedErr1: errors.New("author does not exist"),
-		},
+	for _, tc := range testcases {
+		tc.calls(&tc.input)
 
-		{desc: "invalid publication", input: entities.Book{BookID: 1, AuthorID: 3, Title: "deciding decade",
-			Publication: "pen", PublishedDate: "20/03/2010", Author: &entities.Author{}},
-			expected: entities.Book{}, expectedErr: nil, expectedErr1: nil,
-		},
+		book, err := mock.Post(context.TODO(), &tc.input)
+
+		if !reflect.DeepEqual(book, tc.expected) || !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v, got: %v %v\n", tc.desc, book, err)
+		}
+	}
+}
+
+// TestImport : to test a catalogue book is posted from any publisher and year with a day/month/year date
+func TestImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockAuthorStore := store.NewMockAuthorStorer(ctrl)
+	mockBookStore := store.NewMockBookStorer(ctrl)
+	mock := New(mockBookStore, mockAuthorStore, mockAudit(ctrl), mockHistory(ctrl), mockOutbox(ctrl), mockTx(ctrl))
+
+	author := entities.Author{AuthorID: 1, FirstName: "frank"}
+
+	testcases := []struct {
+		desc  string
+		input entities.Book
+		calls func(book *entities.Book)
+
+		expectedErr error
+	}{
+		{desc: "other publisher and year", input: entities.Book{AuthorID: 1, Title: "Dune", Publication: "Chilton",
+			PublishedDate: "01/08/1965"}, calls: func(book *entities.Book) {
+			mockAuthorStore.EXPECT().IncludeAuthor(context.TODO(), 1).Return(author, nil)
+			mockBookStore.EXPECT().Post(context.TODO(), book).Return(12, nil)
+		}},
+		{desc: "missing date", input: entities.Book{AuthorID: 1, Title: "Dune", Publication: "Chilton"},
+			calls: func(*entities.Book) {}, expectedErr: errors.New("invalid constraints")},
+		{desc: "missing title", input: entities.Book{AuthorID: 1, Publication: "Chilton", PublishedDate: "01/08/1965"},
+			calls: func(*entities.Book) {}, expectedErr: errors.New("invalid constraints")},
 	}
+
 	for _, tc := range testcases {
-		mockBookStore.EXPECT().Post(context.TODO(), &tc.input).Return(tc.expected.BookID, tc.expectedErr).AnyTimes()
-		mockAuthorStore.EXPECT().IncludeAuthor(context.TODO(), tc.input.AuthorID).Return(tc.input.Author, tc.expectedErr1).AnyTimes()
+		tc.calls(&tc.input)
 
-		book, _ := mock.Post(context.TODO(), &tc.input)
-		if !reflect.DeepEqual(book, tc.expected) {
-			t.Errorf("failed for %v\n", tc.desc)
+		_, err := mock.Import(context.TODO(), &tc.input)
+
+		if !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v, got: %v\n", tc.desc, err)
 		}
 	}
 }
@@ -128,71 +187,363 @@ func TestPut(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockAuthorStore := store.NewMockAuthorStorer(ctrl)
 	mockBookStore := store.NewMockBookStorer(ctrl)
-	mock := New(mockBookStore, mockAuthorStore)
+	mock := New(mockBookStore, mockAuthorStore, mockAudit(ctrl), mockHistory(ctrl), mockOutbox(ctrl), mockTx(ctrl))
+
+	author := entities.Author{AuthorID: 1, FirstName: "shani"}
+	existing := entities.Book{BookID: 12, AuthorID: 1, Title: "old title", Publication: "penguin",
+		PublishedDate: "20/03/2010"}
 
 	testcases := []struct {
 		desc    string
 		input   entities.Book
 		inputID int
+		calls   func(book *entities.Book)
 
 		expected    entities.Book
 		expectedErr error
 	}{
-		{desc: "success case", input: entities.Book{BookID: 12, AuthorID: 1, Title: "deciding decade",
-			Publication: "penguin", PublishedDate: "20/03/2010", Author: &entities.Author{}}, inputID: 1,
-			expected: entities.Book{BookID: 12, AuthorID: 1, Title: "deciding decade", Publication: "penguin",
-				PublishedDate: "20/03/2010", Author: &entities.Author{}}, expectedErr: nil,
-		},
-		{desc: "invalid publication", input: entities.Book{BookID: 1, AuthorID: 1, Title: "deciding decade",
-			Publication: "pen", PublishedDate: "20/03/2010", Author: &entities.Author{}},
-			expected: entities.Book{}, expectedErr: nil,
-		},
-		{desc: "error", input: entities.Book{AuthorID: 1, Title: "deciding decade",
-			Publication: "penguin", PublishedDate: "20/03/2010", Author: &entities.Author{}},
-			expectedErr: errors.New("something went wrong"),
-		},
+		{desc: "success case", input: entities.Book{AuthorID: 1, Title: "deciding decade", Publication: "penguin",
+			PublishedDate: "20/03/2010"}, inputID: 12, calls: func(book *entities.Book) {
+			mockAuthorStore.EXPECT().IncludeAuthor(context.TODO(), 1).Return(author, nil)
+			mockBookStore.EXPECT().GetBookByID(context.TODO(), 12).Return(existing, nil)
+			mockBookStore.EXPECT().Put(context.TODO(), book, 12).Return(1, nil)
+		}, expected: entities.Book{BookID: 12, AuthorID: 1, Title: "deciding decade", Publication: "penguin",
+			PublishedDate: "20/03/2010", Author: &author}},
+		{desc: "invalid publication", input: entities.Book{AuthorID: 1, Title: "deciding decade", Publication: "pen",
+			PublishedDate: "20/03/2010"}, inputID: 12, calls: func(*entities.Book) {},
+			expectedErr: errors.New("invalid constraints")},
+		{desc: "author does not exist", input: entities.Book{AuthorID: 1, Title: "deciding decade",
+			Publication: "penguin", PublishedDate: "20/03/2010"}, inputID: 12, calls: func(*entities.Book) {
+			mockAuthorStore.EXPECT().IncludeAuthor(context.TODO(), 1).Return(entities.Author{}, errors.New("no rows"))
+		}, expectedErr: errors.New("author does not exist")},
+		{desc: "book does not exist", input: entities.Book{AuthorID: 1, Title: "deciding decade",
+			Publication: "penguin", PublishedDate: "20/03/2010"}, inputID: 13, calls: func(*entities.Book) {
+			mockAuthorStore.EXPECT().IncludeAuthor(context.TODO(), 1).Return(author, nil)
+			mockBookStore.EXPECT().GetBookByID(context.TODO(), 13).Return(entities.Book{}, errors.New("no rows"))
+		}, expectedErr: errors.New("book does not exist")},
+		{desc: "error", input: entities.Book{AuthorID: 1, Title: "deciding decade", Publication: "penguin",
+			PublishedDate: "20/03/2010"}, inputID: 12, calls: func(book *entities.Book) {
+			mockAuthorStore.EXPECT().IncludeAuthor(context.TODO(), 1).Return(author, nil)
+			mockBookStore.EXPECT().GetBookByID(context.TODO(), 12).Return(existing, nil)
+			mockBookStore.EXPECT().Put(context.TODO(), book, 12).Return(0, errors.New("something went wrong"))
+		}, expectedErr: errors.New("book does not exist")},
 	}
+
 	for _, tc := range testcases {
-		if tc.desc != "invalid publication" {
-			mockBookStore.EXPECT().Put(context.TODO(), &tc.input, tc.inputID).Return(tc.expected.BookID, tc.expectedErr)
-		}
+		tc.calls(&tc.input)
 
-		book, _ := mock.Put(context.TODO(), &tc.input, tc.inputID)
+		book, err := mock.Put(context.TODO(), &tc.input, tc.inputID)
 
-		if !reflect.DeepEqual(book, tc.expected) {
-			t.Errorf("failed for %v\n", tc.desc)
+		if !reflect.DeepEqual(book, tc.expected) || !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v, got: %v %v\n", tc.desc, book, err)
 		}
 	}
 }
 
-// TestDelete : to test delete method
+// TestDelete : to test delete method, the book is read within the transaction for its audit entry
 func TestDelete(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockAuthorStore := store.NewMockAuthorStorer(ctrl)
 	mockBookStore := store.NewMockBookStorer(ctrl)
-	mock := New(mockBookStore, mockAuthorStore)
+	mock := New(mockBookStore, mockAuthorStore, mockAudit(ctrl), mockHistory(ctrl), mockOutbox(ctrl), mockTx(ctrl))
+
+	book := entities.Book{BookID: 1, AuthorID: 1, Title: "deciding decade", Publication: "penguin",
+		PublishedDate: "20/03/2010"}
 
 	testcases := []struct {
 		desc    string
 		inputID int
+		calls   func()
 
-		expectedID  int
 		expectedErr error
 	}{
-		{"valid id", 1, 1, nil},
-		{"invalid id", -1, -1, nil},
-		{"error case", 1, -1, errors.New("something went wrong")},
+		{"valid id", 1, func() {
+			mockBookStore.EXPECT().GetBookByID(context.TODO(), 1).Return(book, nil)
+			mockBookStore.EXPECT().Delete(context.TODO(), 1).Return(1, nil)
+		}, nil},
+		{"invalid id", -1, func() {}, errors.New("invalid id")},
+		{"book does not exist", 2, func() {
+			mockBookStore.EXPECT().GetBookByID(context.TODO(), 2).Return(entities.Book{}, errors.New("no rows"))
+			mockBookStore.EXPECT().Delete(context.TODO(), 2).Return(0, nil)
+		}, errors.New("book does not exist")},
+		{"error case", 1, func() {
+			mockBookStore.EXPECT().GetBookByID(context.TODO(), 1).Return(book, nil)
+			mockBookStore.EXPECT().Delete(context.TODO(), 1).Return(-1, errors.New("something went wrong"))
+		}, errors.New("something went wrong")},
 	}
 
 	for _, tc := range testcases {
-		if tc.desc != "invalid id" {
-			mockBookStore.EXPECT().Delete(context.TODO(), tc.inputID).Return(tc.expectedID, tc.expectedErr)
-		}
+		tc.calls()
 
 		err := mock.Delete(context.TODO(), tc.inputID)
 
 		if !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v, got: %v\n", tc.desc, err)
+		}
+	}
+}
+
+// TestCheckEdition : to test the validation of the edition details
+func TestCheckEdition(t *testing.T) {
+	testcases := []struct {
+		desc    string
+		edition entities.Edition
+
+		expected     bool
+		expectedISBN string
+	}{
+		{desc: "valid ISBN-13", edition: entities.Edition{WorkID: 1, ISBN: "978-0-306-40615-7", Format: "Paperback",
+			PageCount: 320}, expected: true, expectedISBN: "9780306406157"},
+		{desc: "valid ISBN-10 with X", edition: entities.Edition{WorkID: 1, ISBN: "0-8044-2957-X", Format: "hardcover"},
+			expected: true, expectedISBN: "080442957X"},
+		{desc: "wrong check digit", edition: entities.Edition{WorkID: 1, ISBN: "9780306406158", Format: "ebook"},
+			expected: false, expectedISBN: "9780306406158"},
+		{desc: "unknown format", edition: entities.Edition{WorkID: 1, ISBN: "9780306406157", Format: "scroll"},
+			expected: false, expectedISBN: "9780306406157"},
+		{desc: "missing work", edition: entities.Edition{ISBN: "9780306406157", Format: "audiobook"},
+			expected: false, expectedISBN: "9780306406157"},
+	}
+
+	for _, tc := range testcases {
+		valid := checkEdition(&tc.edition)
+
+		if valid != tc.expected || tc.edition.ISBN != tc.expectedISBN {
 			t.Errorf("failed for %v\n", tc.desc)
 		}
 	}
 }
+
+// TestCheckPublishedDate : to test dates which are not day/month/year are rejected instead of panicking
+func TestCheckPublishedDate(t *testing.T) {
+	testcases := []struct {
+		date     string
+		expected bool
+	}{
+		{"12/05/2010", true},
+		{"", false},
+		{"2010", false},
+		{"12/05", false},
+		{"12/05/2010/1", false},
+		{"12/13/2010", false},
+	}
+
+	for _, tc := range testcases {
+		if valid := checkPublishedDate(tc.date); valid != tc.expected {
+			t.Errorf("failed for %q, got %v", tc.date, valid)
+		}
+	}
+}
+
+// TestAudit : to test that a new book is recorded with its row and actor
+func TestAudit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockAuthorStore := store.NewMockAuthorStorer(ctrl)
+	mockBookStore := store.NewMockBookStorer(ctrl)
+	mockAuditStore := store.NewMockAuditStorer(ctrl)
+	mock := New(mockBookStore, mockAuthorStore, mockAuditStore, mockHistory(ctrl), mockOutbox(ctrl), mockTx(ctrl))
+
+	ctx := auth.NewContext(context.TODO(), auth.Identity{Subject: "asha"})
+	book := entities.Book{AuthorID: 1, Title: "deciding decade", Publication: "penguin", PublishedDate: "20/03/2010"}
+	after, _ := json.Marshal(entities.Book{BookID: 15, AuthorID: 1, Title: "deciding decade", Publication: "penguin",
+		PublishedDate: "20/03/2010"})
+
+	mockAuthorStore.EXPECT().IncludeAuthor(ctx, 1).Return(entities.Author{AuthorID: 1, FirstName: "shani"}, nil)
+	mockBookStore.EXPECT().Post(ctx, &book).Return(15, nil)
+	mockAuditStore.EXPECT().Post(ctx, entities.AuditEntry{Actor: "asha", Operation: entities.AuditCreate,
+		EntityType: "book", EntityID: 15, After: after}).Return(nil)
+
+	if _, err := mock.Post(ctx, &book); err != nil {
+		t.Errorf("failed to post: %v", err)
+	}
+}
+
+// TestEvents : to test that a new book is published with its row through the outbox
+func TestEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockAuthorStore := store.NewMockAuthorStorer(ctrl)
+	mockBookStore := store.NewMockBookStorer(ctrl)
+	mockOutboxStore := store.NewMockOutboxStorer(ctrl)
+	mock := New(mockBookStore, mockAuthorStore, mockAudit(ctrl), mockHistory(ctrl), mockOutboxStore, mockTx(ctrl))
+
+	ctx := auth.NewContext(context.TODO(), auth.Identity{Subject: "asha"})
+	book := entities.Book{AuthorID: 1, Title: "deciding decade", Publication: "penguin", PublishedDate: "20/03/2010"}
+	payload, _ := json.Marshal(entities.Book{BookID: 15, AuthorID: 1, Title: "deciding decade", Publication: "penguin",
+		PublishedDate: "20/03/2010"})
+
+	mockAuthorStore.EXPECT().IncludeAuthor(ctx, 1).Return(entities.Author{AuthorID: 1, FirstName: "shani"}, nil)
+	mockBookStore.EXPECT().Post(ctx, &book).Return(15, nil)
+	mockOutboxStore.EXPECT().Post(ctx, entities.Event{Type: entities.EventBookCreated, EntityType: "book",
+		EntityID: 15, Actor: "asha", Payload: payload}).Return(nil)
+
+	if _, err := mock.Post(ctx, &book); err != nil {
+		t.Errorf("failed to post: %v", err)
+	}
+}
+
+// TestGetBookAsOf : to test the point-in-time read of a book
+func TestGetBookAsOf(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockAuthorStore := store.NewMockAuthorStorer(ctrl)
+	mockHistoryStore := store.NewMockHistoryStorer(ctrl)
+	mock := New(store.NewMockBookStorer(ctrl), mockAuthorStore, mockAudit(ctrl), mockHistoryStore, mockOutbox(ctrl),
+		mockTx(ctrl))
+
+	book := entities.Book{BookID: 3, AuthorID: 1, Title: "old title", Publication: "penguin",
+		PublishedDate: "20/03/2010"}
+	author := entities.Author{AuthorID: 1, FirstName: "shani"}
+
+	testcases := []struct {
+		desc string
+		asOf string
+
+		dbTime      string
+		rev         entities.BookRevision
+		revErr      error
+		expected    entities.Book
+		expectedErr error
+	}{
+		{desc: "updated book", asOf: "2022-08-01T15:30:00+05:30", dbTime: "2022-08-01 10:00:00",
+			rev: entities.BookRevision{Revision: 2, Operation: entities.AuditUpdate, Book: book},
+			expected: entities.Book{BookID: 3, AuthorID: 1, Title: "old title", Publication: "penguin",
+				PublishedDate: "20/03/2010", Author: &author}},
+		{desc: "deleted by then", asOf: "2022-09-01T00:00:00Z", dbTime: "2022-09-01 00:00:00",
+			rev:         entities.BookRevision{Revision: 3, Operation: entities.AuditDelete, Book: book},
+			expectedErr: errors.New("book did not exist at that time")},
+		{desc: "before the first revision", asOf: "2020-01-01T00:00:00Z", dbTime: "2020-01-01 00:00:00",
+			revErr: errors.New("no rows"), expectedErr: errors.New("book did not exist at that time")},
+		{desc: "invalid time", asOf: "yesterday", expectedErr: errors.New("invalid asOf")},
+	}
+
+	for _, tc := range testcases {
+		mockHistoryStore.EXPECT().GetBookAsOf(context.TODO(), 3, tc.dbTime).Return(tc.rev, tc.revErr).AnyTimes()
+		mockAuthorStore.EXPECT().IncludeAuthor(context.TODO(), 1).Return(author, nil).AnyTimes()
+
+		result, err := mock.GetBookAsOf(context.TODO(), 3, tc.asOf)
+
+		if !reflect.DeepEqual(err, tc.expectedErr) || !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("failed for %v, got: %v %v\n", tc.desc, result, err)
+		}
+	}
+}
+
+// TestRevert : to test a revision is restored through the checks of Put
+func TestRevert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockAuthorStore := store.NewMockAuthorStorer(ctrl)
+	mockBookStore := store.NewMockBookStorer(ctrl)
+	mockHistoryStore := store.NewMockHistoryStorer(ctrl)
+	mock := New(mockBookStore, mockAuthorStore, mockAudit(ctrl), mockHistoryStore, mockOutbox(ctrl), mockTx(ctrl))
+
+	old := entities.Book{BookID: 3, AuthorID: 1, Title: "old title", Publication: "penguin", PublishedDate: "20/03/2010"}
+	current := entities.Book{BookID: 3, AuthorID: 1, Title: "new title", Publication: "penguin",
+		PublishedDate: "20/03/2010"}
+	author := entities.Author{AuthorID: 1, FirstName: "shani"}
+
+	mockHistoryStore.EXPECT().GetBookRevision(context.TODO(), 3, 1).Return(entities.BookRevision{Revision: 1,
+		Operation: entities.AuditCreate, Book: old}, nil)
+	mockHistoryStore.EXPECT().GetBookRevision(context.TODO(), 3, 9).Return(entities.BookRevision{},
+		errors.New("no rows"))
+	mockHistoryStore.EXPECT().PostBook(context.TODO(), entities.BookRevision{Operation: entities.AuditUpdate,
+		Actor: "anonymous", Book: old}).Return(nil)
+	mockAuthorStore.EXPECT().IncludeAuthor(context.TODO(), 1).Return(author, nil)
+	mockBookStore.EXPECT().GetBookByID(context.TODO(), 3).Return(current, nil)
+	mockBookStore.EXPECT().Put(context.TODO(), &old, 3).Return(1, nil)
+
+	book, err := mock.Revert(context.TODO(), 3, 1)
+	if err != nil || book.Title != "old title" {
+		t.Errorf("failed to revert: %v %v", book, err)
+	}
+
+	if _, err = mock.Revert(context.TODO(), 3, 9); !reflect.DeepEqual(err, errors.New("revision does not exist")) {
+		t.Errorf("expected missing revision, got: %v", err)
+	}
+}
+
+// mockAudit : an audit store accepting every entry
+func mockAudit(ctrl *gomock.Controller) *store.MockAuditStorer {
+	m := store.NewMockAuditStorer(ctrl)
+	m.EXPECT().Post(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	return m
+}
+
+// mockHistory : a history store accepting every revision
+func mockHistory(ctrl *gomock.Controller) *store.MockHistoryStorer {
+	m := store.NewMockHistoryStorer(ctrl)
+	m.EXPECT().PostAuthor(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	m.EXPECT().PostBook(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	return m
+}
+
+// mockOutbox : an outbox accepting every event
+func mockOutbox(ctrl *gomock.Controller) *store.MockOutboxStorer {
+	m := store.NewMockOutboxStorer(ctrl)
+	m.EXPECT().Post(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	return m
+}
+
+// mockTx : a transactor running the function on the given context
+func mockTx(ctrl *gomock.Controller) *store.MockTransactor {
+	m := store.NewMockTransactor(ctrl)
+	m.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) }).AnyTimes()
+
+	return m
+}
+
+// TestStreamBooks : test that the stream reads the ratings once per batch of books
+func TestStreamBooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockAuthorStore := store.NewMockAuthorStorer(ctrl)
+	mockBookStore := store.NewMockBookStorer(ctrl)
+	mock := New(mockBookStore, mockAuthorStore, mockAudit(ctrl), mockHistory(ctrl), mockOutbox(ctrl), mockTx(ctrl))
+
+	filter := entities.BookFilter{Tags: []string{" Kids "}}
+
+	// the books are read a batch at a time after the last id of the previous batch
+	each := func(from, to int) func(context.Context, entities.BookFilter, entities.Projection,
+		func(entities.Book) error) error {
+		return func(_ context.Context, _ entities.BookFilter, _ entities.Projection, fn func(entities.Book) error) error {
+			for i := from; i <= to; i++ {
+				if err := fn(entities.Book{BookID: i}); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+	}
+
+	gomock.InOrder(
+		mockBookStore.EXPECT().EachBook(gomock.Any(), entities.BookFilter{Tags: []string{"kids"}, Limit: streamBatch},
+			entities.Projection{}, gomock.Any()).DoAndReturn(each(1, streamBatch)),
+		mockBookStore.EXPECT().EachBook(gomock.Any(), entities.BookFilter{Tags: []string{"kids"}, After: streamBatch,
+			Limit: streamBatch}, entities.Projection{}, gomock.Any()).DoAndReturn(each(streamBatch+1, streamBatch+1)),
+	)
+	mockBookStore.EXPECT().GetRatings(gomock.Any(), gomock.Any()).Return(map[int]entities.Rating{1: {Count: 2}}, nil)
+	mockBookStore.EXPECT().GetRatings(gomock.Any(), []int{streamBatch + 1}).Return(map[int]entities.Rating{}, nil)
+
+	var books []entities.Book
+
+	err := mock.StreamBooks(context.TODO(), filter, entities.Projection{}, func(book entities.Book) error {
+		books = append(books, book)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if len(books) != streamBatch+1 || books[0].Rating == nil || books[0].Rating.Count != 2 || books[1].Rating != nil {
+		t.Errorf("unexpected books %v", books)
+	}
+
+	stop := errors.New("stop")
+
+	mockBookStore.EXPECT().EachBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(stop)
+
+	if err := mock.StreamBooks(context.TODO(), entities.BookFilter{}, entities.Projection{}, nil); err != stop {
+		t.Errorf("expected %v, got %v", stop, err)
+	}
+}
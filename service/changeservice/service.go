@@ -0,0 +1,142 @@
+package changeservice
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/events"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+// batchSize : the events read from the outbox at a time
+const batchSize = 500
+
+// commitGrace : how long an event is read again after it was first seen, an event with a lower id whose
+// transaction commits within it is still sent since the ids are taken on insert and not on commit
+const commitGrace = 10 * time.Second
+
+// Subscriber : the bus the outbox relay of this process publishes the events to
+type Subscriber interface {
+	Subscribe(h events.Handler) (cancel func())
+}
+
+type ChangeService struct {
+	outbox   store.OutboxStorer
+	bus      Subscriber
+	interval time.Duration
+	grace    time.Duration
+}
+
+// New : factory function, the outbox is read every interval so the events written by every instance are followed,
+// an event published on the bus of this process reads it right away
+func New(o store.OutboxStorer, bus Subscriber, interval time.Duration) ChangeService {
+	return ChangeService{o, bus, interval, commitGrace}
+}
+
+// Follow : sends the events after the event with the id after which pass the filter until the context is done or
+// send fails, from the latest event on when after is entities.LatestEvent. An event is sent once per call; one
+// whose transaction commits more than the grace period after a later event was seen is missed
+func (c ChangeService) Follow(ctx context.Context, after int, filter entities.EventFilter,
+	send func(event entities.Event) error) error {
+	wake := make(chan struct{}, 1)
+
+	cancel := c.bus.Subscribe(func(_ context.Context, event entities.Event) error {
+		if filter.Matches(event) {
+			select {
+			case wake <- struct{}{}:
+			default:
+			}
+		}
+
+		return nil
+	})
+	defer cancel()
+
+	if after == entities.LatestEvent {
+		var err error
+
+		if after, err = c.outbox.LastID(ctx); err != nil {
+			return err
+		}
+	}
+
+	f := feed{after: after, seen: make(map[int]time.Time)}
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.poll(ctx, &f, filter, send); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// feed : the position of a follower, the events after the id after are read on each poll and the ones among them
+// which were sent already are in seen with the time they were first read
+type feed struct {
+	after int
+	seen  map[int]time.Time
+}
+
+// poll : sends the events after the position which were not sent yet and moves the position past the events seen
+// longer than the grace period ago, so seen only holds the events of that period
+func (c ChangeService) poll(ctx context.Context, f *feed, filter entities.EventFilter,
+	send func(event entities.Event) error) error {
+	now := time.Now()
+
+	for from := f.after; ; {
+		batch, err := c.outbox.GetSince(ctx, from, filter, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for i := range batch {
+			if _, ok := f.seen[batch[i].EventID]; ok {
+				continue
+			}
+
+			if err := send(batch[i]); err != nil {
+				return err
+			}
+
+			f.seen[batch[i].EventID] = now
+		}
+
+		if len(batch) < batchSize {
+			break
+		}
+
+		from = batch[len(batch)-1].EventID
+	}
+
+	ids := make([]int, 0, len(f.seen))
+	for id := range f.seen {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		if now.Sub(f.seen[id]) >= c.grace {
+			f.after = id
+		}
+	}
+
+	for _, id := range ids {
+		if id <= f.after {
+			delete(f.seen, id)
+		}
+	}
+
+	return nil
+}
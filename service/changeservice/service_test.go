@@ -0,0 +1,137 @@
+package changeservice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/events"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+// TestFollow : test that a new feed starts at the latest event, is woken by a published event and sends an event
+// committed late with a lower id once, without the ones it sent already
+func TestFollow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOutbox := store.NewMockOutboxStorer(ctrl)
+	bus := events.NewBus()
+	filter := entities.EventFilter{EntityType: "book"}
+
+	event := func(id int) entities.Event {
+		return entities.Event{EventID: id, Type: entities.EventBookUpdated, EntityType: "book", EntityID: 3}
+	}
+
+	mockOutbox.EXPECT().LastID(gomock.Any()).Return(4, nil)
+	gomock.InOrder(
+		mockOutbox.EXPECT().GetSince(gomock.Any(), 4, filter, batchSize).DoAndReturn(
+			func(ctx context.Context, _ int, _ entities.EventFilter, _ int) ([]entities.Event, error) {
+				_ = bus.Publish(ctx, event(8))
+				return []entities.Event{event(5), event(7)}, nil
+			}),
+		mockOutbox.EXPECT().GetSince(gomock.Any(), 4, filter, batchSize).
+			Return([]entities.Event{event(5), event(6), event(7), event(8)}, nil),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var sent []entities.Event
+
+	err := New(mockOutbox, bus, time.Hour).Follow(ctx, entities.LatestEvent, filter, func(e entities.Event) error {
+		sent = append(sent, e)
+		if len(sent) == 4 {
+			cancel()
+		}
+
+		return nil
+	})
+
+	expected := []entities.Event{event(5), event(7), event(6), event(8)}
+
+	if err != nil || !reflect.DeepEqual(sent, expected) {
+		t.Errorf("unexpected feed %v %v", sent, err)
+	}
+}
+
+// TestPoll : test that the position moves past the events seen longer than the grace period ago and that they are
+// forgotten, reading the outbox a batch at a time
+func TestPoll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOutbox := store.NewMockOutboxStorer(ctrl)
+
+	full := make([]entities.Event, batchSize)
+	for i := range full {
+		full[i] = entities.Event{EventID: 5 + i}
+	}
+
+	last := entities.Event{EventID: 5 + batchSize}
+
+	gomock.InOrder(
+		mockOutbox.EXPECT().GetSince(gomock.Any(), 4, entities.EventFilter{}, batchSize).Return(full, nil),
+		mockOutbox.EXPECT().GetSince(gomock.Any(), 4+batchSize, entities.EventFilter{}, batchSize).
+			Return([]entities.Event{last}, nil),
+	)
+
+	c := New(mockOutbox, events.NewBus(), time.Hour)
+	c.grace = 0
+
+	f := feed{after: 4, seen: make(map[int]time.Time)}
+	sent := 0
+
+	err := c.poll(context.TODO(), &f, entities.EventFilter{}, func(entities.Event) error {
+		sent++
+		return nil
+	})
+
+	if err != nil || sent != batchSize+1 || f.after != last.EventID || len(f.seen) != 0 {
+		t.Errorf("unexpected poll %v %v %v %v", err, sent, f.after, len(f.seen))
+	}
+}
+
+// TestFollowErrors : test that the feed stops when the outbox or the client fails
+func TestFollowErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOutbox := store.NewMockOutboxStorer(ctrl)
+	event := entities.Event{EventID: 5, Type: entities.EventBookCreated, EntityType: "book", EntityID: 3}
+
+	testcases := []struct {
+		desc    string
+		after   int
+		lastErr error
+		stored  []entities.Event
+		readErr error
+		sendErr error
+
+		expectedErr error
+	}{
+		{desc: "latest event unreadable", after: entities.LatestEvent, lastErr: errors.New("connection lost"),
+			expectedErr: errors.New("connection lost")},
+		{desc: "outbox unreadable", after: 4, readErr: errors.New("connection lost"),
+			expectedErr: errors.New("connection lost")},
+		{desc: "client gone", after: 4, stored: []entities.Event{event}, sendErr: errors.New("broken pipe"),
+			expectedErr: errors.New("broken pipe")},
+	}
+
+	for _, tc := range testcases {
+		if tc.after == entities.LatestEvent {
+			mockOutbox.EXPECT().LastID(gomock.Any()).Return(0, tc.lastErr)
+		} else {
+			mockOutbox.EXPECT().GetSince(gomock.Any(), tc.after, entities.EventFilter{}, batchSize).
+				Return(tc.stored, tc.readErr)
+		}
+
+		err := New(mockOutbox, events.NewBus(), time.Hour).Follow(context.TODO(), tc.after, entities.EventFilter{},
+			func(entities.Event) error {
+				return tc.sendErr
+			})
+
+		if !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("%v: expected %v, got %v", tc.desc, tc.expectedErr, err)
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package exportservice
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+// the MARC 21 separators of the ISO 2709 exchange format
+const (
+	marcSubfield = 0x1f
+	marcField    = 0x1e
+	marcRecord   = 0x1d
+)
+
+const (
+	marcXMLNamespace = "http://www.loc.gov/MARC21/slim"
+	oaiDCNamespace   = "http://www.openarchives.org/OAI/2.0/oai_dc/"
+	dcNamespace      = "http://purl.org/dc/elements/1.1/"
+)
+
+// marcCleaner : drops the separators of the exchange format from values so they can not break the record
+var marcCleaner = strings.NewReplacer(string(rune(marcSubfield)), "", string(rune(marcField)), "",
+	string(rune(marcRecord)), "")
+
+// bibliographic : the formats of library systems, they describe books only and always carry the author
+var bibliographic = map[string]bool{"marcxml": true, "marc": true, "bibtex": true, "dc": true}
+
+// newRecordWriter : gives the writer of a bibliographic format, a bulk export wraps the records of the XML formats
+// in a collection while a single record is the root element itself
+func newRecordWriter(w io.Writer, format string, bulk bool) (rowWriter, error) {
+	bw := bufio.NewWriter(w)
+
+	switch format {
+	case "marcxml":
+		mw := marcXMLWriter{bw, bulk}
+		if bulk {
+			bw.WriteString(xml.Header + `<collection xmlns="` + marcXMLNamespace + `">` + "\n")
+		}
+
+		return mw, nil
+	case "marc":
+		return marcWriter{bw}, nil
+	case "bibtex":
+		return bibtexWriter{bw}, nil
+	case "dc":
+		if bulk {
+			bw.WriteString(xml.Header + `<records xmlns:oai_dc="` + oaiDCNamespace + `" xmlns:dc="` + dcNamespace + `">` +
+				"\n")
+		}
+
+		return dcWriter{bw, bulk}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported format %v", format)
+}
+
+// marcSubfieldValue : a subfield of a data field
+type marcSubfieldValue struct {
+	code  byte
+	value string
+}
+
+// marcFieldValue : a control field when subfields is empty, a data field otherwise
+type marcFieldValue struct {
+	tag       string
+	ind1      byte
+	ind2      byte
+	value     string
+	subfields []marcSubfieldValue
+}
+
+// marcFields : maps a book onto MARC 21 bibliographic fields, 001 the id, 100 the author, 245 the title
+// and 264 the publisher with the year of publication
+func marcFields(book entities.Book) []marcFieldValue {
+	fields := []marcFieldValue{{tag: "001", value: strconv.Itoa(book.BookID)}}
+
+	titleInd := byte('0')
+
+	if name := authorName(book); name != "" {
+		fields = append(fields, marcFieldValue{tag: "100", ind1: '1', ind2: ' ',
+			subfields: []marcSubfieldValue{{'a', name}}})
+		titleInd = '1'
+	}
+
+	fields = append(fields, marcFieldValue{tag: "245", ind1: titleInd, ind2: '0',
+		subfields: []marcSubfieldValue{{'a', book.Title}}})
+
+	imprint := marcFieldValue{tag: "264", ind1: ' ', ind2: '1'}
+	if book.Publication != "" {
+		imprint.subfields = append(imprint.subfields, marcSubfieldValue{'b', book.Publication})
+	}
+
+	if year := publishedYear(book.PublishedDate); year != "" {
+		imprint.subfields = append(imprint.subfields, marcSubfieldValue{'c', year})
+	}
+
+	if len(imprint.subfields) > 0 {
+		fields = append(fields, imprint)
+	}
+
+	return fields
+}
+
+// marcLeader : the leader of a new monograph record in UTF-8, the lengths are only known to the binary format
+func marcLeader(length, base int) string {
+	return fmt.Sprintf("%05dnam a22%05d   4500", length, base)
+}
+
+type marcXMLWriter struct {
+	w    *bufio.Writer
+	bulk bool
+}
+
+func (mw marcXMLWriter) write(record interface{}, _ []string) error {
+	book, _ := record.(entities.Book)
+
+	if mw.bulk {
+		mw.w.WriteString("<record>")
+	} else {
+		mw.w.WriteString(xml.Header + `<record xmlns="` + marcXMLNamespace + `">`)
+	}
+
+	mw.w.WriteString("<leader>" + marcLeader(0, 0) + "</leader>")
+
+	for _, field := range marcFields(book) {
+		if len(field.subfields) == 0 {
+			mw.w.WriteString(`<controlfield tag="` + field.tag + `">` + escape(field.value) + `</controlfield>`)
+			continue
+		}
+
+		mw.w.WriteString(`<datafield tag="` + field.tag + `" ind1="` + string(field.ind1) + `" ind2="` +
+			string(field.ind2) + `">`)
+
+		for _, sub := range field.subfields {
+			mw.w.WriteString(`<subfield code="` + string(sub.code) + `">` + escape(sub.value) + `</subfield>`)
+		}
+
+		mw.w.WriteString("</datafield>")
+	}
+
+	_, err := mw.w.WriteString("</record>\n")
+
+	return err
+}
+
+func (mw marcXMLWriter) close() error {
+	if mw.bulk {
+		mw.w.WriteString("</collection>\n")
+	}
+
+	return mw.w.Flush()
+}
+
+// marcWriter : writes ISO 2709 records, the directory gives the length and offset of every field in bytes
+type marcWriter struct {
+	w *bufio.Writer
+}
+
+func (mw marcWriter) write(record interface{}, _ []string) error {
+	book, _ := record.(entities.Book)
+
+	var directory, data strings.Builder
+
+	for _, field := range marcFields(book) {
+		start := data.Len()
+
+		if len(field.subfields) == 0 {
+			data.WriteString(marcCleaner.Replace(field.value))
+		} else {
+			data.WriteByte(field.ind1)
+			data.WriteByte(field.ind2)
+
+			for _, sub := range field.subfields {
+				data.WriteByte(marcSubfield)
+				data.WriteByte(sub.code)
+				data.WriteString(marcCleaner.Replace(sub.value))
+			}
+		}
+
+		data.WriteByte(marcField)
+
+		fmt.Fprintf(&directory, "%s%04d%05d", field.tag, data.Len()-start, start)
+	}
+
+	directory.WriteByte(marcField)
+
+	base := 24 + directory.Len()
+
+	mw.w.WriteString(marcLeader(base+data.Len()+1, base))
+	mw.w.WriteString(directory.String())
+	mw.w.WriteString(data.String())
+
+	return mw.w.WriteByte(marcRecord)
+}
+
+func (mw marcWriter) close() error {
+	return mw.w.Flush()
+}
+
+// bibtexEscaper : escapes the characters which have a meaning to BibTeX or LaTeX
+var bibtexEscaper = strings.NewReplacer(`\`, `\textbackslash{}`, "{", `\{`, "}", `\}`, "&", `\&`, "%", `\%`,
+	"$", `\$`, "#", `\#`, "_", `\_`, "~", `\textasciitilde{}`, "^", `\textasciicircum{}`)
+
+type bibtexWriter struct {
+	w *bufio.Writer
+}
+
+func (bw bibtexWriter) write(record interface{}, _ []string) error {
+	book, _ := record.(entities.Book)
+
+	bw.w.WriteString("@book{book" + strconv.Itoa(book.BookID) + ",\n")
+
+	entries := []struct{ name, value string }{
+		{"author", authorName(book)},
+		{"title", book.Title},
+		{"publisher", book.Publication},
+		{"year", publishedYear(book.PublishedDate)},
+	}
+
+	for _, entry := range entries {
+		if entry.value != "" {
+			bw.w.WriteString("  " + entry.name + " = {" + bibtexEscaper.Replace(entry.value) + "},\n")
+		}
+	}
+
+	_, err := bw.w.WriteString("}\n\n")
+
+	return err
+}
+
+func (bw bibtexWriter) close() error {
+	return bw.w.Flush()
+}
+
+type dcWriter struct {
+	w    *bufio.Writer
+	bulk bool
+}
+
+func (dw dcWriter) write(record interface{}, _ []string) error {
+	book, _ := record.(entities.Book)
+
+	if dw.bulk {
+		dw.w.WriteString("<oai_dc:dc>")
+	} else {
+		dw.w.WriteString(xml.Header + `<oai_dc:dc xmlns:oai_dc="` + oaiDCNamespace + `" xmlns:dc="` + dcNamespace + `">`)
+	}
+
+	elements := []struct{ name, value string }{
+		{"identifier", "book:" + strconv.Itoa(book.BookID)},
+		{"title", book.Title},
+		{"creator", authorName(book)},
+		{"publisher", book.Publication},
+		{"date", isoDate(book.PublishedDate)},
+		{"type", "Text"},
+	}
+
+	for _, element := range elements {
+		if element.value != "" {
+			dw.w.WriteString("<dc:" + element.name + ">" + escape(element.value) + "</dc:" + element.name + ">")
+		}
+	}
+
+	_, err := dw.w.WriteString("</oai_dc:dc>\n")
+
+	return err
+}
+
+func (dw dcWriter) close() error {
+	if dw.bulk {
+		dw.w.WriteString("</records>\n")
+	}
+
+	return dw.w.Flush()
+}
+
+// authorName : the author in the inverted form catalogues sort by, "Last, First"
+func authorName(book entities.Book) string {
+	if book.Author == nil {
+		return ""
+	}
+
+	switch {
+	case book.Author.LastName == "":
+		return book.Author.FirstName
+	case book.Author.FirstName == "":
+		return book.Author.LastName
+	}
+
+	return book.Author.LastName + ", " + book.Author.FirstName
+}
+
+// publishedYear : the year of a dd/mm/yyyy published date
+func publishedYear(date string) string {
+	parts := strings.Split(date, "/")
+	if len(parts) != 3 || len(parts[2]) != 4 {
+		return ""
+	}
+
+	return parts[2]
+}
+
+// isoDate : a dd/mm/yyyy published date in the yyyy-mm-dd form of Dublin Core, other values are kept as they are
+func isoDate(date string) string {
+	parts := strings.Split(date, "/")
+	if len(parts) != 3 {
+		return date
+	}
+
+	day, errDay := strconv.Atoi(parts[0])
+	month, errMonth := strconv.Atoi(parts[1])
+	year, errYear := strconv.Atoi(parts[2])
+
+	if errDay != nil || errMonth != nil || errYear != nil {
+		return date
+	}
+
+	return fmt.Sprintf("%04d-%02d-%02d", year, month, day)
+}
+
+// escape : the value as XML character data
+func escape(value string) string {
+	var sb strings.Builder
+
+	_ = xml.EscapeText(&sb, []byte(value))
+
+	return sb.String()
+}
@@ -0,0 +1,136 @@
+package exportservice
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+
+	"github.com/golang/mock/gomock"
+)
+
+// TestExportBook : test the library formats of a single book
+func TestExportBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bookStore := store.NewMockBookStorer(ctrl)
+	authorStore := store.NewMockAuthorStorer(ctrl)
+	mock := New(bookStore, authorStore)
+
+	book := entities.Book{BookID: 7, AuthorID: 1, Title: "Tom & Jerry_100%", Publication: "Penguin",
+		PublishedDate: "11/03/1980"}
+	author := entities.Author{AuthorID: 1, FirstName: "Ruskin", LastName: "Bond", DOB: "19/05/1934"}
+
+	testcases := []struct {
+		desc   string
+		id     int
+		format string
+		found  bool
+
+		expected    string
+		expectedErr error
+	}{
+		{desc: "marcxml", id: 7, format: "marcxml", found: true, expected: `<?xml version="1.0" encoding="UTF-8"?>` +
+			"\n" + `<record xmlns="http://www.loc.gov/MARC21/slim"><leader>00000nam a2200000   4500</leader>` +
+			`<controlfield tag="001">7</controlfield>` +
+			`<datafield tag="100" ind1="1" ind2=" "><subfield code="a">Bond, Ruskin</subfield></datafield>` +
+			`<datafield tag="245" ind1="1" ind2="0"><subfield code="a">Tom &amp; Jerry_100%</subfield></datafield>` +
+			`<datafield tag="264" ind1=" " ind2="1"><subfield code="b">Penguin</subfield>` +
+			`<subfield code="c">1980</subfield></datafield></record>` + "\n"},
+		{desc: "bibtex", id: 7, format: "bibtex", found: true, expected: "@book{book7,\n  author = {Bond, Ruskin},\n" +
+			"  title = {Tom \\& Jerry\\_100\\%},\n  publisher = {Penguin},\n  year = {1980},\n}\n\n"},
+		{desc: "dublin core", id: 7, format: "dc", found: true, expected: `<?xml version="1.0" encoding="UTF-8"?>` +
+			"\n" + `<oai_dc:dc xmlns:oai_dc="http://www.openarchives.org/OAI/2.0/oai_dc/" ` +
+			`xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:identifier>book:7</dc:identifier>` +
+			`<dc:title>Tom &amp; Jerry_100%</dc:title><dc:creator>Bond, Ruskin</dc:creator>` +
+			`<dc:publisher>Penguin</dc:publisher><dc:date>1980-03-11</dc:date><dc:type>Text</dc:type></oai_dc:dc>` + "\n"},
+		{desc: "missing book", id: 8, format: "bibtex", expectedErr: errors.New("book does not exist")},
+		{desc: "invalid id", id: -1, format: "bibtex", expectedErr: errors.New("invalid id")},
+		{desc: "invalid format", id: 7, format: "pdf", expectedErr: errors.New("invalid constraints")},
+	}
+
+	for _, tc := range testcases {
+		switch {
+		case tc.found:
+			bookStore.EXPECT().GetBookByID(context.TODO(), tc.id).Return(book, nil)
+			authorStore.EXPECT().IncludeAuthor(context.TODO(), 1).Return(author, nil)
+		case tc.id > 0 && tc.format != "pdf":
+			bookStore.EXPECT().GetBookByID(context.TODO(), tc.id).Return(entities.Book{}, sql.ErrNoRows)
+		}
+
+		var buf bytes.Buffer
+
+		err := mock.ExportBook(context.TODO(), &buf, tc.id, tc.format)
+
+		if !reflect.DeepEqual(err, tc.expectedErr) || buf.String() != tc.expected {
+			t.Errorf("failed for %v, got: %q, %v\n", tc.desc, buf.String(), err)
+		}
+	}
+}
+
+// TestExportMARC : test the leader and directory of binary records and the collection of a bulk MARCXML export
+func TestExportMARC(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bookStore := store.NewMockBookStorer(ctrl)
+	mock := New(bookStore, store.NewMockAuthorStorer(ctrl))
+
+	books := []entities.Book{
+		{BookID: 7, AuthorID: 1, Title: "Rusty", Publication: "Penguin", PublishedDate: "11/03/1980",
+			Author: &entities.Author{FirstName: "Ruskin", LastName: "Bond"}},
+		{BookID: 8, AuthorID: 2, Title: "Gitanjali"},
+	}
+
+	bookStore.EXPECT().EachBook(context.TODO(), entities.BookFilter{}, authorProjection, gomock.Any()).
+		DoAndReturn(eachBook(books...)).Times(2)
+
+	var buf bytes.Buffer
+
+	if err := mock.Export(context.TODO(), &buf, entities.ExportOptions{Format: "marc"}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	records := strings.Split(strings.TrimSuffix(buf.String(), "\x1d"), "\x1d")
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records got %v", len(records))
+	}
+
+	// 4 directory entries of 12 bytes and a terminator after the leader put the data at 73, the data takes 47 bytes
+	first := "00121nam a2200073   4500001000200000100001700002245001000019264001800029\x1e7\x1e" +
+		"1 \x1faBond, Ruskin\x1e10\x1faRusty\x1e 1\x1fbPenguin\x1fc1980\x1e"
+	if records[0] != first {
+		t.Errorf("unexpected record %q", records[0])
+	}
+
+	buf.Reset()
+
+	if err := mock.Export(context.TODO(), &buf, entities.ExportOptions{Format: "marcxml"}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	xml := buf.String()
+	if !strings.HasPrefix(xml, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<collection xmlns="http://www.loc.gov/MARC21/slim">`) || strings.Count(xml, "<record>") != 2 ||
+		!strings.HasSuffix(xml, "</collection>\n") {
+		t.Errorf("unexpected collection %v", xml)
+	}
+
+	if !strings.Contains(xml, `<datafield tag="245" ind1="0" ind2="0"><subfield code="a">Gitanjali</subfield>`) {
+		t.Errorf("a book without author needs the title indicator 0: %v", xml)
+	}
+}
+
+// TestExportBibliographicAuthors : test that authors have no library format
+func TestExportBibliographicAuthors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := New(store.NewMockBookStorer(ctrl), store.NewMockAuthorStorer(ctrl))
+
+	err := mock.Export(context.TODO(), &bytes.Buffer{}, entities.ExportOptions{Entity: "author", Format: "bibtex"})
+	if !reflect.DeepEqual(err, errors.New("invalid constraints")) {
+		t.Errorf("expected invalid constraints got %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+package exportservice
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// column : a column of the tabular formats, numeric columns are written as numbers into spreadsheets
+type column struct {
+	name    string
+	numeric bool
+}
+
+// rowWriter : encodes the rows of an export, write gets the entity for the formats which keep its structure
+// and its column values for the tabular ones
+type rowWriter interface {
+	write(record interface{}, values []string) error
+	close() error
+}
+
+// newRowWriter : gives the writer of the format, the tabular formats start with a header row
+func newRowWriter(w io.Writer, format, sheet string, columns []column) (rowWriter, error) {
+	switch format {
+	case "csv":
+		return newCSVWriter(w, columns)
+	case "ndjson":
+		return ndjsonWriter{bufio.NewWriter(w)}, nil
+	case "xlsx":
+		return newXLSXWriter(w, sheet, columns)
+	}
+
+	return nil, errors.New("unsupported format " + format)
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer, columns []column) (csvWriter, error) {
+	cw := csvWriter{csv.NewWriter(w)}
+
+	names := make([]string, len(columns))
+	for i := range columns {
+		names[i] = columns[i].name
+	}
+
+	return cw, cw.w.Write(names)
+}
+
+func (cw csvWriter) write(_ interface{}, values []string) error {
+	return cw.w.Write(values)
+}
+
+func (cw csvWriter) close() error {
+	cw.w.Flush()
+
+	return cw.w.Error()
+}
+
+type ndjsonWriter struct {
+	w *bufio.Writer
+}
+
+func (nw ndjsonWriter) write(record interface{}, _ []string) error {
+	return json.NewEncoder(nw.w).Encode(record)
+}
+
+func (nw ndjsonWriter) close() error {
+	return nw.w.Flush()
+}
+
+// the parts of a workbook with a single sheet, its rows are written as inline strings so no shared string table
+// has to be kept in memory
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ` +
+		`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ` +
+		`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Target="xl/workbook.xml" ` +
+		`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument"/></Relationships>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Target="worksheets/sheet1.xml" ` +
+		`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// xlsxWriter : streams a spreadsheet, the zip entries are written with data descriptors so w need not seek
+type xlsxWriter struct {
+	zw      *zip.Writer
+	sheet   *bufio.Writer
+	columns []column
+	row     int
+}
+
+func newXLSXWriter(w io.Writer, sheet string, columns []column) (*xlsxWriter, error) {
+	zw := zip.NewWriter(w)
+
+	var name strings.Builder
+	if err := xml.EscapeText(&name, []byte(sheet)); err != nil {
+		return nil, err
+	}
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, name.String())},
+	}
+
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	xw := &xlsxWriter{zw: zw, sheet: bufio.NewWriter(f), columns: columns}
+
+	if _, err = xw.sheet.WriteString(xlsxSheetStart); err != nil {
+		return nil, err
+	}
+
+	header := make([]string, len(columns))
+	for i := range columns {
+		header[i] = columns[i].name
+	}
+
+	return xw, xw.cells(header, false)
+}
+
+func (xw *xlsxWriter) write(_ interface{}, values []string) error {
+	return xw.cells(values, true)
+}
+
+// cells : writes a row, values of numeric columns are written as numbers when typed is set
+func (xw *xlsxWriter) cells(values []string, typed bool) error {
+	xw.row++
+	ref := strconv.Itoa(xw.row)
+
+	xw.sheet.WriteString(`<row r="` + ref + `">`)
+
+	for i, value := range values {
+		cell := columnName(i) + ref
+
+		if typed && i < len(xw.columns) && xw.columns[i].numeric {
+			if _, err := strconv.ParseFloat(value, 64); err == nil {
+				xw.sheet.WriteString(`<c r="` + cell + `"><v>` + value + `</v></c>`)
+				continue
+			}
+		}
+
+		xw.sheet.WriteString(`<c r="` + cell + `" t="inlineStr"><is><t xml:space="preserve">`)
+
+		if err := xml.EscapeText(xw.sheet, []byte(value)); err != nil {
+			return err
+		}
+
+		xw.sheet.WriteString(`</t></is></c>`)
+	}
+
+	_, err := xw.sheet.WriteString(`</row>`)
+
+	return err
+}
+
+func (xw *xlsxWriter) close() error {
+	if _, err := xw.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return err
+	}
+
+	if err := xw.sheet.Flush(); err != nil {
+		return err
+	}
+
+	return xw.zw.Close()
+}
+
+// columnName : the letters of a zero based column index, 0 is A and 26 is AA
+func columnName(i int) string {
+	name := ""
+
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+
+	return name
+}
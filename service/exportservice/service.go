@@ -0,0 +1,174 @@
+package exportservice
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"strconv"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service/tagservice"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+var (
+	bookColumns = []column{{"book_id", true}, {"author_id", true}, {"title", false}, {"publication", false},
+		{"published_date", false}}
+	bookAuthorColumns = []column{{"author_first_name", false}, {"author_last_name", false}, {"author_dob", false},
+		{"author_pen_name", false}}
+	authorColumns = []column{{"author_id", true}, {"first_name", false}, {"last_name", false}, {"dob", false},
+		{"pen_name", false}}
+)
+
+type ExportService struct {
+	bookStore   store.BookStorer
+	authorStore store.AuthorStorer
+}
+
+// New : factory function
+func New(bs store.BookStorer, as store.AuthorStorer) ExportService {
+	return ExportService{bs, as}
+}
+
+// Export : writes the books or authors to w one row at a time as they are read from the store, the columns are
+// named like the import fields so an export can be imported again; the library formats MARC 21, MARCXML, BibTeX and
+// Dublin Core only describe books. Nothing is written when the options are invalid
+func (s ExportService) Export(ctx context.Context, w io.Writer, opts entities.ExportOptions) error {
+	if !checkOptions(&opts) {
+		return errors.New("invalid constraints")
+	}
+
+	if opts.Entity == "author" {
+		rw, err := newRowWriter(w, opts.Format, "authors", authorColumns)
+		if err != nil {
+			return err
+		}
+
+		err = s.authorStore.EachAuthor(ctx, entities.AuthorFilter{}, entities.Projection{}, func(author entities.Author) error {
+			return rw.write(author, []string{strconv.Itoa(author.AuthorID), author.FirstName, author.LastName,
+				author.DOB, author.PenName})
+		})
+		if err != nil {
+			return err
+		}
+
+		return rw.close()
+	}
+
+	for i := range opts.Filter.Tags {
+		opts.Filter.Tags[i] = tagservice.NormalizeName(opts.Filter.Tags[i])
+	}
+
+	rw, err := newBookWriter(w, opts, true)
+	if err != nil {
+		return err
+	}
+
+	var projection entities.Projection
+	if opts.IncludeAuthor {
+		projection.Expand = []string{"author"}
+	}
+
+	err = s.bookStore.EachBook(ctx, opts.Filter, projection, func(book entities.Book) error {
+		return rw.write(book, bookValues(book, opts.IncludeAuthor))
+	})
+	if err != nil {
+		return err
+	}
+
+	return rw.close()
+}
+
+// ExportBook : writes a single book with its author in one of the export formats
+func (s ExportService) ExportBook(ctx context.Context, w io.Writer, id int, format string) error {
+	opts := entities.ExportOptions{Entity: "book", Format: format, IncludeAuthor: true}
+	if !checkOptions(&opts) {
+		return errors.New("invalid constraints")
+	}
+
+	if id <= 0 {
+		return errors.New("invalid id")
+	}
+
+	book, err := s.bookStore.GetBookByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("book does not exist")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	author, err := s.authorStore.IncludeAuthor(ctx, book.AuthorID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if err == nil {
+		book.Author = &author
+	}
+
+	rw, err := newBookWriter(w, opts, false)
+	if err != nil {
+		return err
+	}
+
+	if err = rw.write(book, bookValues(book, true)); err != nil {
+		return err
+	}
+
+	return rw.close()
+}
+
+// newBookWriter : gives the writer of the book rows, the author columns are only written when they are included
+func newBookWriter(w io.Writer, opts entities.ExportOptions, bulk bool) (rowWriter, error) {
+	if bibliographic[opts.Format] {
+		return newRecordWriter(w, opts.Format, bulk)
+	}
+
+	columns := bookColumns
+	if opts.IncludeAuthor {
+		columns = append(append([]column{}, bookColumns...), bookAuthorColumns...)
+	}
+
+	return newRowWriter(w, opts.Format, "books", columns)
+}
+
+// bookValues : the column values of a book row
+func bookValues(book entities.Book, includeAuthor bool) []string {
+	values := []string{strconv.Itoa(book.BookID), strconv.Itoa(book.AuthorID), book.Title, book.Publication,
+		book.PublishedDate}
+
+	if includeAuthor {
+		author := entities.Author{}
+		if book.Author != nil {
+			author = *book.Author
+		}
+
+		values = append(values, author.FirstName, author.LastName, author.DOB, author.PenName)
+	}
+
+	return values
+}
+
+// checkOptions : validates the options, filling in the defaults
+func checkOptions(opts *entities.ExportOptions) bool {
+	if opts.Entity == "" {
+		opts.Entity = "book"
+	}
+
+	if opts.Format == "" {
+		opts.Format = "csv"
+	}
+
+	if bibliographic[opts.Format] {
+		// the library formats describe books by their author
+		opts.IncludeAuthor = true
+
+		return opts.Entity == "book"
+	}
+
+	return (opts.Entity == "book" || opts.Entity == "author") &&
+		(opts.Format == "csv" || opts.Format == "ndjson" || opts.Format == "xlsx")
+}
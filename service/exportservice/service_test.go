@@ -0,0 +1,176 @@
+package exportservice
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+
+	"github.com/golang/mock/gomock"
+)
+
+// authorProjection : the projection of an export including the author
+var authorProjection = entities.Projection{Expand: []string{"author"}}
+
+// eachBook : returns a store stub which hands the books to the callback
+func eachBook(books ...entities.Book) func(context.Context, entities.BookFilter, entities.Projection,
+	func(entities.Book) error) error {
+	return func(_ context.Context, _ entities.BookFilter, _ entities.Projection, fn func(entities.Book) error) error {
+		for _, book := range books {
+			if err := fn(book); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// TestExport : test the formats, the filter passed to the store and the validation of the options
+func TestExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bookStore := store.NewMockBookStorer(ctrl)
+	authorStore := store.NewMockAuthorStorer(ctrl)
+	mock := New(bookStore, authorStore)
+
+	author := entities.Author{AuthorID: 1, FirstName: "Ruskin", LastName: "Bond", DOB: "19/05/1934", PenName: "RB"}
+	book := entities.Book{BookID: 7, AuthorID: 1, Title: "Rusty, the boy", Publication: "Penguin",
+		PublishedDate: "11/03/1980"}
+	withAuthor := book
+	withAuthor.Author = &author
+
+	testcases := []struct {
+		desc  string
+		opts  entities.ExportOptions
+		setup func()
+
+		expected    string
+		expectedErr error
+	}{
+		{desc: "books as csv", opts: entities.ExportOptions{Filter: entities.BookFilter{Tags: []string{" Classic "}}},
+			setup: func() {
+				bookStore.EXPECT().EachBook(context.TODO(), entities.BookFilter{Tags: []string{"classic"}}, entities.Projection{},
+					gomock.Any()).DoAndReturn(eachBook(book))
+			},
+			expected: "book_id,author_id,title,publication,published_date\n7,1,\"Rusty, the boy\",Penguin,11/03/1980\n"},
+		{desc: "books with authors as csv", opts: entities.ExportOptions{IncludeAuthor: true},
+			setup: func() {
+				bookStore.EXPECT().EachBook(context.TODO(), entities.BookFilter{}, authorProjection, gomock.Any()).
+					DoAndReturn(eachBook(withAuthor))
+			},
+			expected: "book_id,author_id,title,publication,published_date,author_first_name,author_last_name," +
+				"author_dob,author_pen_name\n7,1,\"Rusty, the boy\",Penguin,11/03/1980,Ruskin,Bond,19/05/1934,RB\n"},
+		{desc: "books as ndjson", opts: entities.ExportOptions{Format: "ndjson", Filter: entities.BookFilter{Title: "x"}},
+			setup: func() {
+				bookStore.EXPECT().EachBook(context.TODO(), entities.BookFilter{Title: "x"}, entities.Projection{}, gomock.Any()).
+					DoAndReturn(eachBook(book, book))
+			},
+			expected: strings.Repeat(`{"bookID":7,"authorID":1,"title":"Rusty, the boy","publication":"Penguin",`+
+				`"publishedDate":"11/03/1980"}`+"\n", 2)},
+		{desc: "authors as ndjson", opts: entities.ExportOptions{Entity: "author", Format: "ndjson"},
+			setup: func() {
+				authorStore.EXPECT().EachAuthor(context.TODO(), entities.AuthorFilter{}, entities.Projection{},
+					gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ entities.AuthorFilter, _ entities.Projection,
+						fn func(entities.Author) error) error {
+						return fn(author)
+					})
+			},
+			expected: `{"authorID":1,"firstName":"Ruskin","lastName":"Bond","DOB":"19/05/1934","penName":"RB"}` + "\n"},
+		{desc: "store failure", opts: entities.ExportOptions{},
+			setup: func() {
+				bookStore.EXPECT().EachBook(context.TODO(), entities.BookFilter{}, entities.Projection{}, gomock.Any()).
+					Return(errors.New("connection lost"))
+			},
+			expectedErr: errors.New("connection lost")},
+		{desc: "unknown entity", opts: entities.ExportOptions{Entity: "genre"}, expectedErr: errors.New("invalid constraints")},
+		{desc: "unknown format", opts: entities.ExportOptions{Format: "pdf"}, expectedErr: errors.New("invalid constraints")},
+	}
+
+	for _, tc := range testcases {
+		if tc.setup != nil {
+			tc.setup()
+		}
+
+		var buf bytes.Buffer
+
+		err := mock.Export(context.TODO(), &buf, tc.opts)
+
+		if !reflect.DeepEqual(err, tc.expectedErr) || (err == nil && buf.String() != tc.expected) {
+			t.Errorf("failed for %v, got: %q, %v\n", tc.desc, buf.String(), err)
+		}
+	}
+}
+
+// TestExportXLSX : test that the spreadsheet is a zip with the workbook parts and the rows in its sheet
+func TestExportXLSX(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bookStore := store.NewMockBookStorer(ctrl)
+	mock := New(bookStore, store.NewMockAuthorStorer(ctrl))
+
+	bookStore.EXPECT().EachBook(context.TODO(), entities.BookFilter{}, entities.Projection{}, gomock.Any()).
+		DoAndReturn(eachBook(entities.Book{BookID: 7, AuthorID: 1, Title: "Tom & Jerry", Publication: "Penguin"}))
+
+	var buf bytes.Buffer
+
+	if err := mock.Export(context.TODO(), &buf, entities.ExportOptions{Format: "xlsx"}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("not a zip: %v", err)
+	}
+
+	parts := map[string]string{}
+
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("%v: %v", f.Name, err)
+		}
+
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+
+		parts[f.Name] = string(content)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("missing part %v", name)
+		}
+	}
+
+	if !strings.Contains(parts["xl/workbook.xml"], `<sheet name="books"`) {
+		t.Errorf("unexpected workbook %v", parts["xl/workbook.xml"])
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, cell := range []string{`<c r="A1" t="inlineStr"><is><t xml:space="preserve">book_id</t></is></c>`,
+		`<c r="A2"><v>7</v></c>`, `<c r="C2" t="inlineStr"><is><t xml:space="preserve">Tom &amp; Jerry</t></is></c>`} {
+		if !strings.Contains(sheet, cell) {
+			t.Errorf("sheet is missing %v: %v", cell, sheet)
+		}
+	}
+
+	if !strings.HasSuffix(sheet, "</sheetData></worksheet>") {
+		t.Errorf("sheet is not closed: %v", sheet)
+	}
+}
+
+// TestColumnName : test the letters of the spreadsheet columns
+func TestColumnName(t *testing.T) {
+	for i, expected := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(i); got != expected {
+			t.Errorf("column %v: expected %v got %v", i, expected, got)
+		}
+	}
+}
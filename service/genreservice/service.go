@@ -0,0 +1,180 @@
+package genreservice
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+type GenreService struct {
+	datastore store.GenreStorer
+	bookStore store.BookStorer
+}
+
+// New : factory function
+func New(s store.GenreStorer, bs store.BookStorer) GenreService {
+	return GenreService{s, bs}
+}
+
+// GetAll : gives the genre taxonomy as a tree of top level genres
+func (s GenreService) GetAll(ctx context.Context) ([]entities.Genre, error) {
+	genres, err := s.datastore.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTree(genres, 0), nil
+}
+
+// GetByID : gives the genre with its sub-genres
+func (s GenreService) GetByID(ctx context.Context, id int) (entities.Genre, error) {
+	if id <= 0 {
+		return entities.Genre{}, errors.New("invalid id")
+	}
+
+	genre, err := s.datastore.GetByID(ctx, id)
+	if err != nil {
+		return entities.Genre{}, errors.New("genre does not exist")
+	}
+
+	descendants, err := s.datastore.GetDescendants(ctx, id)
+	if err != nil {
+		return entities.Genre{}, err
+	}
+
+	genre.Children = buildTree(descendants, id)
+
+	return genre, nil
+}
+
+// Post : checks the genre and its parent before posting
+func (s GenreService) Post(ctx context.Context, g entities.Genre) (entities.Genre, error) {
+	g.Name = strings.TrimSpace(g.Name)
+	if g.Name == "" || g.ParentID < 0 {
+		return entities.Genre{}, errors.New("invalid constraints")
+	}
+
+	if g.ParentID > 0 {
+		if _, err := s.datastore.GetByID(ctx, g.ParentID); err != nil {
+			return entities.Genre{}, errors.New("parent genre does not exist")
+		}
+	}
+
+	id, err := s.datastore.Post(ctx, g)
+	if err != nil || id <= 0 {
+		return entities.Genre{}, err
+	}
+
+	g.GenreID = id
+	g.Children = nil
+
+	return g, nil
+}
+
+// Put : checks the genre before updating, a genre can not be moved below itself
+func (s GenreService) Put(ctx context.Context, g entities.Genre, id int) (entities.Genre, error) {
+	g.Name = strings.TrimSpace(g.Name)
+	if g.Name == "" || g.ParentID < 0 || g.ParentID == id {
+		return entities.Genre{}, errors.New("invalid constraints")
+	}
+
+	if _, err := s.datastore.GetByID(ctx, id); err != nil {
+		return entities.Genre{}, errors.New("genre does not exist")
+	}
+
+	if g.ParentID > 0 {
+		if _, err := s.datastore.GetByID(ctx, g.ParentID); err != nil {
+			return entities.Genre{}, errors.New("parent genre does not exist")
+		}
+
+		descendants, err := s.datastore.GetDescendants(ctx, id)
+		if err != nil {
+			return entities.Genre{}, err
+		}
+
+		for i := range descendants {
+			if descendants[i].GenreID == g.ParentID {
+				return entities.Genre{}, errors.New("genre can not be moved below its own sub-genre")
+			}
+		}
+	}
+
+	if _, err := s.datastore.Put(ctx, g, id); err != nil {
+		return entities.Genre{}, err
+	}
+
+	if err := s.invalidate(ctx, id); err != nil {
+		return entities.Genre{}, err
+	}
+
+	g.GenreID = id
+	g.Children = nil
+
+	return g, nil
+}
+
+// Delete : deletes a genre which has no sub-genres
+func (s GenreService) Delete(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid id")
+	}
+
+	descendants, err := s.datastore.GetDescendants(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if len(descendants) > 0 {
+		return errors.New("genre has sub-genres")
+	}
+
+	books, err := s.datastore.GetBookIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	count, err := s.datastore.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if count <= 0 {
+		return errors.New("genre does not exist")
+	}
+
+	store.InvalidateBooks(ctx, s.bookStore, books...)
+
+	return nil
+}
+
+// invalidate : drops the books of the genre from the book cache, the genres are shown with their books
+func (s GenreService) invalidate(ctx context.Context, id int) error {
+	books, err := s.datastore.GetBookIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	store.InvalidateBooks(ctx, s.bookStore, books...)
+
+	return nil
+}
+
+// buildTree : nests the flat list of genres below the genre with particular parent id
+func buildTree(genres []entities.Genre, parentID int) []entities.Genre {
+	var tree []entities.Genre
+
+	for i := range genres {
+		if genres[i].ParentID != parentID {
+			continue
+		}
+
+		genre := genres[i]
+		genre.Children = buildTree(genres, genre.GenreID)
+		tree = append(tree, genre)
+	}
+
+	return tree
+}
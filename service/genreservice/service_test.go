@@ -0,0 +1,140 @@
+package genreservice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+
+	"github.com/golang/mock/gomock"
+)
+
+// TestGetAll : test the genres are returned as a tree
+func TestGetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockGenreStorer(ctrl)
+	mock := New(mockStore, store.NewMockBookStorer(ctrl))
+
+	mockStore.EXPECT().GetAll(context.TODO()).Return([]entities.Genre{{GenreID: 1, Name: "Fiction"},
+		{GenreID: 2, Name: "Fantasy", ParentID: 1}, {GenreID: 3, Name: "Epic", ParentID: 2},
+		{GenreID: 4, Name: "Non-fiction"}}, nil)
+
+	expected := []entities.Genre{{GenreID: 1, Name: "Fiction", Children: []entities.Genre{{GenreID: 2,
+		Name: "Fantasy", ParentID: 1, Children: []entities.Genre{{GenreID: 3, Name: "Epic", ParentID: 2}}}}},
+		{GenreID: 4, Name: "Non-fiction"}}
+
+	genres, err := mock.GetAll(context.TODO())
+	if err != nil || !reflect.DeepEqual(genres, expected) {
+		t.Errorf("expected: %v, got: %v", expected, genres)
+	}
+}
+
+// TestPost : test the logic of posting a genre
+func TestPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockGenreStorer(ctrl)
+	mock := New(mockStore, store.NewMockBookStorer(ctrl))
+
+	testcases := []struct {
+		desc  string
+		input entities.Genre
+
+		parentErr   error
+		expected    entities.Genre
+		expectedErr error
+	}{
+		{desc: "top level genre", input: entities.Genre{Name: "Fiction"},
+			expected: entities.Genre{GenreID: 7, Name: "Fiction"}},
+		{desc: "sub-genre", input: entities.Genre{Name: "Fantasy", ParentID: 1},
+			expected: entities.Genre{GenreID: 7, Name: "Fantasy", ParentID: 1}},
+		{desc: "missing name", input: entities.Genre{Name: " "},
+			expected: entities.Genre{}, expectedErr: errors.New("invalid constraints")},
+		{desc: "missing parent", input: entities.Genre{Name: "Fantasy", ParentID: 9}, parentErr: errors.New("no rows"),
+			expected: entities.Genre{}, expectedErr: errors.New("parent genre does not exist")},
+	}
+
+	for _, tc := range testcases {
+		mockStore.EXPECT().GetByID(context.TODO(), tc.input.ParentID).Return(entities.Genre{}, tc.parentErr).AnyTimes()
+		mockStore.EXPECT().Post(context.TODO(), gomock.Any()).Return(7, nil).AnyTimes()
+
+		genre, err := mock.Post(context.TODO(), tc.input)
+
+		if !reflect.DeepEqual(genre, tc.expected) || !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
+
+// TestPut : test a genre can not be moved below itself
+func TestPut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockGenreStorer(ctrl)
+	mock := New(mockStore, store.NewMockBookStorer(ctrl))
+
+	testcases := []struct {
+		desc     string
+		input    entities.Genre
+		targetID int
+
+		expected    entities.Genre
+		expectedErr error
+	}{
+		{desc: "rename", input: entities.Genre{Name: "Speculative"}, targetID: 1,
+			expected: entities.Genre{GenreID: 1, Name: "Speculative"}},
+		{desc: "own parent", input: entities.Genre{Name: "Fiction", ParentID: 1}, targetID: 1,
+			expectedErr: errors.New("invalid constraints")},
+		{desc: "below own sub-genre", input: entities.Genre{Name: "Fiction", ParentID: 3}, targetID: 1,
+			expectedErr: errors.New("genre can not be moved below its own sub-genre")},
+	}
+
+	for _, tc := range testcases {
+		mockStore.EXPECT().GetByID(context.TODO(), gomock.Any()).Return(entities.Genre{GenreID: 1}, nil).AnyTimes()
+		mockStore.EXPECT().GetDescendants(context.TODO(), 1).Return([]entities.Genre{{GenreID: 2, ParentID: 1},
+			{GenreID: 3, ParentID: 2}}, nil).AnyTimes()
+		mockStore.EXPECT().Put(context.TODO(), gomock.Any(), tc.targetID).Return(1, nil).AnyTimes()
+		mockStore.EXPECT().GetBookIDs(context.TODO(), tc.targetID).Return([]int{4}, nil).AnyTimes()
+
+		genre, err := mock.Put(context.TODO(), tc.input, tc.targetID)
+
+		if !reflect.DeepEqual(genre, tc.expected) || !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
+
+// TestDelete : test a genre with sub-genres is not deleted
+func TestDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockGenreStorer(ctrl)
+	mock := New(mockStore, store.NewMockBookStorer(ctrl))
+
+	testcases := []struct {
+		desc        string
+		target      int
+		descendants []entities.Genre
+		count       int
+
+		expectedErr error
+	}{
+		{desc: "leaf genre", target: 3, count: 1},
+		{desc: "genre with sub-genres", target: 1, descendants: []entities.Genre{{GenreID: 2}},
+			expectedErr: errors.New("genre has sub-genres")},
+		{desc: "not existing", target: 9, count: 0, expectedErr: errors.New("genre does not exist")},
+		{desc: "invalid id", target: -1, expectedErr: errors.New("invalid id")},
+	}
+
+	for _, tc := range testcases {
+		mockStore.EXPECT().GetDescendants(context.TODO(), tc.target).Return(tc.descendants, nil).AnyTimes()
+		mockStore.EXPECT().GetBookIDs(context.TODO(), tc.target).Return([]int{4}, nil).AnyTimes()
+		mockStore.EXPECT().Delete(context.TODO(), tc.target).Return(tc.count, nil).AnyTimes()
+
+		err := mock.Delete(context.TODO(), tc.target)
+
+		if !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
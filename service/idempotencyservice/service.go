@@ -0,0 +1,85 @@
+package idempotencyservice
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+var (
+	// ErrKeyReused : the key was used before for a different request
+	ErrKeyReused = errors.New("idempotency key reused with a different request")
+	// ErrInProgress : the first request with the key is not answered yet
+	ErrInProgress = errors.New("a request with the idempotency key is in progress")
+)
+
+type IdempotencyService struct {
+	store  store.IdempotencyStorer
+	window time.Duration
+}
+
+// New : factory function, a key and the response recorded with it are kept for the window
+func New(s store.IdempotencyStorer, window time.Duration) IdempotencyService {
+	return IdempotencyService{s, window}
+}
+
+// Begin : reserves the key of the request, when the request was answered already its recorded response is given
+// and nothing is to be done
+func (s IdempotencyService) Begin(ctx context.Context, request entities.IdempotentRequest) (
+	*entities.IdempotentRequest, error) {
+	recorded, ok, err := s.store.Reserve(ctx, request, int(s.window/time.Second))
+	if err != nil || ok {
+		return nil, err
+	}
+
+	if recorded.Fingerprint != request.Fingerprint {
+		return nil, ErrKeyReused
+	}
+
+	if recorded.Status == 0 {
+		return nil, ErrInProgress
+	}
+
+	return &recorded, nil
+}
+
+// Finish : records the response of a reserved request, a server error frees the key instead so the request can be
+// retried with it; a response which could not be recorded frees it too, else every retry would be in progress
+func (s IdempotencyService) Finish(ctx context.Context, request entities.IdempotentRequest) error {
+	if request.Status >= http.StatusInternalServerError {
+		return s.store.Release(ctx, request.Scope, request.Key)
+	}
+
+	err := s.store.Complete(ctx, request)
+	if err == nil {
+		return nil
+	}
+
+	if releaseErr := s.store.Release(ctx, request.Scope, request.Key); releaseErr != nil {
+		return errors.New(err.Error() + ", release: " + releaseErr.Error())
+	}
+
+	return err
+}
+
+// Run : deletes the expired keys every interval until the context is done
+func (s IdempotencyService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.store.DeleteExpired(ctx); err != nil {
+				log.Printf("idempotency keys: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package idempotencyservice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+// TestBegin : test that a retry gets the recorded response and a reuse of the key for another request is refused
+func TestBegin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockIdempotencyStorer(ctrl)
+	s := New(mockStore, time.Hour)
+
+	request := entities.IdempotentRequest{Scope: "asha", Key: "k1", Fingerprint: "f1"}
+	answered := entities.IdempotentRequest{Scope: "asha", Key: "k1", Fingerprint: "f1", Status: 201,
+		ContentType: "application/json", Body: []byte(`{"bookID":3}`)}
+
+	testcases := []struct {
+		desc     string
+		recorded entities.IdempotentRequest
+		ok       bool
+		err      error
+
+		expected    *entities.IdempotentRequest
+		expectedErr error
+	}{
+		{desc: "first request", recorded: request, ok: true},
+		{desc: "retry", recorded: answered, expected: &answered},
+		{desc: "other request", recorded: entities.IdempotentRequest{Fingerprint: "f2", Status: 201},
+			expectedErr: ErrKeyReused},
+		{desc: "in progress", recorded: entities.IdempotentRequest{Fingerprint: "f1"}, expectedErr: ErrInProgress},
+		{desc: "store failure", err: errors.New("connection lost"), expectedErr: errors.New("connection lost")},
+	}
+
+	for _, tc := range testcases {
+		mockStore.EXPECT().Reserve(gomock.Any(), request, 3600).Return(tc.recorded, tc.ok, tc.err)
+
+		replay, err := s.Begin(context.TODO(), request)
+
+		if !reflect.DeepEqual(replay, tc.expected) || !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("%v: unexpected result %v %v", tc.desc, replay, err)
+		}
+	}
+}
+
+// TestFinish : test that responses are recorded and server errors free the key
+func TestFinish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockIdempotencyStorer(ctrl)
+	s := New(mockStore, time.Hour)
+
+	created := entities.IdempotentRequest{Scope: "asha", Key: "k1", Status: 201}
+	invalid := entities.IdempotentRequest{Scope: "asha", Key: "k2", Status: 400}
+
+	mockStore.EXPECT().Complete(gomock.Any(), created).Return(nil)
+	mockStore.EXPECT().Complete(gomock.Any(), invalid).Return(nil)
+	mockStore.EXPECT().Release(gomock.Any(), "asha", "k3").Return(nil)
+
+	for _, request := range []entities.IdempotentRequest{created, invalid, {Scope: "asha", Key: "k3", Status: 500}} {
+		if err := s.Finish(context.TODO(), request); err != nil {
+			t.Errorf("failed to finish %v: %v", request.Key, err)
+		}
+	}
+	// a response which can not be recorded frees the key for a retry
+	lost := entities.IdempotentRequest{Scope: "asha", Key: "k4", Status: 201}
+	errLost := errors.New("connection lost")
+
+	mockStore.EXPECT().Complete(gomock.Any(), lost).Return(errLost)
+	mockStore.EXPECT().Release(gomock.Any(), "asha", "k4").Return(nil)
+
+	if err := s.Finish(context.TODO(), lost); err != errLost {
+		t.Errorf("expected %v, got %v", errLost, err)
+	}
+}
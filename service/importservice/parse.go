@@ -0,0 +1,160 @@
+package importservice
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+// maxLineSize bounds a single NDJSON line
+const maxLineSize = 1 << 20
+
+// row : the fields of a source row by import field name, along with the line it starts on
+type row struct {
+	line   int
+	fields map[string]string
+}
+
+// parse : reads the rows of the source, malformed rows are reported with their line and skipped
+func parse(format string, data []byte, mapping map[string]string) ([]row, []entities.ImportError, error) {
+	// spreadsheet programs like to start their exports with a byte order mark
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	if format == "ndjson" {
+		return parseNDJSON(data, mapping)
+	}
+
+	return parseCSV(data, mapping)
+}
+
+// parseCSV : reads a CSV source whose first line names the columns
+func parseCSV(data []byte, mapping map[string]string) ([]row, []entities.ImportError, error) {
+	var (
+		rows []row
+		errs []entities.ImportError
+	)
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, nil, errors.New("missing header line")
+	}
+
+	for i := range header {
+		header[i] = fieldName(header[i], mapping)
+	}
+
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			errs = append(errs, entities.ImportError{Line: parseErr.StartLine, Reason: parseErr.Err.Error()})
+			continue
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		line, _ := r.FieldPos(0)
+
+		if len(record) != len(header) {
+			errs = append(errs, entities.ImportError{Line: line,
+				Reason: fmt.Sprintf("expected %v columns, got %v", len(header), len(record))})
+
+			continue
+		}
+
+		fields := make(map[string]string, len(header))
+		for i, name := range header {
+			fields[name] = strings.TrimSpace(record[i])
+		}
+
+		rows = append(rows, row{line: line, fields: fields})
+	}
+
+	return rows, errs, nil
+}
+
+// parseNDJSON : reads a source holding one JSON object per line, blank lines are skipped
+func parseNDJSON(data []byte, mapping map[string]string) ([]row, []entities.ImportError, error) {
+	var (
+		rows []row
+		errs []entities.ImportError
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		var object map[string]interface{}
+
+		if err := json.Unmarshal(text, &object); err != nil {
+			errs = append(errs, entities.ImportError{Line: line, Reason: "invalid JSON object"})
+			continue
+		}
+
+		fields := make(map[string]string, len(object))
+
+		for key, value := range object {
+			switch v := value.(type) {
+			case nil:
+				fields[fieldName(key, mapping)] = ""
+			case string:
+				fields[fieldName(key, mapping)] = strings.TrimSpace(v)
+			case float64:
+				fields[fieldName(key, mapping)] = strconv.FormatFloat(v, 'f', -1, 64)
+			case bool:
+				fields[fieldName(key, mapping)] = strconv.FormatBool(v)
+			default:
+				errs = append(errs, entities.ImportError{Line: line, Reason: "unsupported value for " + key})
+				fields = nil
+			}
+
+			if fields == nil {
+				break
+			}
+		}
+
+		if fields != nil {
+			rows = append(rows, row{line: line, fields: fields})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return rows, errs, nil
+}
+
+// fieldName : gives the import field a source column maps to, unmapped columns keep their own name
+func fieldName(column string, mapping map[string]string) string {
+	column = strings.TrimSpace(column)
+
+	if field, ok := mapping[column]; ok {
+		column = field
+	}
+
+	return strings.ToLower(column)
+}
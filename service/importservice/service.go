@@ -0,0 +1,319 @@
+package importservice
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+	"projects/GoLang-Interns-2022/authorbook/service"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+const (
+	// maxErrors bounds the errors kept on the job, the failed count keeps counting past it
+	maxErrors     = 1000
+	progressEvery = 100
+)
+
+var (
+	errDryRun = errors.New("dry run")
+	errRows   = errors.New("rows failed")
+)
+
+type ImportService struct {
+	jobStore      store.ImportJobStorer
+	authorStore   store.AuthorStorer
+	authorService service.AuthorService
+	bookService   service.BookService
+	tx            store.Transactor
+}
+
+// New : factory function, rows go through the author and book services so they get the same checks,
+// audit and history as single requests
+func New(js store.ImportJobStorer, as store.AuthorStorer, authors service.AuthorService, books service.BookService,
+	tx store.Transactor) ImportService {
+	return ImportService{js, as, authors, books, tx}
+}
+
+// Post : checks the options, records the job and runs it in the background
+func (s ImportService) Post(ctx context.Context, opts entities.ImportOptions, data []byte) (entities.ImportJob, error) {
+	if !checkOptions(&opts) {
+		return entities.ImportJob{}, errors.New("invalid constraints")
+	}
+
+	if len(data) == 0 {
+		return entities.ImportJob{}, errors.New("nothing to import")
+	}
+
+	job := entities.ImportJob{ImportOptions: opts, Status: entities.ImportPending, Actor: auth.Actor(ctx)}
+
+	id, err := s.jobStore.Post(ctx, job)
+	if err != nil || id <= 0 {
+		return entities.ImportJob{}, errors.New("import could not be started")
+	}
+
+	job.JobID = id
+
+	// the job outlives the request, it only keeps the identity of the caller for the audit log
+	background := context.Background()
+	if identity, ok := auth.FromContext(ctx); ok {
+		background = auth.NewContext(background, identity)
+	}
+
+	go s.run(background, job, data)
+
+	return job, nil
+}
+
+// GetByID : gives the progress of an import job
+func (s ImportService) GetByID(ctx context.Context, id int) (entities.ImportJob, error) {
+	if id <= 0 {
+		return entities.ImportJob{}, errors.New("invalid id")
+	}
+
+	job, err := s.jobStore.GetByID(ctx, id)
+	if err != nil {
+		return entities.ImportJob{}, errors.New("import job does not exist")
+	}
+
+	return job, nil
+}
+
+// run : imports the rows and records the outcome on the job
+func (s ImportService) run(ctx context.Context, job entities.ImportJob, data []byte) {
+	job.Status = entities.ImportRunning
+	s.save(ctx, &job)
+
+	rows, parseErrs, err := parse(job.Format, data, job.Mapping)
+	if err != nil {
+		job.Status = entities.ImportFailed
+		addError(&job, 1, err.Error())
+		s.save(ctx, &job)
+
+		return
+	}
+
+	job.Total = len(rows) + len(parseErrs)
+	for _, e := range parseErrs {
+		addError(&job, e.Line, e.Reason)
+	}
+
+	// atomic imports and dry runs share one transaction which the rows join, best-effort rows commit one by one
+	if job.Mode == entities.ImportAtomic || job.DryRun {
+		err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+			s.importRows(ctx, &job, rows)
+
+			switch {
+			case job.DryRun:
+				return errDryRun
+			case job.Failed > 0:
+				return errRows
+			}
+
+			return nil
+		})
+	} else {
+		s.importRows(ctx, &job, rows)
+	}
+
+	switch {
+	case err == nil || errors.Is(err, errDryRun):
+		job.Status = entities.ImportDone
+	case errors.Is(err, errRows):
+		job.Status = entities.ImportFailed
+		job.Imported = 0
+	default:
+		job.Status = entities.ImportFailed
+		job.Imported = 0
+		addError(&job, 0, err.Error())
+	}
+
+	s.save(ctx, &job)
+}
+
+// importRows : imports the rows one by one, recording the errors of the failed rows
+func (s ImportService) importRows(ctx context.Context, job *entities.ImportJob, rows []row) {
+	authors := make(map[string]int)
+
+	for i, r := range rows {
+		var skipped bool
+
+		err := validate(job.Entity, r.fields)
+		if err == nil {
+			err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+				var err error
+
+				if job.Entity == "author" {
+					skipped, err = s.importAuthor(ctx, r.fields)
+				} else {
+					err = s.importBook(ctx, r.fields, authors)
+				}
+
+				return err
+			})
+		}
+
+		switch {
+		case err != nil:
+			addError(job, r.line, err.Error())
+			// authors matched or created by the failed row may have been rolled back with it
+			authors = make(map[string]int)
+		case skipped:
+			job.Skipped++
+		default:
+			job.Imported++
+		}
+
+		if (i+1)%progressEvery == 0 {
+			s.save(ctx, job)
+		}
+	}
+}
+
+// validate : checks the fields of a row before it is imported, a malformed row is reported on its line
+func validate(entity string, fields map[string]string) error {
+	required, dates := "first_name", []string{"dob"}
+	if entity == "book" {
+		required, dates = "title", []string{"published_date", "author_dob"}
+	}
+
+	if fields[required] == "" {
+		return errors.New("missing " + required)
+	}
+
+	for _, name := range dates {
+		if value := fields[name]; value != "" && !isDate(value) {
+			return errors.New("invalid " + name + " " + value + ", expected DD/MM/YYYY")
+		}
+	}
+
+	return nil
+}
+
+// isDate : whether the value has the day/month/year form the services check
+func isDate(value string) bool {
+	parts := strings.Split(value, "/")
+	if len(parts) != 3 {
+		return false
+	}
+
+	for _, part := range parts {
+		if _, err := strconv.Atoi(part); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// importBook : posts the book of a row, its author is matched by name or created
+func (s ImportService) importBook(ctx context.Context, fields map[string]string, authors map[string]int) error {
+	authorID, err := s.matchAuthor(ctx, fields, authors)
+	if err != nil {
+		return err
+	}
+
+	book := entities.Book{AuthorID: authorID, Title: fields["title"], Publication: fields["publication"],
+		PublishedDate: fields["published_date"]}
+
+	_, err = s.bookService.Post(ctx, &book)
+
+	return err
+}
+
+// importAuthor : posts the author of a row, an author with the same name is left as it is
+func (s ImportService) importAuthor(ctx context.Context, fields map[string]string) (bool, error) {
+	firstName, lastName := fields["first_name"], fields["last_name"]
+
+	_, err := s.authorStore.GetAuthorByName(ctx, firstName, lastName)
+	if err == nil {
+		return true, nil
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+
+	_, err = s.authorService.Post(ctx, entities.Author{FirstName: firstName, LastName: lastName, DOB: fields["dob"],
+		PenName: fields["pen_name"]})
+
+	return false, err
+}
+
+// matchAuthor : finds the author of a book row by name, authors which do not exist yet are created
+// when the row has their date of birth
+func (s ImportService) matchAuthor(ctx context.Context, fields map[string]string, authors map[string]int) (int, error) {
+	firstName, lastName := fields["author_first_name"], fields["author_last_name"]
+
+	if firstName == "" {
+		names := strings.Fields(fields["author"])
+		if len(names) == 0 {
+			return 0, errors.New("missing author")
+		}
+
+		firstName, lastName = names[0], strings.Join(names[1:], " ")
+	}
+
+	key := strings.ToLower(firstName + "\x00" + lastName)
+	if id, ok := authors[key]; ok {
+		return id, nil
+	}
+
+	author, err := s.authorStore.GetAuthorByName(ctx, firstName, lastName)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	if err != nil {
+		if fields["author_dob"] == "" {
+			return 0, fmt.Errorf("author %v does not exist and author_dob is needed to create it",
+				strings.TrimSpace(firstName+" "+lastName))
+		}
+
+		author, err = s.authorService.Post(ctx, entities.Author{FirstName: firstName, LastName: lastName,
+			DOB: fields["author_dob"], PenName: fields["author_pen_name"]})
+		if err != nil {
+			return 0, fmt.Errorf("author: %v", err)
+		}
+	}
+
+	authors[key] = author.AuthorID
+
+	return author.AuthorID, nil
+}
+
+// save : stores the progress of the job, a failure only costs the progress report
+func (s ImportService) save(ctx context.Context, job *entities.ImportJob) {
+	if err := s.jobStore.Put(ctx, *job); err != nil {
+		log.Printf("import job %v: %v", job.JobID, err)
+	}
+}
+
+// addError : counts a failed row and keeps its reason
+func addError(job *entities.ImportJob, line int, reason string) {
+	job.Failed++
+
+	if len(job.Errors) < maxErrors {
+		job.Errors = append(job.Errors, entities.ImportError{Line: line, Reason: reason})
+	}
+}
+
+// checkOptions : validates the options, filling in the defaults
+func checkOptions(opts *entities.ImportOptions) bool {
+	if opts.Entity == "" {
+		opts.Entity = "book"
+	}
+
+	if opts.Mode == "" {
+		opts.Mode = entities.ImportAtomic
+	}
+
+	return (opts.Entity == "book" || opts.Entity == "author") && (opts.Format == "csv" || opts.Format == "ndjson") &&
+		(opts.Mode == entities.ImportAtomic || opts.Mode == entities.ImportBestEffort)
+}
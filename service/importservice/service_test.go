@@ -0,0 +1,157 @@
+package importservice
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+	"projects/GoLang-Interns-2022/authorbook/store"
+
+	"github.com/golang/mock/gomock"
+)
+
+// TestParse : test the rows, line numbers and column mapping of both formats
+func TestParse(t *testing.T) {
+	testcases := []struct {
+		desc    string
+		format  string
+		data    string
+		mapping map[string]string
+
+		expectedRows []row
+		expectedErrs []entities.ImportError
+	}{
+		{desc: "csv with mapping", format: "csv", mapping: map[string]string{"Book Title": "title"},
+			data: "\xef\xbb\xbfBook Title,Author\nthe road,Cormac McCarthy\n\"multi\nline\",asha rao\nbroken\n",
+			expectedRows: []row{{line: 2, fields: map[string]string{"title": "the road", "author": "Cormac McCarthy"}},
+				{line: 3, fields: map[string]string{"title": "multi\nline", "author": "asha rao"}}},
+			expectedErrs: []entities.ImportError{{Line: 5, Reason: "expected 2 columns, got 1"}}},
+		{desc: "ndjson", format: "ndjson", mapping: map[string]string{"name": "title"},
+			data:         "{\"name\":\"the road\",\"year\":2006}\n\nnot json\n{\"title\":[1]}\n",
+			expectedRows: []row{{line: 1, fields: map[string]string{"title": "the road", "year": "2006"}}},
+			expectedErrs: []entities.ImportError{{Line: 3, Reason: "invalid JSON object"},
+				{Line: 4, Reason: "unsupported value for title"}}},
+	}
+
+	for _, tc := range testcases {
+		rows, errs, err := parse(tc.format, []byte(tc.data), tc.mapping)
+
+		if err != nil || !reflect.DeepEqual(rows, tc.expectedRows) || !reflect.DeepEqual(errs, tc.expectedErrs) {
+			t.Errorf("failed for %v, got: %v %v %v\n", tc.desc, rows, errs, err)
+		}
+	}
+}
+
+// TestRun : test the commit modes and the author matching of a book import
+func TestRun(t *testing.T) {
+	data := []byte("title,publication,published_date,author,author_dob\n" +
+		"the road,penguin,20/03/2006,Cormac McCarthy,\n" +
+		"new book,scholastic,01/01/2020,asha rao,02/02/1990\n" +
+		"lost book,penguin,01/01/2020,nobody known,\n" +
+		"bad date,penguin,2010,Cormac McCarthy,\n")
+
+	testcases := []struct {
+		desc   string
+		mode   string
+		dryRun bool
+
+		expected entities.ImportJob
+		// the outermost transaction is the last one to finish
+		expectedTxErr error
+	}{
+		{desc: "best effort keeps the valid rows", mode: entities.ImportBestEffort,
+			expected: entities.ImportJob{Status: entities.ImportDone, Total: 4, Imported: 2, Failed: 2}},
+		{desc: "atomic imports nothing", mode: entities.ImportAtomic,
+			expected:      entities.ImportJob{Status: entities.ImportFailed, Total: 4, Imported: 0, Failed: 2},
+			expectedTxErr: errRows},
+		{desc: "dry run reports the valid rows", mode: entities.ImportBestEffort, dryRun: true,
+			expected:      entities.ImportJob{Status: entities.ImportDone, Total: 4, Imported: 2, Failed: 2},
+			expectedTxErr: errDryRun},
+	}
+
+	for _, tc := range testcases {
+		ctrl := gomock.NewController(t)
+		mockJobStore := store.NewMockImportJobStorer(ctrl)
+		mockAuthorStore := store.NewMockAuthorStorer(ctrl)
+		mockAuthorService := service.NewMockAuthorService(ctrl)
+		mockBookService := service.NewMockBookService(ctrl)
+		mockTx := store.NewMockTransactor(ctrl)
+		mock := New(mockJobStore, mockAuthorStore, mockAuthorService, mockBookService, mockTx)
+
+		var txErr error
+
+		mockTx.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(ctx context.Context) error) error {
+				txErr = fn(ctx)
+				return txErr
+			}).AnyTimes()
+
+		mockAuthorStore.EXPECT().GetAuthorByName(gomock.Any(), "Cormac", "McCarthy").
+			Return(entities.Author{AuthorID: 1}, nil)
+		mockAuthorStore.EXPECT().GetAuthorByName(gomock.Any(), "asha", "rao").Return(entities.Author{}, sql.ErrNoRows)
+		mockAuthorStore.EXPECT().GetAuthorByName(gomock.Any(), "nobody", "known").Return(entities.Author{}, sql.ErrNoRows)
+		mockAuthorService.EXPECT().Post(gomock.Any(), entities.Author{FirstName: "asha", LastName: "rao",
+			DOB: "02/02/1990"}).Return(entities.Author{AuthorID: 2}, nil)
+		mockBookService.EXPECT().Post(gomock.Any(), gomock.Any()).Return(entities.Book{}, nil).Times(2)
+
+		var job entities.ImportJob
+
+		mockJobStore.EXPECT().Put(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, j entities.ImportJob) error {
+				job = j
+				return nil
+			}).Times(2)
+
+		mock.run(context.TODO(), entities.ImportJob{JobID: 1, ImportOptions: entities.ImportOptions{Entity: "book",
+			Format: "csv", Mode: tc.mode, DryRun: tc.dryRun}}, data)
+
+		tc.expected.JobID = 1
+		tc.expected.ImportOptions = job.ImportOptions
+		tc.expected.Errors = []entities.ImportError{{Line: 4,
+			Reason: "author nobody known does not exist and author_dob is needed to create it"},
+			{Line: 5, Reason: "invalid published_date 2010, expected DD/MM/YYYY"}}
+
+		if !reflect.DeepEqual(job, tc.expected) {
+			t.Errorf("failed for %v, got: %+v\n", tc.desc, job)
+		}
+
+		if tc.expectedTxErr != nil && txErr != tc.expectedTxErr {
+			t.Errorf("failed for %v, the import was not rolled back: %v\n", tc.desc, txErr)
+		}
+
+		ctrl.Finish()
+	}
+}
+
+// TestPost : test the options are checked before a job is started
+func TestPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := New(store.NewMockImportJobStorer(ctrl), store.NewMockAuthorStorer(ctrl), service.NewMockAuthorService(ctrl),
+		service.NewMockBookService(ctrl), store.NewMockTransactor(ctrl))
+
+	testcases := []struct {
+		desc string
+		opts entities.ImportOptions
+		data string
+
+		expectedErr error
+	}{
+		{desc: "unknown format", opts: entities.ImportOptions{Format: "xml"}, data: "<book/>",
+			expectedErr: errors.New("invalid constraints")},
+		{desc: "unknown mode", opts: entities.ImportOptions{Format: "csv", Mode: "some"}, data: "title",
+			expectedErr: errors.New("invalid constraints")},
+		{desc: "empty body", opts: entities.ImportOptions{Format: "csv"}, expectedErr: errors.New("nothing to import")},
+	}
+
+	for _, tc := range testcases {
+		_, err := mock.Post(context.TODO(), tc.opts, []byte(tc.data))
+
+		if !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v, got: %v\n", tc.desc, err)
+		}
+	}
+}
@@ -2,19 +2,117 @@ package service
 
 import (
 	"context"
+	"io"
 	"projects/GoLang-Interns-2022/authorbook/entities"
 )
 
 type AuthorService interface {
+	GetAll(ctx context.Context, filter entities.AuthorFilter, projection entities.Projection) ([]entities.Author, error)
+	StreamAuthors(ctx context.Context, projection entities.Projection, fn func(author entities.Author) error) error
+	GetByIDs(ctx context.Context, ids []int) (map[int]entities.Author, error)
+	GetBooksByAuthors(ctx context.Context, ids []int) (map[int][]entities.Book, error)
 	Post(ctx context.Context, author entities.Author) (entities.Author, error)
 	Put(ctx context.Context, author entities.Author, id int) (entities.Author, error)
 	Delete(ctx context.Context, id int) error
+	GetHistory(ctx context.Context, id int) ([]entities.AuthorRevision, error)
 }
 
 type BookService interface {
-	GetAllBook(ctx context.Context, title string, includeAuthor string) ([]entities.Book, error)
+	GetAllBook(ctx context.Context, filter entities.BookFilter, projection entities.Projection) ([]entities.Book, error)
+	StreamBooks(ctx context.Context, filter entities.BookFilter, projection entities.Projection,
+		fn func(book entities.Book) error) error
 	GetBookByID(ctx context.Context, id int) (entities.Book, error)
 	Post(ctx context.Context, book *entities.Book) (entities.Book, error)
+	Import(ctx context.Context, book *entities.Book) (entities.Book, error)
 	Put(ctx context.Context, book *entities.Book, id int) (entities.Book, error)
 	Delete(ctx context.Context, id int) error
+	GetBookAsOf(ctx context.Context, id int, asOf string) (entities.Book, error)
+	GetHistory(ctx context.Context, id int) ([]entities.BookRevision, error)
+	Revert(ctx context.Context, id, revision int) (entities.Book, error)
+}
+
+type GenreService interface {
+	GetAll(ctx context.Context) ([]entities.Genre, error)
+	GetByID(ctx context.Context, id int) (entities.Genre, error)
+	Post(ctx context.Context, genre entities.Genre) (entities.Genre, error)
+	Put(ctx context.Context, genre entities.Genre, id int) (entities.Genre, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type TagService interface {
+	GetAll(ctx context.Context) ([]entities.Tag, error)
+	Post(ctx context.Context, tag entities.Tag) (entities.Tag, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type SeriesService interface {
+	GetByID(ctx context.Context, id int) (entities.Series, error)
+	Post(ctx context.Context, series entities.Series) (entities.Series, error)
+	Put(ctx context.Context, series entities.Series, id int) (entities.Series, error)
+	Delete(ctx context.Context, id int) error
+	AddBook(ctx context.Context, id int, book entities.SeriesBook) (entities.Series, error)
+	RemoveBook(ctx context.Context, id, bookID int) error
+}
+
+type WorkService interface {
+	GetByID(ctx context.Context, id int) (entities.Work, error)
+	Post(ctx context.Context, work entities.Work) (entities.Work, error)
+	Put(ctx context.Context, work entities.Work, id int) (entities.Work, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type ReviewService interface {
+	Post(ctx context.Context, bookID int, review entities.Review) (entities.Review, error)
+	GetByBook(ctx context.Context, bookID int, filter entities.ReviewFilter) (entities.ReviewPage, error)
+	UpdateStatus(ctx context.Context, id int, status string) (entities.Review, error)
+	MarkHelpful(ctx context.Context, id int) (entities.Review, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type APIKeyService interface {
+	GetAll(ctx context.Context) ([]entities.APIKey, error)
+	Post(ctx context.Context, key entities.APIKey) (entities.APIKey, error)
+	Put(ctx context.Context, key entities.APIKey, id int) (entities.APIKey, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type AuditService interface {
+	GetAll(ctx context.Context, filter entities.AuditFilter) ([]entities.AuditEntry, error)
+}
+
+type ImportService interface {
+	Post(ctx context.Context, opts entities.ImportOptions, data []byte) (entities.ImportJob, error)
+	GetByID(ctx context.Context, id int) (entities.ImportJob, error)
+}
+
+type ExportService interface {
+	Export(ctx context.Context, w io.Writer, opts entities.ExportOptions) error
+	ExportBook(ctx context.Context, w io.Writer, id int, format string) error
+}
+
+type WebhookService interface {
+	GetAll(ctx context.Context) ([]entities.Webhook, error)
+	GetByID(ctx context.Context, id int) (entities.Webhook, error)
+	Post(ctx context.Context, webhook entities.Webhook) (entities.Webhook, error)
+	Put(ctx context.Context, webhook entities.Webhook, id int) (entities.Webhook, error)
+	Delete(ctx context.Context, id int) error
+	GetDeliveries(ctx context.Context, id int, filter entities.DeliveryFilter) ([]entities.Delivery, error)
+	Redeliver(ctx context.Context, id, deliveryID int) (entities.Delivery, error)
+}
+
+type ChangeService interface {
+	Follow(ctx context.Context, after int, filter entities.EventFilter, send func(event entities.Event) error) error
+}
+
+type BatchService interface {
+	Run(ctx context.Context, batch entities.Batch) (entities.BatchResponse, error)
+}
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, request entities.IdempotentRequest) (*entities.IdempotentRequest, error)
+	Finish(ctx context.Context, request entities.IdempotentRequest) error
+}
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event entities.Event) error
 }
@@ -6,6 +6,7 @@ package service
 
 import (
 	context "context"
+	io "io"
 	entities "projects/GoLang-Interns-2022/authorbook/entities"
 	reflect "reflect"
 
@@ -36,7 +37,7 @@ func (m *MockAuthorService) EXPECT() *MockAuthorServiceMockRecorder {
 }
 
 // Delete mocks base method.
-func (m *MockAuthorService) Delete(ctx context.Context, id int)  error {
+func (m *MockAuthorService) Delete(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(error)
@@ -49,6 +50,66 @@ func (mr *MockAuthorServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAuthorService)(nil).Delete), ctx, id)
 }
 
+// GetAll mocks base method.
+func (m *MockAuthorService) GetAll(ctx context.Context, filter entities.AuthorFilter, projection entities.Projection) ([]entities.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, filter, projection)
+	ret0, _ := ret[0].([]entities.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAuthorServiceMockRecorder) GetAll(ctx, filter, projection interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAuthorService)(nil).GetAll), ctx, filter, projection)
+}
+
+// GetBooksByAuthors mocks base method.
+func (m *MockAuthorService) GetBooksByAuthors(ctx context.Context, ids []int) (map[int][]entities.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBooksByAuthors", ctx, ids)
+	ret0, _ := ret[0].(map[int][]entities.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBooksByAuthors indicates an expected call of GetBooksByAuthors.
+func (mr *MockAuthorServiceMockRecorder) GetBooksByAuthors(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooksByAuthors", reflect.TypeOf((*MockAuthorService)(nil).GetBooksByAuthors), ctx, ids)
+}
+
+// GetByIDs mocks base method.
+func (m *MockAuthorService) GetByIDs(ctx context.Context, ids []int) (map[int]entities.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", ctx, ids)
+	ret0, _ := ret[0].(map[int]entities.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockAuthorServiceMockRecorder) GetByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockAuthorService)(nil).GetByIDs), ctx, ids)
+}
+
+// GetHistory mocks base method.
+func (m *MockAuthorService) GetHistory(ctx context.Context, id int) ([]entities.AuthorRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, id)
+	ret0, _ := ret[0].([]entities.AuthorRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockAuthorServiceMockRecorder) GetHistory(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockAuthorService)(nil).GetHistory), ctx, id)
+}
+
 // Post mocks base method.
 func (m *MockAuthorService) Post(ctx context.Context, author entities.Author) (entities.Author, error) {
 	m.ctrl.T.Helper()
@@ -79,6 +140,20 @@ func (mr *MockAuthorServiceMockRecorder) Put(ctx, author, id interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockAuthorService)(nil).Put), ctx, author, id)
 }
 
+// StreamAuthors mocks base method.
+func (m *MockAuthorService) StreamAuthors(ctx context.Context, projection entities.Projection, fn func(entities.Author) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamAuthors", ctx, projection, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamAuthors indicates an expected call of StreamAuthors.
+func (mr *MockAuthorServiceMockRecorder) StreamAuthors(ctx, projection, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamAuthors", reflect.TypeOf((*MockAuthorService)(nil).StreamAuthors), ctx, projection, fn)
+}
+
 // MockBookService is a mock of BookService interface.
 type MockBookService struct {
 	ctrl     *gomock.Controller
@@ -103,12 +178,11 @@ func (m *MockBookService) EXPECT() *MockBookServiceMockRecorder {
 }
 
 // Delete mocks base method.
-func (m *MockBookService) Delete(ctx context.Context, id int) (int, error) {
+func (m *MockBookService) Delete(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Delete", ctx, id)
-	ret0, _ := ret[0].(int)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // Delete indicates an expected call of Delete.
@@ -118,18 +192,33 @@ func (mr *MockBookServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Call
 }
 
 // GetAllBook mocks base method.
-func (m *MockBookService) GetAllBook(ctx context.Context, title, includeAuthor string) ([]entities.Book, error) {
+func (m *MockBookService) GetAllBook(ctx context.Context, filter entities.BookFilter, projection entities.Projection) ([]entities.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllBook", ctx, title, includeAuthor)
+	ret := m.ctrl.Call(m, "GetAllBook", ctx, filter, projection)
 	ret0, _ := ret[0].([]entities.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllBook indicates an expected call of GetAllBook.
-func (mr *MockBookServiceMockRecorder) GetAllBook(ctx, title, includeAuthor interface{}) *gomock.Call {
+func (mr *MockBookServiceMockRecorder) GetAllBook(ctx, filter, projection interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllBook", reflect.TypeOf((*MockBookService)(nil).GetAllBook), ctx, filter, projection)
+}
+
+// GetBookAsOf mocks base method.
+func (m *MockBookService) GetBookAsOf(ctx context.Context, id int, asOf string) (entities.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookAsOf", ctx, id, asOf)
+	ret0, _ := ret[0].(entities.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookAsOf indicates an expected call of GetBookAsOf.
+func (mr *MockBookServiceMockRecorder) GetBookAsOf(ctx, id, asOf interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllBook", reflect.TypeOf((*MockBookService)(nil).GetAllBook), ctx, title, includeAuthor)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookAsOf", reflect.TypeOf((*MockBookService)(nil).GetBookAsOf), ctx, id, asOf)
 }
 
 // GetBookByID mocks base method.
@@ -147,6 +236,36 @@ func (mr *MockBookServiceMockRecorder) GetBookByID(ctx, id interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByID", reflect.TypeOf((*MockBookService)(nil).GetBookByID), ctx, id)
 }
 
+// GetHistory mocks base method.
+func (m *MockBookService) GetHistory(ctx context.Context, id int) ([]entities.BookRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, id)
+	ret0, _ := ret[0].([]entities.BookRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockBookServiceMockRecorder) GetHistory(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockBookService)(nil).GetHistory), ctx, id)
+}
+
+// Import mocks base method.
+func (m *MockBookService) Import(ctx context.Context, book *entities.Book) (entities.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, book)
+	ret0, _ := ret[0].(entities.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockBookServiceMockRecorder) Import(ctx, book interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockBookService)(nil).Import), ctx, book)
+}
+
 // Post mocks base method.
 func (m *MockBookService) Post(ctx context.Context, book *entities.Book) (entities.Book, error) {
 	m.ctrl.T.Helper()
@@ -176,3 +295,1001 @@ func (mr *MockBookServiceMockRecorder) Put(ctx, book, id interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockBookService)(nil).Put), ctx, book, id)
 }
+
+// Revert mocks base method.
+func (m *MockBookService) Revert(ctx context.Context, id, revision int) (entities.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revert", ctx, id, revision)
+	ret0, _ := ret[0].(entities.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revert indicates an expected call of Revert.
+func (mr *MockBookServiceMockRecorder) Revert(ctx, id, revision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revert", reflect.TypeOf((*MockBookService)(nil).Revert), ctx, id, revision)
+}
+
+// StreamBooks mocks base method.
+func (m *MockBookService) StreamBooks(ctx context.Context, filter entities.BookFilter, projection entities.Projection, fn func(entities.Book) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamBooks", ctx, filter, projection, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamBooks indicates an expected call of StreamBooks.
+func (mr *MockBookServiceMockRecorder) StreamBooks(ctx, filter, projection, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamBooks", reflect.TypeOf((*MockBookService)(nil).StreamBooks), ctx, filter, projection, fn)
+}
+
+// MockGenreService is a mock of GenreService interface.
+type MockGenreService struct {
+	ctrl     *gomock.Controller
+	recorder *MockGenreServiceMockRecorder
+}
+
+// MockGenreServiceMockRecorder is the mock recorder for MockGenreService.
+type MockGenreServiceMockRecorder struct {
+	mock *MockGenreService
+}
+
+// NewMockGenreService creates a new mock instance.
+func NewMockGenreService(ctrl *gomock.Controller) *MockGenreService {
+	mock := &MockGenreService{ctrl: ctrl}
+	mock.recorder = &MockGenreServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGenreService) EXPECT() *MockGenreServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockGenreService) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockGenreServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockGenreService)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockGenreService) GetAll(ctx context.Context) ([]entities.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]entities.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockGenreServiceMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockGenreService)(nil).GetAll), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockGenreService) GetByID(ctx context.Context, id int) (entities.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(entities.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockGenreServiceMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockGenreService)(nil).GetByID), ctx, id)
+}
+
+// Post mocks base method.
+func (m *MockGenreService) Post(ctx context.Context, genre entities.Genre) (entities.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, genre)
+	ret0, _ := ret[0].(entities.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockGenreServiceMockRecorder) Post(ctx, genre interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockGenreService)(nil).Post), ctx, genre)
+}
+
+// Put mocks base method.
+func (m *MockGenreService) Put(ctx context.Context, genre entities.Genre, id int) (entities.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, genre, id)
+	ret0, _ := ret[0].(entities.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockGenreServiceMockRecorder) Put(ctx, genre, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockGenreService)(nil).Put), ctx, genre, id)
+}
+
+// MockTagService is a mock of TagService interface.
+type MockTagService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagServiceMockRecorder
+}
+
+// MockTagServiceMockRecorder is the mock recorder for MockTagService.
+type MockTagServiceMockRecorder struct {
+	mock *MockTagService
+}
+
+// NewMockTagService creates a new mock instance.
+func NewMockTagService(ctrl *gomock.Controller) *MockTagService {
+	mock := &MockTagService{ctrl: ctrl}
+	mock.recorder = &MockTagServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagService) EXPECT() *MockTagServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockTagService) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTagServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTagService)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockTagService) GetAll(ctx context.Context) ([]entities.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]entities.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTagServiceMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTagService)(nil).GetAll), ctx)
+}
+
+// Post mocks base method.
+func (m *MockTagService) Post(ctx context.Context, tag entities.Tag) (entities.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, tag)
+	ret0, _ := ret[0].(entities.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockTagServiceMockRecorder) Post(ctx, tag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockTagService)(nil).Post), ctx, tag)
+}
+
+// MockSeriesService is a mock of SeriesService interface.
+type MockSeriesService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSeriesServiceMockRecorder
+}
+
+// MockSeriesServiceMockRecorder is the mock recorder for MockSeriesService.
+type MockSeriesServiceMockRecorder struct {
+	mock *MockSeriesService
+}
+
+// NewMockSeriesService creates a new mock instance.
+func NewMockSeriesService(ctrl *gomock.Controller) *MockSeriesService {
+	mock := &MockSeriesService{ctrl: ctrl}
+	mock.recorder = &MockSeriesServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSeriesService) EXPECT() *MockSeriesServiceMockRecorder {
+	return m.recorder
+}
+
+// AddBook mocks base method.
+func (m *MockSeriesService) AddBook(ctx context.Context, id int, book entities.SeriesBook) (entities.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBook", ctx, id, book)
+	ret0, _ := ret[0].(entities.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddBook indicates an expected call of AddBook.
+func (mr *MockSeriesServiceMockRecorder) AddBook(ctx, id, book interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBook", reflect.TypeOf((*MockSeriesService)(nil).AddBook), ctx, id, book)
+}
+
+// Delete mocks base method.
+func (m *MockSeriesService) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSeriesServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSeriesService)(nil).Delete), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockSeriesService) GetByID(ctx context.Context, id int) (entities.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(entities.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockSeriesServiceMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockSeriesService)(nil).GetByID), ctx, id)
+}
+
+// Post mocks base method.
+func (m *MockSeriesService) Post(ctx context.Context, series entities.Series) (entities.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, series)
+	ret0, _ := ret[0].(entities.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockSeriesServiceMockRecorder) Post(ctx, series interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockSeriesService)(nil).Post), ctx, series)
+}
+
+// Put mocks base method.
+func (m *MockSeriesService) Put(ctx context.Context, series entities.Series, id int) (entities.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, series, id)
+	ret0, _ := ret[0].(entities.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockSeriesServiceMockRecorder) Put(ctx, series, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockSeriesService)(nil).Put), ctx, series, id)
+}
+
+// RemoveBook mocks base method.
+func (m *MockSeriesService) RemoveBook(ctx context.Context, id, bookID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveBook", ctx, id, bookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveBook indicates an expected call of RemoveBook.
+func (mr *MockSeriesServiceMockRecorder) RemoveBook(ctx, id, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveBook", reflect.TypeOf((*MockSeriesService)(nil).RemoveBook), ctx, id, bookID)
+}
+
+// MockWorkService is a mock of WorkService interface.
+type MockWorkService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkServiceMockRecorder
+}
+
+// MockWorkServiceMockRecorder is the mock recorder for MockWorkService.
+type MockWorkServiceMockRecorder struct {
+	mock *MockWorkService
+}
+
+// NewMockWorkService creates a new mock instance.
+func NewMockWorkService(ctrl *gomock.Controller) *MockWorkService {
+	mock := &MockWorkService{ctrl: ctrl}
+	mock.recorder = &MockWorkServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkService) EXPECT() *MockWorkServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockWorkService) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWorkServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWorkService)(nil).Delete), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockWorkService) GetByID(ctx context.Context, id int) (entities.Work, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(entities.Work)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockWorkServiceMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockWorkService)(nil).GetByID), ctx, id)
+}
+
+// Post mocks base method.
+func (m *MockWorkService) Post(ctx context.Context, work entities.Work) (entities.Work, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, work)
+	ret0, _ := ret[0].(entities.Work)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockWorkServiceMockRecorder) Post(ctx, work interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockWorkService)(nil).Post), ctx, work)
+}
+
+// Put mocks base method.
+func (m *MockWorkService) Put(ctx context.Context, work entities.Work, id int) (entities.Work, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, work, id)
+	ret0, _ := ret[0].(entities.Work)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockWorkServiceMockRecorder) Put(ctx, work, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockWorkService)(nil).Put), ctx, work, id)
+}
+
+// MockReviewService is a mock of ReviewService interface.
+type MockReviewService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReviewServiceMockRecorder
+}
+
+// MockReviewServiceMockRecorder is the mock recorder for MockReviewService.
+type MockReviewServiceMockRecorder struct {
+	mock *MockReviewService
+}
+
+// NewMockReviewService creates a new mock instance.
+func NewMockReviewService(ctrl *gomock.Controller) *MockReviewService {
+	mock := &MockReviewService{ctrl: ctrl}
+	mock.recorder = &MockReviewServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReviewService) EXPECT() *MockReviewServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockReviewService) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockReviewServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockReviewService)(nil).Delete), ctx, id)
+}
+
+// GetByBook mocks base method.
+func (m *MockReviewService) GetByBook(ctx context.Context, bookID int, filter entities.ReviewFilter) (entities.ReviewPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByBook", ctx, bookID, filter)
+	ret0, _ := ret[0].(entities.ReviewPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByBook indicates an expected call of GetByBook.
+func (mr *MockReviewServiceMockRecorder) GetByBook(ctx, bookID, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByBook", reflect.TypeOf((*MockReviewService)(nil).GetByBook), ctx, bookID, filter)
+}
+
+// MarkHelpful mocks base method.
+func (m *MockReviewService) MarkHelpful(ctx context.Context, id int) (entities.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkHelpful", ctx, id)
+	ret0, _ := ret[0].(entities.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkHelpful indicates an expected call of MarkHelpful.
+func (mr *MockReviewServiceMockRecorder) MarkHelpful(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkHelpful", reflect.TypeOf((*MockReviewService)(nil).MarkHelpful), ctx, id)
+}
+
+// Post mocks base method.
+func (m *MockReviewService) Post(ctx context.Context, bookID int, review entities.Review) (entities.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, bookID, review)
+	ret0, _ := ret[0].(entities.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockReviewServiceMockRecorder) Post(ctx, bookID, review interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockReviewService)(nil).Post), ctx, bookID, review)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockReviewService) UpdateStatus(ctx context.Context, id int, status string) (entities.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, status)
+	ret0, _ := ret[0].(entities.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockReviewServiceMockRecorder) UpdateStatus(ctx, id, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockReviewService)(nil).UpdateStatus), ctx, id, status)
+}
+
+// MockAPIKeyService is a mock of APIKeyService interface.
+type MockAPIKeyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyServiceMockRecorder
+}
+
+// MockAPIKeyServiceMockRecorder is the mock recorder for MockAPIKeyService.
+type MockAPIKeyServiceMockRecorder struct {
+	mock *MockAPIKeyService
+}
+
+// NewMockAPIKeyService creates a new mock instance.
+func NewMockAPIKeyService(ctrl *gomock.Controller) *MockAPIKeyService {
+	mock := &MockAPIKeyService{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyService) EXPECT() *MockAPIKeyServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockAPIKeyService) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAPIKeyServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAPIKeyService)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockAPIKeyService) GetAll(ctx context.Context) ([]entities.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]entities.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAPIKeyServiceMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAPIKeyService)(nil).GetAll), ctx)
+}
+
+// Post mocks base method.
+func (m *MockAPIKeyService) Post(ctx context.Context, key entities.APIKey) (entities.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, key)
+	ret0, _ := ret[0].(entities.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockAPIKeyServiceMockRecorder) Post(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockAPIKeyService)(nil).Post), ctx, key)
+}
+
+// Put mocks base method.
+func (m *MockAPIKeyService) Put(ctx context.Context, key entities.APIKey, id int) (entities.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, key, id)
+	ret0, _ := ret[0].(entities.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockAPIKeyServiceMockRecorder) Put(ctx, key, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockAPIKeyService)(nil).Put), ctx, key, id)
+}
+
+// MockAuditService is a mock of AuditService interface.
+type MockAuditService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditServiceMockRecorder
+}
+
+// MockAuditServiceMockRecorder is the mock recorder for MockAuditService.
+type MockAuditServiceMockRecorder struct {
+	mock *MockAuditService
+}
+
+// NewMockAuditService creates a new mock instance.
+func NewMockAuditService(ctrl *gomock.Controller) *MockAuditService {
+	mock := &MockAuditService{ctrl: ctrl}
+	mock.recorder = &MockAuditServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditService) EXPECT() *MockAuditServiceMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method.
+func (m *MockAuditService) GetAll(ctx context.Context, filter entities.AuditFilter) ([]entities.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, filter)
+	ret0, _ := ret[0].([]entities.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAuditServiceMockRecorder) GetAll(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAuditService)(nil).GetAll), ctx, filter)
+}
+
+// MockImportService is a mock of ImportService interface.
+type MockImportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportServiceMockRecorder
+}
+
+// MockImportServiceMockRecorder is the mock recorder for MockImportService.
+type MockImportServiceMockRecorder struct {
+	mock *MockImportService
+}
+
+// NewMockImportService creates a new mock instance.
+func NewMockImportService(ctrl *gomock.Controller) *MockImportService {
+	mock := &MockImportService{ctrl: ctrl}
+	mock.recorder = &MockImportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportService) EXPECT() *MockImportServiceMockRecorder {
+	return m.recorder
+}
+
+// GetByID mocks base method.
+func (m *MockImportService) GetByID(ctx context.Context, id int) (entities.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(entities.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockImportServiceMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockImportService)(nil).GetByID), ctx, id)
+}
+
+// Post mocks base method.
+func (m *MockImportService) Post(ctx context.Context, opts entities.ImportOptions, data []byte) (entities.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, opts, data)
+	ret0, _ := ret[0].(entities.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockImportServiceMockRecorder) Post(ctx, opts, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockImportService)(nil).Post), ctx, opts, data)
+}
+
+// MockExportService is a mock of ExportService interface.
+type MockExportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportServiceMockRecorder
+}
+
+// MockExportServiceMockRecorder is the mock recorder for MockExportService.
+type MockExportServiceMockRecorder struct {
+	mock *MockExportService
+}
+
+// NewMockExportService creates a new mock instance.
+func NewMockExportService(ctrl *gomock.Controller) *MockExportService {
+	mock := &MockExportService{ctrl: ctrl}
+	mock.recorder = &MockExportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportService) EXPECT() *MockExportServiceMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockExportService) Export(ctx context.Context, w io.Writer, opts entities.ExportOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, w, opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockExportServiceMockRecorder) Export(ctx, w, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockExportService)(nil).Export), ctx, w, opts)
+}
+
+// ExportBook mocks base method.
+func (m *MockExportService) ExportBook(ctx context.Context, w io.Writer, id int, format string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportBook", ctx, w, id, format)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportBook indicates an expected call of ExportBook.
+func (mr *MockExportServiceMockRecorder) ExportBook(ctx, w, id, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportBook", reflect.TypeOf((*MockExportService)(nil).ExportBook), ctx, w, id, format)
+}
+
+// MockWebhookService is a mock of WebhookService interface.
+type MockWebhookService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookServiceMockRecorder
+}
+
+// MockWebhookServiceMockRecorder is the mock recorder for MockWebhookService.
+type MockWebhookServiceMockRecorder struct {
+	mock *MockWebhookService
+}
+
+// NewMockWebhookService creates a new mock instance.
+func NewMockWebhookService(ctrl *gomock.Controller) *MockWebhookService {
+	mock := &MockWebhookService{ctrl: ctrl}
+	mock.recorder = &MockWebhookServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookService) EXPECT() *MockWebhookServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockWebhookService) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhookServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhookService)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockWebhookService) GetAll(ctx context.Context) ([]entities.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]entities.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockWebhookServiceMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWebhookService)(nil).GetAll), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockWebhookService) GetByID(ctx context.Context, id int) (entities.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(entities.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockWebhookServiceMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockWebhookService)(nil).GetByID), ctx, id)
+}
+
+// GetDeliveries mocks base method.
+func (m *MockWebhookService) GetDeliveries(ctx context.Context, id int, filter entities.DeliveryFilter) ([]entities.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", ctx, id, filter)
+	ret0, _ := ret[0].([]entities.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockWebhookServiceMockRecorder) GetDeliveries(ctx, id, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockWebhookService)(nil).GetDeliveries), ctx, id, filter)
+}
+
+// Post mocks base method.
+func (m *MockWebhookService) Post(ctx context.Context, webhook entities.Webhook) (entities.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, webhook)
+	ret0, _ := ret[0].(entities.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockWebhookServiceMockRecorder) Post(ctx, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockWebhookService)(nil).Post), ctx, webhook)
+}
+
+// Put mocks base method.
+func (m *MockWebhookService) Put(ctx context.Context, webhook entities.Webhook, id int) (entities.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, webhook, id)
+	ret0, _ := ret[0].(entities.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockWebhookServiceMockRecorder) Put(ctx, webhook, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockWebhookService)(nil).Put), ctx, webhook, id)
+}
+
+// Redeliver mocks base method.
+func (m *MockWebhookService) Redeliver(ctx context.Context, id, deliveryID int) (entities.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeliver", ctx, id, deliveryID)
+	ret0, _ := ret[0].(entities.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redeliver indicates an expected call of Redeliver.
+func (mr *MockWebhookServiceMockRecorder) Redeliver(ctx, id, deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeliver", reflect.TypeOf((*MockWebhookService)(nil).Redeliver), ctx, id, deliveryID)
+}
+
+// MockChangeService is a mock of ChangeService interface.
+type MockChangeService struct {
+	ctrl     *gomock.Controller
+	recorder *MockChangeServiceMockRecorder
+}
+
+// MockChangeServiceMockRecorder is the mock recorder for MockChangeService.
+type MockChangeServiceMockRecorder struct {
+	mock *MockChangeService
+}
+
+// NewMockChangeService creates a new mock instance.
+func NewMockChangeService(ctrl *gomock.Controller) *MockChangeService {
+	mock := &MockChangeService{ctrl: ctrl}
+	mock.recorder = &MockChangeServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChangeService) EXPECT() *MockChangeServiceMockRecorder {
+	return m.recorder
+}
+
+// Follow mocks base method.
+func (m *MockChangeService) Follow(ctx context.Context, after int, filter entities.EventFilter, send func(entities.Event) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Follow", ctx, after, filter, send)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Follow indicates an expected call of Follow.
+func (mr *MockChangeServiceMockRecorder) Follow(ctx, after, filter, send interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Follow", reflect.TypeOf((*MockChangeService)(nil).Follow), ctx, after, filter, send)
+}
+
+// MockBatchService is a mock of BatchService interface.
+type MockBatchService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchServiceMockRecorder
+}
+
+// MockBatchServiceMockRecorder is the mock recorder for MockBatchService.
+type MockBatchServiceMockRecorder struct {
+	mock *MockBatchService
+}
+
+// NewMockBatchService creates a new mock instance.
+func NewMockBatchService(ctrl *gomock.Controller) *MockBatchService {
+	mock := &MockBatchService{ctrl: ctrl}
+	mock.recorder = &MockBatchServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatchService) EXPECT() *MockBatchServiceMockRecorder {
+	return m.recorder
+}
+
+// Run mocks base method.
+func (m *MockBatchService) Run(ctx context.Context, batch entities.Batch) (entities.BatchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", ctx, batch)
+	ret0, _ := ret[0].(entities.BatchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockBatchServiceMockRecorder) Run(ctx, batch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockBatchService)(nil).Run), ctx, batch)
+}
+
+// MockIdempotencyService is a mock of IdempotencyService interface.
+type MockIdempotencyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyServiceMockRecorder
+}
+
+// MockIdempotencyServiceMockRecorder is the mock recorder for MockIdempotencyService.
+type MockIdempotencyServiceMockRecorder struct {
+	mock *MockIdempotencyService
+}
+
+// NewMockIdempotencyService creates a new mock instance.
+func NewMockIdempotencyService(ctrl *gomock.Controller) *MockIdempotencyService {
+	mock := &MockIdempotencyService{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyService) EXPECT() *MockIdempotencyServiceMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockIdempotencyService) Begin(ctx context.Context, request entities.IdempotentRequest) (*entities.IdempotentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx, request)
+	ret0, _ := ret[0].(*entities.IdempotentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockIdempotencyServiceMockRecorder) Begin(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockIdempotencyService)(nil).Begin), ctx, request)
+}
+
+// Finish mocks base method.
+func (m *MockIdempotencyService) Finish(ctx context.Context, request entities.IdempotentRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Finish", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Finish indicates an expected call of Finish.
+func (mr *MockIdempotencyServiceMockRecorder) Finish(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finish", reflect.TypeOf((*MockIdempotencyService)(nil).Finish), ctx, request)
+}
+
+// MockEventPublisher is a mock of EventPublisher interface.
+type MockEventPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventPublisherMockRecorder
+}
+
+// MockEventPublisherMockRecorder is the mock recorder for MockEventPublisher.
+type MockEventPublisherMockRecorder struct {
+	mock *MockEventPublisher
+}
+
+// NewMockEventPublisher creates a new mock instance.
+func NewMockEventPublisher(ctrl *gomock.Controller) *MockEventPublisher {
+	mock := &MockEventPublisher{ctrl: ctrl}
+	mock.recorder = &MockEventPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventPublisher) EXPECT() *MockEventPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockEventPublisher) Publish(ctx context.Context, event entities.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockEventPublisherMockRecorder) Publish(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockEventPublisher)(nil).Publish), ctx, event)
+}
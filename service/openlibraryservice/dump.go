@@ -0,0 +1,222 @@
+package openlibraryservice
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"strings"
+)
+
+const (
+	// checkpointEvery : lines between two checkpoints, a resumed import repeats at most these lines
+	// and finds their records imported
+	checkpointEvery = 1000
+	progressEvery   = 100000
+)
+
+// Checkpoint : how far an import of dump files got, the offset is only used to seek in uncompressed files
+type Checkpoint struct {
+	Done   []string `json:"done"`
+	File   string   `json:"file"`
+	Line   int      `json:"line"`
+	Offset int64    `json:"offset"`
+}
+
+// Summary : what became of the lines of the dumps, Reasons counts the skipped lines by reason
+type Summary struct {
+	Lines      int            `json:"lines"`
+	Imported   int            `json:"imported"`
+	Duplicates int            `json:"duplicates"`
+	Skipped    int            `json:"skipped"`
+	Reasons    map[string]int `json:"reasons"`
+}
+
+// Run : imports the dump files in order, authors have to come before the works and editions which refer to them.
+// Progress is saved to the checkpoint file so an interrupted import resumes where it stopped, the file is removed
+// once every dump is imported
+func (s OpenLibraryService) Run(ctx context.Context, files []string, checkpointFile string) (Summary, error) {
+	summary := Summary{Reasons: map[string]int{}}
+
+	cp, err := loadCheckpoint(checkpointFile)
+	if err != nil {
+		return summary, err
+	}
+
+	for _, file := range files {
+		if contains(cp.Done, file) {
+			log.Printf("%v was imported before", file)
+			continue
+		}
+
+		if err = s.runFile(ctx, file, &cp, checkpointFile, &summary); err != nil {
+			return summary, err
+		}
+
+		cp.Done = append(cp.Done, file)
+		cp.File, cp.Line, cp.Offset = "", 0, 0
+
+		if err = saveCheckpoint(checkpointFile, cp); err != nil {
+			return summary, err
+		}
+	}
+
+	if err = os.Remove(checkpointFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return summary, err
+	}
+
+	return summary, nil
+}
+
+// runFile : imports the lines of a dump from the checkpoint on, gzip compressed dumps are read as they are
+func (s OpenLibraryService) runFile(ctx context.Context, file string, cp *Checkpoint, checkpointFile string,
+	summary *Summary) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if cp.File != file {
+		cp.File, cp.Line, cp.Offset = file, 0, 0
+	}
+
+	compressed := strings.HasSuffix(file, ".gz")
+
+	if !compressed && cp.Offset > 0 {
+		if _, err = f.Seek(cp.Offset, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
+	var source io.Reader = f
+
+	if compressed {
+		gz, err := gzip.NewReader(bufio.NewReaderSize(f, 1<<20))
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+
+		source = gz
+	}
+
+	r := bufio.NewReaderSize(source, 1<<20)
+
+	// a compressed dump can not seek, the lines before the checkpoint are read again and dropped
+	for skip := 0; compressed && skip < cp.Line; skip++ {
+		if _, err = r.ReadBytes('\n'); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("importing %v from line %v", file, cp.Line+1)
+
+	for {
+		if err = ctx.Err(); err != nil {
+			return stop(err, checkpointFile, *cp)
+		}
+
+		line, readErr := r.ReadBytes('\n')
+
+		if len(line) > 0 {
+			outcome, reason, err := s.ImportLine(ctx, line)
+			if err != nil {
+				return stop(err, checkpointFile, *cp)
+			}
+
+			count(summary, outcome, reason)
+
+			cp.Line++
+			cp.Offset += int64(len(line))
+
+			if cp.Line%checkpointEvery == 0 {
+				if err = saveCheckpoint(checkpointFile, *cp); err != nil {
+					return err
+				}
+			}
+
+			if summary.Lines%progressEvery == 0 {
+				log.Printf("%v lines: %v imported, %v duplicates, %v skipped", summary.Lines, summary.Imported,
+					summary.Duplicates, summary.Skipped)
+			}
+		}
+
+		if errors.Is(readErr, io.EOF) {
+			return nil
+		}
+
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
+
+// stop : saves the checkpoint of an import which can not go on and gives the reason it stopped
+func stop(err error, checkpointFile string, cp Checkpoint) error {
+	if saveErr := saveCheckpoint(checkpointFile, cp); saveErr != nil {
+		log.Printf("checkpoint of %v at line %v not saved: %v", cp.File, cp.Line, saveErr)
+	}
+
+	return err
+}
+
+// count : adds the outcome of a line to the summary
+func count(summary *Summary, outcome, reason string) {
+	summary.Lines++
+
+	switch outcome {
+	case imported:
+		summary.Imported++
+	case duplicate:
+		summary.Duplicates++
+	default:
+		summary.Skipped++
+		summary.Reasons[reason]++
+	}
+}
+
+// loadCheckpoint : reads the checkpoint, an import without one starts from the beginning
+func loadCheckpoint(file string) (Checkpoint, error) {
+	var cp Checkpoint
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cp, nil
+	}
+
+	if err != nil {
+		return cp, err
+	}
+
+	return cp, json.Unmarshal(data, &cp)
+}
+
+// saveCheckpoint : writes the checkpoint through a temporary file so an interruption never leaves half of it
+func saveCheckpoint(file string, cp Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(file+".tmp", data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(file+".tmp", file)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,121 @@
+package openlibraryservice
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+)
+
+// dumpLines : lines of records the import skips, so only their keys are looked up
+var dumpLines = []string{
+	"/type/redirect\t/authors/OL1A\t1\t2021-01-01T00:00:00\t{}\n",
+	"/type/redirect\t/authors/OL2A\t1\t2021-01-01T00:00:00\t{}\n",
+	"/type/redirect\t/authors/OL3A\t1\t2021-01-01T00:00:00\t{}\n",
+}
+
+// TestRunResume : test that an import stopped by the database resumes after the last line it finished
+func TestRunResume(t *testing.T) {
+	for _, compressed := range []bool{false, true} {
+		ctrl := gomock.NewController(t)
+		s, m := newService(ctrl)
+		dir := t.TempDir()
+		dump := writeDump(t, dir, compressed)
+		checkpoint := filepath.Join(dir, "checkpoint")
+
+		gomock.InOrder(
+			m.keys.EXPECT().Get(gomock.Any(), "/authors/OL1A").Return(0, sql.ErrNoRows),
+			m.keys.EXPECT().Get(gomock.Any(), "/authors/OL2A").Return(0, errors.New("connection lost")),
+		)
+
+		if _, err := s.Run(context.TODO(), []string{dump}, checkpoint); err == nil {
+			t.Fatalf("compressed %v: expected the import to stop", compressed)
+		}
+
+		var cp Checkpoint
+
+		data, err := os.ReadFile(checkpoint)
+		if err != nil || json.Unmarshal(data, &cp) != nil {
+			t.Fatalf("compressed %v: checkpoint not saved: %v", compressed, err)
+		}
+
+		if !reflect.DeepEqual(cp, Checkpoint{File: dump, Line: 1, Offset: int64(len(dumpLines[0]))}) {
+			t.Errorf("compressed %v: unexpected checkpoint %+v", compressed, cp)
+		}
+
+		gomock.InOrder(
+			m.keys.EXPECT().Get(gomock.Any(), "/authors/OL2A").Return(0, sql.ErrNoRows),
+			m.keys.EXPECT().Get(gomock.Any(), "/authors/OL3A").Return(0, sql.ErrNoRows),
+		)
+
+		summary, err := s.Run(context.TODO(), []string{dump}, checkpoint)
+		if err != nil {
+			t.Fatalf("compressed %v: unexpected error %v", compressed, err)
+		}
+
+		expected := Summary{Lines: 2, Skipped: 2, Reasons: map[string]int{"unsupported type /type/redirect": 2}}
+		if !reflect.DeepEqual(summary, expected) {
+			t.Errorf("compressed %v: unexpected summary %+v", compressed, summary)
+		}
+
+		if _, err = os.Stat(checkpoint); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("compressed %v: the checkpoint of a finished import has to be removed", compressed)
+		}
+	}
+}
+
+// TestRunDone : test that dumps the checkpoint lists as done are not read again
+func TestRunDone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s, _ := newService(ctrl)
+	dir := t.TempDir()
+	checkpoint := filepath.Join(dir, "checkpoint")
+
+	if err := saveCheckpoint(checkpoint, Checkpoint{Done: []string{"authors.txt"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := s.Run(context.TODO(), []string{"authors.txt"}, checkpoint)
+	if err != nil || summary.Lines != 0 {
+		t.Errorf("expected nothing to be imported, got %+v %v", summary, err)
+	}
+}
+
+// writeDump : writes the dump lines to a file, gzip compressed when asked
+func writeDump(t *testing.T, dir string, compressed bool) string {
+	name := filepath.Join(dir, "ol_dump_authors.txt")
+	if compressed {
+		name += ".gz"
+	}
+
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var w io.Writer = f
+
+	if compressed {
+		gz := gzip.NewWriter(f)
+		defer gz.Close()
+
+		w = gz
+	}
+
+	for _, line := range dumpLines {
+		if _, err = w.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return name
+}
@@ -0,0 +1,398 @@
+package openlibraryservice
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+// outcomes of a dump line
+const (
+	imported  = "imported"
+	duplicate = "duplicate"
+	skipped   = "skipped"
+)
+
+// dateLayouts : the forms of the free text dates of Open Library which carry more than a year
+var dateLayouts = []string{"2006-01-02", "January 2, 2006", "2 January 2006", "Jan 2, 2006", "2 Jan 2006",
+	"January 2006", "Jan 2006", "2006-01"}
+
+var yearPattern = regexp.MustCompile(`(?:^|[^0-9])(1[0-9]{3}|20[0-9]{2})(?:$|[^0-9])`)
+
+type OpenLibraryService struct {
+	keyStore    store.OpenLibraryKeyStorer
+	authorStore store.AuthorStorer
+	workStore   store.WorkStorer
+	bookStore   store.BookStorer
+	authors     service.AuthorService
+	works       service.WorkService
+	books       service.BookService
+	tx          store.Transactor
+}
+
+// New : factory function, records go through the author, work and book services so they get the same checks,
+// audit and history as single requests; books are imported without the publisher and year rules of Post
+func New(keys store.OpenLibraryKeyStorer, as store.AuthorStorer, ws store.WorkStorer, bs store.BookStorer,
+	authors service.AuthorService, works service.WorkService, books service.BookService,
+	tx store.Transactor) OpenLibraryService {
+	return OpenLibraryService{keys, as, ws, bs, authors, works, books, tx}
+}
+
+// reference : the {"key": ...} objects records use to point at each other
+type reference struct {
+	Key string `json:"key"`
+}
+
+type authorRecord struct {
+	Key          string `json:"key"`
+	Name         string `json:"name"`
+	PersonalName string `json:"personal_name"`
+	BirthDate    string `json:"birth_date"`
+}
+
+type workRecord struct {
+	Key     string `json:"key"`
+	Title   string `json:"title"`
+	Authors []struct {
+		Author reference `json:"author"`
+	} `json:"authors"`
+}
+
+type editionRecord struct {
+	Key            string      `json:"key"`
+	Title          string      `json:"title"`
+	Subtitle       string      `json:"subtitle"`
+	Authors        []reference `json:"authors"`
+	Works          []reference `json:"works"`
+	Publishers     []string    `json:"publishers"`
+	PublishDate    string      `json:"publish_date"`
+	ISBN13         []string    `json:"isbn_13"`
+	ISBN10         []string    `json:"isbn_10"`
+	NumberOfPages  int         `json:"number_of_pages"`
+	PhysicalFormat string      `json:"physical_format"`
+}
+
+// ImportLine : imports the record of a dump line, the tab separated columns are the type, key, revision,
+// last modification and the JSON record. It gives whether the record was imported, is a duplicate or was skipped
+// with the reason; an error means the database could not be asked and the import has to stop
+func (s OpenLibraryService) ImportLine(ctx context.Context, line []byte) (outcome, reason string, err error) {
+	columns := bytes.Split(bytes.TrimRight(line, "\r\n"), []byte("\t"))
+	if len(columns) < 5 {
+		return skipped, "malformed line", nil
+	}
+
+	found, err := s.imported(ctx, string(columns[1]))
+	if err != nil {
+		return "", "", err
+	}
+
+	if found {
+		return duplicate, "", nil
+	}
+
+	record := columns[len(columns)-1]
+
+	switch string(columns[0]) {
+	case "/type/author":
+		var a authorRecord
+		if err := json.Unmarshal(record, &a); err != nil {
+			return skipped, "malformed record", nil
+		}
+
+		return s.importAuthor(ctx, a)
+	case "/type/work":
+		var w workRecord
+		if err := json.Unmarshal(record, &w); err != nil {
+			return skipped, "malformed record", nil
+		}
+
+		return s.importWork(ctx, w)
+	case "/type/edition":
+		var e editionRecord
+		if err := json.Unmarshal(record, &e); err != nil {
+			return skipped, "malformed record", nil
+		}
+
+		return s.importEdition(ctx, e)
+	}
+
+	return skipped, "unsupported type " + string(columns[0]), nil
+}
+
+// importAuthor : an author with the same name is taken as the same person
+func (s OpenLibraryService) importAuthor(ctx context.Context, a authorRecord) (string, string, error) {
+	name := a.Name
+	if name == "" {
+		name = a.PersonalName
+	}
+
+	names := strings.Fields(name)
+	if len(names) == 0 {
+		return skipped, "missing name", nil
+	}
+
+	firstName, lastName := names[0], strings.Join(names[1:], " ")
+
+	existing, err := s.authorStore.GetAuthorByName(ctx, firstName, lastName)
+	if err == nil {
+		return duplicate, "", s.keyStore.Post(ctx, a.Key, existing.AuthorID)
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", "", err
+	}
+
+	dob := parseDate(a.BirthDate)
+	if dob == "" {
+		return skipped, "missing birth date", nil
+	}
+
+	return s.create(ctx, a.Key, func(ctx context.Context) (int, error) {
+		author, err := s.authors.Post(ctx, entities.Author{FirstName: firstName, LastName: lastName, DOB: dob})
+		return author.AuthorID, err
+	})
+}
+
+// importWork : a work of the same author with the same title is taken as the same work
+func (s OpenLibraryService) importWork(ctx context.Context, w workRecord) (string, string, error) {
+	authorKeys := make([]string, len(w.Authors))
+	for i := range w.Authors {
+		authorKeys[i] = w.Authors[i].Author.Key
+	}
+
+	authorID, err := s.firstImported(ctx, authorKeys)
+	if err != nil || authorID == 0 {
+		return skipped, "author not imported", err
+	}
+
+	existing, err := s.workStore.GetByTitle(ctx, authorID, strings.TrimSpace(w.Title))
+	if err == nil {
+		return duplicate, "", s.keyStore.Post(ctx, w.Key, existing.WorkID)
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", "", err
+	}
+
+	return s.create(ctx, w.Key, func(ctx context.Context) (int, error) {
+		work, err := s.works.Post(ctx, entities.Work{AuthorID: authorID, Title: w.Title})
+		return work.WorkID, err
+	})
+}
+
+// importEdition : an edition becomes a book, it is the same book as one with its ISBN or else as one of the same
+// author with the same title; the edition details are only kept when the work, ISBN and format are known
+func (s OpenLibraryService) importEdition(ctx context.Context, e editionRecord) (string, string, error) {
+	workKeys := make([]string, len(e.Works))
+	for i := range e.Works {
+		workKeys[i] = e.Works[i].Key
+	}
+
+	workID, err := s.firstImported(ctx, workKeys)
+	if err != nil {
+		return "", "", err
+	}
+
+	authorKeys := make([]string, len(e.Authors))
+	for i := range e.Authors {
+		authorKeys[i] = e.Authors[i].Key
+	}
+
+	authorID, err := s.firstImported(ctx, authorKeys)
+	if err != nil {
+		return "", "", err
+	}
+
+	if authorID == 0 && workID > 0 {
+		work, err := s.workStore.GetByID(ctx, workID)
+		if err != nil {
+			return "", "", err
+		}
+
+		authorID = work.AuthorID
+	}
+
+	if authorID == 0 {
+		return skipped, "author not imported", nil
+	}
+
+	title := strings.TrimSpace(e.Title)
+	if e.Subtitle != "" {
+		title += ": " + strings.TrimSpace(e.Subtitle)
+	}
+
+	isbn := firstISBN(append(append([]string{}, e.ISBN13...), e.ISBN10...))
+
+	if id, err := s.existingBook(ctx, isbn, authorID, title); id > 0 || err != nil {
+		if err != nil {
+			return "", "", err
+		}
+
+		return duplicate, "", s.keyStore.Post(ctx, e.Key, id)
+	}
+
+	book := entities.Book{AuthorID: authorID, Title: title, PublishedDate: parseDate(e.PublishDate)}
+	if len(e.Publishers) > 0 {
+		book.Publication = strings.TrimSpace(e.Publishers[0])
+	}
+
+	if format := editionFormat(e.PhysicalFormat); workID > 0 && isbn != "" && format != "" {
+		book.Edition = &entities.Edition{WorkID: workID, ISBN: isbn, Format: format, PageCount: e.NumberOfPages}
+	}
+
+	return s.create(ctx, e.Key, func(ctx context.Context) (int, error) {
+		created, err := s.books.Import(ctx, &book)
+		return created.BookID, err
+	})
+}
+
+// imported : whether the key was imported before
+func (s OpenLibraryService) imported(ctx context.Context, key string) (bool, error) {
+	if key == "" {
+		return false, nil
+	}
+
+	_, err := s.keyStore.Get(ctx, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// firstImported : the id of the first key which was imported, 0 when none was
+func (s OpenLibraryService) firstImported(ctx context.Context, keys []string) (int, error) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+
+		id, err := s.keyStore.Get(ctx, key)
+		if err == nil {
+			return id, nil
+		}
+
+		if !errors.Is(err, sql.ErrNoRows) {
+			return 0, err
+		}
+	}
+
+	return 0, nil
+}
+
+// existingBook : the id of a book with the ISBN or else of the author with the title, 0 when there is none
+func (s OpenLibraryService) existingBook(ctx context.Context, isbn string, authorID int, title string) (int, error) {
+	if isbn != "" {
+		book, err := s.bookStore.GetBookByISBN(ctx, isbn)
+		if err == nil {
+			return book.BookID, nil
+		}
+
+		if !errors.Is(err, sql.ErrNoRows) {
+			return 0, err
+		}
+	}
+
+	books, err := s.bookStore.GetBooksByTitle(ctx, title)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range books {
+		if books[i].AuthorID == authorID {
+			return books[i].BookID, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// create : posts a record and remembers its key in one transaction, a record the services reject is skipped
+func (s OpenLibraryService) create(ctx context.Context, key string, post func(ctx context.Context) (int, error)) (
+	string, string, error) {
+	var rejected error
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		id, err := post(ctx)
+		if err != nil {
+			rejected = err
+			return err
+		}
+
+		if key == "" {
+			return nil
+		}
+
+		return s.keyStore.Post(ctx, key, id)
+	})
+
+	switch {
+	case rejected != nil:
+		return skipped, rejected.Error(), nil
+	case err != nil:
+		return "", "", fmt.Errorf("%v: %w", key, err)
+	}
+
+	return imported, "", nil
+}
+
+// parseDate : an Open Library date in the dd/mm/yyyy form of the catalogue, a date with only a month or a year
+// is taken as its first day; empty when no year can be found
+func parseDate(date string) string {
+	date = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(date), "."))
+	if date == "" {
+		return ""
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t.Format("02/01/2006")
+		}
+	}
+
+	if match := yearPattern.FindStringSubmatch(date); match != nil {
+		return "01/01/" + match[1]
+	}
+
+	return ""
+}
+
+// firstISBN : the first ISBN without its separators, ISBN-13 are listed before ISBN-10
+func firstISBN(isbns []string) string {
+	for _, isbn := range isbns {
+		isbn = strings.NewReplacer("-", "", " ", "").Replace(isbn)
+		if len(isbn) == 10 || len(isbn) == 13 {
+			return isbn
+		}
+	}
+
+	return ""
+}
+
+// editionFormat : maps the free text physical format onto the edition formats, empty when it does not fit one
+func editionFormat(physical string) string {
+	physical = strings.ToLower(physical)
+
+	switch {
+	case strings.Contains(physical, "paperback"), strings.Contains(physical, "softcover"):
+		return "paperback"
+	case strings.Contains(physical, "hardcover"), strings.Contains(physical, "hardback"):
+		return "hardcover"
+	case strings.Contains(physical, "ebook"), strings.Contains(physical, "electronic"):
+		return "ebook"
+	case strings.Contains(physical, "audio"):
+		return "audiobook"
+	}
+
+	return ""
+}
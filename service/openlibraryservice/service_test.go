@@ -0,0 +1,258 @@
+package openlibraryservice
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+	"projects/GoLang-Interns-2022/authorbook/service/bookservice"
+	"projects/GoLang-Interns-2022/authorbook/store"
+
+	"github.com/golang/mock/gomock"
+)
+
+// mocks : the dependencies of the service
+type mocks struct {
+	keys        *store.MockOpenLibraryKeyStorer
+	authorStore *store.MockAuthorStorer
+	workStore   *store.MockWorkStorer
+	bookStore   *store.MockBookStorer
+	authors     *service.MockAuthorService
+	works       *service.MockWorkService
+	books       *service.MockBookService
+}
+
+// newService : gives the service on mocks, the transaction only runs its function
+func newService(ctrl *gomock.Controller) (OpenLibraryService, mocks) {
+	m := mocks{store.NewMockOpenLibraryKeyStorer(ctrl), store.NewMockAuthorStorer(ctrl), store.NewMockWorkStorer(ctrl),
+		store.NewMockBookStorer(ctrl), service.NewMockAuthorService(ctrl), service.NewMockWorkService(ctrl),
+		service.NewMockBookService(ctrl)}
+
+	tx := store.NewMockTransactor(ctrl)
+	tx.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) }).AnyTimes()
+
+	return New(m.keys, m.authorStore, m.workStore, m.bookStore, m.authors, m.works, m.books, tx), m
+}
+
+// TestImportLine : test the mapping and de-duplication of authors, works and editions
+func TestImportLine(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s, m := newService(ctrl)
+
+	ctx := context.TODO()
+	notImported := func(key string) { m.keys.EXPECT().Get(ctx, key).Return(0, sql.ErrNoRows) }
+
+	testcases := []struct {
+		desc  string
+		line  string
+		setup func()
+
+		expectedOutcome string
+		expectedReason  string
+		expectedErr     error
+	}{
+		{desc: "new author", line: "/type/author\t/authors/OL1A\t3\t2021-01-01T00:00:00\t" +
+			`{"key":"/authors/OL1A","name":"Ruskin Bond","birth_date":"19 May 1934"}`,
+			setup: func() {
+				notImported("/authors/OL1A")
+				m.authorStore.EXPECT().GetAuthorByName(ctx, "Ruskin", "Bond").Return(entities.Author{}, sql.ErrNoRows)
+				m.authors.EXPECT().Post(ctx, entities.Author{FirstName: "Ruskin", LastName: "Bond", DOB: "19/05/1934"}).
+					Return(entities.Author{AuthorID: 4}, nil)
+				m.keys.EXPECT().Post(ctx, "/authors/OL1A", 4).Return(nil)
+			}, expectedOutcome: imported},
+		{desc: "imported before", line: "/type/author\t/authors/OL1A\t3\t2021-01-01T00:00:00\t{}",
+			setup:           func() { m.keys.EXPECT().Get(ctx, "/authors/OL1A").Return(4, nil) },
+			expectedOutcome: duplicate},
+		{desc: "author with an existing name", line: "/type/author\t/authors/OL2A\t1\t2021-01-01T00:00:00\t" +
+			`{"key":"/authors/OL2A","personal_name":"Ruskin Bond"}`,
+			setup: func() {
+				notImported("/authors/OL2A")
+				m.authorStore.EXPECT().GetAuthorByName(ctx, "Ruskin", "Bond").Return(entities.Author{AuthorID: 4}, nil)
+				m.keys.EXPECT().Post(ctx, "/authors/OL2A", 4).Return(nil)
+			}, expectedOutcome: duplicate},
+		{desc: "author without birth date", line: "/type/author\t/authors/OL3A\t1\t2021-01-01T00:00:00\t" +
+			`{"key":"/authors/OL3A","name":"Kalidasa"}`,
+			setup: func() {
+				notImported("/authors/OL3A")
+				m.authorStore.EXPECT().GetAuthorByName(ctx, "Kalidasa", "").Return(entities.Author{}, sql.ErrNoRows)
+			}, expectedOutcome: skipped, expectedReason: "missing birth date"},
+		{desc: "new work", line: "/type/work\t/works/OL1W\t2\t2021-01-01T00:00:00\t" +
+			`{"key":"/works/OL1W","title":"Rusty","authors":[{"author":{"key":"/authors/OL9A"}},` +
+			`{"author":{"key":"/authors/OL1A"}}]}`,
+			setup: func() {
+				notImported("/works/OL1W")
+				notImported("/authors/OL9A")
+				m.keys.EXPECT().Get(ctx, "/authors/OL1A").Return(4, nil)
+				m.workStore.EXPECT().GetByTitle(ctx, 4, "Rusty").Return(entities.Work{}, sql.ErrNoRows)
+				m.works.EXPECT().Post(ctx, entities.Work{AuthorID: 4, Title: "Rusty"}).Return(entities.Work{WorkID: 2}, nil)
+				m.keys.EXPECT().Post(ctx, "/works/OL1W", 2).Return(nil)
+			}, expectedOutcome: imported},
+		{desc: "work of an unknown author", line: "/type/work\t/works/OL2W\t2\t2021-01-01T00:00:00\t" +
+			`{"key":"/works/OL2W","title":"Gitanjali","authors":[{"author":{"key":"/authors/OL9A"}}]}`,
+			setup: func() {
+				notImported("/works/OL2W")
+				notImported("/authors/OL9A")
+			}, expectedOutcome: skipped, expectedReason: "author not imported"},
+		{desc: "new edition", line: "/type/edition\t/books/OL1M\t5\t2021-01-01T00:00:00\t" +
+			`{"key":"/books/OL1M","title":"Rusty","subtitle":"the boy","works":[{"key":"/works/OL1W"}],` +
+			`"publishers":["Penguin"],"publish_date":"March 1980","isbn_10":["0-14-303"],"isbn_13":["978-0143033578"],` +
+			`"number_of_pages":120,"physical_format":"Mass Market Paperback"}`,
+			setup: func() {
+				notImported("/books/OL1M")
+				m.keys.EXPECT().Get(ctx, "/works/OL1W").Return(2, nil)
+				m.workStore.EXPECT().GetByID(ctx, 2).Return(entities.Work{WorkID: 2, AuthorID: 4}, nil)
+				m.bookStore.EXPECT().GetBookByISBN(ctx, "9780143033578").Return(entities.Book{}, sql.ErrNoRows)
+				m.bookStore.EXPECT().GetBooksByTitle(ctx, "Rusty: the boy").Return([]entities.Book{{BookID: 1, AuthorID: 5}}, nil)
+				m.books.EXPECT().Import(ctx, &entities.Book{AuthorID: 4, Title: "Rusty: the boy", Publication: "Penguin",
+					PublishedDate: "01/03/1980", Edition: &entities.Edition{WorkID: 2, ISBN: "9780143033578",
+						Format: "paperback", PageCount: 120}}).Return(entities.Book{BookID: 9}, nil)
+				m.keys.EXPECT().Post(ctx, "/books/OL1M", 9).Return(nil)
+			}, expectedOutcome: imported},
+		{desc: "edition with a known ISBN", line: "/type/edition\t/books/OL2M\t1\t2021-01-01T00:00:00\t" +
+			`{"key":"/books/OL2M","title":"Rusty","authors":[{"key":"/authors/OL1A"}],"isbn_10":["0143033573"]}`,
+			setup: func() {
+				notImported("/books/OL2M")
+				m.keys.EXPECT().Get(ctx, "/authors/OL1A").Return(4, nil)
+				m.bookStore.EXPECT().GetBookByISBN(ctx, "0143033573").Return(entities.Book{BookID: 9}, nil)
+				m.keys.EXPECT().Post(ctx, "/books/OL2M", 9).Return(nil)
+			}, expectedOutcome: duplicate},
+		{desc: "edition the book service rejects", line: "/type/edition\t/books/OL3M\t1\t2021-01-01T00:00:00\t" +
+			`{"key":"/books/OL3M","title":"Rusty","authors":[{"key":"/authors/OL1A"}],"publishers":["Rupa"]}`,
+			setup: func() {
+				notImported("/books/OL3M")
+				m.keys.EXPECT().Get(ctx, "/authors/OL1A").Return(4, nil)
+				m.bookStore.EXPECT().GetBooksByTitle(ctx, "Rusty").Return(nil, nil)
+				m.books.EXPECT().Import(ctx, gomock.Any()).Return(entities.Book{}, errors.New("invalid constraints"))
+			}, expectedOutcome: skipped, expectedReason: "invalid constraints"},
+		{desc: "database failure", line: "/type/edition\t/books/OL4M\t1\t2021-01-01T00:00:00\t{}",
+			setup: func() {
+				m.keys.EXPECT().Get(ctx, "/books/OL4M").Return(0, errors.New("connection lost"))
+			}, expectedErr: errors.New("connection lost")},
+		{desc: "unsupported type", line: "/type/redirect\t/authors/OL5A\t1\t2021-01-01T00:00:00\t{}",
+			setup:           func() { notImported("/authors/OL5A") },
+			expectedOutcome: skipped, expectedReason: "unsupported type /type/redirect"},
+		{desc: "malformed line", line: "/type/author\t/authors/OL6A", expectedOutcome: skipped,
+			expectedReason: "malformed line"},
+	}
+
+	for _, tc := range testcases {
+		if tc.setup != nil {
+			tc.setup()
+		}
+
+		outcome, reason, err := s.ImportLine(ctx, []byte(tc.line+"\n"))
+
+		if outcome != tc.expectedOutcome || reason != tc.expectedReason || (err == nil) != (tc.expectedErr == nil) {
+			t.Errorf("failed for %v, got %v %q %v", tc.desc, outcome, reason, err)
+		}
+	}
+}
+
+// TestImportEditionValidation : test editions of any publisher and year pass the checks of the book service, and
+// the ones it still rejects are skipped with its reason
+func TestImportEditionValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := mocks{store.NewMockOpenLibraryKeyStorer(ctrl), store.NewMockAuthorStorer(ctrl), store.NewMockWorkStorer(ctrl),
+		store.NewMockBookStorer(ctrl), nil, nil, nil}
+	ctx := context.TODO()
+
+	tx := store.NewMockTransactor(ctrl)
+	tx.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) }).AnyTimes()
+
+	audit := store.NewMockAuditStorer(ctrl)
+	audit.EXPECT().Post(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	history := store.NewMockHistoryStorer(ctrl)
+	history.EXPECT().PostBook(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	outbox := store.NewMockOutboxStorer(ctrl)
+	outbox.EXPECT().Post(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	books := bookservice.New(m.bookStore, m.authorStore, audit, history, outbox, tx)
+	s := New(m.keys, m.authorStore, m.workStore, m.bookStore, nil, nil, books, tx)
+
+	testcases := []struct {
+		desc  string
+		key   string
+		line  string
+		setup func()
+
+		expectedOutcome string
+		expectedReason  string
+	}{
+		{desc: "old edition of another publisher", key: "/books/OL5M",
+			line: `{"key":"/books/OL5M","title":"Dune","authors":[{"key":"/authors/OL1A"}],"publishers":["Chilton Books"],` +
+				`"publish_date":"1965"}`,
+			setup: func() {
+				m.authorStore.EXPECT().IncludeAuthor(ctx, 4).Return(entities.Author{AuthorID: 4}, nil)
+				m.bookStore.EXPECT().Post(ctx, gomock.Any()).Return(9, nil)
+				m.keys.EXPECT().Post(ctx, "/books/OL5M", 9).Return(nil)
+			}, expectedOutcome: imported},
+		{desc: "recent edition", key: "/books/OL6M",
+			line: `{"key":"/books/OL6M","title":"Dune","authors":[{"key":"/authors/OL1A"}],"publishers":["Ace"],` +
+				`"publish_date":"2024-03-05"}`,
+			setup: func() {
+				m.authorStore.EXPECT().IncludeAuthor(ctx, 4).Return(entities.Author{AuthorID: 4}, nil)
+				m.bookStore.EXPECT().Post(ctx, gomock.Any()).Return(10, nil)
+				m.keys.EXPECT().Post(ctx, "/books/OL6M", 10).Return(nil)
+			}, expectedOutcome: imported},
+		{desc: "edition without a date", key: "/books/OL7M",
+			line:            `{"key":"/books/OL7M","title":"Dune","authors":[{"key":"/authors/OL1A"}]}`,
+			setup:           func() {},
+			expectedOutcome: skipped, expectedReason: "invalid constraints"},
+	}
+
+	for _, tc := range testcases {
+		m.keys.EXPECT().Get(ctx, tc.key).Return(0, sql.ErrNoRows)
+		m.keys.EXPECT().Get(ctx, "/authors/OL1A").Return(4, nil)
+		m.bookStore.EXPECT().GetBooksByTitle(ctx, "Dune").Return(nil, nil)
+		tc.setup()
+
+		outcome, reason, err := s.ImportLine(ctx, []byte("/type/edition\t"+tc.key+"\t1\t2021-01-01T00:00:00\t"+
+			tc.line+"\n"))
+
+		if outcome != tc.expectedOutcome || reason != tc.expectedReason || err != nil {
+			t.Errorf("failed for %v, got %v %q %v", tc.desc, outcome, reason, err)
+		}
+	}
+}
+
+// TestParseDate : test the free text dates of Open Library
+func TestParseDate(t *testing.T) {
+	testcases := map[string]string{
+		"19 May 1934":    "19/05/1934",
+		"May 19, 1934":   "19/05/1934",
+		"1934-05-19":     "19/05/1934",
+		"March 1980":     "01/03/1980",
+		"1980.":          "01/01/1980",
+		"c1980":          "01/01/1980",
+		"[between 1920]": "01/01/1920",
+		"unknown":        "",
+		"":               "",
+	}
+
+	for date, expected := range testcases {
+		if got := parseDate(date); got != expected {
+			t.Errorf("date %q: expected %q got %q", date, expected, got)
+		}
+	}
+}
+
+// TestEditionFormat : test the mapping of the physical formats
+func TestEditionFormat(t *testing.T) {
+	testcases := map[string]string{
+		"Paperback": "paperback", "Mass Market Paperback": "paperback", "Hardback": "hardcover",
+		"Electronic resource": "ebook", "Audio CD": "audiobook", "Microfilm": "",
+	}
+
+	for physical, expected := range testcases {
+		if got := editionFormat(physical); got != expected {
+			t.Errorf("format %q: expected %q got %q", physical, expected, got)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package outboxservice
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"projects/GoLang-Interns-2022/authorbook/service"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+// batchSize : the events read from the outbox at a time
+const batchSize = 100
+
+// Relay : delivers the events of the outbox at least once to the publisher, an event is marked published only
+// after the publisher took it
+type Relay struct {
+	outbox    store.OutboxStorer
+	publisher service.EventPublisher
+}
+
+// New : factory function
+func New(o store.OutboxStorer, p service.EventPublisher) Relay {
+	return Relay{o, p}
+}
+
+// Run : flushes the outbox every interval until the context is done
+func (r Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.Flush(ctx); err != nil {
+			log.Printf("outbox relay: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush : publishes the pending events oldest first and gives how many were published, nothing is done while
+// another relay holds the lock. When an event fails the later events of its entity are held back until the next
+// flush so the events of every entity arrive in order, the events of other entities still go out
+func (r Relay) Flush(ctx context.Context) (int, error) {
+	release, ok, err := r.outbox.Lock(ctx)
+	if err != nil || !ok {
+		return 0, err
+	}
+	defer release()
+
+	published := 0
+
+	for {
+		events, err := r.outbox.GetPending(ctx, batchSize)
+		if err != nil {
+			return published, err
+		}
+
+		var (
+			done    []int
+			failure error
+		)
+
+		held := map[string]bool{}
+
+		for i := range events {
+			key := events[i].Key()
+			if held[key] {
+				continue
+			}
+
+			if err = r.publisher.Publish(ctx, events[i]); err != nil {
+				held[key] = true
+				failure = err
+
+				continue
+			}
+
+			done = append(done, events[i].EventID)
+		}
+
+		if err = r.outbox.MarkPublished(ctx, done); err != nil {
+			return published, err
+		}
+
+		published += len(done)
+
+		if failure != nil || len(events) < batchSize {
+			return published, failure
+		}
+	}
+}
+
+// Purger : deletes the published events once the retention is over, the change feed reads the outbox so the
+// retention bounds how far back a client can resume it
+type Purger struct {
+	outbox    store.OutboxStorer
+	retention time.Duration
+}
+
+// NewPurger : factory function, a published event is kept for the retention
+func NewPurger(o store.OutboxStorer, retention time.Duration) Purger {
+	return Purger{o, retention}
+}
+
+// Purge : deletes the events published before the retention and gives how many
+func (p Purger) Purge(ctx context.Context) (int64, error) {
+	return p.outbox.DeletePublished(ctx, int(p.retention/time.Second))
+}
+
+// Run : purges the published events every interval until the context is done
+func (p Purger) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := p.Purge(ctx); err != nil {
+				log.Printf("outbox purge: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package outboxservice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/service"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+// TestFlush : test that a failed event holds back the later events of its entity only
+func TestFlush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOutbox := store.NewMockOutboxStorer(ctrl)
+	mockPublisher := service.NewMockEventPublisher(ctrl)
+
+	events := []entities.Event{
+		{EventID: 1, Type: entities.EventBookCreated, EntityType: "book", EntityID: 3},
+		{EventID: 2, Type: entities.EventAuthorUpdated, EntityType: "author", EntityID: 3},
+		{EventID: 3, Type: entities.EventBookUpdated, EntityType: "book", EntityID: 3},
+		{EventID: 4, Type: entities.EventAuthorDeleted, EntityType: "author", EntityID: 3},
+	}
+
+	var released bool
+
+	mockOutbox.EXPECT().Lock(gomock.Any()).Return(func() { released = true }, true, nil)
+	mockOutbox.EXPECT().GetPending(gomock.Any(), batchSize).Return(events, nil)
+	mockPublisher.EXPECT().Publish(gomock.Any(), events[0]).Return(nil)
+	mockPublisher.EXPECT().Publish(gomock.Any(), events[1]).Return(errors.New("broker down"))
+	mockPublisher.EXPECT().Publish(gomock.Any(), events[2]).Return(nil)
+	mockOutbox.EXPECT().MarkPublished(gomock.Any(), []int{1, 3}).Return(nil)
+
+	published, err := New(mockOutbox, mockPublisher).Flush(context.TODO())
+
+	if published != 2 || !reflect.DeepEqual(err, errors.New("broker down")) || !released {
+		t.Errorf("unexpected flush %v %v %v", published, err, released)
+	}
+}
+
+// TestFlushLocked : test that nothing is published while another relay holds the lock
+func TestFlushLocked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOutbox := store.NewMockOutboxStorer(ctrl)
+
+	mockOutbox.EXPECT().Lock(gomock.Any()).Return(nil, false, nil)
+
+	published, err := New(mockOutbox, service.NewMockEventPublisher(ctrl)).Flush(context.TODO())
+
+	if published != 0 || err != nil {
+		t.Errorf("unexpected flush %v %v", published, err)
+	}
+}
+
+// TestFlushBatches : test that full batches are followed by the next one
+func TestFlushBatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOutbox := store.NewMockOutboxStorer(ctrl)
+	mockPublisher := service.NewMockEventPublisher(ctrl)
+
+	full := make([]entities.Event, batchSize)
+	ids := make([]int, batchSize)
+
+	for i := range full {
+		full[i] = entities.Event{EventID: i + 1, EntityType: "book", EntityID: i + 1}
+		ids[i] = i + 1
+	}
+
+	mockOutbox.EXPECT().Lock(gomock.Any()).Return(func() {}, true, nil)
+	gomock.InOrder(
+		mockOutbox.EXPECT().GetPending(gomock.Any(), batchSize).Return(full, nil),
+		mockOutbox.EXPECT().MarkPublished(gomock.Any(), ids).Return(nil),
+		mockOutbox.EXPECT().GetPending(gomock.Any(), batchSize).Return(nil, nil),
+		mockOutbox.EXPECT().MarkPublished(gomock.Any(), nil).Return(nil),
+	)
+	mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).Times(batchSize)
+
+	published, err := New(mockOutbox, mockPublisher).Flush(context.TODO())
+
+	if published != batchSize || err != nil {
+		t.Errorf("unexpected flush %v %v", published, err)
+	}
+}
+
+// TestPurge : test the retention is given to the store in seconds
+func TestPurge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOutbox := store.NewMockOutboxStorer(ctrl)
+
+	mockOutbox.EXPECT().DeletePublished(gomock.Any(), 7*24*3600).Return(int64(12), nil)
+
+	deleted, err := NewPurger(mockOutbox, 7*24*time.Hour).Purge(context.TODO())
+
+	if deleted != 12 || err != nil {
+		t.Errorf("unexpected purge %v %v", deleted, err)
+	}
+}
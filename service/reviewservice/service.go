@@ -0,0 +1,200 @@
+package reviewservice
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+	"projects/GoLang-Interns-2022/authorbook/middleware/authz"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+const (
+	defaultLimit = 10
+	maxLimit     = 100
+	maxTextLen   = 5000
+)
+
+type ReviewService struct {
+	reviewStore store.ReviewStorer
+	bookStore   store.BookStorer
+	policy      authz.Policy
+}
+
+// New : factory function, the policy decides who may list the reviews which are not approved
+func New(rs store.ReviewStorer, bs store.BookStorer, policy authz.Policy) ReviewService {
+	return ReviewService{rs, bs, policy}
+}
+
+// Post : checks the review before posting, new reviews wait for moderation
+func (s ReviewService) Post(ctx context.Context, bookID int, r entities.Review) (entities.Review, error) {
+	// an authenticated caller can only review under their own name
+	if id, ok := auth.FromContext(ctx); ok {
+		r.Reviewer = id.Subject
+	}
+
+	r.Reviewer = strings.TrimSpace(r.Reviewer)
+	if r.Reviewer == "" || r.Rating < 1 || r.Rating > 5 || len(r.Text) > maxTextLen {
+		return entities.Review{}, errors.New("invalid constraints")
+	}
+
+	if _, err := s.bookStore.GetBookByID(ctx, bookID); err != nil {
+		return entities.Review{}, errors.New("book does not exist")
+	}
+
+	exists, err := s.reviewStore.Exists(ctx, bookID, r.Reviewer)
+	if err != nil {
+		return entities.Review{}, err
+	}
+
+	if exists {
+		return entities.Review{}, errors.New("review already exists")
+	}
+
+	r.BookID = bookID
+	r.Status = entities.ReviewPending
+	r.HelpfulCount = 0
+
+	id, err := s.reviewStore.Post(ctx, r)
+	if err != nil || id <= 0 {
+		return entities.Review{}, errors.New("review already exists")
+	}
+
+	// the reviews move the updatedAt of their book
+	store.InvalidateBooks(ctx, s.bookStore, bookID)
+
+	return s.reviewStore.GetByID(ctx, id)
+}
+
+// GetByBook : gives a page of the reviews of the book, approved reviews by newest first unless asked otherwise;
+// the reviews waiting for moderation or rejected are only listed for moderators
+func (s ReviewService) GetByBook(ctx context.Context, bookID int, filter entities.ReviewFilter) (entities.ReviewPage, error) {
+	if bookID <= 0 {
+		return entities.ReviewPage{}, errors.New("invalid id")
+	}
+
+	if filter.Status == "" {
+		filter.Status = entities.ReviewApproved
+	}
+
+	if filter.Sort == "" {
+		filter.Sort = "date"
+	}
+
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultLimit
+	}
+
+	if !checkStatus(filter.Status) || (filter.Sort != "date" && filter.Sort != "helpful") || filter.Page < 0 ||
+		filter.Limit < 0 || filter.Limit > maxLimit {
+		return entities.ReviewPage{}, errors.New("invalid constraints")
+	}
+
+	if filter.Status != entities.ReviewApproved && s.policy.Check(ctx, "review:moderate") != nil {
+		filter.Status = entities.ReviewApproved
+	}
+
+	total, err := s.reviewStore.Count(ctx, bookID, filter.Status)
+	if err != nil {
+		return entities.ReviewPage{}, err
+	}
+
+	reviews, err := s.reviewStore.GetByBook(ctx, bookID, filter)
+	if err != nil {
+		return entities.ReviewPage{}, err
+	}
+
+	if reviews == nil {
+		reviews = []entities.Review{}
+	}
+
+	return entities.ReviewPage{Reviews: reviews, Page: filter.Page, Limit: filter.Limit, Total: total}, nil
+}
+
+// UpdateStatus : moderates the review
+func (s ReviewService) UpdateStatus(ctx context.Context, id int, status string) (entities.Review, error) {
+	if id <= 0 || !checkStatus(status) {
+		return entities.Review{}, errors.New("invalid constraints")
+	}
+
+	count, err := s.reviewStore.UpdateStatus(ctx, id, status)
+	if err != nil {
+		return entities.Review{}, err
+	}
+
+	review, err := s.reviewStore.GetByID(ctx, id)
+	if err != nil {
+		if count <= 0 {
+			return entities.Review{}, errors.New("review does not exist")
+		}
+
+		return entities.Review{}, err
+	}
+
+	if count > 0 {
+		store.InvalidateBooks(ctx, s.bookStore, review.BookID)
+	}
+
+	return review, nil
+}
+
+// MarkHelpful : records that the caller found the approved review helpful, a caller is counted once
+func (s ReviewService) MarkHelpful(ctx context.Context, id int) (entities.Review, error) {
+	if id <= 0 {
+		return entities.Review{}, errors.New("invalid id")
+	}
+
+	review, err := s.reviewStore.GetByID(ctx, id)
+	if err != nil || review.Status != entities.ReviewApproved {
+		return entities.Review{}, errors.New("review does not exist")
+	}
+
+	counted, err := s.reviewStore.MarkHelpful(ctx, id, auth.Actor(ctx))
+	if err != nil {
+		return entities.Review{}, err
+	}
+
+	if counted > 0 {
+		store.InvalidateBooks(ctx, s.bookStore, review.BookID)
+
+		review.HelpfulCount++
+	}
+
+	return review, nil
+}
+
+// Delete : deletes the review at particular id
+func (s ReviewService) Delete(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid id")
+	}
+
+	review, err := s.reviewStore.GetByID(ctx, id)
+	if err != nil {
+		return errors.New("review does not exist")
+	}
+
+	count, err := s.reviewStore.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if count <= 0 {
+		return errors.New("review does not exist")
+	}
+
+	store.InvalidateBooks(ctx, s.bookStore, review.BookID)
+
+	return nil
+}
+
+// checkStatus : validates the moderation state
+func checkStatus(status string) bool {
+	return status == entities.ReviewPending || status == entities.ReviewApproved || status == entities.ReviewRejected
+}
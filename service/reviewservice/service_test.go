@@ -0,0 +1,204 @@
+package reviewservice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+	"projects/GoLang-Interns-2022/authorbook/middleware/authz"
+	"projects/GoLang-Interns-2022/authorbook/store"
+
+	"github.com/golang/mock/gomock"
+)
+
+// TestPost : test the logic of posting a review
+func TestPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockReviewStore := store.NewMockReviewStorer(ctrl)
+	mockBookStore := store.NewMockBookStorer(ctrl)
+	mock := New(mockReviewStore, mockBookStore, authz.DefaultPolicy())
+
+	testcases := []struct {
+		desc   string
+		bookID int
+		input  entities.Review
+
+		bookErr     error
+		exists      bool
+		expectedErr error
+	}{
+		{desc: "valid review", bookID: 1, input: entities.Review{Reviewer: "asha", Rating: 4, Text: "good"}},
+		{desc: "rating too high", bookID: 1, input: entities.Review{Reviewer: "asha", Rating: 6},
+			expectedErr: errors.New("invalid constraints")},
+		{desc: "missing reviewer", bookID: 1, input: entities.Review{Rating: 3},
+			expectedErr: errors.New("invalid constraints")},
+		{desc: "missing book", bookID: 9, input: entities.Review{Reviewer: "asha", Rating: 4},
+			bookErr: errors.New("no rows"), expectedErr: errors.New("book does not exist")},
+		{desc: "second review", bookID: 2, input: entities.Review{Reviewer: "asha", Rating: 4}, exists: true,
+			expectedErr: errors.New("review already exists")},
+	}
+
+	for _, tc := range testcases {
+		pending := tc.input
+		pending.BookID = tc.bookID
+		pending.Status = entities.ReviewPending
+
+		mockBookStore.EXPECT().GetBookByID(context.TODO(), tc.bookID).Return(entities.Book{}, tc.bookErr).AnyTimes()
+		mockReviewStore.EXPECT().Exists(context.TODO(), tc.bookID, tc.input.Reviewer).Return(tc.exists, nil).AnyTimes()
+		mockReviewStore.EXPECT().Post(context.TODO(), pending).Return(8, nil).AnyTimes()
+		mockReviewStore.EXPECT().GetByID(context.TODO(), 8).Return(pending, nil).AnyTimes()
+
+		review, err := mock.Post(context.TODO(), tc.bookID, tc.input)
+
+		if !reflect.DeepEqual(err, tc.expectedErr) || (err == nil && review.Status != entities.ReviewPending) {
+			t.Errorf("failed for %v, got: %v\n", tc.desc, err)
+		}
+	}
+}
+
+// TestGetByBook : test the defaults and the validation of the review listing, only moderators list the reviews
+// which are not approved
+func TestGetByBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockReviewStore := store.NewMockReviewStorer(ctrl)
+	mock := New(mockReviewStore, store.NewMockBookStorer(ctrl), authz.DefaultPolicy())
+
+	moderator := auth.NewContext(context.TODO(), auth.Identity{Subject: "asha", Roles: []string{"editor"}})
+	reader := auth.NewContext(context.TODO(), auth.Identity{Subject: "ravi", Roles: []string{"reader"}})
+
+	testcases := []struct {
+		desc   string
+		ctx    context.Context
+		filter entities.ReviewFilter
+
+		storeFilter entities.ReviewFilter
+		expected    entities.ReviewPage
+		expectedErr error
+	}{
+		{desc: "defaults", ctx: reader, filter: entities.ReviewFilter{},
+			storeFilter: entities.ReviewFilter{Status: entities.ReviewApproved, Sort: "date", Page: 1, Limit: 10},
+			expected:    entities.ReviewPage{Reviews: []entities.Review{}, Page: 1, Limit: 10, Total: 0}},
+		{desc: "pending by helpfulness", ctx: moderator, filter: entities.ReviewFilter{Status: entities.ReviewPending,
+			Sort: "helpful", Page: 2, Limit: 5}, storeFilter: entities.ReviewFilter{Status: entities.ReviewPending,
+			Sort: "helpful", Page: 2, Limit: 5}, expected: entities.ReviewPage{Reviews: []entities.Review{}, Page: 2,
+			Limit: 5}},
+		{desc: "pending for a reader", ctx: reader, filter: entities.ReviewFilter{Status: entities.ReviewPending},
+			storeFilter: entities.ReviewFilter{Status: entities.ReviewApproved, Sort: "date", Page: 1, Limit: 10},
+			expected:    entities.ReviewPage{Reviews: []entities.Review{}, Page: 1, Limit: 10}},
+		{desc: "rejected without identity", ctx: context.TODO(),
+			filter:      entities.ReviewFilter{Status: entities.ReviewRejected},
+			storeFilter: entities.ReviewFilter{Status: entities.ReviewApproved, Sort: "date", Page: 1, Limit: 10},
+			expected:    entities.ReviewPage{Reviews: []entities.Review{}, Page: 1, Limit: 10}},
+		{desc: "unknown sort", ctx: reader, filter: entities.ReviewFilter{Sort: "stars"},
+			expectedErr: errors.New("invalid constraints")},
+		{desc: "unknown status", ctx: moderator, filter: entities.ReviewFilter{Status: "hidden"},
+			expectedErr: errors.New("invalid constraints")},
+		{desc: "limit too large", ctx: reader, filter: entities.ReviewFilter{Limit: 1000},
+			expectedErr: errors.New("invalid constraints")},
+	}
+
+	for _, tc := range testcases {
+		if tc.expectedErr == nil {
+			mockReviewStore.EXPECT().Count(tc.ctx, 1, tc.storeFilter.Status).Return(0, nil)
+			mockReviewStore.EXPECT().GetByBook(tc.ctx, 1, tc.storeFilter).Return(nil, nil)
+		}
+
+		page, err := mock.GetByBook(tc.ctx, 1, tc.filter)
+
+		if !reflect.DeepEqual(page, tc.expected) || !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
+
+// TestMarkHelpful : test only approved reviews can be marked helpful, once per caller
+func TestMarkHelpful(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockReviewStore := store.NewMockReviewStorer(ctrl)
+	mock := New(mockReviewStore, store.NewMockBookStorer(ctrl), authz.DefaultPolicy())
+
+	ctx := auth.NewContext(context.TODO(), auth.Identity{Subject: "asha"})
+
+	testcases := []struct {
+		desc    string
+		review  entities.Review
+		counted int
+
+		expected    entities.Review
+		expectedErr error
+	}{
+		{desc: "approved review", review: entities.Review{ReviewID: 1, Status: entities.ReviewApproved, HelpfulCount: 2},
+			counted: 1, expected: entities.Review{ReviewID: 1, Status: entities.ReviewApproved, HelpfulCount: 3}},
+		{desc: "repeated vote", review: entities.Review{ReviewID: 1, Status: entities.ReviewApproved, HelpfulCount: 3},
+			expected: entities.Review{ReviewID: 1, Status: entities.ReviewApproved, HelpfulCount: 3}},
+		{desc: "pending review", review: entities.Review{ReviewID: 1, Status: entities.ReviewPending},
+			expectedErr: errors.New("review does not exist")},
+	}
+
+	for _, tc := range testcases {
+		mockReviewStore.EXPECT().GetByID(ctx, 1).Return(tc.review, nil)
+
+		if tc.expectedErr == nil {
+			mockReviewStore.EXPECT().MarkHelpful(ctx, 1, "asha").Return(tc.counted, nil)
+		}
+
+		review, err := mock.MarkHelpful(ctx, 1)
+
+		if !reflect.DeepEqual(review, tc.expected) || !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
+
+// TestPostAuthenticated : test the reviewer is taken from the authenticated caller
+func TestPostAuthenticated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockReviewStore := store.NewMockReviewStorer(ctrl)
+	mockBookStore := store.NewMockBookStorer(ctrl)
+	mock := New(mockReviewStore, mockBookStore, authz.DefaultPolicy())
+
+	ctx := auth.NewContext(context.TODO(), auth.Identity{Subject: "asha"})
+	expected := entities.Review{BookID: 1, Reviewer: "asha", Rating: 4, Status: entities.ReviewPending}
+
+	mockBookStore.EXPECT().GetBookByID(ctx, 1).Return(entities.Book{BookID: 1}, nil)
+	mockReviewStore.EXPECT().Exists(ctx, 1, "asha").Return(false, nil)
+	mockReviewStore.EXPECT().Post(ctx, expected).Return(8, nil)
+	mockReviewStore.EXPECT().GetByID(ctx, 8).Return(expected, nil)
+
+	review, err := mock.Post(ctx, 1, entities.Review{Reviewer: "someone else", Rating: 4})
+	if err != nil || review.Reviewer != "asha" {
+		t.Errorf("expected review by asha, got: %v %v", review, err)
+	}
+}
+
+// cachedBooks : a book store with a cache in front of it
+type cachedBooks struct {
+	*store.MockBookStorer
+	*store.MockBookInvalidator
+}
+
+// TestInvalidate : test the book of a review is dropped from the book cache when the review is moderated or deleted
+func TestInvalidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockReviewStore := store.NewMockReviewStorer(ctrl)
+	mockInvalidator := store.NewMockBookInvalidator(ctrl)
+	mock := New(mockReviewStore, cachedBooks{store.NewMockBookStorer(ctrl), mockInvalidator}, authz.DefaultPolicy())
+
+	review := entities.Review{ReviewID: 1, BookID: 3, Status: entities.ReviewApproved}
+
+	mockReviewStore.EXPECT().UpdateStatus(context.TODO(), 1, entities.ReviewApproved).Return(1, nil)
+	mockReviewStore.EXPECT().GetByID(context.TODO(), 1).Return(review, nil).Times(2)
+	mockReviewStore.EXPECT().Delete(context.TODO(), 1).Return(1, nil)
+	mockInvalidator.EXPECT().Invalidate(context.TODO(), 3).Times(2)
+
+	if _, err := mock.UpdateStatus(context.TODO(), 1, entities.ReviewApproved); err != nil {
+		t.Errorf("failed to moderate: %v", err)
+	}
+
+	if err := mock.Delete(context.TODO(), 1); err != nil {
+		t.Errorf("failed to delete: %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package seriesservice
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+type SeriesService struct {
+	seriesStore store.SeriesStorer
+	bookStore   store.BookStorer
+}
+
+// New : factory function
+func New(ss store.SeriesStorer, bs store.BookStorer) SeriesService {
+	return SeriesService{ss, bs}
+}
+
+// GetByID : gives the series with its books in reading order
+func (s SeriesService) GetByID(ctx context.Context, id int) (entities.Series, error) {
+	if id <= 0 {
+		return entities.Series{}, errors.New("invalid id")
+	}
+
+	series, err := s.seriesStore.GetByID(ctx, id)
+	if err != nil {
+		return entities.Series{}, errors.New("series does not exist")
+	}
+
+	series.Books, err = s.seriesStore.GetBooks(ctx, id)
+	if err != nil {
+		return entities.Series{}, err
+	}
+
+	return series, nil
+}
+
+// Post : checks the series before posting
+func (s SeriesService) Post(ctx context.Context, series entities.Series) (entities.Series, error) {
+	series.Name = strings.TrimSpace(series.Name)
+	if series.Name == "" {
+		return entities.Series{}, errors.New("invalid constraints")
+	}
+
+	id, err := s.seriesStore.Post(ctx, series)
+	if err != nil || id <= 0 {
+		return entities.Series{}, err
+	}
+
+	series.SeriesID = id
+	series.Books = nil
+
+	return series, nil
+}
+
+// Put : checks the series before updating its name and description
+func (s SeriesService) Put(ctx context.Context, series entities.Series, id int) (entities.Series, error) {
+	series.Name = strings.TrimSpace(series.Name)
+	if series.Name == "" {
+		return entities.Series{}, errors.New("invalid constraints")
+	}
+
+	if _, err := s.seriesStore.GetByID(ctx, id); err != nil {
+		return entities.Series{}, errors.New("series does not exist")
+	}
+
+	if _, err := s.seriesStore.Put(ctx, series, id); err != nil {
+		return entities.Series{}, err
+	}
+
+	series, err := s.GetByID(ctx, id)
+	if err != nil {
+		return entities.Series{}, err
+	}
+
+	s.invalidate(ctx, series.Books)
+
+	return series, nil
+}
+
+// Delete : deletes the series at particular id
+func (s SeriesService) Delete(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid id")
+	}
+
+	books, err := s.seriesStore.GetBooks(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	count, err := s.seriesStore.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if count <= 0 {
+		return errors.New("series does not exist")
+	}
+
+	s.invalidate(ctx, books)
+
+	return nil
+}
+
+// AddBook : places an existing book at a free position of the series
+func (s SeriesService) AddBook(ctx context.Context, id int, book entities.SeriesBook) (entities.Series, error) {
+	if book.Position <= 0 {
+		return entities.Series{}, errors.New("invalid position")
+	}
+
+	series, err := s.GetByID(ctx, id)
+	if err != nil {
+		return entities.Series{}, err
+	}
+
+	if _, err = s.bookStore.GetBookByID(ctx, book.BookID); err != nil {
+		return entities.Series{}, errors.New("book does not exist")
+	}
+
+	for i := range series.Books {
+		if series.Books[i].Position == book.Position && series.Books[i].BookID != book.BookID {
+			return entities.Series{}, errors.New("position already taken")
+		}
+	}
+
+	if err = s.seriesStore.AddBook(ctx, id, book); err != nil {
+		return entities.Series{}, err
+	}
+
+	if series, err = s.GetByID(ctx, id); err != nil {
+		return entities.Series{}, err
+	}
+
+	s.invalidate(ctx, series.Books)
+
+	return series, nil
+}
+
+// RemoveBook : takes the book out of the series
+func (s SeriesService) RemoveBook(ctx context.Context, id, bookID int) error {
+	if id <= 0 || bookID <= 0 {
+		return errors.New("invalid id")
+	}
+
+	books, err := s.seriesStore.GetBooks(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	count, err := s.seriesStore.RemoveBook(ctx, id, bookID)
+	if err != nil {
+		return err
+	}
+
+	if count <= 0 {
+		return errors.New("book is not part of the series")
+	}
+
+	s.invalidate(ctx, books)
+
+	return nil
+}
+
+// invalidate : drops the books of the series from the book cache, a change of the series is shown with each of them
+func (s SeriesService) invalidate(ctx context.Context, books []entities.SeriesBook) {
+	ids := make([]int, len(books))
+	for i := range books {
+		ids[i] = books[i].BookID
+	}
+
+	store.InvalidateBooks(ctx, s.bookStore, ids...)
+}
@@ -0,0 +1,150 @@
+package seriesservice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+
+	"github.com/golang/mock/gomock"
+)
+
+// TestGetByID : test the series is returned with its books
+func TestGetByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSeriesStore := store.NewMockSeriesStorer(ctrl)
+	mock := New(mockSeriesStore, store.NewMockBookStorer(ctrl))
+
+	books := []entities.SeriesBook{{BookID: 4, Title: "book one", Position: 1}, {BookID: 9, Title: "novella",
+		Position: 1.5}}
+
+	testcases := []struct {
+		desc     string
+		targetID int
+		storeErr error
+
+		expected    entities.Series
+		expectedErr error
+	}{
+		{desc: "existing series", targetID: 1,
+			expected: entities.Series{SeriesID: 1, Name: "Discworld", Books: books}},
+		{desc: "not existing", targetID: 2, storeErr: errors.New("no rows"),
+			expectedErr: errors.New("series does not exist")},
+		{desc: "invalid id", targetID: 0, expectedErr: errors.New("invalid id")},
+	}
+
+	for _, tc := range testcases {
+		mockSeriesStore.EXPECT().GetByID(context.TODO(), tc.targetID).
+			Return(entities.Series{SeriesID: tc.targetID, Name: "Discworld"}, tc.storeErr).AnyTimes()
+		mockSeriesStore.EXPECT().GetBooks(context.TODO(), tc.targetID).Return(books, nil).AnyTimes()
+
+		series, err := mock.GetByID(context.TODO(), tc.targetID)
+
+		if !reflect.DeepEqual(series, tc.expected) || !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
+
+// TestAddBook : test the logic of placing a book in a series
+func TestAddBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSeriesStore := store.NewMockSeriesStorer(ctrl)
+	mockBookStore := store.NewMockBookStorer(ctrl)
+	mock := New(mockSeriesStore, mockBookStore)
+
+	testcases := []struct {
+		desc string
+		book entities.SeriesBook
+
+		bookErr     error
+		expectedErr error
+	}{
+		{desc: "novella between books", book: entities.SeriesBook{BookID: 9, Position: 1.5}},
+		{desc: "moving a member", book: entities.SeriesBook{BookID: 4, Position: 1}},
+		{desc: "taken position", book: entities.SeriesBook{BookID: 9, Position: 2},
+			expectedErr: errors.New("position already taken")},
+		{desc: "invalid position", book: entities.SeriesBook{BookID: 9, Position: 0},
+			expectedErr: errors.New("invalid position")},
+		{desc: "missing book", book: entities.SeriesBook{BookID: 99, Position: 3}, bookErr: errors.New("no rows"),
+			expectedErr: errors.New("book does not exist")},
+	}
+
+	for _, tc := range testcases {
+		mockSeriesStore.EXPECT().GetByID(context.TODO(), 1).Return(entities.Series{SeriesID: 1}, nil).AnyTimes()
+		mockSeriesStore.EXPECT().GetBooks(context.TODO(), 1).Return([]entities.SeriesBook{{BookID: 4, Position: 1},
+			{BookID: 5, Position: 2}}, nil).AnyTimes()
+		mockBookStore.EXPECT().GetBookByID(context.TODO(), tc.book.BookID).Return(entities.Book{}, tc.bookErr).AnyTimes()
+		mockSeriesStore.EXPECT().AddBook(context.TODO(), 1, tc.book).Return(nil).AnyTimes()
+
+		_, err := mock.AddBook(context.TODO(), 1, tc.book)
+
+		if !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v, got: %v\n", tc.desc, err)
+		}
+	}
+}
+
+// TestRemoveBook : test the logic of taking a book out of a series
+func TestRemoveBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSeriesStore := store.NewMockSeriesStorer(ctrl)
+	mock := New(mockSeriesStore, store.NewMockBookStorer(ctrl))
+
+	testcases := []struct {
+		desc   string
+		bookID int
+		count  int
+
+		expectedErr error
+	}{
+		{desc: "member book", bookID: 4, count: 1},
+		{desc: "not a member", bookID: 7, count: 0, expectedErr: errors.New("book is not part of the series")},
+	}
+
+	for _, tc := range testcases {
+		mockSeriesStore.EXPECT().GetBooks(context.TODO(), 1).Return([]entities.SeriesBook{{BookID: 4, Position: 1}},
+			nil)
+		mockSeriesStore.EXPECT().RemoveBook(context.TODO(), 1, tc.bookID).Return(tc.count, nil)
+
+		err := mock.RemoveBook(context.TODO(), 1, tc.bookID)
+
+		if !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
+
+// cachedBooks : a book store with a cache in front of it
+type cachedBooks struct {
+	*store.MockBookStorer
+	*store.MockBookInvalidator
+}
+
+// TestInvalidate : test the books of a series are dropped from the book cache when the series changes
+func TestInvalidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSeriesStore := store.NewMockSeriesStorer(ctrl)
+	mockInvalidator := store.NewMockBookInvalidator(ctrl)
+	mock := New(mockSeriesStore, cachedBooks{store.NewMockBookStorer(ctrl), mockInvalidator})
+
+	books := []entities.SeriesBook{{BookID: 4, Position: 1}, {BookID: 5, Position: 2}}
+
+	mockSeriesStore.EXPECT().GetByID(context.TODO(), 1).Return(entities.Series{SeriesID: 1, Name: "Dune"}, nil).
+		Times(2)
+	mockSeriesStore.EXPECT().GetBooks(context.TODO(), 1).Return(books, nil).Times(2)
+	mockSeriesStore.EXPECT().Put(context.TODO(), entities.Series{Name: "Dune"}, 1).Return(1, nil)
+	mockSeriesStore.EXPECT().Delete(context.TODO(), 1).Return(1, nil)
+	mockInvalidator.EXPECT().Invalidate(context.TODO(), 4, 5).Times(2)
+
+	if _, err := mock.Put(context.TODO(), entities.Series{Name: "Dune"}, 1); err != nil {
+		t.Errorf("failed to update: %v", err)
+	}
+
+	if err := mock.Delete(context.TODO(), 1); err != nil {
+		t.Errorf("failed to delete: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package tagservice
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+type TagService struct {
+	datastore store.TagStorer
+	bookStore store.BookStorer
+}
+
+// New : factory function
+func New(s store.TagStorer, bs store.BookStorer) TagService {
+	return TagService{s, bs}
+}
+
+// GetAll : gives every tag
+func (s TagService) GetAll(ctx context.Context) ([]entities.Tag, error) {
+	return s.datastore.GetAll(ctx)
+}
+
+// Post : normalises the tag name before posting
+func (s TagService) Post(ctx context.Context, t entities.Tag) (entities.Tag, error) {
+	t.Name = NormalizeName(t.Name)
+	if t.Name == "" {
+		return entities.Tag{}, errors.New("invalid constraints")
+	}
+
+	id, err := s.datastore.Post(ctx, t)
+	if err != nil || id <= 0 {
+		return entities.Tag{}, errors.New("tag already exists")
+	}
+
+	t.TagID = id
+
+	return t, nil
+}
+
+// Delete : deletes the tag at particular id
+func (s TagService) Delete(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid id")
+	}
+
+	books, err := s.datastore.GetBookIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	count, err := s.datastore.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if count <= 0 {
+		return errors.New("tag does not exist")
+	}
+
+	// the tags are shown with their books
+	store.InvalidateBooks(ctx, s.bookStore, books...)
+
+	return nil
+}
+
+// NormalizeName : tags are free-form, so they are compared trimmed and in lower case
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
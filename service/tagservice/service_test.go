@@ -0,0 +1,71 @@
+package tagservice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+
+	"github.com/golang/mock/gomock"
+)
+
+// TestPost : test the tag name is normalised before posting
+func TestPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockTagStorer(ctrl)
+	mock := New(mockStore, store.NewMockBookStorer(ctrl))
+
+	testcases := []struct {
+		desc  string
+		input entities.Tag
+
+		expected    entities.Tag
+		expectedErr error
+	}{
+		{desc: "valid tag", input: entities.Tag{Name: " Dragons "}, expected: entities.Tag{TagID: 3, Name: "dragons"}},
+		{desc: "empty tag", input: entities.Tag{Name: "  "}, expectedErr: errors.New("invalid constraints")},
+	}
+
+	for _, tc := range testcases {
+		mockStore.EXPECT().Post(context.TODO(), entities.Tag{Name: "dragons"}).Return(3, nil).AnyTimes()
+
+		tag, err := mock.Post(context.TODO(), tc.input)
+
+		if !reflect.DeepEqual(tag, tc.expected) || !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
+
+// TestDelete : test the logic of deleting a tag
+func TestDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockTagStorer(ctrl)
+	mock := New(mockStore, store.NewMockBookStorer(ctrl))
+
+	testcases := []struct {
+		desc   string
+		target int
+		count  int
+
+		expectedErr error
+	}{
+		{desc: "existing tag", target: 2, count: 1},
+		{desc: "not existing", target: 9, count: 0, expectedErr: errors.New("tag does not exist")},
+		{desc: "invalid id", target: 0, expectedErr: errors.New("invalid id")},
+	}
+
+	for _, tc := range testcases {
+		mockStore.EXPECT().GetBookIDs(context.TODO(), tc.target).Return([]int{4}, nil).AnyTimes()
+		mockStore.EXPECT().Delete(context.TODO(), tc.target).Return(tc.count, nil).AnyTimes()
+
+		err := mock.Delete(context.TODO(), tc.target)
+
+		if !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package webhookservice
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+const (
+	// dueBatch : the due deliveries read at a time and workers : the deliveries sent at once
+	dueBatch = 100
+	workers  = 8
+
+	// maxAttempts : the attempts before a delivery is dead
+	maxAttempts = 10
+	// firstRetry and maxRetry : the backoff doubles from the first retry up to the max
+	firstRetry = 30 * time.Second
+	maxRetry   = 6 * time.Hour
+	// lease : how long a claimed delivery is left to its dispatcher, longer than a request may take
+	lease = time.Minute
+
+	maxErrorLength = 255
+)
+
+// SignatureHeader : the header carrying the signature of a delivery, t is the unix time of the attempt and v1 the
+// hex HMAC-SHA256 of "t.body" with the secret of the webhook
+const SignatureHeader = "X-Authorbook-Signature"
+
+// Dispatcher : posts the due deliveries to their webhooks and schedules the failed ones again
+type Dispatcher struct {
+	webhookStore store.WebhookStorer
+	client       *http.Client
+	now          func() time.Time
+}
+
+// NewDispatcher : factory function, the client bounds how long a webhook may take to answer
+func NewDispatcher(s store.WebhookStorer, client *http.Client) Dispatcher {
+	return Dispatcher{s, client, time.Now}
+}
+
+// Run : dispatches the due deliveries every interval until the context is done
+func (d Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.Dispatch(ctx); err != nil {
+			log.Printf("webhook dispatcher: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch : sends a batch of due deliveries and gives how many were delivered, a delivery another dispatcher
+// claimed first is left to it
+func (d Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	due, err := d.webhookStore.GetDue(ctx, dueBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		delivered int
+	)
+
+	webhooks := map[int]entities.Webhook{}
+	slots := make(chan struct{}, workers)
+
+	for _, delivery := range due {
+		w, ok := webhooks[delivery.WebhookID]
+		if !ok {
+			if w, err = d.webhookStore.GetByID(ctx, delivery.WebhookID); err != nil {
+				// deleted since, its deliveries go with it
+				log.Print(err)
+				continue
+			}
+
+			webhooks[delivery.WebhookID] = w
+		}
+
+		claimed, err := d.webhookStore.Claim(ctx, delivery, int(lease/time.Second))
+		if err != nil {
+			return delivered, err
+		}
+
+		if !claimed {
+			continue
+		}
+
+		slots <- struct{}{}
+
+		wg.Add(1)
+
+		go func(w entities.Webhook, delivery entities.Delivery) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			if d.attempt(ctx, w, delivery) {
+				mu.Lock()
+				delivered++
+				mu.Unlock()
+			}
+		}(w, delivery)
+	}
+
+	wg.Wait()
+
+	return delivered, nil
+}
+
+// attempt : posts the delivery and records the outcome, true when the webhook took it with a 2xx status
+func (d Dispatcher) attempt(ctx context.Context, w entities.Webhook, delivery entities.Delivery) bool {
+	delivery.Attempts++
+	delivery.ResponseStatus, delivery.LastError = d.post(ctx, w, delivery)
+
+	retryIn := time.Duration(0)
+
+	switch {
+	case delivery.LastError == "":
+		delivery.Status = entities.DeliveryDelivered
+	case delivery.Attempts >= maxAttempts:
+		delivery.Status = entities.DeliveryDead
+	default:
+		delivery.Status = entities.DeliveryRetrying
+		retryIn = Backoff(delivery.Attempts)
+	}
+
+	if err := d.webhookStore.Record(ctx, delivery, int(retryIn/time.Second)); err != nil {
+		log.Print(err)
+	}
+
+	return delivery.Status == entities.DeliveryDelivered
+}
+
+// post : sends the signed payload, gives the response status and the reason of a failure
+func (d Dispatcher) post(ctx context.Context, w entities.Webhook, delivery entities.Delivery) (int, string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, truncate(err.Error())
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "authorbook-webhooks")
+	req.Header.Set("X-Authorbook-Event", delivery.EventType)
+	req.Header.Set("X-Authorbook-Delivery", strconv.Itoa(delivery.DeliveryID))
+	req.Header.Set(SignatureHeader, Sign(w.Secret, d.now().Unix(), delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, truncate(err.Error())
+	}
+	defer resp.Body.Close()
+
+	// the body is drained so the connection can be reused, it is not kept
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, resp.Status
+	}
+
+	return resp.StatusCode, ""
+}
+
+// Sign : the signature header value of a payload sent at the unix time
+func Sign(secret string, timestamp int64, payload []byte) string {
+	t := strconv.FormatInt(timestamp, 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t + "."))
+	mac.Write(payload)
+
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff : the wait before the next attempt after the given number of attempts
+func Backoff(attempts int) time.Duration {
+	wait := firstRetry
+
+	for i := 1; i < attempts && wait < maxRetry; i++ {
+		wait *= 2
+	}
+
+	if wait > maxRetry {
+		return maxRetry
+	}
+
+	return wait
+}
+
+// truncate : keeps an error within the column of the delivery log
+func truncate(s string) string {
+	if len(s) > maxErrorLength {
+		return s[:maxErrorLength]
+	}
+
+	return s
+}
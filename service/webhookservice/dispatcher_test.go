@@ -0,0 +1,123 @@
+package webhookservice
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+// TestDispatch : test that deliveries are signed, failures are retried with backoff and the last attempt is dead
+func TestDispatch(t *testing.T) {
+	payload := json.RawMessage(`{"eventID":21}`)
+	now := time.Unix(1660000000, 0)
+
+	var (
+		mu         sync.Mutex
+		signatures []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != string(payload) {
+			t.Errorf("unexpected body %s", body)
+		}
+
+		mu.Lock()
+		signatures = append(signatures, r.Header.Get(SignatureHeader))
+		mu.Unlock()
+
+		if r.Header.Get("X-Authorbook-Delivery") != "7" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockWebhookStorer(ctrl)
+
+	due := []entities.Delivery{
+		{DeliveryID: 7, WebhookID: 3, EventID: 21, Payload: payload, Status: entities.DeliveryPending},
+		{DeliveryID: 8, WebhookID: 3, EventID: 21, Payload: payload, Status: entities.DeliveryRetrying, Attempts: 2},
+		{DeliveryID: 9, WebhookID: 3, EventID: 21, Payload: payload, Status: entities.DeliveryRetrying,
+			Attempts: maxAttempts - 1},
+		{DeliveryID: 10, WebhookID: 3, EventID: 21, Payload: payload, Status: entities.DeliveryPending},
+	}
+
+	mockStore.EXPECT().GetDue(gomock.Any(), dueBatch).Return(due, nil)
+	mockStore.EXPECT().GetByID(gomock.Any(), 3).Return(entities.Webhook{WebhookID: 3, URL: server.URL,
+		Secret: "whsec_0123456789abcdef"}, nil)
+
+	for _, d := range due[:3] {
+		mockStore.EXPECT().Claim(gomock.Any(), d, 60).Return(true, nil)
+	}
+
+	// claimed by another dispatcher in between
+	mockStore.EXPECT().Claim(gomock.Any(), due[3], 60).Return(false, nil)
+
+	mockStore.EXPECT().Record(gomock.Any(), entities.Delivery{DeliveryID: 7, WebhookID: 3, EventID: 21,
+		Payload: payload, Status: entities.DeliveryDelivered, Attempts: 1, ResponseStatus: 200}, 0).Return(nil)
+	mockStore.EXPECT().Record(gomock.Any(), entities.Delivery{DeliveryID: 8, WebhookID: 3, EventID: 21,
+		Payload: payload, Status: entities.DeliveryRetrying, Attempts: 3, ResponseStatus: 503,
+		LastError: "503 Service Unavailable"}, 120).Return(nil)
+	mockStore.EXPECT().Record(gomock.Any(), entities.Delivery{DeliveryID: 9, WebhookID: 3, EventID: 21,
+		Payload: payload, Status: entities.DeliveryDead, Attempts: maxAttempts, ResponseStatus: 503,
+		LastError: "503 Service Unavailable"}, 0).Return(nil)
+
+	d := NewDispatcher(mockStore, server.Client())
+	d.now = func() time.Time { return now }
+
+	delivered, err := d.Dispatch(context.TODO())
+	if delivered != 1 || err != nil {
+		t.Errorf("unexpected dispatch %v %v", delivered, err)
+	}
+
+	expected := Sign("whsec_0123456789abcdef", now.Unix(), payload)
+	if len(signatures) != 3 {
+		t.Errorf("expected 3 attempts, got: %v", len(signatures))
+	}
+
+	for _, s := range signatures {
+		if s != expected {
+			t.Errorf("unexpected signature %v", s)
+		}
+	}
+}
+
+// TestSign : test the signature against a known HMAC-SHA256
+func TestSign(t *testing.T) {
+	got := Sign("secret", 1660000000, []byte(`{}`))
+	expected := "t=1660000000,v1=adec1f5fdfc32678ba7182519dd3588c5c2f59bc8683b5d276162806740ca6e4"
+
+	if got != expected {
+		t.Errorf("unexpected signature %v", got)
+	}
+}
+
+// TestBackoff : test that the wait doubles up to the cap
+func TestBackoff(t *testing.T) {
+	testcases := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{20, maxRetry},
+	}
+
+	for _, tc := range testcases {
+		if got := Backoff(tc.attempts); got != tc.expected {
+			t.Errorf("failed for %v attempts, got: %v", tc.attempts, got)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package webhookservice
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+const (
+	secretPrefix   = "whsec_"
+	minSecret      = 16
+	defaultLimit   = 50
+	maxLimit       = 500
+	maxEventTypes  = 6
+	maxURLLength   = 2048
+	generatedBytes = 32
+)
+
+// eventTypes : the event types a webhook can subscribe to
+var eventTypes = map[string]bool{
+	entities.EventAuthorCreated: true,
+	entities.EventAuthorUpdated: true,
+	entities.EventAuthorDeleted: true,
+	entities.EventBookCreated:   true,
+	entities.EventBookUpdated:   true,
+	entities.EventBookDeleted:   true,
+}
+
+type WebhookService struct {
+	webhookStore store.WebhookStorer
+}
+
+// New : factory function
+func New(s store.WebhookStorer) WebhookService {
+	return WebhookService{s}
+}
+
+// GetAll : gives every webhook without its secret
+func (s WebhookService) GetAll(ctx context.Context) ([]entities.Webhook, error) {
+	webhooks, err := s.webhookStore.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhooks == nil {
+		webhooks = []entities.Webhook{}
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+// GetByID : gives the webhook with particular id without its secret
+func (s WebhookService) GetByID(ctx context.Context, id int) (entities.Webhook, error) {
+	if id <= 0 {
+		return entities.Webhook{}, errors.New("invalid id")
+	}
+
+	w, err := s.webhookStore.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.Webhook{}, errors.New("webhook does not exist")
+	}
+
+	if err != nil {
+		return entities.Webhook{}, err
+	}
+
+	w.Secret = ""
+
+	return w, nil
+}
+
+// Post : subscribes a url to events, a secret is generated when none is given and it is only part of this response
+func (s WebhookService) Post(ctx context.Context, w entities.Webhook) (entities.Webhook, error) {
+	if !checkWebhook(w) {
+		return entities.Webhook{}, errors.New("invalid constraints")
+	}
+
+	if w.Secret == "" {
+		secret, err := random(generatedBytes)
+		if err != nil {
+			return entities.Webhook{}, err
+		}
+
+		w.Secret = secretPrefix + secret
+	}
+
+	w.Actor = auth.Actor(ctx)
+
+	id, err := s.webhookStore.Post(ctx, w)
+	if err != nil || id <= 0 {
+		return entities.Webhook{}, errors.New("webhook could not be created")
+	}
+
+	created, err := s.GetByID(ctx, id)
+	if err != nil {
+		return entities.Webhook{}, err
+	}
+
+	created.Secret = w.Secret
+
+	return created, nil
+}
+
+// Put : changes the url and the subscribed events of a webhook, the secret is rotated when a new one is given
+func (s WebhookService) Put(ctx context.Context, w entities.Webhook, id int) (entities.Webhook, error) {
+	if id <= 0 {
+		return entities.Webhook{}, errors.New("invalid id")
+	}
+
+	if !checkWebhook(w) {
+		return entities.Webhook{}, errors.New("invalid constraints")
+	}
+
+	// the row count is 0 for a missing webhook and for one that did not change, so it is read to tell them apart
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return entities.Webhook{}, err
+	}
+
+	if _, err := s.webhookStore.Put(ctx, w, id); err != nil {
+		return entities.Webhook{}, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// Delete : deletes a webhook and its delivery log
+func (s WebhookService) Delete(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid id")
+	}
+
+	ra, err := s.webhookStore.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if ra <= 0 {
+		return errors.New("webhook does not exist")
+	}
+
+	return nil
+}
+
+// GetDeliveries : gives the delivery log of a webhook, newest first
+func (s WebhookService) GetDeliveries(ctx context.Context, id int, filter entities.DeliveryFilter) (
+	[]entities.Delivery, error) {
+	switch filter.Status {
+	case "", entities.DeliveryPending, entities.DeliveryRetrying, entities.DeliveryDelivered, entities.DeliveryDead:
+	default:
+		return nil, errors.New("invalid status")
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultLimit
+	}
+
+	if filter.Limit > maxLimit {
+		return nil, errors.New("invalid limit")
+	}
+
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.webhookStore.GetDeliveries(ctx, id, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if deliveries == nil {
+		deliveries = []entities.Delivery{}
+	}
+
+	return deliveries, nil
+}
+
+// Redeliver : sends a delivery of the webhook again with fresh retries, whatever became of it before
+func (s WebhookService) Redeliver(ctx context.Context, id, deliveryID int) (entities.Delivery, error) {
+	if id <= 0 || deliveryID <= 0 {
+		return entities.Delivery{}, errors.New("invalid id")
+	}
+
+	ra, err := s.webhookStore.Redeliver(ctx, id, deliveryID)
+	if err != nil {
+		return entities.Delivery{}, err
+	}
+
+	if ra <= 0 {
+		return entities.Delivery{}, errors.New("delivery does not exist")
+	}
+
+	return s.webhookStore.GetDelivery(ctx, id, deliveryID)
+}
+
+// Enqueue : queues a delivery of the event for every webhook subscribed to it, it subscribes to the published
+// events and an error makes the relay publish the event again
+func (s WebhookService) Enqueue(ctx context.Context, event entities.Event) error {
+	webhooks, err := s.webhookStore.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+
+	for _, w := range webhooks {
+		if !w.Matches(event) {
+			continue
+		}
+
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				return err
+			}
+		}
+
+		err = s.webhookStore.PostDelivery(ctx, entities.Delivery{WebhookID: w.WebhookID, EventID: event.EventID,
+			EventType: event.Type, Payload: payload})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkWebhook : validates the url, the event types, the entities and the secret of a webhook
+func checkWebhook(w entities.Webhook) bool {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || len(w.URL) > maxURLLength {
+		return false
+	}
+
+	if len(w.EventTypes) == 0 || len(w.EventTypes) > maxEventTypes {
+		return false
+	}
+
+	for _, t := range w.EventTypes {
+		if !eventTypes[t] {
+			return false
+		}
+	}
+
+	for _, id := range w.EntityIDs {
+		if id <= 0 {
+			return false
+		}
+	}
+
+	return w.Secret == "" || len(w.Secret) >= minSecret
+}
+
+// random : n bytes from the system's secure random source, URL safe encoded
+func random(n int) (string, error) {
+	b := make([]byte, n)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,123 @@
+package webhookservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/middleware/auth"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+// TestPost : test that a secret is generated and only given back on create
+func TestPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockWebhookStorer(ctrl)
+	s := New(mockStore)
+
+	ctx := auth.NewContext(context.TODO(), auth.Identity{Subject: "asha"})
+
+	var stored entities.Webhook
+
+	mockStore.EXPECT().Post(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, w entities.Webhook) (int, error) {
+		stored = w
+		return 3, nil
+	})
+	mockStore.EXPECT().GetByID(ctx, 3).DoAndReturn(func(_ context.Context, id int) (entities.Webhook, error) {
+		w := stored
+		w.WebhookID = id
+
+		return w, nil
+	})
+
+	w, err := s.Post(ctx, entities.Webhook{URL: "https://partner.example/hook",
+		EventTypes: []string{entities.EventBookUpdated}})
+
+	if err != nil || w.WebhookID != 3 || !strings.HasPrefix(w.Secret, secretPrefix) || w.Secret != stored.Secret ||
+		stored.Actor != "asha" {
+		t.Errorf("unexpected webhook %+v %v", w, err)
+	}
+
+	mockStore.EXPECT().GetAll(ctx).Return([]entities.Webhook{stored}, nil)
+
+	if webhooks, _ := s.GetAll(ctx); len(webhooks) != 1 || webhooks[0].Secret != "" {
+		t.Errorf("expected the secret to be hidden, got: %+v", webhooks)
+	}
+}
+
+// TestPostInvalid : test the checks of the url, events and secret
+func TestPostInvalid(t *testing.T) {
+	testcases := []struct {
+		desc    string
+		webhook entities.Webhook
+	}{
+		{desc: "no scheme", webhook: entities.Webhook{URL: "partner.example/hook", EventTypes: []string{"BookUpdated"}}},
+		{desc: "ftp", webhook: entities.Webhook{URL: "ftp://partner.example", EventTypes: []string{"BookUpdated"}}},
+		{desc: "no events", webhook: entities.Webhook{URL: "https://partner.example"}},
+		{desc: "unknown event", webhook: entities.Webhook{URL: "https://partner.example",
+			EventTypes: []string{"BookRead"}}},
+		{desc: "short secret", webhook: entities.Webhook{URL: "https://partner.example",
+			EventTypes: []string{"BookUpdated"}, Secret: "abc"}},
+		{desc: "invalid entity", webhook: entities.Webhook{URL: "https://partner.example",
+			EventTypes: []string{"BookUpdated"}, EntityIDs: []int{0}}},
+	}
+
+	s := New(store.NewMockWebhookStorer(gomock.NewController(t)))
+
+	for _, tc := range testcases {
+		if _, err := s.Post(context.TODO(), tc.webhook); !reflect.DeepEqual(err, errors.New("invalid constraints")) {
+			t.Errorf("failed for %v, got: %v", tc.desc, err)
+		}
+	}
+}
+
+// TestEnqueue : test that only the webhooks subscribed to the event and its entity get a delivery
+func TestEnqueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockWebhookStorer(ctrl)
+
+	event := entities.Event{EventID: 21, Type: entities.EventBookDeleted, EntityType: "book", EntityID: 4}
+	payload, _ := json.Marshal(event)
+
+	mockStore.EXPECT().GetAll(gomock.Any()).Return([]entities.Webhook{
+		{WebhookID: 1, EventTypes: []string{entities.EventBookDeleted}},
+		{WebhookID: 2, EventTypes: []string{entities.EventBookUpdated}},
+		{WebhookID: 3, EventTypes: []string{entities.EventBookDeleted}, EntityIDs: []int{9}},
+		{WebhookID: 4, EventTypes: []string{entities.EventBookUpdated, entities.EventBookDeleted},
+			EntityIDs: []int{4, 9}},
+	}, nil)
+	mockStore.EXPECT().PostDelivery(gomock.Any(), entities.Delivery{WebhookID: 1, EventID: 21,
+		EventType: entities.EventBookDeleted, Payload: payload}).Return(nil)
+	mockStore.EXPECT().PostDelivery(gomock.Any(), entities.Delivery{WebhookID: 4, EventID: 21,
+		EventType: entities.EventBookDeleted, Payload: payload}).Return(nil)
+
+	if err := New(mockStore).Enqueue(context.TODO(), event); err != nil {
+		t.Errorf("failed to enqueue: %v", err)
+	}
+}
+
+// TestRedeliver : test that a redelivery of another webhook's delivery is not found
+func TestRedeliver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockWebhookStorer(ctrl)
+	s := New(mockStore)
+
+	mockStore.EXPECT().Redeliver(gomock.Any(), 3, 7).Return(1, nil)
+	mockStore.EXPECT().GetDelivery(gomock.Any(), 3, 7).Return(entities.Delivery{DeliveryID: 7, WebhookID: 3,
+		Status: entities.DeliveryPending}, nil)
+	mockStore.EXPECT().Redeliver(gomock.Any(), 4, 7).Return(0, nil)
+
+	if d, err := s.Redeliver(context.TODO(), 3, 7); err != nil || d.Status != entities.DeliveryPending {
+		t.Errorf("unexpected redelivery %+v %v", d, err)
+	}
+
+	if _, err := s.Redeliver(context.TODO(), 4, 7); !reflect.DeepEqual(err, errors.New("delivery does not exist")) {
+		t.Errorf("expected a missing delivery, got: %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package workservice
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+type WorkService struct {
+	workStore   store.WorkStorer
+	authorStore store.AuthorStorer
+	bookStore   store.BookStorer
+}
+
+// New : factory function
+func New(ws store.WorkStorer, as store.AuthorStorer, bs store.BookStorer) WorkService {
+	return WorkService{ws, as, bs}
+}
+
+// GetByID : gives the work with all of its editions
+func (s WorkService) GetByID(ctx context.Context, id int) (entities.Work, error) {
+	if id <= 0 {
+		return entities.Work{}, errors.New("invalid id")
+	}
+
+	work, err := s.workStore.GetByID(ctx, id)
+	if err != nil {
+		return entities.Work{}, errors.New("work does not exist")
+	}
+
+	work.Editions, err = s.bookStore.GetEditionsByWork(ctx, id)
+	if err != nil {
+		return entities.Work{}, err
+	}
+
+	return work, nil
+}
+
+// Post : checks the work and its author before posting
+func (s WorkService) Post(ctx context.Context, work entities.Work) (entities.Work, error) {
+	if err := s.check(ctx, &work); err != nil {
+		return entities.Work{}, err
+	}
+
+	id, err := s.workStore.Post(ctx, work)
+	if err != nil || id <= 0 {
+		return entities.Work{}, err
+	}
+
+	work.WorkID = id
+	work.Editions = nil
+
+	return work, nil
+}
+
+// Put : checks the work before updating
+func (s WorkService) Put(ctx context.Context, work entities.Work, id int) (entities.Work, error) {
+	if err := s.check(ctx, &work); err != nil {
+		return entities.Work{}, err
+	}
+
+	if _, err := s.workStore.GetByID(ctx, id); err != nil {
+		return entities.Work{}, errors.New("work does not exist")
+	}
+
+	if _, err := s.workStore.Put(ctx, work, id); err != nil {
+		return entities.Work{}, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// Delete : deletes a work which no longer has editions
+func (s WorkService) Delete(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid id")
+	}
+
+	editions, err := s.bookStore.GetEditionsByWork(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if len(editions) > 0 {
+		return errors.New("work has editions")
+	}
+
+	count, err := s.workStore.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if count <= 0 {
+		return errors.New("work does not exist")
+	}
+
+	return nil
+}
+
+// check : validates the work and the existence of its author
+func (s WorkService) check(ctx context.Context, work *entities.Work) error {
+	work.Title = strings.TrimSpace(work.Title)
+	if work.Title == "" || work.AuthorID <= 0 {
+		return errors.New("invalid constraints")
+	}
+
+	if _, err := s.authorStore.IncludeAuthor(ctx, work.AuthorID); err != nil {
+		return errors.New("author does not exist")
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package workservice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+
+	"github.com/golang/mock/gomock"
+)
+
+// TestGetByID : test the work is returned with its editions
+func TestGetByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockWorkStore := store.NewMockWorkStorer(ctrl)
+	mockBookStore := store.NewMockBookStorer(ctrl)
+	mock := New(mockWorkStore, store.NewMockAuthorStorer(ctrl), mockBookStore)
+
+	editions := []entities.Edition{{BookID: 4, WorkID: 1, ISBN: "9780306406157", Format: "hardcover"},
+		{BookID: 5, WorkID: 1, ISBN: "0306406152", Format: "paperback"}}
+
+	mockWorkStore.EXPECT().GetByID(context.TODO(), 1).Return(entities.Work{WorkID: 1, AuthorID: 3, Title: "t"}, nil)
+	mockBookStore.EXPECT().GetEditionsByWork(context.TODO(), 1).Return(editions, nil)
+
+	expected := entities.Work{WorkID: 1, AuthorID: 3, Title: "t", Editions: editions}
+
+	work, err := mock.GetByID(context.TODO(), 1)
+	if err != nil || !reflect.DeepEqual(work, expected) {
+		t.Errorf("expected: %v, got: %v", expected, work)
+	}
+}
+
+// TestPost : test the logic of posting a work
+func TestPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockWorkStore := store.NewMockWorkStorer(ctrl)
+	mockAuthorStore := store.NewMockAuthorStorer(ctrl)
+	mock := New(mockWorkStore, mockAuthorStore, store.NewMockBookStorer(ctrl))
+
+	testcases := []struct {
+		desc  string
+		input entities.Work
+
+		authorErr   error
+		expected    entities.Work
+		expectedErr error
+	}{
+		{desc: "valid work", input: entities.Work{AuthorID: 1, Title: "deciding decade"},
+			expected: entities.Work{WorkID: 6, AuthorID: 1, Title: "deciding decade"}},
+		{desc: "missing title", input: entities.Work{AuthorID: 1}, expectedErr: errors.New("invalid constraints")},
+		{desc: "missing author", input: entities.Work{AuthorID: 2, Title: "deciding decade"},
+			authorErr: errors.New("no rows"), expectedErr: errors.New("author does not exist")},
+	}
+
+	for _, tc := range testcases {
+		mockAuthorStore.EXPECT().IncludeAuthor(context.TODO(), tc.input.AuthorID).
+			Return(entities.Author{AuthorID: tc.input.AuthorID}, tc.authorErr).AnyTimes()
+		mockWorkStore.EXPECT().Post(context.TODO(), tc.input).Return(6, nil).AnyTimes()
+
+		work, err := mock.Post(context.TODO(), tc.input)
+
+		if !reflect.DeepEqual(work, tc.expected) || !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
+
+// TestDelete : test a work with editions is not deleted
+func TestDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockWorkStore := store.NewMockWorkStorer(ctrl)
+	mockBookStore := store.NewMockBookStorer(ctrl)
+	mock := New(mockWorkStore, store.NewMockAuthorStorer(ctrl), mockBookStore)
+
+	testcases := []struct {
+		desc     string
+		target   int
+		editions []entities.Edition
+		count    int
+
+		expectedErr error
+	}{
+		{desc: "work without editions", target: 1, count: 1},
+		{desc: "work with editions", target: 2, editions: []entities.Edition{{BookID: 4, WorkID: 2}},
+			expectedErr: errors.New("work has editions")},
+		{desc: "not existing", target: 3, expectedErr: errors.New("work does not exist")},
+	}
+
+	for _, tc := range testcases {
+		mockBookStore.EXPECT().GetEditionsByWork(context.TODO(), tc.target).Return(tc.editions, nil)
+		mockWorkStore.EXPECT().Delete(context.TODO(), tc.target).Return(tc.count, nil).AnyTimes()
+
+		err := mock.Delete(context.TODO(), tc.target)
+
+		if !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v\n", tc.desc)
+		}
+	}
+}
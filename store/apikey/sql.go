@@ -0,0 +1,142 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+type Store struct {
+	DB *sql.DB
+}
+
+// New : factory function
+func New(db *sql.DB) Store {
+	return Store{db}
+}
+
+const columns = "api_key_id,name,prefix,role,scopes,salt,hash,created_at,last_used_at,revoked_at"
+
+// Post : inserts an API key
+func (s Store) Post(ctx context.Context, key entities.APIKey) (int, error) {
+	res, err := s.DB.ExecContext(ctx, "insert into api_key(name,prefix,role,scopes,salt,hash)values(?,?,?,?,?,?)",
+		key.Name, key.Prefix, key.Role, strings.Join(key.Scopes, ","), key.Salt, key.Hash)
+	if err != nil {
+		log.Print(err)
+		return -1, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(id), nil
+}
+
+// GetAll : gives every API key, revoked ones included
+func (s Store) GetAll(ctx context.Context) ([]entities.APIKey, error) {
+	var keys []entities.APIKey
+
+	rows, err := s.DB.QueryContext(ctx, "select "+columns+" from api_key order by api_key_id")
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		key, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// GetByID : gives the API key with particular id
+func (s Store) GetByID(ctx context.Context, id int) (entities.APIKey, error) {
+	return scan(s.DB.QueryRowContext(ctx, "select "+columns+" from api_key where api_key_id=?", id))
+}
+
+// GetByPrefix : gives the API key with particular prefix
+func (s Store) GetByPrefix(ctx context.Context, prefix string) (entities.APIKey, error) {
+	return scan(s.DB.QueryRowContext(ctx, "select "+columns+" from api_key where prefix=?", prefix))
+}
+
+// Put : updates the name, role and scopes of an API key which is not revoked
+func (s Store) Put(ctx context.Context, key entities.APIKey, id int) (int, error) {
+	res, err := s.DB.ExecContext(ctx, "update api_key set name=?,role=?,scopes=? where api_key_id=? and revoked_at is null",
+		key.Name, key.Role, strings.Join(key.Scopes, ","), id)
+	if err != nil {
+		log.Print(err)
+		return -1, err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(ra), nil
+}
+
+// Revoke : marks the API key as revoked, the row is kept for the record
+func (s Store) Revoke(ctx context.Context, id int) (int, error) {
+	res, err := s.DB.ExecContext(ctx, "update api_key set revoked_at=now() where api_key_id=? and revoked_at is null", id)
+	if err != nil {
+		return -1, err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(ra), nil
+}
+
+// TouchLastUsed : records the use of the API key, at most once a minute to spare writes
+func (s Store) TouchLastUsed(ctx context.Context, id int) error {
+	_, err := s.DB.ExecContext(ctx, "update api_key set last_used_at=now() where api_key_id=? and "+
+		"(last_used_at is null or last_used_at<now()-interval 1 minute)", id)
+	if err != nil {
+		log.Print(err)
+	}
+
+	return err
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scan : reads an API key row
+func scan(row scanner) (entities.APIKey, error) {
+	var (
+		key                 entities.APIKey
+		scopes              string
+		lastUsed, revokedAt sql.NullString
+	)
+
+	err := row.Scan(&key.APIKeyID, &key.Name, &key.Prefix, &key.Role, &scopes, &key.Salt, &key.Hash, &key.CreatedAt,
+		&lastUsed, &revokedAt)
+	if err != nil {
+		return entities.APIKey{}, err
+	}
+
+	if scopes != "" {
+		key.Scopes = strings.Split(scopes, ",")
+	}
+
+	key.LastUsedAt = lastUsed.String
+	key.RevokedAt = revokedAt.String
+
+	return key, nil
+}
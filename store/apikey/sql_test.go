@@ -0,0 +1,122 @@
+package apikey
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+// TestPost : to test issuing an API key
+func TestPost(t *testing.T) {
+	testcases := []struct {
+		desc string
+		body entities.APIKey
+
+		execErr    error
+		expectedID int
+	}{
+		{desc: "new key", body: entities.APIKey{Name: "importer", Prefix: "cafe0001", Role: "editor",
+			Scopes: []string{"read-only", "books-only"}, Salt: "aa", Hash: "bb"}, expectedID: 4},
+		{desc: "prefix clash", body: entities.APIKey{Name: "importer", Prefix: "cafe0001", Role: "reader",
+			Salt: "aa", Hash: "bb"}, execErr: errors.New("duplicate entry"), expectedID: -1},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectExec("insert into api_key(name,prefix,role,scopes,salt,hash)values(?,?,?,?,?,?)").
+			WithArgs(tc.body.Name, tc.body.Prefix, tc.body.Role, sqlmock.AnyArg(), tc.body.Salt, tc.body.Hash).
+			WillReturnResult(sqlmock.NewResult(4, 1)).WillReturnError(tc.execErr)
+
+		id, _ := New(db).Post(context.TODO(), tc.body)
+		if id != tc.expectedID {
+			t.Errorf("failed for %s, expected: %v, got: %v", tc.desc, tc.expectedID, id)
+		}
+
+		db.Close()
+	}
+}
+
+// TestGetByPrefix : to test reading a key with its scopes and timestamps
+func TestGetByPrefix(t *testing.T) {
+	cols := []string{"api_key_id", "name", "prefix", "role", "scopes", "salt", "hash", "created_at", "last_used_at",
+		"revoked_at"}
+
+	testcases := []struct {
+		desc string
+		row  []driver.Value
+
+		expected    entities.APIKey
+		expectedErr bool
+	}{
+		{desc: "scoped key", row: []driver.Value{4, "importer", "cafe0001", "editor", "read-only,books-only", "aa", "bb",
+			"2022-08-01 10:00:00", "2022-08-02 10:00:00", nil}, expected: entities.APIKey{APIKeyID: 4, Name: "importer",
+			Prefix: "cafe0001", Role: "editor", Scopes: []string{"read-only", "books-only"}, Salt: "aa", Hash: "bb",
+			CreatedAt: "2022-08-01 10:00:00", LastUsedAt: "2022-08-02 10:00:00"}},
+		{desc: "unused key without scopes", row: []driver.Value{5, "ci", "cafe0001", "reader", "", "aa", "bb",
+			"2022-08-01 10:00:00", nil, nil}, expected: entities.APIKey{APIKeyID: 5, Name: "ci", Prefix: "cafe0001",
+			Role: "reader", Salt: "aa", Hash: "bb", CreatedAt: "2022-08-01 10:00:00"}},
+		{desc: "unknown prefix", expectedErr: true},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		rows := sqlmock.NewRows(cols)
+		if tc.row != nil {
+			rows.AddRow(tc.row...)
+		}
+
+		mock.ExpectQuery("select " + columns + " from api_key where prefix=?").WithArgs("cafe0001").WillReturnRows(rows)
+
+		key, err := New(db).GetByPrefix(context.TODO(), "cafe0001")
+		if (err != nil) != tc.expectedErr || !reflect.DeepEqual(key, tc.expected) {
+			t.Errorf("failed for %s, got: %v %v", tc.desc, key, err)
+		}
+
+		db.Close()
+	}
+}
+
+// TestRevoke : to test that a key is only revoked once
+func TestRevoke(t *testing.T) {
+	testcases := []struct {
+		desc string
+		id   int
+
+		affected   int64
+		expectedRA int
+	}{
+		{desc: "active key", id: 4, affected: 1, expectedRA: 1},
+		{desc: "revoked key", id: 5, affected: 0, expectedRA: 0},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectExec("update api_key set revoked_at=now() where api_key_id=? and revoked_at is null").
+			WithArgs(tc.id).WillReturnResult(sqlmock.NewResult(0, tc.affected))
+
+		ra, _ := New(db).Revoke(context.TODO(), tc.id)
+		if ra != tc.expectedRA {
+			t.Errorf("failed for %s, expected: %v, got: %v", tc.desc, tc.expectedRA, ra)
+		}
+
+		db.Close()
+	}
+}
@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+type Store struct {
+	DB *sql.DB
+}
+
+// New : factory function
+func New(db *sql.DB) Store {
+	return Store{db}
+}
+
+const columns = "audit_id,actor,operation,entity_type,entity_id,before_data,after_data,created_at"
+
+// Post : appends an entry to the audit log, inside the transaction of the context when there is one
+func (s Store) Post(ctx context.Context, entry entities.AuditEntry) error {
+	_, err := store.Executor(ctx, s.DB).ExecContext(ctx, "insert into audit_log(actor,operation,entity_type,entity_id,"+
+		"before_data,after_data)values(?,?,?,?,?,?)", entry.Actor, entry.Operation, entry.EntityType, entry.EntityID,
+		nullJSON(entry.Before), nullJSON(entry.After))
+	if err != nil {
+		log.Print(err)
+	}
+
+	return err
+}
+
+// GetAll : gives the entries matching the filter, newest first, From and To are in UTC and created_at in the time
+// zone of the session so the bounds are converted to it, which keeps the index on created_at usable
+func (s Store) GetAll(ctx context.Context, filter entities.AuditFilter) ([]entities.AuditEntry, error) {
+	var (
+		entries    []entities.AuditEntry
+		conditions []string
+		args       []interface{}
+	)
+
+	if filter.EntityType != "" {
+		conditions = append(conditions, "entity_type=?")
+		args = append(args, filter.EntityType)
+	}
+
+	if filter.EntityID > 0 {
+		conditions = append(conditions, "entity_id=?")
+		args = append(args, filter.EntityID)
+	}
+
+	if filter.Actor != "" {
+		conditions = append(conditions, "actor=?")
+		args = append(args, filter.Actor)
+	}
+
+	if filter.From != "" {
+		conditions = append(conditions, "created_at>=CONVERT_TZ(?,'+00:00',@@session.time_zone)")
+		args = append(args, filter.From)
+	}
+
+	if filter.To != "" {
+		conditions = append(conditions, "created_at<CONVERT_TZ(?,'+00:00',@@session.time_zone)")
+		args = append(args, filter.To)
+	}
+
+	query := "select " + columns + " from audit_log"
+	if len(conditions) > 0 {
+		query += " where " + strings.Join(conditions, " and ")
+	}
+
+	query += " order by audit_id desc limit ? offset ?"
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			entry         entities.AuditEntry
+			before, after []byte
+		)
+
+		err = rows.Scan(&entry.AuditID, &entry.Actor, &entry.Operation, &entry.EntityType, &entry.EntityID, &before,
+			&after, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.Before = before
+		entry.After = after
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// nullJSON : stores a missing document as NULL instead of an empty string
+func nullJSON(doc []byte) interface{} {
+	if len(doc) == 0 {
+		return nil
+	}
+
+	return string(doc)
+}
@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+// TestPost : to test the entry is written inside the transaction of the change
+func TestPost(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	entry := entities.AuditEntry{Actor: "asha", Operation: entities.AuditDelete, EntityType: "author", EntityID: 4,
+		Before: json.RawMessage(`{"id":4}`)}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("delete from author where author_id=?").WithArgs(4).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("insert into audit_log(actor,operation,entity_type,entity_id,before_data,after_data)"+
+		"values(?,?,?,?,?,?)").WithArgs("asha", "delete", "author", 4, `{"id":4}`, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = store.NewTransaction(db).WithTx(context.TODO(), func(ctx context.Context) error {
+		if _, err := store.Executor(ctx, db).ExecContext(ctx, "delete from author where author_id=?", 4); err != nil {
+			return err
+		}
+
+		return New(db).Post(ctx, entry)
+	})
+
+	if err != nil {
+		t.Errorf("failed to write the entry: %v", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// TestGetAll : to test the filters become conditions of the query
+func TestGetAll(t *testing.T) {
+	cols := []string{"audit_id", "actor", "operation", "entity_type", "entity_id", "before_data", "after_data",
+		"created_at"}
+	entry := entities.AuditEntry{AuditID: 7, Actor: "asha", Operation: "create", EntityType: "book", EntityID: 3,
+		After: json.RawMessage(`{"id":3}`), CreatedAt: "2022-08-01 10:00:00"}
+
+	testcases := []struct {
+		desc   string
+		filter entities.AuditFilter
+
+		query string
+		args  []driver.Value
+	}{
+		{desc: "no filter", filter: entities.AuditFilter{Page: 1, Limit: 50},
+			query: "select " + columns + " from audit_log order by audit_id desc limit ? offset ?", args: []driver.Value{50, 0}},
+		{desc: "entity, actor and range", filter: entities.AuditFilter{EntityType: "book", EntityID: 3, Actor: "asha",
+			From: "2022-08-01 00:00:00", To: "2022-08-02 00:00:00", Page: 2, Limit: 10}, query: "select " + columns +
+			" from audit_log where entity_type=? and entity_id=? and actor=? and " +
+			"created_at>=CONVERT_TZ(?,'+00:00',@@session.time_zone) and " +
+			"created_at<CONVERT_TZ(?,'+00:00',@@session.time_zone) order by audit_id desc limit ? offset ?", args: []driver.Value{"book", 3, "asha", "2022-08-01 00:00:00",
+			"2022-08-02 00:00:00", 10, 10}},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectQuery(tc.query).WithArgs(tc.args...).WillReturnRows(sqlmock.NewRows(cols).
+			AddRow(7, "asha", "create", "book", 3, nil, `{"id":3}`, "2022-08-01 10:00:00"))
+
+		entries, err := New(db).GetAll(context.TODO(), tc.filter)
+		if err != nil || !reflect.DeepEqual(entries, []entities.AuditEntry{entry}) {
+			t.Errorf("failed for %s, got: %v %v", tc.desc, entries, err)
+		}
+
+		db.Close()
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"log"
+	"strings"
 
 	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
 )
 
 type Store struct {
@@ -19,7 +21,7 @@ func New(db *sql.DB) Store {
 
 // Post : insert an author
 func (s Store) Post(ctx context.Context, author entities.Author) (int, error) {
-	res, err := s.DB.ExecContext(ctx, "insert into author(first_name,last_name,dob,pen_name)values(?,?,?,?)",
+	res, err := store.Executor(ctx, s.DB).ExecContext(ctx, "insert into author(first_name,last_name,dob,pen_name)values(?,?,?,?)",
 		author.FirstName, author.LastName, author.DOB, author.PenName)
 	if err != nil {
 		log.Print(err)
@@ -36,7 +38,7 @@ func (s Store) Post(ctx context.Context, author entities.Author) (int, error) {
 
 // Put : inserts an author if that does not exist and update author if exists
 func (s Store) Put(ctx context.Context, author entities.Author, id int) (int, error) {
-	_, err := s.DB.ExecContext(ctx, "update author set first_name=?,last_name=?,dob=?,pen_name=? where author_id=?",
+	_, err := store.Executor(ctx, s.DB).ExecContext(ctx, "update author set first_name=?,last_name=?,dob=?,pen_name=? where author_id=?",
 		author.FirstName, author.LastName, author.DOB, author.PenName, id)
 	if err != nil {
 		log.Print(err)
@@ -48,7 +50,7 @@ func (s Store) Put(ctx context.Context, author entities.Author, id int) (int, er
 
 // Delete :  deletes an author
 func (s Store) Delete(ctx context.Context, id int) (int, error) {
-	res, err := s.DB.ExecContext(ctx, "delete from author where author_id=?", id)
+	res, err := store.Executor(ctx, s.DB).ExecContext(ctx, "delete from author where author_id=?", id)
 	if err != nil {
 		return -1, err
 	}
@@ -65,11 +67,157 @@ func (s Store) Delete(ctx context.Context, id int) (int, error) {
 func (s Store) IncludeAuthor(ctx context.Context, id int) (entities.Author, error) {
 	var author entities.Author
 
-	Row := s.DB.QueryRowContext(ctx, "SELECT * FROM author where author_id=?", id)
+	Row := store.Executor(ctx, s.DB).QueryRowContext(ctx, "SELECT author_id,first_name,last_name,dob,pen_name,"+
+		store.UpdatedAt("author")+" FROM author where author_id=?", id)
 
-	if err := Row.Scan(&author.AuthorID, &author.FirstName, &author.LastName, &author.DOB, &author.PenName); err != nil {
+	if err := Row.Scan(&author.AuthorID, &author.FirstName, &author.LastName, &author.DOB, &author.PenName,
+		&author.UpdatedAt); err != nil {
 		return entities.Author{}, err
 	}
 
 	return author, nil
 }
+
+// GetAuthorByName : gives the first author with the name, the comparison follows the collation of the table
+func (s Store) GetAuthorByName(ctx context.Context, firstName, lastName string) (entities.Author, error) {
+	var author entities.Author
+
+	row := store.Executor(ctx, s.DB).QueryRowContext(ctx, "SELECT author_id,first_name,last_name,dob,pen_name,"+
+		store.UpdatedAt("author")+" FROM author where first_name=? and last_name=? order by author_id limit 1",
+		firstName, lastName)
+
+	if err := row.Scan(&author.AuthorID, &author.FirstName, &author.LastName, &author.DOB, &author.PenName,
+		&author.UpdatedAt); err != nil {
+		return entities.Author{}, err
+	}
+
+	return author, nil
+}
+
+// EachAuthor : calls fn with the authors of the filter one row at a time in the order of their id, only the columns
+// of the fields of the projection are read; an error of fn stops the iteration and is returned
+func (s Store) EachAuthor(ctx context.Context, filter entities.AuthorFilter, projection entities.Projection,
+	fn func(author entities.Author) error) error {
+	var (
+		author  entities.Author
+		columns = []string{"author_id"}
+		dest    = []interface{}{&author.AuthorID}
+	)
+
+	for _, c := range []struct {
+		field, column string
+		dest          *string
+	}{
+		{"firstName", "first_name", &author.FirstName},
+		{"lastName", "last_name", &author.LastName},
+		{"DOB", "dob", &author.DOB},
+		{"penName", "pen_name", &author.PenName},
+	} {
+		if projection.Includes(c.field) {
+			columns, dest = append(columns, c.column), append(dest, c.dest)
+		}
+	}
+
+	var args []interface{}
+
+	query := "SELECT " + strings.Join(columns, ",") + " FROM author"
+	if filter.After > 0 {
+		query += " WHERE author_id>?"
+		args = append(args, filter.After)
+	}
+
+	query += " ORDER BY author_id"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := store.Executor(ctx, s.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		author = entities.Author{}
+
+		if err = rows.Scan(dest...); err != nil {
+			return err
+		}
+
+		if err = fn(author); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// GetBooksByAuthors : gives the books of each of the authors by author id, read in one query for a whole listing
+func (s Store) GetBooksByAuthors(ctx context.Context, ids []int) (map[int][]entities.Book, error) {
+	books := make(map[int][]entities.Book)
+
+	if len(ids) == 0 {
+		return books, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i := range ids {
+		args[i] = ids[i]
+	}
+
+	rows, err := store.Executor(ctx, s.DB).QueryContext(ctx, "SELECT id,author_id,title,publication,published_date "+
+		"FROM book WHERE author_id IN (?"+strings.Repeat(",?", len(ids)-1)+") ORDER BY author_id,id", args...)
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var book entities.Book
+
+		if err = rows.Scan(&book.BookID, &book.AuthorID, &book.Title, &book.Publication, &book.PublishedDate); err != nil {
+			return nil, err
+		}
+
+		books[book.AuthorID] = append(books[book.AuthorID], book)
+	}
+
+	return books, rows.Err()
+}
+
+// GetAuthorsByIDs : gives the authors with the ids by author id in one query, missing ids are left out
+func (s Store) GetAuthorsByIDs(ctx context.Context, ids []int) (map[int]entities.Author, error) {
+	authors := make(map[int]entities.Author)
+
+	if len(ids) == 0 {
+		return authors, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i := range ids {
+		args[i] = ids[i]
+	}
+
+	rows, err := store.Executor(ctx, s.DB).QueryContext(ctx, "SELECT author_id,first_name,last_name,dob,pen_name "+
+		"FROM author WHERE author_id IN (?"+strings.Repeat(",?", len(ids)-1)+")", args...)
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var author entities.Author
+
+		if err = rows.Scan(&author.AuthorID, &author.FirstName, &author.LastName, &author.DOB, &author.PenName); err != nil {
+			return nil, err
+		}
+
+		authors[author.AuthorID] = author
+	}
+
+	return authors, rows.Err()
+}
@@ -2,13 +2,16 @@ package author
 
 import (
 	"context"
+	"database/sql/driver"
 	"errors"
 	"log"
+	"reflect"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 
 	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
 )
 
 // TestPost : to test post an author
@@ -150,9 +153,10 @@ func TestIncludeAuthor(t *testing.T) {
 	}
 
 	var (
-		author  = entities.Author{AuthorID: 1, FirstName: "shani", LastName: "kumar", DOB: "20/06/2000", PenName: "sk"}
-		author1 = sqlmock.NewRows([]string{"author_id", "first_name", "last_name", "dob", "pen_name"}).AddRow(author.AuthorID,
-			author.FirstName, author.LastName, author.DOB, author.PenName)
+		author = entities.Author{AuthorID: 1, FirstName: "shani", LastName: "kumar", DOB: "20/06/2000", PenName: "sk",
+			UpdatedAt: "2022-05-01T10:00:00Z"}
+		author1 = sqlmock.NewRows([]string{"author_id", "first_name", "last_name", "dob", "pen_name", "updated_at"}).
+			AddRow(author.AuthorID, author.FirstName, author.LastName, author.DOB, author.PenName, author.UpdatedAt)
 	)
 
 	Testcases := []struct {
@@ -163,7 +167,7 @@ func TestIncludeAuthor(t *testing.T) {
 		expectedErr error
 	}{
 		{desc: "fetching book by id",
-			targetID: 1, expected: entities.Author{AuthorID: 1, FirstName: "shani", LastName: "kumar", DOB: "20/06/2000", PenName: "sk"},
+			targetID: 1, expected: author,
 		},
 		{"invalid id", -1, entities.Author{}, errors.New("invalid")},
 	}
@@ -171,15 +175,154 @@ func TestIncludeAuthor(t *testing.T) {
 	for _, tc := range Testcases {
 		bs := New(db)
 
-		mock.ExpectQuery("SELECT * FROM author where author_id=?").WithArgs(tc.targetID).WillReturnRows(author1).WillReturnError(tc.expectedErr)
+		mock.ExpectQuery("SELECT author_id,first_name,last_name,dob,pen_name," + store.UpdatedAt("author") +
+			" FROM author where author_id=?").WithArgs(tc.targetID).WillReturnRows(author1).WillReturnError(tc.expectedErr)
 
 		a, err := bs.IncludeAuthor(context.TODO(), tc.targetID)
 		if err != nil {
 			log.Print(err)
 		}
 
-		if a != tc.expected || err != tc.expectedErr {
+		if !reflect.DeepEqual(a, tc.expected) || err != tc.expectedErr {
 			t.Errorf("failed for %v\n", tc.desc)
 		}
 	}
 }
+
+// TestEachAuthor : to test EachAuthor
+func TestEachAuthor(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Print(err)
+	}
+	defer db.Close()
+
+	query := "SELECT author_id,first_name,last_name,dob,pen_name FROM author ORDER BY author_id"
+	columns := []string{"author_id", "first_name", "last_name", "dob", "pen_name"}
+	errStop := errors.New("stop")
+
+	Testcases := []struct {
+		desc     string
+		filter   entities.AuthorFilter
+		query    string
+		args     []driver.Value
+		stopAt   int
+		queryErr error
+
+		expected    int
+		expectedErr error
+	}{
+		{desc: "every author", query: query, expected: 2},
+		{desc: "page after an author", filter: entities.AuthorFilter{After: 4, Limit: 3},
+			query: "SELECT author_id,first_name,last_name,dob,pen_name FROM author WHERE author_id>? " +
+				"ORDER BY author_id LIMIT ?", args: []driver.Value{4, 3}, expected: 2},
+		{desc: "stopped by the callback", query: query, stopAt: 1, expected: 1, expectedErr: errStop},
+		{desc: "query failure", query: query, queryErr: errors.New("connection lost"),
+			expectedErr: errors.New("connection lost")},
+	}
+
+	for _, tc := range Testcases {
+		rows := sqlmock.NewRows(columns).AddRow(1, "shani", "kumar", "20/06/2000", "sk").
+			AddRow(2, "ravi", "verma", "01/01/1990", "rv")
+		if tc.queryErr != nil {
+			mock.ExpectQuery(tc.query).WillReturnError(tc.queryErr)
+		} else {
+			mock.ExpectQuery(tc.query).WithArgs(tc.args...).WillReturnRows(rows)
+		}
+
+		count := 0
+		err := New(db).EachAuthor(context.TODO(), tc.filter, entities.Projection{}, func(author entities.Author) error {
+			count++
+			if count == tc.stopAt {
+				return errStop
+			}
+
+			return nil
+		})
+
+		if count != tc.expected {
+			t.Errorf("failed for %v, expected %v authors got %v", tc.desc, tc.expected, count)
+		}
+
+		if (err == nil) != (tc.expectedErr == nil) || (err != nil && err.Error() != tc.expectedErr.Error()) {
+			t.Errorf("failed for %v, expected error %v got %v", tc.desc, tc.expectedErr, err)
+		}
+	}
+}
+
+// TestEachAuthorProjection : test that only the columns of the requested fields are read
+func TestEachAuthorProjection(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Print(err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT author_id,pen_name FROM author ORDER BY author_id").
+		WillReturnRows(sqlmock.NewRows([]string{"author_id", "pen_name"}).AddRow(1, "sk"))
+
+	var authors []entities.Author
+
+	err = New(db).EachAuthor(context.TODO(), entities.AuthorFilter{}, entities.Projection{Fields: []string{"authorID", "penName"}},
+		func(author entities.Author) error {
+			authors = append(authors, author)
+			return nil
+		})
+
+	if err != nil || !reflect.DeepEqual(authors, []entities.Author{{AuthorID: 1, PenName: "sk"}}) {
+		t.Errorf("unexpected authors %v %v", authors, err)
+	}
+}
+
+// TestGetBooksByAuthors : test reading the books of a listing of authors in one query
+func TestGetBooksByAuthors(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Print(err)
+	}
+	defer db.Close()
+
+	query := "SELECT id,author_id,title,publication,published_date FROM book WHERE author_id IN (?,?) " +
+		"ORDER BY author_id,id"
+
+	mock.ExpectQuery(query).WithArgs(1, 2).WillReturnRows(sqlmock.NewRows(
+		[]string{"id", "author_id", "title", "publication", "published_date"}).
+		AddRow(3, 1, "rusty", "penguin", "20/06/2000").AddRow(4, 1, "ruskin", "penguin", "20/06/2001"))
+
+	books, err := New(db).GetBooksByAuthors(context.TODO(), []int{1, 2})
+
+	expected := map[int][]entities.Book{1: {
+		{BookID: 3, AuthorID: 1, Title: "rusty", Publication: "penguin", PublishedDate: "20/06/2000"},
+		{BookID: 4, AuthorID: 1, Title: "ruskin", Publication: "penguin", PublishedDate: "20/06/2001"},
+	}}
+
+	if err != nil || !reflect.DeepEqual(books, expected) {
+		t.Errorf("unexpected books %v %v", books, err)
+	}
+
+	if books, err = New(db).GetBooksByAuthors(context.TODO(), nil); err != nil || len(books) != 0 {
+		t.Errorf("expected no books, got %v %v", books, err)
+	}
+}
+
+// TestGetAuthorsByIDs : test reading a batch of authors in one query
+func TestGetAuthorsByIDs(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		log.Print(err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT author_id,first_name,last_name,dob,pen_name FROM author WHERE author_id IN (?,?)").
+		WithArgs(1, 9).WillReturnRows(sqlmock.NewRows([]string{"author_id", "first_name", "last_name", "dob", "pen_name"}).
+		AddRow(1, "ruskin", "bond", "19/05/1934", "rb"))
+
+	authors, err := New(db).GetAuthorsByIDs(context.TODO(), []int{1, 9})
+
+	expected := map[int]entities.Author{1: {AuthorID: 1, FirstName: "ruskin", LastName: "bond", DOB: "19/05/1934",
+		PenName: "rb"}}
+
+	if err != nil || !reflect.DeepEqual(authors, expected) {
+		t.Errorf("unexpected authors %v %v", authors, err)
+	}
+}
@@ -3,11 +3,18 @@ package book
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"log"
+	"strconv"
+	"strings"
 
 	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
 )
 
+// columns : the columns of a book in the order they are scanned
+var columns = "id,author_id,title,publication,published_date," + store.UpdatedAt("book")
+
 type Store struct {
 	DB *sql.DB
 }
@@ -17,24 +24,26 @@ func New(db *sql.DB) Store {
 	return Store{db}
 }
 
-// GetAllBook : fetches the all book from database
-func (bs Store) GetAllBook(ctx context.Context) ([]entities.Book, error) {
+// GetBooksByTitle : give the books with particular title
+func (bs Store) GetBooksByTitle(ctx context.Context, title string) ([]entities.Book, error) {
 	var (
 		books []entities.Book
-		rows  *sql.Rows
+		Rows  *sql.Rows
 		err   error
 	)
 
-	rows, err = bs.DB.QueryContext(ctx, "SELECT * FROM book")
+	Rows, err = store.Executor(ctx, bs.DB).QueryContext(ctx, "SELECT "+columns+" FROM book WHERE title=?", title)
 	if err != nil {
+		log.Print(err)
 		return nil, err
 	}
-	defer rows.Close()
+	defer Rows.Close()
 
-	for rows.Next() {
+	for Rows.Next() {
 		var book entities.Book
 
-		err = rows.Scan(&book.BookID, &book.AuthorID, &book.Title, &book.Publication, &book.PublishedDate)
+		err = Rows.Scan(&book.BookID, &book.AuthorID, &book.Title, &book.Publication, &book.PublishedDate,
+			&book.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -45,42 +54,202 @@ func (bs Store) GetAllBook(ctx context.Context) ([]entities.Book, error) {
 	return books, nil
 }
 
-// GetBooksByTitle : give the books with particular title
-func (bs Store) GetBooksByTitle(ctx context.Context, title string) ([]entities.Book, error) {
-	var (
-		books []entities.Book
-		Rows  *sql.Rows
-		err   error
-	)
+// EachBook : calls fn with the books matching the filter one row at a time in the order of their id, only the
+// columns of the fields of the projection are read and only the expanded author and publisher are joined; an error
+// of fn stops the iteration and is returned
+func (bs Store) EachBook(ctx context.Context, filter entities.BookFilter, projection entities.Projection,
+	fn func(book entities.Book) error) error {
+	query, args, fields := bookQuery(filter, projection)
 
-	Rows, err = bs.DB.QueryContext(ctx, "SELECT * FROM book WHERE title=?", title)
+	rows, err := store.Executor(ctx, bs.DB).QueryContext(ctx, query, args...)
 	if err != nil {
 		log.Print(err)
-		return nil, err
+		return err
 	}
-	defer Rows.Close()
+	defer rows.Close()
 
-	for Rows.Next() {
-		var book entities.Book
+	for rows.Next() {
+		var row bookRow
 
-		err = Rows.Scan(&book.BookID, &book.AuthorID, &book.Title, &book.Publication, &book.PublishedDate)
-		if err != nil {
-			return nil, err
+		dest := make([]interface{}, len(fields))
+		for i := range fields {
+			dest[i] = row.dest(fields[i])
 		}
 
-		books = append(books, book)
+		if err = rows.Scan(dest...); err != nil {
+			return err
+		}
+
+		if err = fn(row.book(projection)); err != nil {
+			return err
+		}
 	}
 
-	return books, nil
+	return rows.Err()
+}
+
+// bookColumns : the columns of a book listing by the json name of the field they are read into
+var bookColumns = []struct{ field, column string }{
+	{"authorID", "book.author_id"},
+	{"title", "title"},
+	{"publication", "book.publication"},
+	{"publishedDate", "published_date"},
+	{"updatedAt", store.UpdatedAt("book")},
+	{"author.firstName", "a.first_name"},
+	{"author.lastName", "a.last_name"},
+	{"author.DOB", "a.dob"},
+	{"author.penName", "a.pen_name"},
+	{"author.updatedAt", store.UpdatedAt("a")},
+	{"publisher.name", "book.publication"},
+	{"publisher.books", "p.books"},
+}
+
+// bookQuery : builds the query of a book listing, the id of the book and of an expanded author are always read;
+// fields are the json names of the columns in the order they are selected
+func bookQuery(filter entities.BookFilter, projection entities.Projection) (query string, args []interface{},
+	fields []string) {
+	with, where, args := filterQuery(filter)
+
+	author, publisher := projection.Expands("author"), projection.Expands("publisher")
+	columns, fields := []string{"id"}, []string{"bookID"}
+	from := " FROM book"
+
+	if author {
+		columns, fields = append(columns, "a.author_id"), append(fields, "author.authorID")
+		from += " LEFT JOIN author a ON a.author_id=book.author_id"
+	}
+
+	if publisher && projection.Includes("publisher.books") {
+		from += " LEFT JOIN (SELECT publication,COUNT(*) AS books FROM book GROUP BY publication) p " +
+			"ON p.publication=book.publication"
+	}
+
+	for _, c := range bookColumns {
+		resource, _, embedded := strings.Cut(c.field, ".")
+		if embedded && !(resource == "author" && author || resource == "publisher" && publisher) {
+			continue
+		}
+
+		if projection.Includes(c.field) {
+			columns, fields = append(columns, c.column), append(fields, c.field)
+		}
+	}
+
+	query = with + "SELECT " + strings.Join(columns, ",") + from + where + " ORDER BY id"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	return query, args, fields
+}
+
+// bookRow : the destinations of the columns of a book listing, the embedded resources are read as nullable
+// since they are left joined
+type bookRow struct {
+	value                                                             entities.Book
+	authorID, books                                                   sql.NullInt64
+	firstName, lastName, dob, penName, authorUpdatedAt, publisherName sql.NullString
+}
+
+// dest : gives the destination of the column read into the field
+func (r *bookRow) dest(field string) interface{} {
+	switch field {
+	case "bookID":
+		return &r.value.BookID
+	case "authorID":
+		return &r.value.AuthorID
+	case "title":
+		return &r.value.Title
+	case "publication":
+		return &r.value.Publication
+	case "publishedDate":
+		return &r.value.PublishedDate
+	case "updatedAt":
+		return &r.value.UpdatedAt
+	case "author.authorID":
+		return &r.authorID
+	case "author.firstName":
+		return &r.firstName
+	case "author.lastName":
+		return &r.lastName
+	case "author.DOB":
+		return &r.dob
+	case "author.penName":
+		return &r.penName
+	case "author.updatedAt":
+		return &r.authorUpdatedAt
+	case "publisher.name":
+		return &r.publisherName
+	default:
+		return &r.books
+	}
+}
+
+// book : gives the book of the row with its expanded resources
+func (r *bookRow) book(projection entities.Projection) entities.Book {
+	book := r.value
+
+	if r.authorID.Valid {
+		book.Author = &entities.Author{AuthorID: int(r.authorID.Int64), FirstName: r.firstName.String,
+			LastName: r.lastName.String, DOB: r.dob.String, PenName: r.penName.String, UpdatedAt: r.authorUpdatedAt.String}
+	}
+
+	if projection.Expands("publisher") {
+		book.Publisher = &entities.Publisher{Name: r.publisherName.String, Books: int(r.books.Int64)}
+	}
+
+	return book
+}
+
+// filterQuery : builds the common table expression, the where clause and the arguments of a book filter
+func filterQuery(filter entities.BookFilter) (with, where string, args []interface{}) {
+	var conditions []string
+
+	if filter.Genre != "" {
+		anchor := "name=?"
+		if _, err := strconv.Atoi(filter.Genre); err == nil {
+			anchor = "genre_id=?"
+		}
+
+		with = "WITH RECURSIVE genre_tree AS (SELECT genre_id FROM genre WHERE " + anchor +
+			" UNION ALL SELECT g.genre_id FROM genre g JOIN genre_tree t ON g.parent_id=t.genre_id) "
+		conditions = append(conditions, "id IN (SELECT book_id FROM book_genre WHERE genre_id IN "+
+			"(SELECT genre_id FROM genre_tree))")
+		args = append(args, filter.Genre)
+	}
+
+	if filter.Title != "" {
+		conditions = append(conditions, "title=?")
+		args = append(args, filter.Title)
+	}
+
+	for _, tag := range filter.Tags {
+		conditions = append(conditions, "id IN (SELECT bt.book_id FROM book_tag bt JOIN tag t ON t.tag_id=bt.tag_id "+
+			"WHERE t.name=?)")
+		args = append(args, tag)
+	}
+
+	if filter.After > 0 {
+		conditions = append(conditions, "book.id>?")
+		args = append(args, filter.After)
+	}
+
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return with, where, args
 }
 
 // GetBookByID : give the book with particular id
 func (bs Store) GetBookByID(ctx context.Context, id int) (entities.Book, error) {
 	var book entities.Book
 
-	row := bs.DB.QueryRowContext(ctx, "select * from book where id=?", id)
+	row := store.Executor(ctx, bs.DB).QueryRowContext(ctx, "select "+columns+" from book where id=?", id)
 
-	err := row.Scan(&book.BookID, &book.AuthorID, &book.Title, &book.Publication, &book.PublishedDate)
+	err := row.Scan(&book.BookID, &book.AuthorID, &book.Title, &book.Publication, &book.PublishedDate,
+		&book.UpdatedAt)
 	if err != nil {
 		log.Print(err)
 		return entities.Book{}, err
@@ -89,9 +258,24 @@ func (bs Store) GetBookByID(ctx context.Context, id int) (entities.Book, error)
 	return book, nil
 }
 
+// GetBookByISBN : gives the book of the edition with the ISBN, which is stored without separators
+func (bs Store) GetBookByISBN(ctx context.Context, isbn string) (entities.Book, error) {
+	var book entities.Book
+
+	row := store.Executor(ctx, bs.DB).QueryRowContext(ctx, "SELECT b.id,b.author_id,b.title,b.publication,"+
+		"b.published_date,"+store.UpdatedAt("b")+" FROM book b JOIN edition e ON e.book_id=b.id WHERE e.isbn=?", isbn)
+
+	if err := row.Scan(&book.BookID, &book.AuthorID, &book.Title, &book.Publication, &book.PublishedDate,
+		&book.UpdatedAt); err != nil {
+		return entities.Book{}, err
+	}
+
+	return book, nil
+}
+
 // Post : inserts the book into database
 func (bs Store) Post(ctx context.Context, book *entities.Book) (int, error) {
-	result, err := bs.DB.ExecContext(ctx, "insert into book(author_id,title,publication,published_date)values(?,?,?,?)",
+	result, err := store.Executor(ctx, bs.DB).ExecContext(ctx, "insert into book(author_id,title,publication,published_date)values(?,?,?,?)",
 		book.AuthorID, book.Title, book.Publication, book.PublishedDate)
 	if err != nil {
 		log.Print(err)
@@ -109,7 +293,7 @@ func (bs Store) Post(ctx context.Context, book *entities.Book) (int, error) {
 
 // Put : updates the book with particular id
 func (bs Store) Put(ctx context.Context, book *entities.Book, id int) (int, error) {
-	res, err := bs.DB.ExecContext(ctx, "update book set author_id=?,title=?,publication=?,published_date=? where id=?",
+	res, err := store.Executor(ctx, bs.DB).ExecContext(ctx, "update book set author_id=?,title=?,publication=?,published_date=? where id=?",
 		book.AuthorID, book.Title, book.Publication, book.PublishedDate, id)
 	if err != nil {
 		return 0, err
@@ -120,12 +304,23 @@ func (bs Store) Put(ctx context.Context, book *entities.Book, id int) (int, erro
 		return 0, err
 	}
 
+	if ra > 0 {
+		if err = bs.touchRelated(ctx, id); err != nil {
+			return 0, err
+		}
+	}
+
 	return int(ra), nil
 }
 
 // Delete : deletes the book by particular id
 func (bs Store) Delete(ctx context.Context, id int) (int, error) {
-	res, err := bs.DB.ExecContext(ctx, "delete from book where id=?", id)
+	// the series and edition rows go with the cascade, which does not fire their triggers
+	if err := bs.touchRelated(ctx, id); err != nil {
+		return -1, err
+	}
+
+	res, err := store.Executor(ctx, bs.DB).ExecContext(ctx, "delete from book where id=?", id)
 	if err != nil {
 		return -1, err
 	}
@@ -137,3 +332,322 @@ func (bs Store) Delete(ctx context.Context, id int) (int, error) {
 
 	return int(ra), nil
 }
+
+// relatedQuery : the other books shown with a book, its neighbours in its series and the other editions of its work
+const relatedQuery = "SELECT sb.book_id FROM series_book sb JOIN series_book own ON own.series_id=sb.series_id " +
+	"WHERE own.book_id=? AND sb.book_id<>own.book_id UNION SELECT e.book_id FROM edition e " +
+	"JOIN edition own ON own.work_id=e.work_id WHERE own.book_id=? AND e.book_id<>own.book_id"
+
+// GetRelated : gives the ids of the other books shown with the book
+func (bs Store) GetRelated(ctx context.Context, id int) ([]int, error) {
+	var ids []int
+
+	rows, err := store.Executor(ctx, bs.DB).QueryContext(ctx, relatedQuery, id, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var related int
+		if err = rows.Scan(&related); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, related)
+	}
+
+	return ids, rows.Err()
+}
+
+// touchRelated : moves the updated_at of the books shown with the book, a trigger on book can not update book itself
+func (bs Store) touchRelated(ctx context.Context, id int) error {
+	_, err := store.Executor(ctx, bs.DB).ExecContext(ctx,
+		"UPDATE book SET updated_at=CURRENT_TIMESTAMP WHERE id IN ("+relatedQuery+")", id, id)
+
+	return err
+}
+
+// GetGenres : gives the genres attached to the book
+func (bs Store) GetGenres(ctx context.Context, id int) ([]entities.Genre, error) {
+	var genres []entities.Genre
+
+	rows, err := store.Executor(ctx, bs.DB).QueryContext(ctx, "SELECT g.genre_id,g.name,g.parent_id FROM genre g "+
+		"JOIN book_genre bg ON bg.genre_id=g.genre_id WHERE bg.book_id=? ORDER BY g.genre_id", id)
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			genre  entities.Genre
+			parent sql.NullInt64
+		)
+
+		if err = rows.Scan(&genre.GenreID, &genre.Name, &parent); err != nil {
+			return nil, err
+		}
+
+		genre.ParentID = int(parent.Int64)
+		genres = append(genres, genre)
+	}
+
+	return genres, nil
+}
+
+// SetGenres : replaces the genres attached to the book, within the transaction of the caller
+func (bs Store) SetGenres(ctx context.Context, id int, genreIDs []int) error {
+	conn := store.Executor(ctx, bs.DB)
+
+	if _, err := conn.ExecContext(ctx, "delete from book_genre where book_id=?", id); err != nil {
+		return err
+	}
+
+	for _, genreID := range genreIDs {
+		_, err := conn.ExecContext(ctx, "insert into book_genre(book_id,genre_id)values(?,?)", id, genreID)
+		if err != nil {
+			log.Print(err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetTags : gives the names of the tags attached to the book
+func (bs Store) GetTags(ctx context.Context, id int) ([]string, error) {
+	var tags []string
+
+	rows, err := store.Executor(ctx, bs.DB).QueryContext(ctx, "SELECT t.name FROM tag t JOIN book_tag bt ON bt.tag_id=t.tag_id "+
+		"WHERE bt.book_id=? ORDER BY t.name", id)
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag string
+
+		if err = rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// SetTags : replaces the tags attached to the book within the transaction of the caller, tags which do not exist yet
+// are created
+func (bs Store) SetTags(ctx context.Context, id int, tags []string) error {
+	conn := store.Executor(ctx, bs.DB)
+
+	if _, err := conn.ExecContext(ctx, "delete from book_tag where book_id=?", id); err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		if _, err := conn.ExecContext(ctx, "insert ignore into tag(name)values(?)", tag); err != nil {
+			log.Print(err)
+			return err
+		}
+
+		_, err := conn.ExecContext(ctx, "insert into book_tag(book_id,tag_id) select ?,tag_id from tag where name=?",
+			id, tag)
+		if err != nil {
+			log.Print(err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetSeries : gives the series the book belongs to along with the previous and next book of each
+func (bs Store) GetSeries(ctx context.Context, id int) ([]entities.BookSeries, error) {
+	var series []entities.BookSeries
+
+	rows, err := store.Executor(ctx, bs.DB).QueryContext(ctx, "WITH ordered AS "+
+		"(SELECT sb.series_id,sb.book_id,sb.position,"+
+		"LAG(sb.book_id) OVER w AS prev_id,LAG(b.title) OVER w AS prev_title,LAG(sb.position) OVER w AS prev_position,"+
+		"LEAD(sb.book_id) OVER w AS next_id,LEAD(b.title) OVER w AS next_title,LEAD(sb.position) OVER w AS next_position "+
+		"FROM series_book sb JOIN book b ON b.id=sb.book_id WINDOW w AS (PARTITION BY sb.series_id ORDER BY sb.position)) "+
+		"SELECT o.series_id,s.name,o.position,o.prev_id,o.prev_title,o.prev_position,o.next_id,o.next_title,o.next_position "+
+		"FROM ordered o JOIN series s ON s.series_id=o.series_id WHERE o.book_id=? ORDER BY o.series_id", id)
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			s                          entities.BookSeries
+			prevID, nextID             sql.NullInt64
+			prevTitle, nextTitle       sql.NullString
+			prevPosition, nextPosition sql.NullFloat64
+		)
+
+		err = rows.Scan(&s.SeriesID, &s.Name, &s.Position, &prevID, &prevTitle, &prevPosition,
+			&nextID, &nextTitle, &nextPosition)
+		if err != nil {
+			return nil, err
+		}
+
+		if prevID.Valid {
+			s.Previous = &entities.SeriesBook{BookID: int(prevID.Int64), Title: prevTitle.String,
+				Position: prevPosition.Float64}
+		}
+
+		if nextID.Valid {
+			s.Next = &entities.SeriesBook{BookID: int(nextID.Int64), Title: nextTitle.String,
+				Position: nextPosition.Float64}
+		}
+
+		series = append(series, s)
+	}
+
+	return series, nil
+}
+
+// GetSeriesByBooks : gives the series of each of the books by book id, without the neighbouring books so a whole
+// listing is read in one query
+func (bs Store) GetSeriesByBooks(ctx context.Context, ids []int) (map[int][]entities.BookSeries, error) {
+	series := make(map[int][]entities.BookSeries)
+
+	if len(ids) == 0 {
+		return series, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i := range ids {
+		args[i] = ids[i]
+	}
+
+	rows, err := store.Executor(ctx, bs.DB).QueryContext(ctx, "SELECT sb.book_id,s.series_id,s.name,sb.position "+
+		"FROM series_book sb JOIN series s ON s.series_id=sb.series_id WHERE sb.book_id IN (?"+
+		strings.Repeat(",?", len(ids)-1)+") ORDER BY sb.book_id,s.series_id", args...)
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id int
+			s  entities.BookSeries
+		)
+
+		if err = rows.Scan(&id, &s.SeriesID, &s.Name, &s.Position); err != nil {
+			return nil, err
+		}
+
+		series[id] = append(series[id], s)
+	}
+
+	return series, rows.Err()
+}
+
+// GetEdition : gives the edition details of the book, a zero edition when the book is not part of a work
+func (bs Store) GetEdition(ctx context.Context, id int) (entities.Edition, error) {
+	var edition entities.Edition
+
+	row := store.Executor(ctx, bs.DB).QueryRowContext(ctx, "SELECT e.book_id,e.work_id,b.title,e.isbn,e.format,e.page_count,b.publication,"+
+		"b.published_date FROM edition e JOIN book b ON b.id=e.book_id WHERE e.book_id=?", id)
+
+	err := row.Scan(&edition.BookID, &edition.WorkID, &edition.Title, &edition.ISBN, &edition.Format, &edition.PageCount,
+		&edition.Publisher, &edition.PublishedDate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.Edition{}, nil
+	}
+
+	if err != nil {
+		log.Print(err)
+		return entities.Edition{}, err
+	}
+
+	return edition, nil
+}
+
+// SetEdition : stores the edition details of the book, replacing the existing ones
+func (bs Store) SetEdition(ctx context.Context, id int, edition entities.Edition) error {
+	_, err := store.Executor(ctx, bs.DB).ExecContext(ctx, "insert into edition(book_id,work_id,isbn,format,page_count)values(?,?,?,?,?) "+
+		"on duplicate key update work_id=values(work_id),isbn=values(isbn),format=values(format),"+
+		"page_count=values(page_count)", id, edition.WorkID, edition.ISBN, edition.Format, edition.PageCount)
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetEditionsByWork : gives every edition of the work ordered by published book id
+func (bs Store) GetEditionsByWork(ctx context.Context, workID int) ([]entities.Edition, error) {
+	var editions []entities.Edition
+
+	rows, err := store.Executor(ctx, bs.DB).QueryContext(ctx, "SELECT e.book_id,e.work_id,b.title,e.isbn,e.format,"+
+		"e.page_count,b.publication,b.published_date FROM edition e JOIN book b ON b.id=e.book_id WHERE e.work_id=? "+
+		"ORDER BY e.book_id", workID)
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var edition entities.Edition
+
+		err = rows.Scan(&edition.BookID, &edition.WorkID, &edition.Title, &edition.ISBN, &edition.Format,
+			&edition.PageCount, &edition.Publisher, &edition.PublishedDate)
+		if err != nil {
+			return nil, err
+		}
+
+		editions = append(editions, edition)
+	}
+
+	return editions, nil
+}
+
+// GetRatings : gives the aggregate rating of the approved reviews for each of the books, books without reviews are left out
+func (bs Store) GetRatings(ctx context.Context, ids []int) (map[int]entities.Rating, error) {
+	ratings := make(map[int]entities.Rating)
+
+	if len(ids) == 0 {
+		return ratings, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i := range ids {
+		args[i] = ids[i]
+	}
+
+	rows, err := store.Executor(ctx, bs.DB).QueryContext(ctx, "SELECT book_id,AVG(rating),COUNT(*) FROM review "+
+		"WHERE status='approved' AND book_id IN (?"+strings.Repeat(",?", len(ids)-1)+") GROUP BY book_id", args...)
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id     int
+			rating entities.Rating
+		)
+
+		if err = rows.Scan(&id, &rating.Average, &rating.Count); err != nil {
+			return nil, err
+		}
+
+		ratings[id] = rating
+	}
+
+	return ratings, nil
+}
@@ -2,165 +2,242 @@ package book
 
 import (
 	"context"
+	"database/sql"
 	"errors"
-	"log"
 	"reflect"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 
 	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
 )
 
-// TestGetAllBook : to test GetAllBook
-func TestGetAllBook(t *testing.T) {
-	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		log.Print(err)
-	}
+// bookRows : the rows of the columns of a book
+func bookRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "author_id", "title", "publication", "published_date", "updated_at"})
+}
 
+// TestGetBooksByTitle : to test GetBooksByTitle
+func TestGetBooksByTitle(t *testing.T) {
 	var (
 		book1 = entities.Book{BookID: 1, AuthorID: 1, Title: "book one", Publication: "penguin",
-			PublishedDate: "20/06/2000", Author: entities.Author{},
-		}
+			PublishedDate: "20/06/2000", UpdatedAt: "2022-05-01T10:00:00Z"}
 
-		book2 = entities.Book{BookID: 2, AuthorID: 1, Title: "book two", Publication: "penguin",
-			PublishedDate: "20/06/2000", Author: entities.Author{},
-		}
-
-		books = sqlmock.NewRows([]string{"id", "author_id", "title", "publication", "published_date"}).
-			AddRow(book1.BookID, book1.AuthorID, book1.Title, book1.Publication, book1.PublishedDate).AddRow(book2.BookID,
-			book2.AuthorID, book2.Title, book2.Publication, book2.PublishedDate)
+		book2 = entities.Book{BookID: 2, AuthorID: 1, Title: "book one", Publication: "penguin",
+			PublishedDate: "20/06/2000", UpdatedAt: "2022-05-02T10:00:00Z"}
 	)
 
-	Testcases := []struct {
-		desc string
+	testcases := []struct {
+		desc     string
+		title    string
+		rows     *sqlmock.Rows
+		queryErr error
 
 		expected    []entities.Book
-		expectedErr error
+		expectedErr bool
 	}{
-		{desc: "getting all books", expected: []entities.Book{book1, book2}, expectedErr: nil},
-		{desc: "getting all books", expected: []entities.Book{}, expectedErr: errors.New("syntax error")},
+		{desc: "books with the title", title: "book one", rows: bookRows().
+			AddRow(book1.BookID, book1.AuthorID, book1.Title, book1.Publication, book1.PublishedDate, book1.UpdatedAt).
+			AddRow(book2.BookID, book2.AuthorID, book2.Title, book2.Publication, book2.PublishedDate, book2.UpdatedAt),
+			expected: []entities.Book{book1, book2}},
+		{desc: "no book", title: "unknown", rows: bookRows()},
+		{desc: "query error", title: "book one", rows: bookRows(), queryErr: errors.New("syntax error"),
+			expectedErr: true},
+		{desc: "scan error", title: "book one", rows: bookRows().AddRow("one", 1, "book one", "penguin", "20/06/2000",
+			"2022-05-01T10:00:00Z"), expectedErr: true},
 	}
 
-	for _, tc := range Testcases {
-		bs := New(db)
-
-		mock.ExpectQuery("SELECT * FROM book").WithArgs().WillReturnRows(books).WillReturnError(tc.expectedErr)
-
-		b, err := bs.GetAllBook(context.TODO())
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 		if err != nil {
-			log.Print(err)
+			t.Fatalf("error during the opening of database:%v\n", err)
 		}
 
-		if !reflect.DeepEqual(b, tc.expected) {
-			t.Errorf("failed for %s ", tc.desc)
+		mock.ExpectQuery("SELECT " + columns + " FROM book WHERE title=?").WithArgs(tc.title).WillReturnRows(tc.rows).
+			WillReturnError(tc.queryErr)
+
+		books, err := New(db).GetBooksByTitle(context.TODO(), tc.title)
+
+		if !reflect.DeepEqual(books, tc.expected) || (err != nil) != tc.expectedErr {
+			t.Errorf("failed for %s, got: %v %v", tc.desc, books, err)
 		}
+
+		db.Close()
 	}
 }
 
-// TestGetBooksByTitle : to test GetBooksByTitle
-func TestGetBooksByTitle(t *testing.T) {
-	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		log.Print(err)
+// TestGetBookByID : to test GetBookByID
+func TestGetBookByID(t *testing.T) {
+	book := entities.Book{BookID: 1, AuthorID: 1, Title: "book one", Publication: "penguin",
+		PublishedDate: "20/06/2000", UpdatedAt: "2022-05-01T10:00:00Z"}
+
+	testcases := []struct {
+		desc     string
+		targetID int
+		rows     *sqlmock.Rows
+
+		expected    entities.Book
+		expectedErr error
+	}{
+		{desc: "fetching book by id", targetID: 1, rows: bookRows().AddRow(book.BookID, book.AuthorID, book.Title,
+			book.Publication, book.PublishedDate, book.UpdatedAt), expected: book},
+		{desc: "not existing", targetID: 9, rows: bookRows(), expected: entities.Book{}, expectedErr: sql.ErrNoRows},
 	}
 
-	var (
-		book1 = entities.Book{BookID: 1, AuthorID: 1, Title: "book one", Publication: "penguin",
-			PublishedDate: "20/06/2000", Author: entities.Author{},
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
 		}
 
-		book2 = entities.Book{BookID: 2, AuthorID: 1, Title: "book one", Publication: "penguin",
-			PublishedDate: "20/06/2000", Author: entities.Author{},
-		}
+		mock.ExpectQuery("select " + columns + " from book where id=?").WithArgs(tc.targetID).WillReturnRows(tc.rows)
 
-		book3 = entities.Book{}
+		b, err := New(db).GetBookByID(context.TODO(), tc.targetID)
 
-		books = sqlmock.NewRows([]string{"id", "author_id", "title", "publication", "published_date"}).
-			AddRow(book1.BookID, book1.AuthorID, book1.Title, book1.Publication, book1.PublishedDate).AddRow(book2.BookID,
-			book2.AuthorID, book2.Title, book2.Publication, book2.PublishedDate)
+		if !reflect.DeepEqual(b, tc.expected) || !errors.Is(err, tc.expectedErr) {
+			t.Errorf("failed for %v, got: %v %v\n", tc.desc, b, err)
+		}
 
-		books1 = sqlmock.NewRows([]string{"id", "author_id", "title", "publication", "published_date"}).
-			AddRow(book3.BookID, book3.AuthorID, book3.Title, book3.Publication, book3.PublishedDate)
-	)
+		db.Close()
+	}
+}
 
-	Testcases := []struct {
-		desc  string
-		title string
+// TestGetBookByISBN : to test fetching the book of an edition
+func TestGetBookByISBN(t *testing.T) {
+	book := entities.Book{BookID: 3, AuthorID: 1, Title: "Dune", Publication: "penguin",
+		PublishedDate: "01/08/1965", UpdatedAt: "2022-05-01T10:00:00Z"}
 
-		expected    []entities.Book
+	testcases := []struct {
+		desc string
+		isbn string
+		rows *sqlmock.Rows
+
+		expected    entities.Book
 		expectedErr error
 	}{
-		{desc: "getting all books", title: "book one", expected: []entities.Book{book1, book2}, expectedErr: nil},
-		{desc: "invalid case", title: "", expected: []entities.Book{}, expectedErr: errors.New("syntax error")},
-		{desc: "scan error", title: "unique", expected: []entities.Book{book3}, expectedErr: nil},
+		{desc: "edition of the book", isbn: "9780441013593", rows: bookRows().AddRow(book.BookID, book.AuthorID,
+			book.Title, book.Publication, book.PublishedDate, book.UpdatedAt), expected: book},
+		{desc: "unknown isbn", isbn: "9780000000000", rows: bookRows(), expectedErr: sql.ErrNoRows},
 	}
 
-	for _, tc := range Testcases {
-		bs := New(db)
-
-		if tc.title != "unique" {
-			mock.ExpectQuery("SELECT * FROM book WHERE title=?").WithArgs().WillReturnRows(books).
-				WillReturnError(tc.expectedErr)
-		} else {
-			mock.ExpectQuery("SELECT * FROM book WHERE title=?").WithArgs().WillReturnRows(books1).
-				WillReturnError(tc.expectedErr)
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
 		}
 
-		b, err := bs.GetBooksByTitle(context.TODO(), tc.title)
+		mock.ExpectQuery("SELECT b.id,b.author_id,b.title,b.publication,b.published_date," + store.UpdatedAt("b") +
+			" FROM book b JOIN edition e ON e.book_id=b.id WHERE e.isbn=?").WithArgs(tc.isbn).WillReturnRows(tc.rows)
 
-		if err != nil {
-			log.Print(err)
-		}
+		b, err := New(db).GetBookByISBN(context.TODO(), tc.isbn)
 
-		if !reflect.DeepEqual(b, tc.expected) {
-			t.Errorf("failed for %s", tc.desc)
+		if !reflect.DeepEqual(b, tc.expected) || !errors.Is(err, tc.expectedErr) {
+			t.Errorf("failed for %v, got: %v %v\n", tc.desc, b, err)
 		}
+
+		db.Close()
 	}
 }
 
-// TestGetBookByID : to test GetBookByID
-func TestGetBookByID(t *testing.T) {
-	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		log.Print(err)
+// TestBookQuery : to test the query built for a filter and a projection
+func TestBookQuery(t *testing.T) {
+	testcases := []struct {
+		desc       string
+		filter     entities.BookFilter
+		projection entities.Projection
+
+		expectedQuery  string
+		expectedArgs   []interface{}
+		expectedFields []string
+	}{
+		{desc: "every book with every field",
+			expectedQuery: "SELECT id,book.author_id,title,book.publication,published_date," + store.UpdatedAt("book") +
+				" FROM book ORDER BY id",
+			expectedFields: []string{"bookID", "authorID", "title", "publication", "publishedDate", "updatedAt"}},
+		{desc: "filtered page of titles",
+			filter:     entities.BookFilter{Title: "Dune", Tags: []string{"kids"}, After: 5, Limit: 10},
+			projection: entities.Projection{Fields: []string{"title"}},
+			expectedQuery: "SELECT id,title FROM book WHERE title=? AND id IN (SELECT bt.book_id FROM book_tag bt " +
+				"JOIN tag t ON t.tag_id=bt.tag_id WHERE t.name=?) AND book.id>? ORDER BY id LIMIT ?",
+			expectedArgs: []interface{}{"Dune", "kids", 5, 10}, expectedFields: []string{"bookID", "title"}},
+		{desc: "genre by id with the expanded author",
+			filter: entities.BookFilter{Genre: "3"},
+			projection: entities.Projection{Fields: []string{"title", "author.penName"},
+				Expand: []string{"author"}},
+			expectedQuery: "WITH RECURSIVE genre_tree AS (SELECT genre_id FROM genre WHERE genre_id=? UNION ALL " +
+				"SELECT g.genre_id FROM genre g JOIN genre_tree t ON g.parent_id=t.genre_id) " +
+				"SELECT id,a.author_id,title,a.pen_name FROM book LEFT JOIN author a ON a.author_id=book.author_id " +
+				"WHERE id IN (SELECT book_id FROM book_genre WHERE genre_id IN (SELECT genre_id FROM genre_tree)) " +
+				"ORDER BY id",
+			expectedArgs: []interface{}{"3"}, expectedFields: []string{"bookID", "author.authorID", "title",
+				"author.penName"}},
+		{desc: "expanded publisher",
+			projection: entities.Projection{Fields: []string{"publisher"}, Expand: []string{"publisher"}},
+			expectedQuery: "SELECT id,book.publication,p.books FROM book LEFT JOIN (SELECT publication,COUNT(*) " +
+				"AS books FROM book GROUP BY publication) p ON p.publication=book.publication ORDER BY id",
+			expectedFields: []string{"bookID", "publisher.name", "publisher.books"}},
 	}
 
-	var (
-		book = entities.Book{BookID: 1,
-			AuthorID: 1, Title: "book one", Publication: "penguin", PublishedDate: "20/06/2000"}
-		book1 = sqlmock.NewRows([]string{"id", "author_id", "title", "publication", "published_date"}).
-			AddRow(book.BookID, book.AuthorID, book.Title, book.Publication, book.PublishedDate)
-	)
+	for _, tc := range testcases {
+		query, args, fields := bookQuery(tc.filter, tc.projection)
 
-	Testcases := []struct {
-		desc     string
-		targetID int
+		if query != tc.expectedQuery || !reflect.DeepEqual(args, tc.expectedArgs) ||
+			!reflect.DeepEqual(fields, tc.expectedFields) {
+			t.Errorf("failed for %s, got: %q %v %v", tc.desc, query, args, fields)
+		}
+	}
+}
 
-		expected    entities.Book
-		expectedErr error
-	}{
-		{desc: "fetching book by id",
-			targetID: 1, expected: entities.Book{BookID: 1,
-				AuthorID: 1, Title: "book one", Publication: "penguin", PublishedDate: "20/06/2000"}, expectedErr: nil},
+// TestEachBook : to test reading a page of books with the expanded author
+func TestEachBook(t *testing.T) {
+	filter := entities.BookFilter{After: 2, Limit: 2}
+	projection := entities.Projection{Fields: []string{"title", "author.firstName"}, Expand: []string{"author"}}
+	query := "SELECT id,a.author_id,title,a.first_name FROM book LEFT JOIN author a ON a.author_id=book.author_id " +
+		"WHERE book.id>? ORDER BY id LIMIT ?"
 
-		{"invalid id", -1, entities.Book{}, errors.New("invalid")},
+	rows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "author_id", "title", "first_name"}).
+			AddRow(3, 1, "Dune", "Frank").AddRow(4, nil, "Emma", nil)
 	}
 
-	for _, tc := range Testcases {
-		bs := New(db)
+	testcases := []struct {
+		desc     string
+		queryErr error
+		fnErr    error
 
-		mock.ExpectQuery("select * from book where id=?").WithArgs(tc.targetID).WillReturnRows(book1).WillReturnError(tc.expectedErr)
+		expected    []entities.Book
+		expectedErr error
+	}{
+		{desc: "page of books", expected: []entities.Book{
+			{BookID: 3, Title: "Dune", Author: &entities.Author{AuthorID: 1, FirstName: "Frank"}},
+			{BookID: 4, Title: "Emma"}}},
+		{desc: "error of fn", fnErr: errors.New("client gone"), expected: []entities.Book{
+			{BookID: 3, Title: "Dune", Author: &entities.Author{AuthorID: 1, FirstName: "Frank"}}},
+			expectedErr: errors.New("client gone")},
+		{desc: "query error", queryErr: errors.New("syntax error"), expectedErr: errors.New("syntax error")},
+	}
 
-		b, err := bs.GetBookByID(context.TODO(), tc.targetID)
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 		if err != nil {
-			log.Print(err)
+			t.Fatalf("error during the opening of database:%v\n", err)
 		}
 
-		if b != tc.expected || err != tc.expectedErr {
-			t.Errorf("failed for %v\n", tc.desc)
+		mock.ExpectQuery(query).WithArgs(2, 2).WillReturnRows(rows()).WillReturnError(tc.queryErr)
+
+		var books []entities.Book
+
+		err = New(db).EachBook(context.TODO(), filter, projection, func(book entities.Book) error {
+			books = append(books, book)
+			return tc.fnErr
+		})
+
+		if !reflect.DeepEqual(books, tc.expected) || !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %s, got: %v %v", tc.desc, books, err)
 		}
+
+		db.Close()
 	}
 }
 
@@ -175,15 +252,15 @@ func TestPost(t *testing.T) {
 		LastInserted int64
 	}{
 		{desc: "valid book", input: entities.Book{BookID: 1, AuthorID: 1, Title: "book one", Publication: "penguin",
-			PublishedDate: "20/06/2000", Author: entities.Author{}},
+			PublishedDate: "20/06/2000"},
 			expectedErr: nil, RowAffected: 1, LastInserted: 15,
 		},
 		{desc: "exiting book", input: entities.Book{BookID: 1, AuthorID: 1, Title: "book one", Publication: "penguin",
-			PublishedDate: "20/06/2000", Author: entities.Author{}},
+			PublishedDate: "20/06/2000"},
 			expectedErr: errors.New("already exists"), RowAffected: 0, LastInserted: 0,
 		},
 		{desc: "error case", input: entities.Book{BookID: 3, AuthorID: 1, Title: "book one", Publication: "penguin",
-			PublishedDate: "20/06/2000", Author: entities.Author{}},
+			PublishedDate: "20/06/2000"},
 			expectedErr: errors.New("last inserted error"), RowAffected: 1, LastInserted: 15,
 		},
 	}
@@ -207,9 +284,11 @@ func TestPost(t *testing.T) {
 		bs := New(db)
 
 		_, err = bs.Post(context.TODO(), &tc.input)
-		if err != tc.expectedErr {
+		if !reflect.DeepEqual(err, tc.expectedErr) {
 			t.Errorf("failed for %s", tc.desc)
 		}
+
+		db.Close()
 	}
 }
 
@@ -225,15 +304,15 @@ func TestPut(t *testing.T) {
 		LastInserted int64
 	}{
 		{desc: "not existing book", input: entities.Book{BookID: 1, AuthorID: 1, Title: "book one", Publication: "penguin",
-			PublishedDate: "20/06/2000", Author: entities.Author{}}, targetID: -1,
+			PublishedDate: "20/06/2000"}, targetID: -1,
 			expectedErr: errors.New("does not exist"), RowAffected: 0, LastInserted: 0,
 		},
 		{desc: "exiting book", input: entities.Book{BookID: 12, AuthorID: 1, Title: "book one", Publication: "penguin",
-			PublishedDate: "20/06/2000", Author: entities.Author{}}, targetID: 4,
+			PublishedDate: "20/06/2000"}, targetID: 4,
 			expectedErr: nil, RowAffected: 1, LastInserted: 15,
 		},
 		{desc: "error case", input: entities.Book{BookID: 13, AuthorID: 1, Title: "book one", Publication: "penguin",
-			PublishedDate: "20/06/2000", Author: entities.Author{}}, targetID: 4,
+			PublishedDate: "20/06/2000"}, targetID: 4,
 			expectedErr: errors.New("database error"), RowAffected: 1, LastInserted: 15,
 		},
 	}
@@ -245,25 +324,76 @@ func TestPut(t *testing.T) {
 		}
 
 		if tc.input.BookID != 13 {
-			mock.ExpectExec("update book set id=?,author_id=?,title=?,publication=?,published_date=? where id=?").
-				WithArgs(tc.input.BookID, tc.input.AuthorID, tc.input.Title, tc.input.Publication, tc.input.PublishedDate, tc.targetID).
+			mock.ExpectExec("update book set author_id=?,title=?,publication=?,published_date=? where id=?").
+				WithArgs(tc.input.AuthorID, tc.input.Title, tc.input.Publication, tc.input.PublishedDate, tc.targetID).
 				WillReturnResult(sqlmock.NewResult(tc.LastInserted, tc.RowAffected)).WillReturnError(tc.expectedErr)
+
+			if tc.expectedErr == nil {
+				mock.ExpectExec(touchQuery).WithArgs(tc.targetID, tc.targetID).WillReturnResult(sqlmock.NewResult(0, 2))
+			}
 		} else {
-			mock.ExpectExec("update book set id=?,author_id=?,title=?,publication=?,published_date=? where id=?").
-				WithArgs(tc.input.BookID, tc.input.AuthorID, tc.input.Title, tc.input.Publication, tc.input.PublishedDate, tc.targetID).
+			mock.ExpectExec("update book set author_id=?,title=?,publication=?,published_date=? where id=?").
+				WithArgs(tc.input.AuthorID, tc.input.Title, tc.input.Publication, tc.input.PublishedDate, tc.targetID).
 				WillReturnResult(sqlmock.NewErrorResult(tc.expectedErr)).WillReturnError(nil)
 		}
 
 		bs := New(db)
 
 		_, err = bs.Put(context.TODO(), &tc.input, tc.targetID)
-		if err != tc.expectedErr {
+		if !reflect.DeepEqual(err, tc.expectedErr) || mock.ExpectationsWereMet() != nil {
 			t.Errorf("failed for %s", tc.desc)
 		}
+
+		db.Close()
+	}
+}
+
+// TestGetRelated : to test reading the books shown with a book
+func TestGetRelated(t *testing.T) {
+	testcases := []struct {
+		desc string
+		rows *sqlmock.Rows
+		err  error
+
+		expected    []int
+		expectedErr error
+	}{
+		{desc: "neighbours and editions", rows: sqlmock.NewRows([]string{"book_id"}).AddRow(4).AddRow(7),
+			expected: []int{4, 7}},
+		{desc: "alone", rows: sqlmock.NewRows([]string{"book_id"})},
+		{desc: "error", err: errors.New("connection lost"), expectedErr: errors.New("connection lost")},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		query := mock.ExpectQuery(relatedQuery).WithArgs(3, 3)
+		if tc.err != nil {
+			query.WillReturnError(tc.err)
+		} else {
+			query.WillReturnRows(tc.rows)
+		}
+
+		ids, err := New(db).GetRelated(context.TODO(), 3)
+
+		if !reflect.DeepEqual(ids, tc.expected) || !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %v, got: %v %v\n", tc.desc, ids, err)
+		}
+
+		db.Close()
 	}
 }
 
-// TestDelete : to test delete method
+// touchQuery : the update of the books shown with a changed book
+const touchQuery = "UPDATE book SET updated_at=CURRENT_TIMESTAMP WHERE id IN (SELECT sb.book_id FROM series_book sb " +
+	"JOIN series_book own ON own.series_id=sb.series_id WHERE own.book_id=? AND sb.book_id<>own.book_id UNION " +
+	"SELECT e.book_id FROM edition e JOIN edition own ON own.work_id=e.work_id WHERE own.book_id=? " +
+	"AND e.book_id<>own.book_id)"
+
+// TestDelete : to test delete method, the books shown with it are touched first
 func TestDelete(t *testing.T) {
 	testcases := []struct {
 		// input
@@ -282,11 +412,13 @@ func TestDelete(t *testing.T) {
 	for _, tc := range testcases {
 		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 		if err != nil {
-			log.Print(err)
+			t.Fatalf("error during the opening of database:%v\n", err)
 		}
 
 		bs := New(db)
 
+		mock.ExpectExec(touchQuery).WithArgs(tc.target, tc.target).WillReturnResult(sqlmock.NewResult(0, 0))
+
 		if tc.target != 100 {
 			mock.ExpectExec("delete from book where id=?").WithArgs(tc.target).
 				WillReturnResult(sqlmock.NewResult(tc.lastInsertedID, tc.rowsAffected)).WillReturnError(tc.expectedErr)
@@ -296,8 +428,413 @@ func TestDelete(t *testing.T) {
 		}
 
 		_, err = bs.Delete(context.TODO(), tc.target)
-		if err != tc.expectedErr {
+		if !reflect.DeepEqual(err, tc.expectedErr) || mock.ExpectationsWereMet() != nil {
 			t.Errorf("failed for %v\n", tc.desc)
 		}
+
+		db.Close()
+	}
+}
+
+// TestGetGenres : to test fetching the genres of a book
+func TestGetGenres(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		rows     *sqlmock.Rows
+		queryErr error
+
+		expected    []entities.Genre
+		expectedErr bool
+	}{
+		{desc: "genres of the book", rows: sqlmock.NewRows([]string{"genre_id", "name", "parent_id"}).
+			AddRow(1, "Fiction", nil).AddRow(2, "Fantasy", 1),
+			expected: []entities.Genre{{GenreID: 1, Name: "Fiction"}, {GenreID: 2, Name: "Fantasy", ParentID: 1}}},
+		{desc: "no genre", rows: sqlmock.NewRows([]string{"genre_id", "name", "parent_id"})},
+		{desc: "query error", rows: sqlmock.NewRows([]string{"genre_id", "name", "parent_id"}),
+			queryErr: errors.New("syntax error"), expectedErr: true},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectQuery("SELECT g.genre_id,g.name,g.parent_id FROM genre g JOIN book_genre bg ON " +
+			"bg.genre_id=g.genre_id WHERE bg.book_id=? ORDER BY g.genre_id").WithArgs(1).WillReturnRows(tc.rows).
+			WillReturnError(tc.queryErr)
+
+		genres, err := New(db).GetGenres(context.TODO(), 1)
+
+		if !reflect.DeepEqual(genres, tc.expected) || (err != nil) != tc.expectedErr {
+			t.Errorf("failed for %s, got: %v %v", tc.desc, genres, err)
+		}
+
+		db.Close()
+	}
+}
+
+// TestSetGenres : to test replacing the genres of a book
+func TestSetGenres(t *testing.T) {
+	testcases := []struct {
+		desc      string
+		genreIDs  []int
+		deleteErr error
+		insertErr error
+
+		expectedErr error
+	}{
+		{desc: "replace the genres", genreIDs: []int{1, 2}},
+		{desc: "clear the genres"},
+		{desc: "delete error", genreIDs: []int{1}, deleteErr: errors.New("lock timeout"),
+			expectedErr: errors.New("lock timeout")},
+		{desc: "unknown genre", genreIDs: []int{9}, insertErr: errors.New("foreign key"),
+			expectedErr: errors.New("foreign key")},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectExec("delete from book_genre where book_id=?").WithArgs(4).
+			WillReturnResult(sqlmock.NewResult(0, 1)).WillReturnError(tc.deleteErr)
+
+		if tc.deleteErr == nil {
+			for _, id := range tc.genreIDs {
+				mock.ExpectExec("insert into book_genre(book_id,genre_id)values(?,?)").WithArgs(4, id).
+					WillReturnResult(sqlmock.NewResult(0, 1)).WillReturnError(tc.insertErr)
+			}
+		}
+
+		err = New(db).SetGenres(context.TODO(), 4, tc.genreIDs)
+
+		if !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %s, got: %v", tc.desc, err)
+		}
+
+		if tc.insertErr == nil {
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("failed for %s: %v", tc.desc, err)
+			}
+		}
+
+		db.Close()
+	}
+}
+
+// TestGetTags : to test fetching the tags of a book
+func TestGetTags(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		rows     *sqlmock.Rows
+		queryErr error
+
+		expected    []string
+		expectedErr bool
+	}{
+		{desc: "tags of the book", rows: sqlmock.NewRows([]string{"name"}).AddRow("classic").AddRow("kids"),
+			expected: []string{"classic", "kids"}},
+		{desc: "no tag", rows: sqlmock.NewRows([]string{"name"})},
+		{desc: "query error", rows: sqlmock.NewRows([]string{"name"}), queryErr: errors.New("syntax error"),
+			expectedErr: true},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectQuery("SELECT t.name FROM tag t JOIN book_tag bt ON bt.tag_id=t.tag_id WHERE bt.book_id=? " +
+			"ORDER BY t.name").WithArgs(1).WillReturnRows(tc.rows).WillReturnError(tc.queryErr)
+
+		tags, err := New(db).GetTags(context.TODO(), 1)
+
+		if !reflect.DeepEqual(tags, tc.expected) || (err != nil) != tc.expectedErr {
+			t.Errorf("failed for %s, got: %v %v", tc.desc, tags, err)
+		}
+
+		db.Close()
+	}
+}
+
+// TestSetTags : to test replacing the tags of a book, creating the new ones
+func TestSetTags(t *testing.T) {
+	testcases := []struct {
+		desc      string
+		tags      []string
+		createErr error
+		attachErr error
+
+		expectedErr error
+	}{
+		{desc: "replace the tags", tags: []string{"classic", "kids"}},
+		{desc: "clear the tags"},
+		{desc: "create error", tags: []string{"kids"}, createErr: errors.New("lock timeout"),
+			expectedErr: errors.New("lock timeout")},
+		{desc: "attach error", tags: []string{"kids"}, attachErr: errors.New("lock timeout"),
+			expectedErr: errors.New("lock timeout")},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectExec("delete from book_tag where book_id=?").WithArgs(4).WillReturnResult(sqlmock.NewResult(0, 2))
+
+		for _, tag := range tc.tags {
+			mock.ExpectExec("insert ignore into tag(name)values(?)").WithArgs(tag).
+				WillReturnResult(sqlmock.NewResult(1, 1)).WillReturnError(tc.createErr)
+
+			if tc.createErr != nil {
+				break
+			}
+
+			mock.ExpectExec("insert into book_tag(book_id,tag_id) select ?,tag_id from tag where name=?").
+				WithArgs(4, tag).WillReturnResult(sqlmock.NewResult(0, 1)).WillReturnError(tc.attachErr)
+		}
+
+		err = New(db).SetTags(context.TODO(), 4, tc.tags)
+
+		if !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %s, got: %v", tc.desc, err)
+		}
+
+		if err = mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("failed for %s: %v", tc.desc, err)
+		}
+
+		db.Close()
+	}
+}
+
+// TestGetSeries : to test fetching the series of a book with its neighbouring books
+func TestGetSeries(t *testing.T) {
+	query := "WITH ordered AS (SELECT sb.series_id,sb.book_id,sb.position," +
+		"LAG(sb.book_id) OVER w AS prev_id,LAG(b.title) OVER w AS prev_title,LAG(sb.position) OVER w AS prev_position," +
+		"LEAD(sb.book_id) OVER w AS next_id,LEAD(b.title) OVER w AS next_title,LEAD(sb.position) OVER w AS next_position " +
+		"FROM series_book sb JOIN book b ON b.id=sb.book_id WINDOW w AS (PARTITION BY sb.series_id ORDER BY sb.position)) " +
+		"SELECT o.series_id,s.name,o.position,o.prev_id,o.prev_title,o.prev_position,o.next_id,o.next_title,o.next_position " +
+		"FROM ordered o JOIN series s ON s.series_id=o.series_id WHERE o.book_id=? ORDER BY o.series_id"
+	names := []string{"series_id", "name", "position", "prev_id", "prev_title", "prev_position", "next_id",
+		"next_title", "next_position"}
+
+	testcases := []struct {
+		desc     string
+		rows     *sqlmock.Rows
+		queryErr error
+
+		expected    []entities.BookSeries
+		expectedErr bool
+	}{
+		{desc: "middle and first of a series", rows: sqlmock.NewRows(names).
+			AddRow(1, "Dune", 2.0, 1, "Dune", 1.0, 3, "Children of Dune", 3.0).
+			AddRow(2, "Chronicles", 1.0, nil, nil, nil, 5, "Heretics", 2.0),
+			expected: []entities.BookSeries{
+				{SeriesID: 1, Name: "Dune", Position: 2, Previous: &entities.SeriesBook{BookID: 1, Title: "Dune",
+					Position: 1}, Next: &entities.SeriesBook{BookID: 3, Title: "Children of Dune", Position: 3}},
+				{SeriesID: 2, Name: "Chronicles", Position: 1, Next: &entities.SeriesBook{BookID: 5, Title: "Heretics",
+					Position: 2}}}},
+		{desc: "no series", rows: sqlmock.NewRows(names)},
+		{desc: "query error", rows: sqlmock.NewRows(names), queryErr: errors.New("syntax error"), expectedErr: true},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectQuery(query).WithArgs(2).WillReturnRows(tc.rows).WillReturnError(tc.queryErr)
+
+		series, err := New(db).GetSeries(context.TODO(), 2)
+
+		if !reflect.DeepEqual(series, tc.expected) || (err != nil) != tc.expectedErr {
+			t.Errorf("failed for %s, got: %v %v", tc.desc, series, err)
+		}
+
+		db.Close()
+	}
+}
+
+// TestGetSeriesByBooks : to test fetching the series of a listing in one query
+func TestGetSeriesByBooks(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT sb.book_id,s.series_id,s.name,sb.position FROM series_book sb JOIN series s ON "+
+		"s.series_id=sb.series_id WHERE sb.book_id IN (?,?) ORDER BY sb.book_id,s.series_id").WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "series_id", "name", "position"}).
+			AddRow(1, 1, "Dune", 1.0).AddRow(2, 1, "Dune", 2.0).AddRow(2, 4, "Classics", 7.0))
+
+	series, err := New(db).GetSeriesByBooks(context.TODO(), []int{1, 2})
+
+	expected := map[int][]entities.BookSeries{
+		1: {{SeriesID: 1, Name: "Dune", Position: 1}},
+		2: {{SeriesID: 1, Name: "Dune", Position: 2}, {SeriesID: 4, Name: "Classics", Position: 7}},
+	}
+
+	if err != nil || !reflect.DeepEqual(series, expected) {
+		t.Errorf("unexpected series %v %v", series, err)
+	}
+
+	// no query is made for an empty listing
+	series, err = New(db).GetSeriesByBooks(context.TODO(), nil)
+	if err != nil || len(series) != 0 {
+		t.Errorf("unexpected series of no book %v %v", series, err)
+	}
+}
+
+// TestGetEdition : to test fetching the edition details of a book
+func TestGetEdition(t *testing.T) {
+	names := []string{"book_id", "work_id", "title", "isbn", "format", "page_count", "publication", "published_date"}
+	edition := entities.Edition{BookID: 3, WorkID: 1, Title: "Dune", ISBN: "9780441013593", Format: "paperback",
+		PageCount: 617, Publisher: "penguin", PublishedDate: "01/08/1965"}
+
+	testcases := []struct {
+		desc     string
+		rows     *sqlmock.Rows
+		queryErr error
+
+		expected    entities.Edition
+		expectedErr bool
+	}{
+		{desc: "edition of a work", rows: sqlmock.NewRows(names).AddRow(edition.BookID, edition.WorkID, edition.Title,
+			edition.ISBN, edition.Format, edition.PageCount, edition.Publisher, edition.PublishedDate),
+			expected: edition},
+		{desc: "book outside of a work", rows: sqlmock.NewRows(names)},
+		{desc: "query error", rows: sqlmock.NewRows(names), queryErr: errors.New("syntax error"), expectedErr: true},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectQuery("SELECT e.book_id,e.work_id,b.title,e.isbn,e.format,e.page_count,b.publication," +
+			"b.published_date FROM edition e JOIN book b ON b.id=e.book_id WHERE e.book_id=?").WithArgs(3).
+			WillReturnRows(tc.rows).WillReturnError(tc.queryErr)
+
+		e, err := New(db).GetEdition(context.TODO(), 3)
+
+		if !reflect.DeepEqual(e, tc.expected) || (err != nil) != tc.expectedErr {
+			t.Errorf("failed for %s, got: %v %v", tc.desc, e, err)
+		}
+
+		db.Close()
+	}
+}
+
+// TestSetEdition : to test storing the edition details of a book
+func TestSetEdition(t *testing.T) {
+	edition := entities.Edition{WorkID: 1, ISBN: "9780441013593", Format: "paperback", PageCount: 617}
+
+	testcases := []struct {
+		desc    string
+		execErr error
+
+		expectedErr error
+	}{
+		{desc: "stored edition"},
+		{desc: "duplicate isbn", execErr: errors.New("duplicate entry"), expectedErr: errors.New("duplicate entry")},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectExec("insert into edition(book_id,work_id,isbn,format,page_count)values(?,?,?,?,?) "+
+			"on duplicate key update work_id=values(work_id),isbn=values(isbn),format=values(format),"+
+			"page_count=values(page_count)").WithArgs(3, edition.WorkID, edition.ISBN, edition.Format,
+			edition.PageCount).WillReturnResult(sqlmock.NewResult(0, 1)).WillReturnError(tc.execErr)
+
+		err = New(db).SetEdition(context.TODO(), 3, edition)
+
+		if !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %s, got: %v", tc.desc, err)
+		}
+
+		db.Close()
+	}
+}
+
+// TestGetEditionsByWork : to test fetching every edition of a work
+func TestGetEditionsByWork(t *testing.T) {
+	names := []string{"book_id", "work_id", "title", "isbn", "format", "page_count", "publication", "published_date"}
+
+	testcases := []struct {
+		desc     string
+		rows     *sqlmock.Rows
+		queryErr error
+
+		expected    []entities.Edition
+		expectedErr bool
+	}{
+		{desc: "editions of the work", rows: sqlmock.NewRows(names).
+			AddRow(3, 1, "Dune", "9780441013593", "paperback", 617, "penguin", "01/08/1965").
+			AddRow(8, 1, "Dune", "9780340960196", "hardcover", 592, "hodder", "01/01/2005"),
+			expected: []entities.Edition{
+				{BookID: 3, WorkID: 1, Title: "Dune", ISBN: "9780441013593", Format: "paperback", PageCount: 617,
+					Publisher: "penguin", PublishedDate: "01/08/1965"},
+				{BookID: 8, WorkID: 1, Title: "Dune", ISBN: "9780340960196", Format: "hardcover", PageCount: 592,
+					Publisher: "hodder", PublishedDate: "01/01/2005"}}},
+		{desc: "query error", rows: sqlmock.NewRows(names), queryErr: errors.New("syntax error"), expectedErr: true},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectQuery("SELECT e.book_id,e.work_id,b.title,e.isbn,e.format,e.page_count,b.publication," +
+			"b.published_date FROM edition e JOIN book b ON b.id=e.book_id WHERE e.work_id=? ORDER BY e.book_id").
+			WithArgs(1).WillReturnRows(tc.rows).WillReturnError(tc.queryErr)
+
+		editions, err := New(db).GetEditionsByWork(context.TODO(), 1)
+
+		if !reflect.DeepEqual(editions, tc.expected) || (err != nil) != tc.expectedErr {
+			t.Errorf("failed for %s, got: %v %v", tc.desc, editions, err)
+		}
+
+		db.Close()
+	}
+}
+
+// TestGetRatings : to test the aggregate ratings of the approved reviews of a listing
+func TestGetRatings(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT book_id,AVG(rating),COUNT(*) FROM review WHERE status='approved' AND "+
+		"book_id IN (?,?,?) GROUP BY book_id").WithArgs(1, 2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "avg", "count"}).AddRow(1, 4.5, 2).AddRow(3, 3.0, 1))
+
+	ratings, err := New(db).GetRatings(context.TODO(), []int{1, 2, 3})
+
+	expected := map[int]entities.Rating{1: {Average: 4.5, Count: 2}, 3: {Average: 3, Count: 1}}
+
+	if err != nil || !reflect.DeepEqual(ratings, expected) {
+		t.Errorf("unexpected ratings %v %v", ratings, err)
+	}
+
+	mock.ExpectQuery("SELECT book_id,AVG(rating),COUNT(*) FROM review WHERE status='approved' AND " +
+		"book_id IN (?) GROUP BY book_id").WithArgs(4).WillReturnError(errors.New("syntax error"))
+
+	if _, err = New(db).GetRatings(context.TODO(), []int{4}); err == nil {
+		t.Errorf("expected the error of the query")
 	}
 }
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+// AuthorStore : reads single authors through the cache, every other call goes to the wrapped store
+type AuthorStore struct {
+	store.AuthorStorer
+	rt readThrough
+}
+
+// NewAuthorStore : factory function, authors are cached for the ttl unless they change before
+func NewAuthorStore(s store.AuthorStorer, c Cache, ttl time.Duration, m *Metrics) AuthorStore {
+	return AuthorStore{s, newReadThrough("author", c, ttl, m)}
+}
+
+func authorKey(id int) string {
+	return "author:" + strconv.Itoa(id)
+}
+
+// IncludeAuthor : gives the author from the cache, reads inside a transaction go to the store as they may see
+// changes which are rolled back
+func (s AuthorStore) IncludeAuthor(ctx context.Context, id int) (entities.Author, error) {
+	if store.InTx(ctx) {
+		return s.AuthorStorer.IncludeAuthor(ctx, id)
+	}
+
+	var author entities.Author
+
+	err := s.rt.get(ctx, authorKey(id), &author, func(ctx context.Context) (interface{}, error) {
+		return s.AuthorStorer.IncludeAuthor(ctx, id)
+	})
+	if err != nil {
+		return entities.Author{}, err
+	}
+
+	return author, nil
+}
+
+// Put : updates the author and drops it from the cache
+func (s AuthorStore) Put(ctx context.Context, author entities.Author, id int) (int, error) {
+	defer s.rt.invalidate(ctx, authorKey(id))
+
+	return s.AuthorStorer.Put(ctx, author, id)
+}
+
+// Delete : deletes the author and drops it from the cache
+func (s AuthorStore) Delete(ctx context.Context, id int) (int, error) {
+	defer s.rt.invalidate(ctx, authorKey(id))
+
+	return s.AuthorStorer.Delete(ctx, id)
+}
@@ -0,0 +1,245 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+// failing : a cache whose backend is down
+type failing struct{}
+
+func (failing) Get(context.Context, string) ([]byte, bool, error) {
+	return nil, false, errors.New("connection refused")
+}
+
+func (failing) Set(context.Context, string, []byte, time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func (failing) Delete(context.Context, ...string) error {
+	return errors.New("connection refused")
+}
+
+// newMetrics : metrics on a registry of the test
+func newMetrics(t *testing.T) *Metrics {
+	m, err := NewMetrics(prometheus.NewRegistry())
+	if err != nil {
+		t.Fatalf("failed to register the metrics: %v", err)
+	}
+
+	return m
+}
+
+// counted : the reads of a cache with the result
+func counted(m *Metrics, name, result string) float64 {
+	return testutil.ToFloat64(m.requests.WithLabelValues(name, result))
+}
+
+// TestIncludeAuthor : to test an author is read from the store once, errors are not cached and a change drops it
+func TestIncludeAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockAuthorStorer(ctrl)
+	m := newMetrics(t)
+	s := NewAuthorStore(mockStore, NewLRU(10), time.Minute, m)
+
+	author := entities.Author{AuthorID: 1, FirstName: "Frank", LastName: "Herbert", DOB: "08/10/1920"}
+
+	gomock.InOrder(
+		mockStore.EXPECT().IncludeAuthor(gomock.Any(), 1).Return(author, nil),
+		mockStore.EXPECT().Put(gomock.Any(), author, 1).Return(1, nil),
+		mockStore.EXPECT().IncludeAuthor(gomock.Any(), 1).Return(author, nil),
+		mockStore.EXPECT().Delete(gomock.Any(), 1).Return(1, nil),
+		mockStore.EXPECT().IncludeAuthor(gomock.Any(), 1).Return(entities.Author{}, sql.ErrNoRows).Times(2),
+	)
+
+	for i := 0; i < 2; i++ {
+		if got, err := s.IncludeAuthor(context.TODO(), 1); !reflect.DeepEqual(got, author) || err != nil {
+			t.Errorf("unexpected author %v %v", got, err)
+		}
+	}
+
+	_, _ = s.Put(context.TODO(), author, 1)
+
+	if got, err := s.IncludeAuthor(context.TODO(), 1); !reflect.DeepEqual(got, author) || err != nil {
+		t.Errorf("unexpected author after the update %v %v", got, err)
+	}
+
+	_, _ = s.Delete(context.TODO(), 1)
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.IncludeAuthor(context.TODO(), 1); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("expected no rows, got %v", err)
+		}
+	}
+
+	if counted(m, "author", "hit") != 1 || counted(m, "author", "miss") != 4 {
+		t.Errorf("unexpected metrics, %v hits and %v misses", counted(m, "author", "hit"),
+			counted(m, "author", "miss"))
+	}
+}
+
+// TestIncludeAuthorStampede : to test the callers missing an author together share one read of the store
+func TestIncludeAuthorStampede(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockAuthorStorer(ctrl)
+	s := NewAuthorStore(mockStore, NewLRU(10), time.Minute, nil)
+
+	var loads int32
+
+	release := make(chan struct{})
+
+	mockStore.EXPECT().IncludeAuthor(gomock.Any(), 1).DoAndReturn(func(context.Context, int) (entities.Author, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+
+		return entities.Author{AuthorID: 1}, nil
+	}).MinTimes(1)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if author, err := s.IncludeAuthor(context.TODO(), 1); author.AuthorID != 1 || err != nil {
+				t.Errorf("unexpected author %v %v", author, err)
+			}
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if loads != 1 {
+		t.Errorf("the store was read %v times", loads)
+	}
+}
+
+// TestIncludeAuthorCancel : to test a caller going away does not fail the others waiting for the read it started
+func TestIncludeAuthorCancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockAuthorStorer(ctrl)
+	s := NewAuthorStore(mockStore, NewLRU(10), time.Minute, nil)
+
+	started, release := make(chan struct{}), make(chan struct{})
+
+	mockStore.EXPECT().IncludeAuthor(gomock.Any(), 1).DoAndReturn(func(ctx context.Context, _ int) (entities.Author,
+		error) {
+		close(started)
+		<-release
+
+		return entities.Author{AuthorID: 1}, ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+
+	go func() {
+		_, err := s.IncludeAuthor(ctx, 1)
+		first <- err
+	}()
+
+	<-started
+
+	second := make(chan entities.Author)
+
+	go func() {
+		author, _ := s.IncludeAuthor(context.TODO(), 1)
+		second <- author
+	}()
+
+	cancel()
+
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the cancellation, got %v", err)
+	}
+
+	close(release)
+
+	if author := <-second; author.AuthorID != 1 {
+		t.Errorf("unexpected author of the waiting caller %v", author)
+	}
+}
+
+// TestIncludeAuthorCacheDown : to test a failing cache only costs the read of the store
+func TestIncludeAuthorCacheDown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockAuthorStorer(ctrl)
+	m := newMetrics(t)
+	s := NewAuthorStore(mockStore, failing{}, time.Minute, m)
+
+	mockStore.EXPECT().IncludeAuthor(gomock.Any(), 1).Return(entities.Author{AuthorID: 1}, nil)
+	mockStore.EXPECT().Delete(gomock.Any(), 1).Return(1, nil)
+
+	if author, err := s.IncludeAuthor(context.TODO(), 1); author.AuthorID != 1 || err != nil {
+		t.Errorf("unexpected author %v %v", author, err)
+	}
+
+	if deleted, err := s.Delete(context.TODO(), 1); deleted != 1 || err != nil {
+		t.Errorf("unexpected delete %v %v", deleted, err)
+	}
+
+	if counted(m, "author", "error") != 1 {
+		t.Errorf("the error was not counted")
+	}
+}
+
+// TestIncludeAuthorInvalidatedLoad : to test a read which began before the author changed is not cached
+func TestIncludeAuthorInvalidatedLoad(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockAuthorStorer(ctrl)
+	s := NewAuthorStore(mockStore, NewLRU(10), time.Minute, nil)
+
+	started, release := make(chan struct{}), make(chan struct{})
+
+	gomock.InOrder(
+		mockStore.EXPECT().IncludeAuthor(gomock.Any(), 1).DoAndReturn(func(context.Context, int) (entities.Author,
+			error) {
+			close(started)
+			<-release
+
+			return entities.Author{AuthorID: 1, FirstName: "old"}, nil
+		}),
+		mockStore.EXPECT().Put(gomock.Any(), entities.Author{FirstName: "new"}, 1).Return(1, nil),
+		mockStore.EXPECT().IncludeAuthor(gomock.Any(), 1).Return(entities.Author{AuthorID: 1, FirstName: "new"}, nil),
+	)
+
+	first := make(chan entities.Author)
+
+	go func() {
+		author, _ := s.IncludeAuthor(context.TODO(), 1)
+		first <- author
+	}()
+
+	<-started
+
+	if _, err := s.Put(context.TODO(), entities.Author{FirstName: "new"}, 1); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	close(release)
+
+	if author := <-first; author.FirstName != "old" {
+		t.Errorf("unexpected author of the read in flight %v", author)
+	}
+
+	if author, err := s.IncludeAuthor(context.TODO(), 1); author.FirstName != "new" || err != nil {
+		t.Errorf("unexpected author after the change %v %v", author, err)
+	}
+}
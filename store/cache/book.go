@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+// BookStore : reads single books through the cache, every other call goes to the wrapped store. Only the book row
+// is cached, its genres, tags and editions are read as before. The updatedAt of a book also moves when one of them,
+// its series, its reviews or the books shown with it change, so the book is dropped then too
+type BookStore struct {
+	store.BookStorer
+	rt readThrough
+}
+
+// NewBookStore : factory function, books are cached for the ttl unless they change before
+func NewBookStore(s store.BookStorer, c Cache, ttl time.Duration, m *Metrics) BookStore {
+	return BookStore{s, newReadThrough("book", c, ttl, m)}
+}
+
+func bookKey(id int) string {
+	return "book:" + strconv.Itoa(id)
+}
+
+// GetBookByID : gives the book from the cache, reads inside a transaction go to the store as they may see changes
+// which are rolled back
+func (s BookStore) GetBookByID(ctx context.Context, id int) (entities.Book, error) {
+	if store.InTx(ctx) {
+		return s.BookStorer.GetBookByID(ctx, id)
+	}
+
+	var book entities.Book
+
+	err := s.rt.get(ctx, bookKey(id), &book, func(ctx context.Context) (interface{}, error) {
+		return s.BookStorer.GetBookByID(ctx, id)
+	})
+	if err != nil {
+		return entities.Book{}, err
+	}
+
+	return book, nil
+}
+
+// Invalidate : drops the books from the cache, for the changes which touch them without going through the store
+func (s BookStore) Invalidate(ctx context.Context, ids ...int) {
+	for _, id := range ids {
+		s.rt.invalidate(ctx, bookKey(id))
+	}
+}
+
+// invalidateRelated : drops the book and the books shown with it, a failing lookup only drops the book
+func (s BookStore) invalidateRelated(ctx context.Context, id int) {
+	related, _ := s.BookStorer.GetRelated(ctx, id)
+
+	s.Invalidate(ctx, append(related, id)...)
+}
+
+// Put : updates the book and drops it and the books shown with it from the cache
+func (s BookStore) Put(ctx context.Context, book *entities.Book, id int) (int, error) {
+	defer s.invalidateRelated(ctx, id)
+
+	return s.BookStorer.Put(ctx, book, id)
+}
+
+// Delete : deletes the book and drops it and the books shown with it from the cache
+func (s BookStore) Delete(ctx context.Context, id int) (int, error) {
+	related, _ := s.BookStorer.GetRelated(ctx, id)
+	defer s.Invalidate(ctx, append(related, id)...)
+
+	return s.BookStorer.Delete(ctx, id)
+}
+
+// SetGenres : attaches the genres and drops the book from the cache
+func (s BookStore) SetGenres(ctx context.Context, id int, genreIDs []int) error {
+	defer s.Invalidate(ctx, id)
+
+	return s.BookStorer.SetGenres(ctx, id, genreIDs)
+}
+
+// SetTags : attaches the tags and drops the book from the cache
+func (s BookStore) SetTags(ctx context.Context, id int, tags []string) error {
+	defer s.Invalidate(ctx, id)
+
+	return s.BookStorer.SetTags(ctx, id, tags)
+}
+
+// SetEdition : attaches the edition and drops the book and the other editions of its old and new work from the cache
+func (s BookStore) SetEdition(ctx context.Context, id int, edition entities.Edition) error {
+	related, _ := s.BookStorer.GetRelated(ctx, id)
+	defer s.Invalidate(ctx, related...)
+	defer s.invalidateRelated(ctx, id)
+
+	return s.BookStorer.SetEdition(ctx, id, edition)
+}
@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+// TestGetBookByID : to test a book is read from the store once and other calls pass through
+func TestGetBookByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockBookStorer(ctrl)
+	m := newMetrics(t)
+	s := NewBookStore(mockStore, NewLRU(10), time.Minute, m)
+
+	book := entities.Book{BookID: 3, AuthorID: 1, Title: "Dune", Publication: "Chilton", PublishedDate: "01/08/1965",
+		UpdatedAt: "2022-05-01T10:00:00Z"}
+
+	mockStore.EXPECT().GetBookByID(gomock.Any(), 3).Return(book, nil)
+	mockStore.EXPECT().GetTags(gomock.Any(), 3).Return([]string{"classic"}, nil).Times(2)
+
+	for i := 0; i < 3; i++ {
+		if got, err := s.GetBookByID(context.TODO(), 3); !reflect.DeepEqual(got, book) || err != nil {
+			t.Errorf("unexpected book %v %v", got, err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if tags, err := s.GetTags(context.TODO(), 3); len(tags) != 1 || err != nil {
+			t.Errorf("unexpected tags %v %v", tags, err)
+		}
+	}
+
+	if counted(m, "book", "hit") != 2 || counted(m, "book", "miss") != 1 {
+		t.Errorf("unexpected metrics, %v hits and %v misses", counted(m, "book", "hit"), counted(m, "book", "miss"))
+	}
+}
+
+// TestInvalidate : to test a book is dropped when what is shown with it changes, itself or through the services
+func TestInvalidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockBookStorer(ctrl)
+	s := NewBookStore(mockStore, NewLRU(10), time.Minute, nil)
+
+	old := entities.Book{BookID: 3, Title: "Dune", UpdatedAt: "2022-05-01T10:00:00Z"}
+	changed := entities.Book{BookID: 3, Title: "Dune", UpdatedAt: "2022-05-02T08:00:00Z"}
+	edition := entities.Edition{WorkID: 2, ISBN: "9780306406157", Format: "paperback"}
+
+	testcases := []struct {
+		desc   string
+		change func(ctx context.Context)
+		calls  func()
+	}{
+		{"genres", func(ctx context.Context) { _ = s.SetGenres(ctx, 3, []int{1}) }, func() {
+			mockStore.EXPECT().SetGenres(gomock.Any(), 3, []int{1}).Return(nil)
+		}},
+		{"tags", func(ctx context.Context) { _ = s.SetTags(ctx, 3, []string{"classic"}) }, func() {
+			mockStore.EXPECT().SetTags(gomock.Any(), 3, []string{"classic"}).Return(nil)
+		}},
+		{"other edition", func(ctx context.Context) { _ = s.SetEdition(ctx, 5, edition) }, func() {
+			mockStore.EXPECT().GetRelated(gomock.Any(), 5).Return(nil, nil)
+			mockStore.EXPECT().SetEdition(gomock.Any(), 5, edition).Return(nil)
+			mockStore.EXPECT().GetRelated(gomock.Any(), 5).Return([]int{3}, nil)
+		}},
+		{"neighbour", func(ctx context.Context) { _, _ = s.Put(ctx, &entities.Book{Title: "Children of Dune"}, 4) },
+			func() {
+				mockStore.EXPECT().Put(gomock.Any(), &entities.Book{Title: "Children of Dune"}, 4).Return(1, nil)
+				mockStore.EXPECT().GetRelated(gomock.Any(), 4).Return([]int{3}, nil)
+			}},
+		{"review", func(ctx context.Context) { store.InvalidateBooks(ctx, s, 3) }, func() {}},
+	}
+
+	for _, tc := range testcases {
+		gomock.InOrder(
+			mockStore.EXPECT().GetBookByID(gomock.Any(), 3).Return(old, nil),
+			mockStore.EXPECT().GetBookByID(gomock.Any(), 3).Return(changed, nil),
+		)
+
+		if got, err := s.GetBookByID(context.TODO(), 3); !reflect.DeepEqual(got, old) || err != nil {
+			t.Errorf("%v: unexpected book %v %v", tc.desc, got, err)
+		}
+
+		tc.calls()
+		tc.change(context.TODO())
+
+		if got, err := s.GetBookByID(context.TODO(), 3); !reflect.DeepEqual(got, changed) || err != nil {
+			t.Errorf("%v: unexpected book after the change %v %v", tc.desc, got, err)
+		}
+
+		s.Invalidate(context.TODO(), 3)
+	}
+}
+
+// TestGetBookByIDInTx : to test reads inside a transaction are not cached and a change is dropped again once it
+// is committed
+func TestGetBookByIDInTx(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := store.NewMockBookStorer(ctrl)
+	c := NewLRU(10)
+	s := NewBookStore(mockStore, c, time.Minute, nil)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	old := entities.Book{BookID: 3, Title: "Dune"}
+	changed := entities.Book{BookID: 3, Title: "Dune Messiah"}
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	gomock.InOrder(
+		mockStore.EXPECT().GetBookByID(gomock.Any(), 3).Return(old, nil),
+		mockStore.EXPECT().Put(gomock.Any(), &changed, 3).Return(1, nil),
+		mockStore.EXPECT().GetBookByID(gomock.Any(), 3).Return(changed, nil).Times(2),
+	)
+	mockStore.EXPECT().GetRelated(gomock.Any(), 3).Return(nil, nil)
+
+	err = store.NewTransaction(db).WithTx(context.TODO(), func(ctx context.Context) error {
+		if _, err := s.GetBookByID(ctx, 3); err != nil {
+			return err
+		}
+
+		if _, err := s.Put(ctx, &changed, 3); err != nil {
+			return err
+		}
+
+		if got, err := s.GetBookByID(ctx, 3); got.Title != changed.Title || err != nil {
+			t.Errorf("unexpected book inside the transaction %v %v", got, err)
+		}
+
+		// a read of another request before the commit still sees the old book
+		_ = c.Set(ctx, bookKey(3), []byte(`{"bookID":3,"title":"Dune"}`), time.Minute)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if got, err := s.GetBookByID(context.TODO(), 3); got.Title != changed.Title || err != nil {
+		t.Errorf("unexpected book after the commit %v %v", got, err)
+	}
+}
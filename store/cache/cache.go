@@ -0,0 +1,234 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
+
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+// Cache : keeps encoded values for a while, a missing or expired key is a miss and not an error
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Metrics : counts the hits, misses and errors of the caches by the name of the cache
+type Metrics struct {
+	requests *prometheus.CounterVec
+}
+
+// NewMetrics : factory function, the counters are registered with reg unless they are already
+func NewMetrics(reg prometheus.Registerer) (*Metrics, error) {
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "authorbook_cache_requests_total",
+		Help: "Reads of the store caches by cache and result: hit, miss or error.",
+	}, []string{"cache", "result"})
+
+	if err := reg.Register(requests); err != nil {
+		var registered prometheus.AlreadyRegisteredError
+		if !errors.As(err, &registered) {
+			return nil, err
+		}
+
+		requests = registered.ExistingCollector.(*prometheus.CounterVec)
+	}
+
+	return &Metrics{requests}, nil
+}
+
+func (m *Metrics) count(name, result string) {
+	if m != nil {
+		m.requests.WithLabelValues(name, result).Inc()
+	}
+}
+
+// readThrough : reads a key from the cache and loads it from the store on a miss, the callers missing the same key
+// together share one load so an expired hot record does not send them all to the database
+type readThrough struct {
+	name    string
+	cache   Cache
+	ttl     time.Duration
+	metrics *Metrics
+	group   *singleflight.Group
+	loads   *generations
+}
+
+// loadTimeout : how long a shared load may take, it does not end with the caller which started it
+const loadTimeout = 10 * time.Second
+
+// detached : keeps the values of the context but not its deadline and cancellation
+type detached struct {
+	context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detached) Done() <-chan struct{} {
+	return nil
+}
+
+func (detached) Err() error {
+	return nil
+}
+
+// generations : the keys being loaded with the number of times they were invalidated since, a load only caches what
+// it read when its key was not invalidated while it ran
+type generations struct {
+	mu   sync.Mutex
+	keys map[string]*generation
+}
+
+type generation struct {
+	gen   int
+	loads int
+}
+
+// begin : registers a load of the key and gives its current generation
+func (g *generations) begin(key string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	k, ok := g.keys[key]
+	if !ok {
+		k = &generation{}
+		g.keys[key] = k
+	}
+
+	k.loads++
+
+	return k.gen
+}
+
+// current : whether the key was not invalidated since the load of the generation began
+func (g *generations) current(key string, gen int) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.keys[key].gen == gen
+}
+
+// end : forgets the key once its last load is done
+func (g *generations) end(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	k := g.keys[key]
+	k.loads--
+
+	if k.loads == 0 {
+		delete(g.keys, key)
+	}
+}
+
+// bump : moves the generation of the key when it is being loaded
+func (g *generations) bump(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if k, ok := g.keys[key]; ok {
+		k.gen++
+	}
+}
+
+func newReadThrough(name string, c Cache, ttl time.Duration, m *Metrics) readThrough {
+	return readThrough{name, c, ttl, m, &singleflight.Group{}, &generations{keys: make(map[string]*generation)}}
+}
+
+// get : decodes the value of the key into v, a failing cache only costs the load
+func (rt readThrough) get(ctx context.Context, key string, v interface{},
+	load func(ctx context.Context) (interface{}, error)) error {
+	data, ok, err := rt.cache.Get(ctx, key)
+
+	switch {
+	case err != nil:
+		log.Printf("cache %v: %v", rt.name, err)
+		rt.metrics.count(rt.name, "error")
+	case ok && json.Unmarshal(data, v) == nil:
+		rt.metrics.count(rt.name, "hit")
+		return nil
+	default:
+		rt.metrics.count(rt.name, "miss")
+	}
+
+	// the load is shared so it runs on a context of its own, a caller going away only stops its own wait
+	shared := rt.group.DoChan(key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(detached{ctx}, loadTimeout)
+		defer cancel()
+
+		gen := rt.loads.begin(key)
+		defer rt.loads.end(key)
+
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		rt.set(ctx, key, data, gen)
+
+		return data, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case res := <-shared:
+		if res.Err != nil {
+			return res.Err
+		}
+
+		return json.Unmarshal(res.Val.([]byte), v)
+	}
+}
+
+// set : caches the value loaded in the generation unless the key was invalidated since, an invalidation between the
+// check and the write drops the value again
+func (rt readThrough) set(ctx context.Context, key string, data []byte, gen int) {
+	if !rt.loads.current(key, gen) {
+		return
+	}
+
+	if err := rt.cache.Set(ctx, key, data, rt.ttl); err != nil {
+		log.Printf("cache %v: %v", rt.name, err)
+	}
+
+	if rt.loads.current(key, gen) {
+		return
+	}
+
+	if err := rt.cache.Delete(ctx, key); err != nil {
+		log.Printf("cache %v: %v", rt.name, err)
+	}
+}
+
+// invalidate : drops the key now and again once the transaction of the context commits, so a read between the
+// change and the commit can not keep the old value cached; a load in flight is not shared any more and does not
+// cache what it read
+func (rt readThrough) invalidate(ctx context.Context, key string) {
+	drop := func() {
+		rt.loads.bump(key)
+		rt.group.Forget(key)
+
+		if err := rt.cache.Delete(ctx, key); err != nil {
+			log.Printf("cache %v: %v", rt.name, err)
+		}
+	}
+
+	drop()
+	store.AfterCommit(ctx, drop)
+}
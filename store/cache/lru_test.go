@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestLRU : to test keys expire after their ttl and the least recently used key is evicted
+func TestLRU(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Date(2022, 8, 1, 10, 0, 0, 0, time.UTC)
+
+	c := NewLRU(2)
+	c.now = func() time.Time { return now }
+
+	_ = c.Set(ctx, "a", []byte("1"), time.Minute)
+	_ = c.Set(ctx, "b", []byte("2"), time.Hour)
+
+	// reading a makes b the least recently used one
+	if value, ok, _ := c.Get(ctx, "a"); !ok || string(value) != "1" {
+		t.Errorf("a is missing: %q %v", value, ok)
+	}
+
+	_ = c.Set(ctx, "c", []byte("3"), time.Hour)
+
+	if _, ok, _ := c.Get(ctx, "b"); ok || c.Len() != 2 {
+		t.Errorf("b was not evicted, %v keys", c.Len())
+	}
+
+	now = now.Add(time.Minute)
+
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Errorf("a did not expire")
+	}
+
+	_ = c.Set(ctx, "c", []byte("4"), time.Hour)
+	_ = c.Delete(ctx, "a", "missing")
+
+	if value, ok, _ := c.Get(ctx, "c"); !ok || string(value) != "4" || c.Len() != 1 {
+		t.Errorf("c was not replaced: %q %v, %v keys", value, ok, c.Len())
+	}
+
+	_ = c.Delete(ctx, "c")
+
+	if _, ok, _ := c.Get(ctx, "c"); ok || c.Len() != 0 {
+		t.Errorf("c was not deleted")
+	}
+}
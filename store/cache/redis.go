@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Redis : a cache shared by every instance of the service, so a change invalidates the key for all of them
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedis : factory function, the keys are prefixed so the cache can share a Redis with others
+func NewRedis(client redis.UniversalClient, prefix string) Redis {
+	return Redis{client, prefix}
+}
+
+// Get : gives the value of the key unless it is missing or expired
+func (c Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+
+	switch {
+	case errors.Is(err, redis.Nil):
+		return nil, false, nil
+	case err != nil:
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+// Set : keeps the value for the ttl
+func (c Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, c.prefix+key, value, ttl).Err()
+}
+
+// Delete : drops the keys
+func (c Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.prefix + key
+	}
+
+	return c.client.Del(ctx, prefixed...).Err()
+}
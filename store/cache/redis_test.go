@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+// TestRedis : to test the keys are prefixed, expire after their ttl and are deleted
+func TestRedis(t *testing.T) {
+	ctx := context.TODO()
+
+	server, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("error during the start of redis:%v\n", err)
+	}
+	defer server.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	c := NewRedis(client, "authorbook:")
+
+	if _, ok, err := c.Get(ctx, "book:1"); ok || err != nil {
+		t.Errorf("unexpected hit on an empty cache: %v", err)
+	}
+
+	if err = c.Set(ctx, "book:1", []byte(`{"bookID":1}`), time.Minute); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+
+	if value, _ := server.Get("authorbook:book:1"); value != `{"bookID":1}` {
+		t.Errorf("the key is not prefixed: %q", value)
+	}
+
+	if value, ok, err := c.Get(ctx, "book:1"); !ok || err != nil || string(value) != `{"bookID":1}` {
+		t.Errorf("unexpected value %q %v %v", value, ok, err)
+	}
+
+	server.FastForward(time.Minute)
+
+	if _, ok, _ := c.Get(ctx, "book:1"); ok {
+		t.Errorf("the key did not expire")
+	}
+
+	_ = c.Set(ctx, "book:2", []byte(`{}`), time.Minute)
+
+	if err = c.Delete(ctx, "book:2", "book:3"); err != nil || server.Exists("authorbook:book:2") {
+		t.Errorf("the key was not deleted: %v", err)
+	}
+
+	server.Close()
+
+	if _, _, err = c.Get(ctx, "book:1"); err == nil {
+		t.Errorf("expected an error once redis is gone")
+	}
+}
@@ -0,0 +1,7 @@
+package store
+
+// UpdatedAt : the updated_at column of the table read as an RFC 3339 time in UTC, a timestamp is given in the time
+// zone of the session
+func UpdatedAt(table string) string {
+	return "DATE_FORMAT(CONVERT_TZ(" + table + ".updated_at,@@session.time_zone,'+00:00'),'%Y-%m-%dT%H:%i:%sZ')"
+}
@@ -8,6 +8,7 @@ USE  AuthorBook;
      title varchar(50),
      publication varchar(50),
      published_date varchar(50),
+     updated_at timestamp not null default CURRENT_TIMESTAMP on update CURRENT_TIMESTAMP,
      PRIMARY KEY(id),
      FOREIGN KEY(author_id)REFERENCES author1(author_id)
      );
@@ -18,7 +19,318 @@ CREATE TABLE author1(
     last_name varchar(50),
     dob varchar(10),
     pen_name varchar(50),
+    updated_at timestamp not null default CURRENT_TIMESTAMP on update CURRENT_TIMESTAMP,
     PRIMARY KEY(author_id)
 );
 
 
+CREATE TABLE genre(
+    genre_id int not null AUTO_INCREMENT,
+    name varchar(50) not null,
+    parent_id int,
+    PRIMARY KEY(genre_id),
+    UNIQUE(parent_id, name),
+    FOREIGN KEY(parent_id) REFERENCES genre(genre_id)
+);
+
+CREATE TABLE tag(
+    tag_id int not null AUTO_INCREMENT,
+    name varchar(50) not null,
+    PRIMARY KEY(tag_id),
+    UNIQUE(name)
+);
+
+CREATE TABLE book_genre(
+    book_id int not null,
+    genre_id int not null,
+    PRIMARY KEY(book_id, genre_id),
+    FOREIGN KEY(book_id) REFERENCES book(id) ON DELETE CASCADE,
+    FOREIGN KEY(genre_id) REFERENCES genre(genre_id) ON DELETE CASCADE
+);
+
+CREATE TABLE book_tag(
+    book_id int not null,
+    tag_id int not null,
+    PRIMARY KEY(book_id, tag_id),
+    FOREIGN KEY(book_id) REFERENCES book(id) ON DELETE CASCADE,
+    FOREIGN KEY(tag_id) REFERENCES tag(tag_id) ON DELETE CASCADE
+);
+
+CREATE TABLE series(
+    series_id int not null AUTO_INCREMENT,
+    name varchar(100) not null,
+    description text,
+    PRIMARY KEY(series_id)
+);
+
+CREATE TABLE series_book(
+    series_id int not null,
+    book_id int not null,
+    position decimal(6,2) not null,
+    PRIMARY KEY(series_id, book_id),
+    UNIQUE(series_id, position),
+    FOREIGN KEY(series_id) REFERENCES series(series_id) ON DELETE CASCADE,
+    FOREIGN KEY(book_id) REFERENCES book(id) ON DELETE CASCADE
+);
+
+CREATE TABLE work(
+    work_id int not null AUTO_INCREMENT,
+    author_id int not null,
+    title varchar(100) not null,
+    PRIMARY KEY(work_id),
+    FOREIGN KEY(author_id) REFERENCES author(author_id)
+);
+
+CREATE TABLE edition(
+    book_id int not null,
+    work_id int not null,
+    isbn varchar(13) not null,
+    format enum('hardcover','paperback','ebook','audiobook') not null,
+    page_count int not null default 0,
+    PRIMARY KEY(book_id),
+    UNIQUE(isbn),
+    FOREIGN KEY(book_id) REFERENCES book(id) ON DELETE CASCADE,
+    FOREIGN KEY(work_id) REFERENCES work(work_id)
+);
+
+CREATE TABLE review(
+    review_id int not null AUTO_INCREMENT,
+    book_id int not null,
+    reviewer varchar(100) not null,
+    rating tinyint not null,
+    text text,
+    status enum('pending','approved','rejected') not null default 'pending',
+    helpful_count int not null default 0,
+    created_at datetime not null default CURRENT_TIMESTAMP,
+    PRIMARY KEY(review_id),
+    UNIQUE(book_id, reviewer),
+    CHECK(rating BETWEEN 1 AND 5),
+    FOREIGN KEY(book_id) REFERENCES book(id) ON DELETE CASCADE
+);
+
+-- a reader counts once towards the helpful_count of a review
+CREATE TABLE review_vote(
+    review_id int not null,
+    voter varchar(100) not null,
+    created_at datetime not null default CURRENT_TIMESTAMP,
+    PRIMARY KEY(review_id, voter),
+    FOREIGN KEY(review_id) REFERENCES review(review_id) ON DELETE CASCADE
+);
+
+-- a book is modified when what is returned with it changes, its genres, tags, series, edition and ratings are kept
+-- in their own tables so they touch the updated_at of the book the responses are validated with
+CREATE TRIGGER book_genre_insert AFTER INSERT ON book_genre FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP WHERE id=NEW.book_id;
+
+CREATE TRIGGER book_genre_update AFTER UPDATE ON book_genre FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP WHERE id=NEW.book_id;
+
+CREATE TRIGGER book_genre_delete AFTER DELETE ON book_genre FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP WHERE id=OLD.book_id;
+
+CREATE TRIGGER book_tag_insert AFTER INSERT ON book_tag FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP WHERE id=NEW.book_id;
+
+CREATE TRIGGER book_tag_update AFTER UPDATE ON book_tag FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP WHERE id=NEW.book_id;
+
+CREATE TRIGGER book_tag_delete AFTER DELETE ON book_tag FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP WHERE id=OLD.book_id;
+
+-- a book is shown with its neighbours in the series and the other editions of its work, so a change to them touches
+-- every book of the series or the work; a change of the book row itself is touched on them by the book store as a
+-- trigger on book can not update book
+CREATE TRIGGER series_book_insert AFTER INSERT ON series_book FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP WHERE id IN (SELECT book_id FROM series_book WHERE series_id=NEW.series_id);
+
+CREATE TRIGGER series_book_update AFTER UPDATE ON series_book FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP
+    WHERE id IN (SELECT book_id FROM series_book WHERE series_id IN (OLD.series_id, NEW.series_id)) OR id=OLD.book_id;
+
+CREATE TRIGGER series_book_delete AFTER DELETE ON series_book FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP
+    WHERE id IN (SELECT book_id FROM series_book WHERE series_id=OLD.series_id) OR id=OLD.book_id;
+
+CREATE TRIGGER edition_insert AFTER INSERT ON edition FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP WHERE id IN (SELECT book_id FROM edition WHERE work_id=NEW.work_id);
+
+CREATE TRIGGER edition_update AFTER UPDATE ON edition FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP
+    WHERE id IN (SELECT book_id FROM edition WHERE work_id IN (OLD.work_id, NEW.work_id)) OR id=OLD.book_id;
+
+CREATE TRIGGER edition_delete AFTER DELETE ON edition FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP
+    WHERE id IN (SELECT book_id FROM edition WHERE work_id=OLD.work_id) OR id=OLD.book_id;
+
+CREATE TRIGGER review_insert AFTER INSERT ON review FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP WHERE id=NEW.book_id;
+
+CREATE TRIGGER review_update AFTER UPDATE ON review FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP WHERE id=NEW.book_id;
+
+CREATE TRIGGER review_delete AFTER DELETE ON review FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP WHERE id=OLD.book_id;
+
+CREATE TRIGGER series_update AFTER UPDATE ON series FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP WHERE id IN (SELECT book_id FROM series_book WHERE series_id=NEW.series_id);
+
+CREATE TRIGGER genre_update AFTER UPDATE ON genre FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP WHERE id IN (SELECT book_id FROM book_genre WHERE genre_id=NEW.genre_id);
+
+-- the rows of the books are removed by the cascade, which does not fire the triggers above
+CREATE TRIGGER series_delete BEFORE DELETE ON series FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP WHERE id IN (SELECT book_id FROM series_book WHERE series_id=OLD.series_id);
+
+CREATE TRIGGER genre_delete BEFORE DELETE ON genre FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP WHERE id IN (SELECT book_id FROM book_genre WHERE genre_id=OLD.genre_id);
+
+CREATE TRIGGER tag_delete BEFORE DELETE ON tag FOR EACH ROW
+    UPDATE book SET updated_at=CURRENT_TIMESTAMP WHERE id IN (SELECT book_id FROM book_tag WHERE tag_id=OLD.tag_id);
+
+
+CREATE TABLE api_key(
+    api_key_id int not null AUTO_INCREMENT,
+    name varchar(100) not null,
+    prefix char(8) not null,
+    role varchar(50) not null,
+    scopes varchar(100) not null default '',
+    salt char(32) not null,
+    hash char(64) not null,
+    created_at datetime not null default CURRENT_TIMESTAMP,
+    last_used_at datetime,
+    revoked_at datetime,
+    PRIMARY KEY(api_key_id),
+    UNIQUE(prefix)
+);
+
+CREATE TABLE audit_log(
+    audit_id bigint not null AUTO_INCREMENT,
+    actor varchar(100) not null,
+    operation enum('create','update','delete') not null,
+    entity_type enum('author','book') not null,
+    entity_id int not null,
+    before_data json,
+    after_data json,
+    created_at datetime not null default CURRENT_TIMESTAMP,
+    PRIMARY KEY(audit_id),
+    INDEX(entity_type, entity_id),
+    INDEX(actor),
+    INDEX(created_at)
+);
+
+-- the audit log is append-only
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log FOR EACH ROW
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only';
+
+CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log FOR EACH ROW
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only';
+
+CREATE TABLE author_history(
+    author_id int not null,
+    revision int not null,
+    operation enum('create','update','delete') not null,
+    actor varchar(100) not null,
+    changed_at datetime not null default CURRENT_TIMESTAMP,
+    first_name varchar(50),
+    last_name varchar(50),
+    dob varchar(10),
+    pen_name varchar(50),
+    PRIMARY KEY(author_id, revision)
+);
+
+CREATE TABLE book_history(
+    book_id int not null,
+    revision int not null,
+    operation enum('create','update','delete') not null,
+    actor varchar(100) not null,
+    changed_at datetime not null default CURRENT_TIMESTAMP,
+    author_id int,
+    title varchar(50),
+    publication varchar(50),
+    published_date varchar(50),
+    PRIMARY KEY(book_id, revision),
+    INDEX(book_id, changed_at)
+);
+
+-- events of author and book changes, written in the transaction of the change and published by the relay
+CREATE TABLE outbox(
+    event_id bigint not null AUTO_INCREMENT,
+    event_type varchar(32) not null,
+    entity_type enum('author','book') not null,
+    entity_id int not null,
+    actor varchar(100) not null,
+    payload json,
+    created_at datetime not null default CURRENT_TIMESTAMP,
+    published_at datetime,
+    PRIMARY KEY(event_id),
+    INDEX(published_at, event_id)
+);
+
+-- webhooks of partners, the secret signs the deliveries so it is kept in plain
+CREATE TABLE webhook(
+    webhook_id int not null AUTO_INCREMENT,
+    url varchar(2048) not null,
+    event_types json not null,
+    entity_ids json,
+    secret varchar(128) not null,
+    actor varchar(100) not null,
+    created_at datetime not null default CURRENT_TIMESTAMP,
+    PRIMARY KEY(webhook_id)
+);
+
+CREATE TABLE webhook_delivery(
+    delivery_id bigint not null AUTO_INCREMENT,
+    webhook_id int not null,
+    event_id bigint not null,
+    event_type varchar(32) not null,
+    payload json not null,
+    status enum('pending','retrying','delivered','dead') not null default 'pending',
+    attempts int not null default 0,
+    next_attempt_at datetime not null default CURRENT_TIMESTAMP,
+    response_status int,
+    last_error varchar(255),
+    created_at datetime not null default CURRENT_TIMESTAMP,
+    delivered_at datetime,
+    PRIMARY KEY(delivery_id),
+    UNIQUE(webhook_id, event_id),
+    INDEX(status, next_attempt_at),
+    FOREIGN KEY(webhook_id) REFERENCES webhook(webhook_id) ON DELETE CASCADE
+);
+
+CREATE TABLE import_job(
+    job_id int not null AUTO_INCREMENT,
+    entity enum('book','author') not null,
+    format enum('csv','ndjson') not null,
+    mode enum('atomic','best-effort') not null,
+    dry_run boolean not null default false,
+    mapping json,
+    status enum('pending','running','done','failed') not null,
+    total int not null default 0,
+    imported int not null default 0,
+    skipped int not null default 0,
+    failed int not null default 0,
+    errors json,
+    actor varchar(100) not null,
+    created_at datetime not null default CURRENT_TIMESTAMP,
+    finished_at datetime,
+    PRIMARY KEY(job_id)
+);
+
+CREATE TABLE openlibrary_key(
+    ol_key varchar(32) not null,
+    entity_id int not null,
+    PRIMARY KEY(ol_key)
+);
+
+CREATE TABLE idempotency_key(
+    scope varchar(100) not null,
+    idem_key varchar(255) not null,
+    fingerprint char(64) not null,
+    status smallint,
+    content_type varchar(100),
+    body mediumblob,
+    created_at datetime not null default CURRENT_TIMESTAMP,
+    expires_at datetime not null,
+    PRIMARY KEY(scope, idem_key),
+    INDEX(expires_at)
+);
@@ -0,0 +1,164 @@
+package genre
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+type Store struct {
+	DB *sql.DB
+}
+
+// New : factory function
+func New(db *sql.DB) Store {
+	return Store{db}
+}
+
+// Post : inserts a genre, a zero parent id makes it a top level genre
+func (s Store) Post(ctx context.Context, genre entities.Genre) (int, error) {
+	res, err := s.DB.ExecContext(ctx, "insert into genre(name,parent_id)values(?,?)", genre.Name, parentID(genre.ParentID))
+	if err != nil {
+		log.Print(err)
+		return -1, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(id), nil
+}
+
+// Put : updates the name and parent of the genre with particular id
+func (s Store) Put(ctx context.Context, genre entities.Genre, id int) (int, error) {
+	res, err := s.DB.ExecContext(ctx, "update genre set name=?,parent_id=? where genre_id=?",
+		genre.Name, parentID(genre.ParentID), id)
+	if err != nil {
+		log.Print(err)
+		return -1, err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(ra), nil
+}
+
+// Delete : deletes the genre with particular id
+func (s Store) Delete(ctx context.Context, id int) (int, error) {
+	res, err := s.DB.ExecContext(ctx, "delete from genre where genre_id=?", id)
+	if err != nil {
+		return -1, err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(ra), nil
+}
+
+// GetByID : gives the genre with particular id
+func (s Store) GetByID(ctx context.Context, id int) (entities.Genre, error) {
+	var (
+		genre  entities.Genre
+		parent sql.NullInt64
+	)
+
+	row := s.DB.QueryRowContext(ctx, "select genre_id,name,parent_id from genre where genre_id=?", id)
+
+	if err := row.Scan(&genre.GenreID, &genre.Name, &parent); err != nil {
+		log.Print(err)
+		return entities.Genre{}, err
+	}
+
+	genre.ParentID = int(parent.Int64)
+
+	return genre, nil
+}
+
+// GetAll : gives every genre as a flat list ordered by id
+func (s Store) GetAll(ctx context.Context) ([]entities.Genre, error) {
+	rows, err := s.DB.QueryContext(ctx, "select genre_id,name,parent_id from genre order by genre_id")
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanGenres(rows)
+}
+
+// GetDescendants : gives every genre below the genre with particular id, at any depth
+func (s Store) GetDescendants(ctx context.Context, id int) ([]entities.Genre, error) {
+	rows, err := s.DB.QueryContext(ctx, "with recursive tree as (select genre_id,name,parent_id from genre "+
+		"where parent_id=? union all select g.genre_id,g.name,g.parent_id from genre g "+
+		"join tree t on g.parent_id=t.genre_id) select genre_id,name,parent_id from tree order by genre_id", id)
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanGenres(rows)
+}
+
+// GetBookIDs : gives the ids of the books the genre is attached to
+func (s Store) GetBookIDs(ctx context.Context, id int) ([]int, error) {
+	rows, err := s.DB.QueryContext(ctx, "select book_id from book_genre where genre_id=? order by book_id", id)
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+
+	for rows.Next() {
+		var bookID int
+		if err = rows.Scan(&bookID); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, bookID)
+	}
+
+	return ids, rows.Err()
+}
+
+// scanGenres : reads the genre rows of a query
+func scanGenres(rows *sql.Rows) ([]entities.Genre, error) {
+	var genres []entities.Genre
+
+	for rows.Next() {
+		var (
+			genre  entities.Genre
+			parent sql.NullInt64
+		)
+
+		if err := rows.Scan(&genre.GenreID, &genre.Name, &parent); err != nil {
+			return nil, err
+		}
+
+		genre.ParentID = int(parent.Int64)
+		genres = append(genres, genre)
+	}
+
+	return genres, rows.Err()
+}
+
+// parentID : top level genres are stored with a null parent
+func parentID(id int) interface{} {
+	if id <= 0 {
+		return nil
+	}
+
+	return id
+}
@@ -0,0 +1,158 @@
+package genre
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+// TestPost : to test posting a genre
+func TestPost(t *testing.T) {
+	testcases := []struct {
+		desc   string
+		body   entities.Genre
+		parent interface{}
+
+		lastInserted int64
+		execErr      error
+		expectedID   int
+		expectedErr  error
+	}{
+		{desc: "top level genre", body: entities.Genre{Name: "Fiction"}, parent: nil,
+			lastInserted: 1, expectedID: 1},
+		{desc: "sub-genre", body: entities.Genre{Name: "Fantasy", ParentID: 1}, parent: 1,
+			lastInserted: 2, expectedID: 2},
+		{desc: "duplicate genre", body: entities.Genre{Name: "Fiction"}, parent: nil,
+			execErr: errors.New("duplicate entry"), expectedID: -1, expectedErr: errors.New("duplicate entry")},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectExec("insert into genre(name,parent_id)values(?,?)").WithArgs(tc.body.Name, tc.parent).
+			WillReturnResult(sqlmock.NewResult(tc.lastInserted, 1)).WillReturnError(tc.execErr)
+
+		id, err := New(db).Post(context.TODO(), tc.body)
+
+		if id != tc.expectedID || !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %s, expected: %v %v, got: %v %v", tc.desc, tc.expectedID, tc.expectedErr, id, err)
+		}
+
+		db.Close()
+	}
+}
+
+// TestGetByID : to test fetching a genre
+func TestGetByID(t *testing.T) {
+	testcases := []struct {
+		desc string
+		id   int
+		rows *sqlmock.Rows
+
+		expected    entities.Genre
+		expectedErr bool
+	}{
+		{desc: "top level genre", id: 1, rows: sqlmock.NewRows([]string{"genre_id", "name", "parent_id"}).
+			AddRow(1, "Fiction", nil), expected: entities.Genre{GenreID: 1, Name: "Fiction"}},
+		{desc: "sub-genre", id: 2, rows: sqlmock.NewRows([]string{"genre_id", "name", "parent_id"}).
+			AddRow(2, "Fantasy", 1), expected: entities.Genre{GenreID: 2, Name: "Fantasy", ParentID: 1}},
+		{desc: "not existing", id: 9, rows: sqlmock.NewRows([]string{"genre_id", "name", "parent_id"}),
+			expected: entities.Genre{}, expectedErr: true},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectQuery("select genre_id,name,parent_id from genre where genre_id=?").WithArgs(tc.id).
+			WillReturnRows(tc.rows)
+
+		genre, err := New(db).GetByID(context.TODO(), tc.id)
+
+		if !reflect.DeepEqual(genre, tc.expected) || (err != nil) != tc.expectedErr {
+			t.Errorf("failed for %s, expected: %v, got: %v", tc.desc, tc.expected, genre)
+		}
+
+		db.Close()
+	}
+}
+
+// TestGetDescendants : to test fetching the sub-genres at any depth
+func TestGetDescendants(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("with recursive tree as (select genre_id,name,parent_id from genre " +
+		"where parent_id=? union all select g.genre_id,g.name,g.parent_id from genre g " +
+		"join tree t on g.parent_id=t.genre_id) select genre_id,name,parent_id from tree order by genre_id").
+		WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"genre_id", "name", "parent_id"}).
+		AddRow(2, "Fantasy", 1).AddRow(3, "Epic", 2))
+
+	expected := []entities.Genre{{GenreID: 2, Name: "Fantasy", ParentID: 1}, {GenreID: 3, Name: "Epic", ParentID: 2}}
+
+	genres, err := New(db).GetDescendants(context.TODO(), 1)
+	if err != nil || !reflect.DeepEqual(genres, expected) {
+		t.Errorf("expected: %v, got: %v %v", expected, genres, err)
+	}
+}
+
+// TestDelete : to test deleting a genre
+func TestDelete(t *testing.T) {
+	testcases := []struct {
+		desc         string
+		target       int
+		rowsAffected int64
+
+		expected int
+	}{
+		{"existing genre", 4, 1, 1},
+		{"not existing", 1000, 0, 0},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectExec("delete from genre where genre_id=?").WithArgs(tc.target).
+			WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+
+		count, err := New(db).Delete(context.TODO(), tc.target)
+		if err != nil || count != tc.expected {
+			t.Errorf("failed for %s, expected: %v, got: %v", tc.desc, tc.expected, count)
+		}
+
+		db.Close()
+	}
+}
+
+// TestGetBookIDs : to test fetching the books the genre is attached to
+func TestGetBookIDs(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("select book_id from book_genre where genre_id=? order by book_id").WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id"}).AddRow(4).AddRow(7))
+
+	ids, err := New(db).GetBookIDs(context.TODO(), 2)
+	if err != nil || !reflect.DeepEqual(ids, []int{4, 7}) {
+		t.Errorf("expected: [4 7], got: %v %v", ids, err)
+	}
+}
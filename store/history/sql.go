@@ -0,0 +1,140 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+type Store struct {
+	DB *sql.DB
+}
+
+// New : factory function
+func New(db *sql.DB) Store {
+	return Store{db}
+}
+
+const (
+	authorColumns = "revision,operation,actor,changed_at,author_id,first_name,last_name,dob,pen_name"
+	bookColumns   = "revision,operation,actor,changed_at,book_id,author_id,title,publication,published_date"
+)
+
+// PostAuthor : appends the next revision of the author, inside the transaction of the change when there is one
+func (s Store) PostAuthor(ctx context.Context, rev entities.AuthorRevision) error {
+	a := rev.Author
+
+	_, err := store.Executor(ctx, s.DB).ExecContext(ctx, "insert into author_history(author_id,revision,operation,actor,"+
+		"first_name,last_name,dob,pen_name) select ?,coalesce(max(revision),0)+1,?,?,?,?,?,? from author_history "+
+		"where author_id=?", a.AuthorID, rev.Operation, rev.Actor, a.FirstName, a.LastName, a.DOB, a.PenName, a.AuthorID)
+	if err != nil {
+		log.Print(err)
+	}
+
+	return err
+}
+
+// GetAuthorHistory : gives every revision of the author, newest first
+func (s Store) GetAuthorHistory(ctx context.Context, id int) ([]entities.AuthorRevision, error) {
+	var revisions []entities.AuthorRevision
+
+	rows, err := s.DB.QueryContext(ctx, "select "+authorColumns+" from author_history where author_id=? "+
+		"order by revision desc", id)
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			rev entities.AuthorRevision
+			a   = &rev.Author
+		)
+
+		err = rows.Scan(&rev.Revision, &rev.Operation, &rev.Actor, &rev.ChangedAt, &a.AuthorID, &a.FirstName,
+			&a.LastName, &a.DOB, &a.PenName)
+		if err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, rows.Err()
+}
+
+// PostBook : appends the next revision of the book, inside the transaction of the change when there is one
+func (s Store) PostBook(ctx context.Context, rev entities.BookRevision) error {
+	b := rev.Book
+
+	_, err := store.Executor(ctx, s.DB).ExecContext(ctx, "insert into book_history(book_id,revision,operation,actor,"+
+		"author_id,title,publication,published_date) select ?,coalesce(max(revision),0)+1,?,?,?,?,?,? from book_history "+
+		"where book_id=?", b.BookID, rev.Operation, rev.Actor, b.AuthorID, b.Title, b.Publication, b.PublishedDate,
+		b.BookID)
+	if err != nil {
+		log.Print(err)
+	}
+
+	return err
+}
+
+// GetBookHistory : gives every revision of the book, newest first
+func (s Store) GetBookHistory(ctx context.Context, id int) ([]entities.BookRevision, error) {
+	var revisions []entities.BookRevision
+
+	rows, err := s.DB.QueryContext(ctx, "select "+bookColumns+" from book_history where book_id=? "+
+		"order by revision desc", id)
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rev, err := scanBook(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, rows.Err()
+}
+
+// GetBookRevision : gives a single revision of the book
+func (s Store) GetBookRevision(ctx context.Context, id, revision int) (entities.BookRevision, error) {
+	return scanBook(s.DB.QueryRowContext(ctx, "select "+bookColumns+" from book_history where book_id=? and revision=?",
+		id, revision))
+}
+
+// GetBookAsOf : gives the latest revision of the book made at or before the time, asOf is in UTC and changed_at in
+// the time zone of the session so the time is converted to it, which keeps the index on changed_at usable
+func (s Store) GetBookAsOf(ctx context.Context, id int, asOf string) (entities.BookRevision, error) {
+	return scanBook(s.DB.QueryRowContext(ctx, "select "+bookColumns+" from book_history where book_id=? and "+
+		"changed_at<=CONVERT_TZ(?,'+00:00',@@session.time_zone) order by revision desc limit 1", id, asOf))
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanBook : reads a book revision row
+func scanBook(row scanner) (entities.BookRevision, error) {
+	var (
+		rev entities.BookRevision
+		b   = &rev.Book
+	)
+
+	err := row.Scan(&rev.Revision, &rev.Operation, &rev.Actor, &rev.ChangedAt, &b.BookID, &b.AuthorID, &b.Title,
+		&b.Publication, &b.PublishedDate)
+	if err != nil {
+		return entities.BookRevision{}, err
+	}
+
+	return rev, nil
+}
@@ -0,0 +1,93 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+// TestPostBook : to test the next revision number is taken from the existing ones
+func TestPostBook(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	rev := entities.BookRevision{Operation: entities.AuditUpdate, Actor: "asha", Book: entities.Book{BookID: 3,
+		AuthorID: 1, Title: "new title", Publication: "penguin", PublishedDate: "20/03/2010"}}
+
+	mock.ExpectExec("insert into book_history(book_id,revision,operation,actor,author_id,title,publication,"+
+		"published_date) select ?,coalesce(max(revision),0)+1,?,?,?,?,?,? from book_history where book_id=?").
+		WithArgs(3, "update", "asha", 1, "new title", "penguin", "20/03/2010", 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err = New(db).PostBook(context.TODO(), rev); err != nil {
+		t.Errorf("failed to post revision: %v", err)
+	}
+}
+
+// TestGetBookAsOf : to test the latest revision before the time is read
+func TestGetBookAsOf(t *testing.T) {
+	cols := []string{"revision", "operation", "actor", "changed_at", "book_id", "author_id", "title", "publication",
+		"published_date"}
+
+	testcases := []struct {
+		desc string
+		rows *sqlmock.Rows
+
+		expected    entities.BookRevision
+		expectedErr error
+	}{
+		{desc: "revision found", rows: sqlmock.NewRows(cols).AddRow(2, "update", "asha", "2022-08-01 10:00:00", 3, 1,
+			"old title", "penguin", "20/03/2010"), expected: entities.BookRevision{Revision: 2, Operation: "update",
+			Actor: "asha", ChangedAt: "2022-08-01 10:00:00", Book: entities.Book{BookID: 3, AuthorID: 1,
+				Title: "old title", Publication: "penguin", PublishedDate: "20/03/2010"}}},
+		{desc: "no revision yet", rows: sqlmock.NewRows(cols), expectedErr: errors.New("sql: no rows in result set")},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectQuery("select "+bookColumns+" from book_history where book_id=? and "+
+			"changed_at<=CONVERT_TZ(?,'+00:00',@@session.time_zone) "+
+			"order by revision desc limit 1").WithArgs(3, "2022-08-02 00:00:00").WillReturnRows(tc.rows)
+
+		rev, err := New(db).GetBookAsOf(context.TODO(), 3, "2022-08-02 00:00:00")
+		if !reflect.DeepEqual(rev, tc.expected) || (err == nil) != (tc.expectedErr == nil) {
+			t.Errorf("failed for %s, got: %v %v", tc.desc, rev, err)
+		}
+
+		db.Close()
+	}
+}
+
+// TestGetAuthorHistory : to test the revisions of an author are read newest first
+func TestGetAuthorHistory(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	cols := []string{"revision", "operation", "actor", "changed_at", "author_id", "first_name", "last_name", "dob",
+		"pen_name"}
+
+	mock.ExpectQuery("select " + authorColumns + " from author_history where author_id=? order by revision desc").
+		WithArgs(4).WillReturnRows(sqlmock.NewRows(cols).
+		AddRow(2, "update", "asha", "2022-08-02 10:00:00", 4, "nilotpal", "mrinal", "21/05/1990", "Dh").
+		AddRow(1, "create", "asha", "2022-08-01 10:00:00", 4, "nilotpal", "mrinal", "20/05/1990", "Dh"))
+
+	revisions, err := New(db).GetAuthorHistory(context.TODO(), 4)
+	if err != nil || len(revisions) != 2 || revisions[0].Revision != 2 || revisions[1].Author.DOB != "20/05/1990" {
+		t.Errorf("unexpected revisions %v %v", revisions, err)
+	}
+}
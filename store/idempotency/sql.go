@@ -0,0 +1,93 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+type Store struct {
+	DB *sql.DB
+}
+
+// New : factory function
+func New(db *sql.DB) Store {
+	return Store{db}
+}
+
+// Reserve : records the request as in progress for window seconds and gives true, when the key is taken already it
+// gives the request recorded with it and false. An expired key is free again
+func (s Store) Reserve(ctx context.Context, request entities.IdempotentRequest, window int) (entities.IdempotentRequest,
+	bool, error) {
+	_, err := s.DB.ExecContext(ctx, "delete from idempotency_key where scope=? and idem_key=? and "+
+		"expires_at<=CURRENT_TIMESTAMP", request.Scope, request.Key)
+	if err != nil {
+		log.Print(err)
+		return entities.IdempotentRequest{}, false, err
+	}
+
+	res, err := s.DB.ExecContext(ctx, "insert ignore into idempotency_key(scope,idem_key,fingerprint,expires_at)"+
+		"values(?,?,?,CURRENT_TIMESTAMP + interval ? second)", request.Scope, request.Key, request.Fingerprint, window)
+	if err != nil {
+		log.Print(err)
+		return entities.IdempotentRequest{}, false, err
+	}
+
+	if affected, err := res.RowsAffected(); err != nil || affected == 1 {
+		return request, err == nil, err
+	}
+
+	recorded := entities.IdempotentRequest{Scope: request.Scope, Key: request.Key}
+
+	var (
+		status      sql.NullInt64
+		contentType sql.NullString
+	)
+
+	err = s.DB.QueryRowContext(ctx, "select fingerprint,status,content_type,body from idempotency_key where scope=? "+
+		"and idem_key=?", request.Scope, request.Key).Scan(&recorded.Fingerprint, &status, &contentType,
+		&recorded.Body)
+	if err != nil {
+		log.Print(err)
+		return entities.IdempotentRequest{}, false, err
+	}
+
+	recorded.Status = int(status.Int64)
+	recorded.ContentType = contentType.String
+
+	return recorded, false, nil
+}
+
+// Complete : records the response of the request so a retry gets it again
+func (s Store) Complete(ctx context.Context, request entities.IdempotentRequest) error {
+	_, err := s.DB.ExecContext(ctx, "update idempotency_key set status=?,content_type=?,body=? where scope=? and "+
+		"idem_key=?", request.Status, request.ContentType, request.Body, request.Scope, request.Key)
+	if err != nil {
+		log.Print(err)
+	}
+
+	return err
+}
+
+// Release : frees the key so a retry runs the request again
+func (s Store) Release(ctx context.Context, scope, key string) error {
+	_, err := s.DB.ExecContext(ctx, "delete from idempotency_key where scope=? and idem_key=?", scope, key)
+	if err != nil {
+		log.Print(err)
+	}
+
+	return err
+}
+
+// DeleteExpired : deletes the keys whose window is over and gives how many
+func (s Store) DeleteExpired(ctx context.Context) (int64, error) {
+	res, err := s.DB.ExecContext(ctx, "delete from idempotency_key where expires_at<=CURRENT_TIMESTAMP")
+	if err != nil {
+		log.Print(err)
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
@@ -0,0 +1,126 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+const (
+	deleteExpiredKey = "delete from idempotency_key where scope=? and idem_key=? and expires_at<=CURRENT_TIMESTAMP"
+	insertKey        = "insert ignore into idempotency_key(scope,idem_key,fingerprint,expires_at)values(?,?,?," +
+		"CURRENT_TIMESTAMP + interval ? second)"
+	selectKey = "select fingerprint,status,content_type,body from idempotency_key where scope=? and idem_key=?"
+)
+
+// TestReserve : to test a free key is taken and a taken one gives the request recorded with it
+func TestReserve(t *testing.T) {
+	request := entities.IdempotentRequest{Scope: "asha", Key: "k1", Fingerprint: "f1"}
+
+	testcases := []struct {
+		desc     string
+		inserted int64
+		rows     *sqlmock.Rows
+		err      error
+
+		expected   entities.IdempotentRequest
+		expectedOK bool
+	}{
+		{desc: "free", inserted: 1, expected: request, expectedOK: true},
+		{desc: "answered", rows: sqlmock.NewRows([]string{"fingerprint", "status", "content_type", "body"}).
+			AddRow("f1", 201, "application/json", []byte(`{"bookID":3}`)),
+			expected: entities.IdempotentRequest{Scope: "asha", Key: "k1", Fingerprint: "f1", Status: 201,
+				ContentType: "application/json", Body: []byte(`{"bookID":3}`)}},
+		{desc: "in progress", rows: sqlmock.NewRows([]string{"fingerprint", "status", "content_type", "body"}).
+			AddRow("f0", nil, nil, nil), expected: entities.IdempotentRequest{Scope: "asha", Key: "k1",
+			Fingerprint: "f0"}},
+		{desc: "released meanwhile", err: errors.New("sql: no rows in result set")},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectExec(deleteExpiredKey).WithArgs("asha", "k1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(insertKey).WithArgs("asha", "k1", "f1", 3600).
+			WillReturnResult(sqlmock.NewResult(0, tc.inserted))
+
+		if tc.inserted == 0 {
+			query := mock.ExpectQuery(selectKey).WithArgs("asha", "k1")
+			if tc.err != nil {
+				query.WillReturnError(tc.err)
+			} else {
+				query.WillReturnRows(tc.rows)
+			}
+		}
+
+		recorded, ok, err := New(db).Reserve(context.TODO(), request, 3600)
+		if !reflect.DeepEqual(recorded, tc.expected) || ok != tc.expectedOK || !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("%v: unexpected result %v %v %v", tc.desc, recorded, ok, err)
+		}
+
+		if err = mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%v: unmet expectations: %v", tc.desc, err)
+		}
+
+		db.Close()
+	}
+}
+
+// TestComplete : to test the response is recorded with the key
+func TestComplete(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("update idempotency_key set status=?,content_type=?,body=? where scope=? and idem_key=?").
+		WithArgs(201, "application/json", []byte(`{"bookID":3}`), "asha", "k1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = New(db).Complete(context.TODO(), entities.IdempotentRequest{Scope: "asha", Key: "k1", Status: 201,
+		ContentType: "application/json", Body: []byte(`{"bookID":3}`)})
+	if err != nil {
+		t.Errorf("failed to complete: %v", err)
+	}
+}
+
+// TestRelease : to test the key is freed
+func TestRelease(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("delete from idempotency_key where scope=? and idem_key=?").WithArgs("asha", "k1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err = New(db).Release(context.TODO(), "asha", "k1"); err != nil {
+		t.Errorf("failed to release: %v", err)
+	}
+}
+
+// TestDeleteExpired : to test the expired keys are counted
+func TestDeleteExpired(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("delete from idempotency_key where expires_at<=CURRENT_TIMESTAMP").
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	if deleted, err := New(db).DeleteExpired(context.TODO()); deleted != 4 || err != nil {
+		t.Errorf("unexpected result %v %v", deleted, err)
+	}
+}
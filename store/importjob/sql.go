@@ -0,0 +1,94 @@
+package importjob
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+type Store struct {
+	DB *sql.DB
+}
+
+// New : factory function
+func New(db *sql.DB) Store {
+	return Store{db}
+}
+
+const columns = "job_id,entity,format,mode,dry_run,mapping,status,total,imported,skipped,failed,errors,actor," +
+	"created_at,finished_at"
+
+// Post : inserts a new import job
+func (s Store) Post(ctx context.Context, job entities.ImportJob) (int, error) {
+	mapping, err := json.Marshal(job.Mapping)
+	if err != nil {
+		return -1, err
+	}
+
+	res, err := s.DB.ExecContext(ctx, "insert into import_job(entity,format,mode,dry_run,mapping,status,actor)"+
+		"values(?,?,?,?,?,?,?)", job.Entity, job.Format, job.Mode, job.DryRun, string(mapping), job.Status, job.Actor)
+	if err != nil {
+		log.Print(err)
+		return -1, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(id), nil
+}
+
+// Put : stores the progress of the job, finished jobs get their finishing time
+func (s Store) Put(ctx context.Context, job entities.ImportJob) error {
+	errs, err := json.Marshal(job.Errors)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.DB.ExecContext(ctx, "update import_job set status=?,total=?,imported=?,skipped=?,failed=?,errors=?,"+
+		"finished_at=if(? in ('done','failed'),now(),null) where job_id=?", job.Status, job.Total, job.Imported,
+		job.Skipped, job.Failed, string(errs), job.Status, job.JobID)
+	if err != nil {
+		log.Print(err)
+	}
+
+	return err
+}
+
+// GetByID : gives the import job with particular id
+func (s Store) GetByID(ctx context.Context, id int) (entities.ImportJob, error) {
+	var (
+		job           entities.ImportJob
+		mapping, errs []byte
+		finishedAt    sql.NullString
+	)
+
+	row := s.DB.QueryRowContext(ctx, "select "+columns+" from import_job where job_id=?", id)
+
+	err := row.Scan(&job.JobID, &job.Entity, &job.Format, &job.Mode, &job.DryRun, &mapping, &job.Status, &job.Total,
+		&job.Imported, &job.Skipped, &job.Failed, &errs, &job.Actor, &job.CreatedAt, &finishedAt)
+	if err != nil {
+		return entities.ImportJob{}, err
+	}
+
+	if len(mapping) > 0 {
+		if err = json.Unmarshal(mapping, &job.Mapping); err != nil {
+			return entities.ImportJob{}, err
+		}
+	}
+
+	if len(errs) > 0 {
+		if err = json.Unmarshal(errs, &job.Errors); err != nil {
+			return entities.ImportJob{}, err
+		}
+	}
+
+	job.FinishedAt = finishedAt.String
+
+	return job, nil
+}
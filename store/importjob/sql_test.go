@@ -0,0 +1,57 @@
+package importjob
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+// TestPut : to test the progress and the errors of a job are stored
+func TestPut(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	job := entities.ImportJob{JobID: 2, Status: entities.ImportDone, Total: 3, Imported: 2, Failed: 1,
+		Errors: []entities.ImportError{{Line: 4, Reason: "missing author"}}}
+
+	mock.ExpectExec("update import_job set status=?,total=?,imported=?,skipped=?,failed=?,errors=?,"+
+		"finished_at=if(? in ('done','failed'),now(),null) where job_id=?").
+		WithArgs("done", 3, 2, 0, 1, `[{"line":4,"reason":"missing author"}]`, "done", 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err = New(db).Put(context.TODO(), job); err != nil {
+		t.Errorf("failed to store progress: %v", err)
+	}
+}
+
+// TestGetByID : to test a job is read back with its mapping and errors
+func TestGetByID(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	cols := []string{"job_id", "entity", "format", "mode", "dry_run", "mapping", "status", "total", "imported",
+		"skipped", "failed", "errors", "actor", "created_at", "finished_at"}
+
+	mock.ExpectQuery("select " + columns + " from import_job where job_id=?").WithArgs(2).
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(2, "book", "csv", "atomic", false, `{"Book Title":"title"}`,
+			"running", 0, 0, 0, 0, nil, "asha", "2022-08-01 10:00:00", nil))
+
+	expected := entities.ImportJob{JobID: 2, ImportOptions: entities.ImportOptions{Entity: "book", Format: "csv",
+		Mode: "atomic", Mapping: map[string]string{"Book Title": "title"}}, Status: "running", Actor: "asha",
+		CreatedAt: "2022-08-01 10:00:00"}
+
+	job, err := New(db).GetByID(context.TODO(), 2)
+	if err != nil || !reflect.DeepEqual(job, expected) {
+		t.Errorf("unexpected job %+v %v", job, err)
+	}
+}
@@ -10,14 +10,156 @@ type AuthorStorer interface {
 	Put(ctx context.Context, author entities.Author, id int) (int, error)
 	Delete(ctx context.Context, id int) (int, error)
 	IncludeAuthor(ctx context.Context, id int) (entities.Author, error)
+	GetAuthorByName(ctx context.Context, firstName, lastName string) (entities.Author, error)
+	EachAuthor(ctx context.Context, filter entities.AuthorFilter, projection entities.Projection,
+		fn func(author entities.Author) error) error
+	GetAuthorsByIDs(ctx context.Context, ids []int) (map[int]entities.Author, error)
+	GetBooksByAuthors(ctx context.Context, ids []int) (map[int][]entities.Book, error)
 }
 
 type BookStorer interface {
-	GetAllBook(ctx context.Context) ([]entities.Book, error)
 	GetBooksByTitle(ctx context.Context, title string) ([]entities.Book, error)
+	EachBook(ctx context.Context, filter entities.BookFilter, projection entities.Projection,
+		fn func(book entities.Book) error) error
 
 	GetBookByID(ctx context.Context, id int) (entities.Book, error)
+	GetBookByISBN(ctx context.Context, isbn string) (entities.Book, error)
 	Post(ctx context.Context, book *entities.Book) (int, error)
 	Put(ctx context.Context, book *entities.Book, id int) (int, error)
 	Delete(ctx context.Context, id int) (int, error)
+
+	GetGenres(ctx context.Context, id int) ([]entities.Genre, error)
+	SetGenres(ctx context.Context, id int, genreIDs []int) error
+	GetTags(ctx context.Context, id int) ([]string, error)
+	SetTags(ctx context.Context, id int, tags []string) error
+	GetSeries(ctx context.Context, id int) ([]entities.BookSeries, error)
+	GetSeriesByBooks(ctx context.Context, ids []int) (map[int][]entities.BookSeries, error)
+	GetEdition(ctx context.Context, id int) (entities.Edition, error)
+	SetEdition(ctx context.Context, id int, edition entities.Edition) error
+	GetEditionsByWork(ctx context.Context, workID int) ([]entities.Edition, error)
+	GetRatings(ctx context.Context, ids []int) (map[int]entities.Rating, error)
+	GetRelated(ctx context.Context, id int) ([]int, error)
+}
+
+// BookInvalidator : drops books from the cache in front of a book store
+type BookInvalidator interface {
+	Invalidate(ctx context.Context, ids ...int)
+}
+
+type GenreStorer interface {
+	Post(ctx context.Context, genre entities.Genre) (int, error)
+	Put(ctx context.Context, genre entities.Genre, id int) (int, error)
+	Delete(ctx context.Context, id int) (int, error)
+	GetByID(ctx context.Context, id int) (entities.Genre, error)
+	GetAll(ctx context.Context) ([]entities.Genre, error)
+	GetDescendants(ctx context.Context, id int) ([]entities.Genre, error)
+	GetBookIDs(ctx context.Context, id int) ([]int, error)
+}
+
+type TagStorer interface {
+	Post(ctx context.Context, tag entities.Tag) (int, error)
+	GetAll(ctx context.Context) ([]entities.Tag, error)
+	Delete(ctx context.Context, id int) (int, error)
+	GetBookIDs(ctx context.Context, id int) ([]int, error)
+}
+
+type SeriesStorer interface {
+	Post(ctx context.Context, series entities.Series) (int, error)
+	Put(ctx context.Context, series entities.Series, id int) (int, error)
+	Delete(ctx context.Context, id int) (int, error)
+	GetByID(ctx context.Context, id int) (entities.Series, error)
+	GetBooks(ctx context.Context, id int) ([]entities.SeriesBook, error)
+	AddBook(ctx context.Context, id int, book entities.SeriesBook) error
+	RemoveBook(ctx context.Context, id, bookID int) (int, error)
+}
+
+type WorkStorer interface {
+	Post(ctx context.Context, work entities.Work) (int, error)
+	Put(ctx context.Context, work entities.Work, id int) (int, error)
+	Delete(ctx context.Context, id int) (int, error)
+	GetByID(ctx context.Context, id int) (entities.Work, error)
+	GetByTitle(ctx context.Context, authorID int, title string) (entities.Work, error)
+}
+
+type ReviewStorer interface {
+	Post(ctx context.Context, review entities.Review) (int, error)
+	GetByID(ctx context.Context, id int) (entities.Review, error)
+	Exists(ctx context.Context, bookID int, reviewer string) (bool, error)
+	GetByBook(ctx context.Context, bookID int, filter entities.ReviewFilter) ([]entities.Review, error)
+	Count(ctx context.Context, bookID int, status string) (int, error)
+	UpdateStatus(ctx context.Context, id int, status string) (int, error)
+	MarkHelpful(ctx context.Context, id int, voter string) (int, error)
+	Delete(ctx context.Context, id int) (int, error)
+}
+
+type APIKeyStorer interface {
+	Post(ctx context.Context, key entities.APIKey) (int, error)
+	GetAll(ctx context.Context) ([]entities.APIKey, error)
+	GetByID(ctx context.Context, id int) (entities.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (entities.APIKey, error)
+	Put(ctx context.Context, key entities.APIKey, id int) (int, error)
+	Revoke(ctx context.Context, id int) (int, error)
+	TouchLastUsed(ctx context.Context, id int) error
+}
+
+type AuditStorer interface {
+	Post(ctx context.Context, entry entities.AuditEntry) error
+	GetAll(ctx context.Context, filter entities.AuditFilter) ([]entities.AuditEntry, error)
+}
+
+type HistoryStorer interface {
+	PostAuthor(ctx context.Context, rev entities.AuthorRevision) error
+	GetAuthorHistory(ctx context.Context, id int) ([]entities.AuthorRevision, error)
+	PostBook(ctx context.Context, rev entities.BookRevision) error
+	GetBookHistory(ctx context.Context, id int) ([]entities.BookRevision, error)
+	GetBookRevision(ctx context.Context, id, revision int) (entities.BookRevision, error)
+	GetBookAsOf(ctx context.Context, id int, asOf string) (entities.BookRevision, error)
+}
+
+type OutboxStorer interface {
+	Post(ctx context.Context, event entities.Event) error
+	GetPending(ctx context.Context, limit int) ([]entities.Event, error)
+	GetSince(ctx context.Context, after int, filter entities.EventFilter, limit int) ([]entities.Event, error)
+	LastID(ctx context.Context) (int, error)
+	MarkPublished(ctx context.Context, ids []int) error
+	DeletePublished(ctx context.Context, retention int) (int64, error)
+	Lock(ctx context.Context) (release func(), ok bool, err error)
+}
+
+type WebhookStorer interface {
+	Post(ctx context.Context, webhook entities.Webhook) (int, error)
+	GetAll(ctx context.Context) ([]entities.Webhook, error)
+	GetByID(ctx context.Context, id int) (entities.Webhook, error)
+	Put(ctx context.Context, webhook entities.Webhook, id int) (int, error)
+	Delete(ctx context.Context, id int) (int, error)
+	PostDelivery(ctx context.Context, delivery entities.Delivery) error
+	GetDeliveries(ctx context.Context, webhookID int, filter entities.DeliveryFilter) ([]entities.Delivery, error)
+	GetDelivery(ctx context.Context, webhookID, id int) (entities.Delivery, error)
+	GetDue(ctx context.Context, limit int) ([]entities.Delivery, error)
+	Claim(ctx context.Context, delivery entities.Delivery, lease int) (bool, error)
+	Record(ctx context.Context, delivery entities.Delivery, retryIn int) error
+	Redeliver(ctx context.Context, webhookID, id int) (int, error)
+}
+
+type IdempotencyStorer interface {
+	Reserve(ctx context.Context, request entities.IdempotentRequest, window int) (entities.IdempotentRequest, bool,
+		error)
+	Complete(ctx context.Context, request entities.IdempotentRequest) error
+	Release(ctx context.Context, scope, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type ImportJobStorer interface {
+	Post(ctx context.Context, job entities.ImportJob) (int, error)
+	Put(ctx context.Context, job entities.ImportJob) error
+	GetByID(ctx context.Context, id int) (entities.ImportJob, error)
+}
+
+type OpenLibraryKeyStorer interface {
+	Get(ctx context.Context, key string) (int, error)
+	Post(ctx context.Context, key string, id int) error
+}
+
+type Transactor interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
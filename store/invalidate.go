@@ -0,0 +1,11 @@
+package store
+
+import "context"
+
+// InvalidateBooks : drops the books from the cache in front of the book store when there is one, for the changes
+// which touch a book without going through the book store
+func InvalidateBooks(ctx context.Context, s BookStorer, ids ...int) {
+	if i, ok := s.(BookInvalidator); ok && len(ids) > 0 {
+		i.Invalidate(ctx, ids...)
+	}
+}
@@ -50,6 +50,65 @@ func (mr *MockAuthorStorerMockRecorder) Delete(ctx, id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAuthorStorer)(nil).Delete), ctx, id)
 }
 
+// EachAuthor mocks base method.
+func (m *MockAuthorStorer) EachAuthor(ctx context.Context, filter entities.AuthorFilter, projection entities.Projection, fn func(entities.Author) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EachAuthor", ctx, filter, projection, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EachAuthor indicates an expected call of EachAuthor.
+func (mr *MockAuthorStorerMockRecorder) EachAuthor(ctx, filter, projection, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EachAuthor", reflect.TypeOf((*MockAuthorStorer)(nil).EachAuthor), ctx, filter, projection, fn)
+}
+
+// GetAuthorByName mocks base method.
+func (m *MockAuthorStorer) GetAuthorByName(ctx context.Context, firstName, lastName string) (entities.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorByName", ctx, firstName, lastName)
+	ret0, _ := ret[0].(entities.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorByName indicates an expected call of GetAuthorByName.
+func (mr *MockAuthorStorerMockRecorder) GetAuthorByName(ctx, firstName, lastName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorByName", reflect.TypeOf((*MockAuthorStorer)(nil).GetAuthorByName), ctx, firstName, lastName)
+}
+
+// GetAuthorsByIDs mocks base method.
+func (m *MockAuthorStorer) GetAuthorsByIDs(ctx context.Context, ids []int) (map[int]entities.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorsByIDs", ctx, ids)
+	ret0, _ := ret[0].(map[int]entities.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorsByIDs indicates an expected call of GetAuthorsByIDs.
+func (mr *MockAuthorStorerMockRecorder) GetAuthorsByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorsByIDs", reflect.TypeOf((*MockAuthorStorer)(nil).GetAuthorsByIDs), ctx, ids)
+}
+
+// GetBooksByAuthors mocks base method.
+func (m *MockAuthorStorer) GetBooksByAuthors(ctx context.Context, ids []int) (map[int][]entities.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBooksByAuthors", ctx, ids)
+	ret0, _ := ret[0].(map[int][]entities.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBooksByAuthors indicates an expected call of GetBooksByAuthors.
+func (mr *MockAuthorStorerMockRecorder) GetBooksByAuthors(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooksByAuthors", reflect.TypeOf((*MockAuthorStorer)(nil).GetBooksByAuthors), ctx, ids)
+}
+
 // IncludeAuthor mocks base method.
 func (m *MockAuthorStorer) IncludeAuthor(ctx context.Context, id int) (entities.Author, error) {
 	m.ctrl.T.Helper()
@@ -133,19 +192,18 @@ func (mr *MockBookStorerMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBookStorer)(nil).Delete), ctx, id)
 }
 
-// GetAllBook mocks base method.
-func (m *MockBookStorer) GetAllBook(ctx context.Context) ([]entities.Book, error) {
+// EachBook mocks base method.
+func (m *MockBookStorer) EachBook(ctx context.Context, filter entities.BookFilter, projection entities.Projection, fn func(entities.Book) error) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllBook", ctx)
-	ret0, _ := ret[0].([]entities.Book)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "EachBook", ctx, filter, projection, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetAllBook indicates an expected call of GetAllBook.
-func (mr *MockBookStorerMockRecorder) GetAllBook(ctx interface{}) *gomock.Call {
+// EachBook indicates an expected call of EachBook.
+func (mr *MockBookStorerMockRecorder) EachBook(ctx, filter, projection, fn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllBook", reflect.TypeOf((*MockBookStorer)(nil).GetAllBook), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EachBook", reflect.TypeOf((*MockBookStorer)(nil).EachBook), ctx, filter, projection, fn)
 }
 
 // GetBookByID mocks base method.
@@ -163,6 +221,21 @@ func (mr *MockBookStorerMockRecorder) GetBookByID(ctx, id interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByID", reflect.TypeOf((*MockBookStorer)(nil).GetBookByID), ctx, id)
 }
 
+// GetBookByISBN mocks base method.
+func (m *MockBookStorer) GetBookByISBN(ctx context.Context, isbn string) (entities.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookByISBN", ctx, isbn)
+	ret0, _ := ret[0].(entities.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookByISBN indicates an expected call of GetBookByISBN.
+func (mr *MockBookStorerMockRecorder) GetBookByISBN(ctx, isbn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByISBN", reflect.TypeOf((*MockBookStorer)(nil).GetBookByISBN), ctx, isbn)
+}
+
 // GetBooksByTitle mocks base method.
 func (m *MockBookStorer) GetBooksByTitle(ctx context.Context, title string) ([]entities.Book, error) {
 	m.ctrl.T.Helper()
@@ -178,6 +251,126 @@ func (mr *MockBookStorerMockRecorder) GetBooksByTitle(ctx, title interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooksByTitle", reflect.TypeOf((*MockBookStorer)(nil).GetBooksByTitle), ctx, title)
 }
 
+// GetEdition mocks base method.
+func (m *MockBookStorer) GetEdition(ctx context.Context, id int) (entities.Edition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEdition", ctx, id)
+	ret0, _ := ret[0].(entities.Edition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEdition indicates an expected call of GetEdition.
+func (mr *MockBookStorerMockRecorder) GetEdition(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEdition", reflect.TypeOf((*MockBookStorer)(nil).GetEdition), ctx, id)
+}
+
+// GetEditionsByWork mocks base method.
+func (m *MockBookStorer) GetEditionsByWork(ctx context.Context, workID int) ([]entities.Edition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEditionsByWork", ctx, workID)
+	ret0, _ := ret[0].([]entities.Edition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEditionsByWork indicates an expected call of GetEditionsByWork.
+func (mr *MockBookStorerMockRecorder) GetEditionsByWork(ctx, workID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEditionsByWork", reflect.TypeOf((*MockBookStorer)(nil).GetEditionsByWork), ctx, workID)
+}
+
+// GetGenres mocks base method.
+func (m *MockBookStorer) GetGenres(ctx context.Context, id int) ([]entities.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenres", ctx, id)
+	ret0, _ := ret[0].([]entities.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenres indicates an expected call of GetGenres.
+func (mr *MockBookStorerMockRecorder) GetGenres(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenres", reflect.TypeOf((*MockBookStorer)(nil).GetGenres), ctx, id)
+}
+
+// GetRatings mocks base method.
+func (m *MockBookStorer) GetRatings(ctx context.Context, ids []int) (map[int]entities.Rating, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRatings", ctx, ids)
+	ret0, _ := ret[0].(map[int]entities.Rating)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRatings indicates an expected call of GetRatings.
+func (mr *MockBookStorerMockRecorder) GetRatings(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRatings", reflect.TypeOf((*MockBookStorer)(nil).GetRatings), ctx, ids)
+}
+
+// GetRelated mocks base method.
+func (m *MockBookStorer) GetRelated(ctx context.Context, id int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRelated", ctx, id)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRelated indicates an expected call of GetRelated.
+func (mr *MockBookStorerMockRecorder) GetRelated(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRelated", reflect.TypeOf((*MockBookStorer)(nil).GetRelated), ctx, id)
+}
+
+// GetSeries mocks base method.
+func (m *MockBookStorer) GetSeries(ctx context.Context, id int) ([]entities.BookSeries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeries", ctx, id)
+	ret0, _ := ret[0].([]entities.BookSeries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeries indicates an expected call of GetSeries.
+func (mr *MockBookStorerMockRecorder) GetSeries(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeries", reflect.TypeOf((*MockBookStorer)(nil).GetSeries), ctx, id)
+}
+
+// GetSeriesByBooks mocks base method.
+func (m *MockBookStorer) GetSeriesByBooks(ctx context.Context, ids []int) (map[int][]entities.BookSeries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeriesByBooks", ctx, ids)
+	ret0, _ := ret[0].(map[int][]entities.BookSeries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeriesByBooks indicates an expected call of GetSeriesByBooks.
+func (mr *MockBookStorerMockRecorder) GetSeriesByBooks(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeriesByBooks", reflect.TypeOf((*MockBookStorer)(nil).GetSeriesByBooks), ctx, ids)
+}
+
+// GetTags mocks base method.
+func (m *MockBookStorer) GetTags(ctx context.Context, id int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTags", ctx, id)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTags indicates an expected call of GetTags.
+func (mr *MockBookStorerMockRecorder) GetTags(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockBookStorer)(nil).GetTags), ctx, id)
+}
+
 // Post mocks base method.
 func (m *MockBookStorer) Post(ctx context.Context, book *entities.Book) (int, error) {
 	m.ctrl.T.Helper()
@@ -207,3 +400,1520 @@ func (mr *MockBookStorerMockRecorder) Put(ctx, book, id interface{}) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockBookStorer)(nil).Put), ctx, book, id)
 }
+
+// SetEdition mocks base method.
+func (m *MockBookStorer) SetEdition(ctx context.Context, id int, edition entities.Edition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEdition", ctx, id, edition)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetEdition indicates an expected call of SetEdition.
+func (mr *MockBookStorerMockRecorder) SetEdition(ctx, id, edition interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEdition", reflect.TypeOf((*MockBookStorer)(nil).SetEdition), ctx, id, edition)
+}
+
+// SetGenres mocks base method.
+func (m *MockBookStorer) SetGenres(ctx context.Context, id int, genreIDs []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetGenres", ctx, id, genreIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetGenres indicates an expected call of SetGenres.
+func (mr *MockBookStorerMockRecorder) SetGenres(ctx, id, genreIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGenres", reflect.TypeOf((*MockBookStorer)(nil).SetGenres), ctx, id, genreIDs)
+}
+
+// SetTags mocks base method.
+func (m *MockBookStorer) SetTags(ctx context.Context, id int, tags []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTags", ctx, id, tags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTags indicates an expected call of SetTags.
+func (mr *MockBookStorerMockRecorder) SetTags(ctx, id, tags interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTags", reflect.TypeOf((*MockBookStorer)(nil).SetTags), ctx, id, tags)
+}
+
+// MockBookInvalidator is a mock of BookInvalidator interface.
+type MockBookInvalidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookInvalidatorMockRecorder
+}
+
+// MockBookInvalidatorMockRecorder is the mock recorder for MockBookInvalidator.
+type MockBookInvalidatorMockRecorder struct {
+	mock *MockBookInvalidator
+}
+
+// NewMockBookInvalidator creates a new mock instance.
+func NewMockBookInvalidator(ctrl *gomock.Controller) *MockBookInvalidator {
+	mock := &MockBookInvalidator{ctrl: ctrl}
+	mock.recorder = &MockBookInvalidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBookInvalidator) EXPECT() *MockBookInvalidatorMockRecorder {
+	return m.recorder
+}
+
+// Invalidate mocks base method.
+func (m *MockBookInvalidator) Invalidate(ctx context.Context, ids ...int) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Invalidate", varargs...)
+}
+
+// Invalidate indicates an expected call of Invalidate.
+func (mr *MockBookInvalidatorMockRecorder) Invalidate(ctx interface{}, ids ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invalidate", reflect.TypeOf((*MockBookInvalidator)(nil).Invalidate), varargs...)
+}
+
+// MockGenreStorer is a mock of GenreStorer interface.
+type MockGenreStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockGenreStorerMockRecorder
+}
+
+// MockGenreStorerMockRecorder is the mock recorder for MockGenreStorer.
+type MockGenreStorerMockRecorder struct {
+	mock *MockGenreStorer
+}
+
+// NewMockGenreStorer creates a new mock instance.
+func NewMockGenreStorer(ctrl *gomock.Controller) *MockGenreStorer {
+	mock := &MockGenreStorer{ctrl: ctrl}
+	mock.recorder = &MockGenreStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGenreStorer) EXPECT() *MockGenreStorerMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockGenreStorer) Delete(ctx context.Context, id int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockGenreStorerMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockGenreStorer)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockGenreStorer) GetAll(ctx context.Context) ([]entities.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]entities.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockGenreStorerMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockGenreStorer)(nil).GetAll), ctx)
+}
+
+// GetBookIDs mocks base method.
+func (m *MockGenreStorer) GetBookIDs(ctx context.Context, id int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookIDs", ctx, id)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookIDs indicates an expected call of GetBookIDs.
+func (mr *MockGenreStorerMockRecorder) GetBookIDs(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookIDs", reflect.TypeOf((*MockGenreStorer)(nil).GetBookIDs), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockGenreStorer) GetByID(ctx context.Context, id int) (entities.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(entities.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockGenreStorerMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockGenreStorer)(nil).GetByID), ctx, id)
+}
+
+// GetDescendants mocks base method.
+func (m *MockGenreStorer) GetDescendants(ctx context.Context, id int) ([]entities.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDescendants", ctx, id)
+	ret0, _ := ret[0].([]entities.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDescendants indicates an expected call of GetDescendants.
+func (mr *MockGenreStorerMockRecorder) GetDescendants(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDescendants", reflect.TypeOf((*MockGenreStorer)(nil).GetDescendants), ctx, id)
+}
+
+// Post mocks base method.
+func (m *MockGenreStorer) Post(ctx context.Context, genre entities.Genre) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, genre)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockGenreStorerMockRecorder) Post(ctx, genre interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockGenreStorer)(nil).Post), ctx, genre)
+}
+
+// Put mocks base method.
+func (m *MockGenreStorer) Put(ctx context.Context, genre entities.Genre, id int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, genre, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockGenreStorerMockRecorder) Put(ctx, genre, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockGenreStorer)(nil).Put), ctx, genre, id)
+}
+
+// MockTagStorer is a mock of TagStorer interface.
+type MockTagStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagStorerMockRecorder
+}
+
+// MockTagStorerMockRecorder is the mock recorder for MockTagStorer.
+type MockTagStorerMockRecorder struct {
+	mock *MockTagStorer
+}
+
+// NewMockTagStorer creates a new mock instance.
+func NewMockTagStorer(ctrl *gomock.Controller) *MockTagStorer {
+	mock := &MockTagStorer{ctrl: ctrl}
+	mock.recorder = &MockTagStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagStorer) EXPECT() *MockTagStorerMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockTagStorer) Delete(ctx context.Context, id int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTagStorerMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTagStorer)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockTagStorer) GetAll(ctx context.Context) ([]entities.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]entities.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTagStorerMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTagStorer)(nil).GetAll), ctx)
+}
+
+// GetBookIDs mocks base method.
+func (m *MockTagStorer) GetBookIDs(ctx context.Context, id int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookIDs", ctx, id)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookIDs indicates an expected call of GetBookIDs.
+func (mr *MockTagStorerMockRecorder) GetBookIDs(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookIDs", reflect.TypeOf((*MockTagStorer)(nil).GetBookIDs), ctx, id)
+}
+
+// Post mocks base method.
+func (m *MockTagStorer) Post(ctx context.Context, tag entities.Tag) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, tag)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockTagStorerMockRecorder) Post(ctx, tag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockTagStorer)(nil).Post), ctx, tag)
+}
+
+// MockSeriesStorer is a mock of SeriesStorer interface.
+type MockSeriesStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockSeriesStorerMockRecorder
+}
+
+// MockSeriesStorerMockRecorder is the mock recorder for MockSeriesStorer.
+type MockSeriesStorerMockRecorder struct {
+	mock *MockSeriesStorer
+}
+
+// NewMockSeriesStorer creates a new mock instance.
+func NewMockSeriesStorer(ctrl *gomock.Controller) *MockSeriesStorer {
+	mock := &MockSeriesStorer{ctrl: ctrl}
+	mock.recorder = &MockSeriesStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSeriesStorer) EXPECT() *MockSeriesStorerMockRecorder {
+	return m.recorder
+}
+
+// AddBook mocks base method.
+func (m *MockSeriesStorer) AddBook(ctx context.Context, id int, book entities.SeriesBook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBook", ctx, id, book)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddBook indicates an expected call of AddBook.
+func (mr *MockSeriesStorerMockRecorder) AddBook(ctx, id, book interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBook", reflect.TypeOf((*MockSeriesStorer)(nil).AddBook), ctx, id, book)
+}
+
+// Delete mocks base method.
+func (m *MockSeriesStorer) Delete(ctx context.Context, id int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSeriesStorerMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSeriesStorer)(nil).Delete), ctx, id)
+}
+
+// GetBooks mocks base method.
+func (m *MockSeriesStorer) GetBooks(ctx context.Context, id int) ([]entities.SeriesBook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBooks", ctx, id)
+	ret0, _ := ret[0].([]entities.SeriesBook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBooks indicates an expected call of GetBooks.
+func (mr *MockSeriesStorerMockRecorder) GetBooks(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooks", reflect.TypeOf((*MockSeriesStorer)(nil).GetBooks), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockSeriesStorer) GetByID(ctx context.Context, id int) (entities.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(entities.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockSeriesStorerMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockSeriesStorer)(nil).GetByID), ctx, id)
+}
+
+// Post mocks base method.
+func (m *MockSeriesStorer) Post(ctx context.Context, series entities.Series) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, series)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockSeriesStorerMockRecorder) Post(ctx, series interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockSeriesStorer)(nil).Post), ctx, series)
+}
+
+// Put mocks base method.
+func (m *MockSeriesStorer) Put(ctx context.Context, series entities.Series, id int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, series, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockSeriesStorerMockRecorder) Put(ctx, series, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockSeriesStorer)(nil).Put), ctx, series, id)
+}
+
+// RemoveBook mocks base method.
+func (m *MockSeriesStorer) RemoveBook(ctx context.Context, id, bookID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveBook", ctx, id, bookID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveBook indicates an expected call of RemoveBook.
+func (mr *MockSeriesStorerMockRecorder) RemoveBook(ctx, id, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveBook", reflect.TypeOf((*MockSeriesStorer)(nil).RemoveBook), ctx, id, bookID)
+}
+
+// MockWorkStorer is a mock of WorkStorer interface.
+type MockWorkStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkStorerMockRecorder
+}
+
+// MockWorkStorerMockRecorder is the mock recorder for MockWorkStorer.
+type MockWorkStorerMockRecorder struct {
+	mock *MockWorkStorer
+}
+
+// NewMockWorkStorer creates a new mock instance.
+func NewMockWorkStorer(ctrl *gomock.Controller) *MockWorkStorer {
+	mock := &MockWorkStorer{ctrl: ctrl}
+	mock.recorder = &MockWorkStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkStorer) EXPECT() *MockWorkStorerMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockWorkStorer) Delete(ctx context.Context, id int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWorkStorerMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWorkStorer)(nil).Delete), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockWorkStorer) GetByID(ctx context.Context, id int) (entities.Work, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(entities.Work)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockWorkStorerMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockWorkStorer)(nil).GetByID), ctx, id)
+}
+
+// GetByTitle mocks base method.
+func (m *MockWorkStorer) GetByTitle(ctx context.Context, authorID int, title string) (entities.Work, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByTitle", ctx, authorID, title)
+	ret0, _ := ret[0].(entities.Work)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByTitle indicates an expected call of GetByTitle.
+func (mr *MockWorkStorerMockRecorder) GetByTitle(ctx, authorID, title interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTitle", reflect.TypeOf((*MockWorkStorer)(nil).GetByTitle), ctx, authorID, title)
+}
+
+// Post mocks base method.
+func (m *MockWorkStorer) Post(ctx context.Context, work entities.Work) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, work)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockWorkStorerMockRecorder) Post(ctx, work interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockWorkStorer)(nil).Post), ctx, work)
+}
+
+// Put mocks base method.
+func (m *MockWorkStorer) Put(ctx context.Context, work entities.Work, id int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, work, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockWorkStorerMockRecorder) Put(ctx, work, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockWorkStorer)(nil).Put), ctx, work, id)
+}
+
+// MockReviewStorer is a mock of ReviewStorer interface.
+type MockReviewStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockReviewStorerMockRecorder
+}
+
+// MockReviewStorerMockRecorder is the mock recorder for MockReviewStorer.
+type MockReviewStorerMockRecorder struct {
+	mock *MockReviewStorer
+}
+
+// NewMockReviewStorer creates a new mock instance.
+func NewMockReviewStorer(ctrl *gomock.Controller) *MockReviewStorer {
+	mock := &MockReviewStorer{ctrl: ctrl}
+	mock.recorder = &MockReviewStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReviewStorer) EXPECT() *MockReviewStorerMockRecorder {
+	return m.recorder
+}
+
+// Count mocks base method.
+func (m *MockReviewStorer) Count(ctx context.Context, bookID int, status string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, bookID, status)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockReviewStorerMockRecorder) Count(ctx, bookID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockReviewStorer)(nil).Count), ctx, bookID, status)
+}
+
+// Delete mocks base method.
+func (m *MockReviewStorer) Delete(ctx context.Context, id int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockReviewStorerMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockReviewStorer)(nil).Delete), ctx, id)
+}
+
+// Exists mocks base method.
+func (m *MockReviewStorer) Exists(ctx context.Context, bookID int, reviewer string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", ctx, bookID, reviewer)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockReviewStorerMockRecorder) Exists(ctx, bookID, reviewer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockReviewStorer)(nil).Exists), ctx, bookID, reviewer)
+}
+
+// GetByBook mocks base method.
+func (m *MockReviewStorer) GetByBook(ctx context.Context, bookID int, filter entities.ReviewFilter) ([]entities.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByBook", ctx, bookID, filter)
+	ret0, _ := ret[0].([]entities.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByBook indicates an expected call of GetByBook.
+func (mr *MockReviewStorerMockRecorder) GetByBook(ctx, bookID, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByBook", reflect.TypeOf((*MockReviewStorer)(nil).GetByBook), ctx, bookID, filter)
+}
+
+// GetByID mocks base method.
+func (m *MockReviewStorer) GetByID(ctx context.Context, id int) (entities.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(entities.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockReviewStorerMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockReviewStorer)(nil).GetByID), ctx, id)
+}
+
+// MarkHelpful mocks base method.
+func (m *MockReviewStorer) MarkHelpful(ctx context.Context, id int, voter string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkHelpful", ctx, id, voter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkHelpful indicates an expected call of MarkHelpful.
+func (mr *MockReviewStorerMockRecorder) MarkHelpful(ctx, id, voter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkHelpful", reflect.TypeOf((*MockReviewStorer)(nil).MarkHelpful), ctx, id, voter)
+}
+
+// Post mocks base method.
+func (m *MockReviewStorer) Post(ctx context.Context, review entities.Review) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, review)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockReviewStorerMockRecorder) Post(ctx, review interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockReviewStorer)(nil).Post), ctx, review)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockReviewStorer) UpdateStatus(ctx context.Context, id int, status string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, status)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockReviewStorerMockRecorder) UpdateStatus(ctx, id, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockReviewStorer)(nil).UpdateStatus), ctx, id, status)
+}
+
+// MockAPIKeyStorer is a mock of APIKeyStorer interface.
+type MockAPIKeyStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyStorerMockRecorder
+}
+
+// MockAPIKeyStorerMockRecorder is the mock recorder for MockAPIKeyStorer.
+type MockAPIKeyStorerMockRecorder struct {
+	mock *MockAPIKeyStorer
+}
+
+// NewMockAPIKeyStorer creates a new mock instance.
+func NewMockAPIKeyStorer(ctrl *gomock.Controller) *MockAPIKeyStorer {
+	mock := &MockAPIKeyStorer{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyStorer) EXPECT() *MockAPIKeyStorerMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method.
+func (m *MockAPIKeyStorer) GetAll(ctx context.Context) ([]entities.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]entities.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAPIKeyStorerMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAPIKeyStorer)(nil).GetAll), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockAPIKeyStorer) GetByID(ctx context.Context, id int) (entities.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(entities.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAPIKeyStorerMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAPIKeyStorer)(nil).GetByID), ctx, id)
+}
+
+// GetByPrefix mocks base method.
+func (m *MockAPIKeyStorer) GetByPrefix(ctx context.Context, prefix string) (entities.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPrefix", ctx, prefix)
+	ret0, _ := ret[0].(entities.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPrefix indicates an expected call of GetByPrefix.
+func (mr *MockAPIKeyStorerMockRecorder) GetByPrefix(ctx, prefix interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPrefix", reflect.TypeOf((*MockAPIKeyStorer)(nil).GetByPrefix), ctx, prefix)
+}
+
+// Post mocks base method.
+func (m *MockAPIKeyStorer) Post(ctx context.Context, key entities.APIKey) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, key)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockAPIKeyStorerMockRecorder) Post(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockAPIKeyStorer)(nil).Post), ctx, key)
+}
+
+// Put mocks base method.
+func (m *MockAPIKeyStorer) Put(ctx context.Context, key entities.APIKey, id int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, key, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockAPIKeyStorerMockRecorder) Put(ctx, key, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockAPIKeyStorer)(nil).Put), ctx, key, id)
+}
+
+// Revoke mocks base method.
+func (m *MockAPIKeyStorer) Revoke(ctx context.Context, id int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockAPIKeyStorerMockRecorder) Revoke(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockAPIKeyStorer)(nil).Revoke), ctx, id)
+}
+
+// TouchLastUsed mocks base method.
+func (m *MockAPIKeyStorer) TouchLastUsed(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchLastUsed", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchLastUsed indicates an expected call of TouchLastUsed.
+func (mr *MockAPIKeyStorerMockRecorder) TouchLastUsed(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchLastUsed", reflect.TypeOf((*MockAPIKeyStorer)(nil).TouchLastUsed), ctx, id)
+}
+
+// MockAuditStorer is a mock of AuditStorer interface.
+type MockAuditStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditStorerMockRecorder
+}
+
+// MockAuditStorerMockRecorder is the mock recorder for MockAuditStorer.
+type MockAuditStorerMockRecorder struct {
+	mock *MockAuditStorer
+}
+
+// NewMockAuditStorer creates a new mock instance.
+func NewMockAuditStorer(ctrl *gomock.Controller) *MockAuditStorer {
+	mock := &MockAuditStorer{ctrl: ctrl}
+	mock.recorder = &MockAuditStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditStorer) EXPECT() *MockAuditStorerMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method.
+func (m *MockAuditStorer) GetAll(ctx context.Context, filter entities.AuditFilter) ([]entities.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, filter)
+	ret0, _ := ret[0].([]entities.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAuditStorerMockRecorder) GetAll(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAuditStorer)(nil).GetAll), ctx, filter)
+}
+
+// Post mocks base method.
+func (m *MockAuditStorer) Post(ctx context.Context, entry entities.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockAuditStorerMockRecorder) Post(ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockAuditStorer)(nil).Post), ctx, entry)
+}
+
+// MockHistoryStorer is a mock of HistoryStorer interface.
+type MockHistoryStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryStorerMockRecorder
+}
+
+// MockHistoryStorerMockRecorder is the mock recorder for MockHistoryStorer.
+type MockHistoryStorerMockRecorder struct {
+	mock *MockHistoryStorer
+}
+
+// NewMockHistoryStorer creates a new mock instance.
+func NewMockHistoryStorer(ctrl *gomock.Controller) *MockHistoryStorer {
+	mock := &MockHistoryStorer{ctrl: ctrl}
+	mock.recorder = &MockHistoryStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistoryStorer) EXPECT() *MockHistoryStorerMockRecorder {
+	return m.recorder
+}
+
+// GetAuthorHistory mocks base method.
+func (m *MockHistoryStorer) GetAuthorHistory(ctx context.Context, id int) ([]entities.AuthorRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorHistory", ctx, id)
+	ret0, _ := ret[0].([]entities.AuthorRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorHistory indicates an expected call of GetAuthorHistory.
+func (mr *MockHistoryStorerMockRecorder) GetAuthorHistory(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorHistory", reflect.TypeOf((*MockHistoryStorer)(nil).GetAuthorHistory), ctx, id)
+}
+
+// GetBookAsOf mocks base method.
+func (m *MockHistoryStorer) GetBookAsOf(ctx context.Context, id int, asOf string) (entities.BookRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookAsOf", ctx, id, asOf)
+	ret0, _ := ret[0].(entities.BookRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookAsOf indicates an expected call of GetBookAsOf.
+func (mr *MockHistoryStorerMockRecorder) GetBookAsOf(ctx, id, asOf interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookAsOf", reflect.TypeOf((*MockHistoryStorer)(nil).GetBookAsOf), ctx, id, asOf)
+}
+
+// GetBookHistory mocks base method.
+func (m *MockHistoryStorer) GetBookHistory(ctx context.Context, id int) ([]entities.BookRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookHistory", ctx, id)
+	ret0, _ := ret[0].([]entities.BookRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookHistory indicates an expected call of GetBookHistory.
+func (mr *MockHistoryStorerMockRecorder) GetBookHistory(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookHistory", reflect.TypeOf((*MockHistoryStorer)(nil).GetBookHistory), ctx, id)
+}
+
+// GetBookRevision mocks base method.
+func (m *MockHistoryStorer) GetBookRevision(ctx context.Context, id, revision int) (entities.BookRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookRevision", ctx, id, revision)
+	ret0, _ := ret[0].(entities.BookRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookRevision indicates an expected call of GetBookRevision.
+func (mr *MockHistoryStorerMockRecorder) GetBookRevision(ctx, id, revision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookRevision", reflect.TypeOf((*MockHistoryStorer)(nil).GetBookRevision), ctx, id, revision)
+}
+
+// PostAuthor mocks base method.
+func (m *MockHistoryStorer) PostAuthor(ctx context.Context, rev entities.AuthorRevision) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostAuthor", ctx, rev)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostAuthor indicates an expected call of PostAuthor.
+func (mr *MockHistoryStorerMockRecorder) PostAuthor(ctx, rev interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostAuthor", reflect.TypeOf((*MockHistoryStorer)(nil).PostAuthor), ctx, rev)
+}
+
+// PostBook mocks base method.
+func (m *MockHistoryStorer) PostBook(ctx context.Context, rev entities.BookRevision) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostBook", ctx, rev)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostBook indicates an expected call of PostBook.
+func (mr *MockHistoryStorerMockRecorder) PostBook(ctx, rev interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostBook", reflect.TypeOf((*MockHistoryStorer)(nil).PostBook), ctx, rev)
+}
+
+// MockOutboxStorer is a mock of OutboxStorer interface.
+type MockOutboxStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxStorerMockRecorder
+}
+
+// MockOutboxStorerMockRecorder is the mock recorder for MockOutboxStorer.
+type MockOutboxStorerMockRecorder struct {
+	mock *MockOutboxStorer
+}
+
+// NewMockOutboxStorer creates a new mock instance.
+func NewMockOutboxStorer(ctrl *gomock.Controller) *MockOutboxStorer {
+	mock := &MockOutboxStorer{ctrl: ctrl}
+	mock.recorder = &MockOutboxStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxStorer) EXPECT() *MockOutboxStorerMockRecorder {
+	return m.recorder
+}
+
+// DeletePublished mocks base method.
+func (m *MockOutboxStorer) DeletePublished(ctx context.Context, retention int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePublished", ctx, retention)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePublished indicates an expected call of DeletePublished.
+func (mr *MockOutboxStorerMockRecorder) DeletePublished(ctx, retention interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePublished", reflect.TypeOf((*MockOutboxStorer)(nil).DeletePublished), ctx, retention)
+}
+
+// GetPending mocks base method.
+func (m *MockOutboxStorer) GetPending(ctx context.Context, limit int) ([]entities.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPending", ctx, limit)
+	ret0, _ := ret[0].([]entities.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPending indicates an expected call of GetPending.
+func (mr *MockOutboxStorerMockRecorder) GetPending(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPending", reflect.TypeOf((*MockOutboxStorer)(nil).GetPending), ctx, limit)
+}
+
+// GetSince mocks base method.
+func (m *MockOutboxStorer) GetSince(ctx context.Context, after int, filter entities.EventFilter, limit int) ([]entities.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSince", ctx, after, filter, limit)
+	ret0, _ := ret[0].([]entities.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSince indicates an expected call of GetSince.
+func (mr *MockOutboxStorerMockRecorder) GetSince(ctx, after, filter, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSince", reflect.TypeOf((*MockOutboxStorer)(nil).GetSince), ctx, after, filter, limit)
+}
+
+// LastID mocks base method.
+func (m *MockOutboxStorer) LastID(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastID", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastID indicates an expected call of LastID.
+func (mr *MockOutboxStorerMockRecorder) LastID(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastID", reflect.TypeOf((*MockOutboxStorer)(nil).LastID), ctx)
+}
+
+// Lock mocks base method.
+func (m *MockOutboxStorer) Lock(ctx context.Context) (func(), bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx)
+	ret0, _ := ret[0].(func())
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockOutboxStorerMockRecorder) Lock(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockOutboxStorer)(nil).Lock), ctx)
+}
+
+// MarkPublished mocks base method.
+func (m *MockOutboxStorer) MarkPublished(ctx context.Context, ids []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPublished", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPublished indicates an expected call of MarkPublished.
+func (mr *MockOutboxStorerMockRecorder) MarkPublished(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPublished", reflect.TypeOf((*MockOutboxStorer)(nil).MarkPublished), ctx, ids)
+}
+
+// Post mocks base method.
+func (m *MockOutboxStorer) Post(ctx context.Context, event entities.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockOutboxStorerMockRecorder) Post(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockOutboxStorer)(nil).Post), ctx, event)
+}
+
+// MockWebhookStorer is a mock of WebhookStorer interface.
+type MockWebhookStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookStorerMockRecorder
+}
+
+// MockWebhookStorerMockRecorder is the mock recorder for MockWebhookStorer.
+type MockWebhookStorerMockRecorder struct {
+	mock *MockWebhookStorer
+}
+
+// NewMockWebhookStorer creates a new mock instance.
+func NewMockWebhookStorer(ctrl *gomock.Controller) *MockWebhookStorer {
+	mock := &MockWebhookStorer{ctrl: ctrl}
+	mock.recorder = &MockWebhookStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookStorer) EXPECT() *MockWebhookStorerMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockWebhookStorer) Claim(ctx context.Context, delivery entities.Delivery, lease int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, delivery, lease)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockWebhookStorerMockRecorder) Claim(ctx, delivery, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockWebhookStorer)(nil).Claim), ctx, delivery, lease)
+}
+
+// Delete mocks base method.
+func (m *MockWebhookStorer) Delete(ctx context.Context, id int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhookStorerMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhookStorer)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockWebhookStorer) GetAll(ctx context.Context) ([]entities.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]entities.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockWebhookStorerMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWebhookStorer)(nil).GetAll), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockWebhookStorer) GetByID(ctx context.Context, id int) (entities.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(entities.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockWebhookStorerMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockWebhookStorer)(nil).GetByID), ctx, id)
+}
+
+// GetDeliveries mocks base method.
+func (m *MockWebhookStorer) GetDeliveries(ctx context.Context, webhookID int, filter entities.DeliveryFilter) ([]entities.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", ctx, webhookID, filter)
+	ret0, _ := ret[0].([]entities.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockWebhookStorerMockRecorder) GetDeliveries(ctx, webhookID, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockWebhookStorer)(nil).GetDeliveries), ctx, webhookID, filter)
+}
+
+// GetDelivery mocks base method.
+func (m *MockWebhookStorer) GetDelivery(ctx context.Context, webhookID, id int) (entities.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDelivery", ctx, webhookID, id)
+	ret0, _ := ret[0].(entities.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDelivery indicates an expected call of GetDelivery.
+func (mr *MockWebhookStorerMockRecorder) GetDelivery(ctx, webhookID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelivery", reflect.TypeOf((*MockWebhookStorer)(nil).GetDelivery), ctx, webhookID, id)
+}
+
+// GetDue mocks base method.
+func (m *MockWebhookStorer) GetDue(ctx context.Context, limit int) ([]entities.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDue", ctx, limit)
+	ret0, _ := ret[0].([]entities.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDue indicates an expected call of GetDue.
+func (mr *MockWebhookStorerMockRecorder) GetDue(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDue", reflect.TypeOf((*MockWebhookStorer)(nil).GetDue), ctx, limit)
+}
+
+// Post mocks base method.
+func (m *MockWebhookStorer) Post(ctx context.Context, webhook entities.Webhook) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, webhook)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockWebhookStorerMockRecorder) Post(ctx, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockWebhookStorer)(nil).Post), ctx, webhook)
+}
+
+// PostDelivery mocks base method.
+func (m *MockWebhookStorer) PostDelivery(ctx context.Context, delivery entities.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostDelivery", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostDelivery indicates an expected call of PostDelivery.
+func (mr *MockWebhookStorerMockRecorder) PostDelivery(ctx, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostDelivery", reflect.TypeOf((*MockWebhookStorer)(nil).PostDelivery), ctx, delivery)
+}
+
+// Put mocks base method.
+func (m *MockWebhookStorer) Put(ctx context.Context, webhook entities.Webhook, id int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, webhook, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockWebhookStorerMockRecorder) Put(ctx, webhook, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockWebhookStorer)(nil).Put), ctx, webhook, id)
+}
+
+// Record mocks base method.
+func (m *MockWebhookStorer) Record(ctx context.Context, delivery entities.Delivery, retryIn int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", ctx, delivery, retryIn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockWebhookStorerMockRecorder) Record(ctx, delivery, retryIn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockWebhookStorer)(nil).Record), ctx, delivery, retryIn)
+}
+
+// Redeliver mocks base method.
+func (m *MockWebhookStorer) Redeliver(ctx context.Context, webhookID, id int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeliver", ctx, webhookID, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redeliver indicates an expected call of Redeliver.
+func (mr *MockWebhookStorerMockRecorder) Redeliver(ctx, webhookID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeliver", reflect.TypeOf((*MockWebhookStorer)(nil).Redeliver), ctx, webhookID, id)
+}
+
+// MockIdempotencyStorer is a mock of IdempotencyStorer interface.
+type MockIdempotencyStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyStorerMockRecorder
+}
+
+// MockIdempotencyStorerMockRecorder is the mock recorder for MockIdempotencyStorer.
+type MockIdempotencyStorerMockRecorder struct {
+	mock *MockIdempotencyStorer
+}
+
+// NewMockIdempotencyStorer creates a new mock instance.
+func NewMockIdempotencyStorer(ctrl *gomock.Controller) *MockIdempotencyStorer {
+	mock := &MockIdempotencyStorer{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyStorer) EXPECT() *MockIdempotencyStorerMockRecorder {
+	return m.recorder
+}
+
+// Complete mocks base method.
+func (m *MockIdempotencyStorer) Complete(ctx context.Context, request entities.IdempotentRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockIdempotencyStorerMockRecorder) Complete(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIdempotencyStorer)(nil).Complete), ctx, request)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockIdempotencyStorer) DeleteExpired(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockIdempotencyStorerMockRecorder) DeleteExpired(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockIdempotencyStorer)(nil).DeleteExpired), ctx)
+}
+
+// Release mocks base method.
+func (m *MockIdempotencyStorer) Release(ctx context.Context, scope, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, scope, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIdempotencyStorerMockRecorder) Release(ctx, scope, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIdempotencyStorer)(nil).Release), ctx, scope, key)
+}
+
+// Reserve mocks base method.
+func (m *MockIdempotencyStorer) Reserve(ctx context.Context, request entities.IdempotentRequest, window int) (entities.IdempotentRequest, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, request, window)
+	ret0, _ := ret[0].(entities.IdempotentRequest)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockIdempotencyStorerMockRecorder) Reserve(ctx, request, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockIdempotencyStorer)(nil).Reserve), ctx, request, window)
+}
+
+// MockImportJobStorer is a mock of ImportJobStorer interface.
+type MockImportJobStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportJobStorerMockRecorder
+}
+
+// MockImportJobStorerMockRecorder is the mock recorder for MockImportJobStorer.
+type MockImportJobStorerMockRecorder struct {
+	mock *MockImportJobStorer
+}
+
+// NewMockImportJobStorer creates a new mock instance.
+func NewMockImportJobStorer(ctrl *gomock.Controller) *MockImportJobStorer {
+	mock := &MockImportJobStorer{ctrl: ctrl}
+	mock.recorder = &MockImportJobStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportJobStorer) EXPECT() *MockImportJobStorerMockRecorder {
+	return m.recorder
+}
+
+// GetByID mocks base method.
+func (m *MockImportJobStorer) GetByID(ctx context.Context, id int) (entities.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(entities.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockImportJobStorerMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockImportJobStorer)(nil).GetByID), ctx, id)
+}
+
+// Post mocks base method.
+func (m *MockImportJobStorer) Post(ctx context.Context, job entities.ImportJob) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, job)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockImportJobStorerMockRecorder) Post(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockImportJobStorer)(nil).Post), ctx, job)
+}
+
+// Put mocks base method.
+func (m *MockImportJobStorer) Put(ctx context.Context, job entities.ImportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockImportJobStorerMockRecorder) Put(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockImportJobStorer)(nil).Put), ctx, job)
+}
+
+// MockOpenLibraryKeyStorer is a mock of OpenLibraryKeyStorer interface.
+type MockOpenLibraryKeyStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockOpenLibraryKeyStorerMockRecorder
+}
+
+// MockOpenLibraryKeyStorerMockRecorder is the mock recorder for MockOpenLibraryKeyStorer.
+type MockOpenLibraryKeyStorerMockRecorder struct {
+	mock *MockOpenLibraryKeyStorer
+}
+
+// NewMockOpenLibraryKeyStorer creates a new mock instance.
+func NewMockOpenLibraryKeyStorer(ctrl *gomock.Controller) *MockOpenLibraryKeyStorer {
+	mock := &MockOpenLibraryKeyStorer{ctrl: ctrl}
+	mock.recorder = &MockOpenLibraryKeyStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOpenLibraryKeyStorer) EXPECT() *MockOpenLibraryKeyStorerMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockOpenLibraryKeyStorer) Get(ctx context.Context, key string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockOpenLibraryKeyStorerMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockOpenLibraryKeyStorer)(nil).Get), ctx, key)
+}
+
+// Post mocks base method.
+func (m *MockOpenLibraryKeyStorer) Post(ctx context.Context, key string, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, key, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockOpenLibraryKeyStorerMockRecorder) Post(ctx, key, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockOpenLibraryKeyStorer)(nil).Post), ctx, key, id)
+}
+
+// MockTransactor is a mock of Transactor interface.
+type MockTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactorMockRecorder
+}
+
+// MockTransactorMockRecorder is the mock recorder for MockTransactor.
+type MockTransactorMockRecorder struct {
+	mock *MockTransactor
+}
+
+// NewMockTransactor creates a new mock instance.
+func NewMockTransactor(ctrl *gomock.Controller) *MockTransactor {
+	mock := &MockTransactor{ctrl: ctrl}
+	mock.recorder = &MockTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactor) EXPECT() *MockTransactorMockRecorder {
+	return m.recorder
+}
+
+// WithTx mocks base method.
+func (m *MockTransactor) WithTx(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockTransactorMockRecorder) WithTx(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockTransactor)(nil).WithTx), ctx, fn)
+}
@@ -0,0 +1,43 @@
+package openlibrary
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+type Store struct {
+	DB *sql.DB
+}
+
+// New : factory function
+func New(db *sql.DB) Store {
+	return Store{db}
+}
+
+// Get : gives the id of the author, work or book imported for an Open Library key such as /authors/OL23919A,
+// sql.ErrNoRows when the key was never imported
+func (s Store) Get(ctx context.Context, key string) (int, error) {
+	var id int
+
+	row := store.Executor(ctx, s.DB).QueryRowContext(ctx, "select entity_id from openlibrary_key where ol_key=?", key)
+
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// Post : remembers the id an Open Library key was imported as
+func (s Store) Post(ctx context.Context, key string, id int) error {
+	_, err := store.Executor(ctx, s.DB).ExecContext(ctx, "insert into openlibrary_key(ol_key,entity_id)values(?,?)", key, id)
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package openlibrary
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestGet : test the lookup of imported keys
+func TestGet(t *testing.T) {
+	testcases := []struct {
+		desc string
+		key  string
+		rows *sqlmock.Rows
+
+		expected    int
+		expectedErr error
+	}{
+		{desc: "imported", key: "/authors/OL1A", rows: sqlmock.NewRows([]string{"entity_id"}).AddRow(4), expected: 4},
+		{desc: "not imported", key: "/authors/OL2A", rows: sqlmock.NewRows([]string{"entity_id"}),
+			expectedErr: sql.ErrNoRows},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectQuery("select entity_id from openlibrary_key where ol_key=?").WithArgs(tc.key).WillReturnRows(tc.rows)
+
+		id, err := New(db).Get(context.TODO(), tc.key)
+
+		if id != tc.expected || !errors.Is(err, tc.expectedErr) {
+			t.Errorf("failed for %v, expected %v %v got %v %v", tc.desc, tc.expected, tc.expectedErr, id, err)
+		}
+
+		db.Close()
+	}
+}
+
+// TestPost : test that keys are stored with their id
+func TestPost(t *testing.T) {
+	testcases := []struct {
+		desc   string
+		result error
+
+		expectedErr error
+	}{
+		{desc: "stored"},
+		{desc: "already stored", result: errors.New("duplicate entry"), expectedErr: errors.New("duplicate entry")},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		exec := mock.ExpectExec("insert into openlibrary_key(ol_key,entity_id)values(?,?)").WithArgs("/works/OL1W", 3)
+		if tc.result != nil {
+			exec.WillReturnError(tc.result)
+		} else {
+			exec.WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+
+		err = New(db).Post(context.TODO(), "/works/OL1W", 3)
+
+		if (err == nil) != (tc.expectedErr == nil) {
+			t.Errorf("failed for %v, expected %v got %v", tc.desc, tc.expectedErr, err)
+		}
+
+		db.Close()
+	}
+}
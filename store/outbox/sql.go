@@ -0,0 +1,181 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+// lockName : the named lock held by the relay publishing the outbox, one relay publishes at a time so the events
+// of an entity can not overtake each other
+const lockName = "authorbook_outbox_relay"
+
+type Store struct {
+	DB *sql.DB
+}
+
+// New : factory function
+func New(db *sql.DB) Store {
+	return Store{db}
+}
+
+// Post : appends the event to the outbox, inside the transaction of the change when there is one
+func (s Store) Post(ctx context.Context, event entities.Event) error {
+	var payload interface{}
+	if len(event.Payload) > 0 {
+		payload = string(event.Payload)
+	}
+
+	_, err := store.Executor(ctx, s.DB).ExecContext(ctx, "insert into outbox(event_type,entity_type,entity_id,actor,"+
+		"payload)values(?,?,?,?,?)", event.Type, event.EntityType, event.EntityID, event.Actor, payload)
+	if err != nil {
+		log.Print(err)
+	}
+
+	return err
+}
+
+// GetPending : gives up to limit events which are not published yet, oldest first
+func (s Store) GetPending(ctx context.Context, limit int) ([]entities.Event, error) {
+	rows, err := s.DB.QueryContext(ctx, "select event_id,event_type,entity_type,entity_id,actor,payload,created_at "+
+		"from outbox where published_at is null order by event_id limit ?", limit)
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+// GetSince : gives up to limit events after the event with the id after which pass the filter, published or not,
+// in the order they were written
+func (s Store) GetSince(ctx context.Context, after int, filter entities.EventFilter, limit int) ([]entities.Event,
+	error) {
+	query := "select event_id,event_type,entity_type,entity_id,actor,payload,created_at from outbox where event_id>?"
+	args := []interface{}{after}
+
+	if filter.EntityType != "" {
+		query += " and entity_type=?"
+
+		args = append(args, filter.EntityType)
+	}
+
+	if len(filter.EntityIDs) > 0 {
+		query += " and entity_id in (?" + strings.Repeat(",?", len(filter.EntityIDs)-1) + ")"
+
+		for _, id := range filter.EntityIDs {
+			args = append(args, id)
+		}
+	}
+
+	rows, err := s.DB.QueryContext(ctx, query+" order by event_id limit ?", append(args, limit)...)
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+// LastID : gives the id of the latest event written, 0 when the outbox is empty
+func (s Store) LastID(ctx context.Context) (int, error) {
+	var id int
+
+	if err := s.DB.QueryRowContext(ctx, "select coalesce(max(event_id),0) from outbox").Scan(&id); err != nil {
+		log.Print(err)
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func scanEvents(rows *sql.Rows) ([]entities.Event, error) {
+	var events []entities.Event
+
+	for rows.Next() {
+		var (
+			event   entities.Event
+			payload []byte
+		)
+
+		err := rows.Scan(&event.EventID, &event.Type, &event.EntityType, &event.EntityID, &event.Actor, &payload,
+			&event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		event.Payload = payload
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// MarkPublished : records the events as published so the relay does not send them again
+func (s Store) MarkPublished(ctx context.Context, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	_, err := s.DB.ExecContext(ctx, "update outbox set published_at=CURRENT_TIMESTAMP where event_id in (?"+
+		strings.Repeat(",?", len(ids)-1)+")", args...)
+	if err != nil {
+		log.Print(err)
+	}
+
+	return err
+}
+
+// DeletePublished : deletes the events published more than retention seconds ago and gives how many, the events
+// which are not published yet are kept however old they are
+func (s Store) DeletePublished(ctx context.Context, retention int) (int64, error) {
+	res, err := s.DB.ExecContext(ctx, "delete from outbox where published_at<=CURRENT_TIMESTAMP - interval ? second",
+		retention)
+	if err != nil {
+		log.Print(err)
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// Lock : takes the relay lock on a connection of its own without waiting, false when another relay holds it;
+// release gives the lock and the connection back
+func (s Store) Lock(ctx context.Context) (release func(), ok bool, err error) {
+	conn, err := s.DB.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var acquired sql.NullInt64
+
+	if err = conn.QueryRowContext(ctx, "select get_lock(?,0)", lockName).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return nil, false, err
+	}
+
+	if acquired.Int64 != 1 {
+		_ = conn.Close()
+		return nil, false, nil
+	}
+
+	return func() {
+		// a released lock is also given up when the connection closes, the error only matters for the log
+		if _, err := conn.ExecContext(context.Background(), "select release_lock(?)", lockName); err != nil {
+			log.Print(err)
+		}
+
+		_ = conn.Close()
+	}, true, nil
+}
@@ -0,0 +1,238 @@
+package outbox
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+// TestPost : to test the event is written inside the transaction of the change
+func TestPost(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	event := entities.Event{Type: entities.EventAuthorCreated, EntityType: "author", EntityID: 4, Actor: "asha",
+		Payload: json.RawMessage(`{"authorID":4}`)}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("insert into outbox(event_type,entity_type,entity_id,actor,payload)values(?,?,?,?,?)").
+		WithArgs("AuthorCreated", "author", 4, "asha", `{"authorID":4}`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = store.NewTransaction(db).WithTx(context.TODO(), func(ctx context.Context) error {
+		return New(db).Post(ctx, event)
+	})
+
+	if err != nil {
+		t.Errorf("failed to write the event: %v", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// TestGetPending : to test the unpublished events are read oldest first
+func TestGetPending(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("select event_id,event_type,entity_type,entity_id,actor,payload,created_at from outbox " +
+		"where published_at is null order by event_id limit ?").WithArgs(2).WillReturnRows(sqlmock.NewRows(
+		[]string{"event_id", "event_type", "entity_type", "entity_id", "actor", "payload", "created_at"}).
+		AddRow(5, "BookCreated", "book", 3, "asha", `{"bookID":3}`, "2022-08-01 10:00:00").
+		AddRow(6, "BookDeleted", "book", 3, "ravi", nil, "2022-08-01 10:05:00"))
+
+	events, err := New(db).GetPending(context.TODO(), 2)
+
+	expected := []entities.Event{
+		{EventID: 5, Type: "BookCreated", EntityType: "book", EntityID: 3, Actor: "asha",
+			Payload: json.RawMessage(`{"bookID":3}`), CreatedAt: "2022-08-01 10:00:00"},
+		{EventID: 6, Type: "BookDeleted", EntityType: "book", EntityID: 3, Actor: "ravi",
+			CreatedAt: "2022-08-01 10:05:00"},
+	}
+
+	if err != nil || !reflect.DeepEqual(events, expected) {
+		t.Errorf("unexpected events %v %v", events, err)
+	}
+}
+
+// TestGetSince : to test the filters narrow the events read after the last seen one
+func TestGetSince(t *testing.T) {
+	columns := []string{"event_id", "event_type", "entity_type", "entity_id", "actor", "payload", "created_at"}
+
+	testcases := []struct {
+		desc   string
+		filter entities.EventFilter
+
+		query string
+		args  []driver.Value
+	}{
+		{desc: "every event", query: "select event_id,event_type,entity_type,entity_id,actor,payload,created_at " +
+			"from outbox where event_id>? order by event_id limit ?", args: []driver.Value{4, 2}},
+		{desc: "entity type", filter: entities.EventFilter{EntityType: "book"},
+			query: "select event_id,event_type,entity_type,entity_id,actor,payload,created_at from outbox " +
+				"where event_id>? and entity_type=? order by event_id limit ?", args: []driver.Value{4, "book", 2}},
+		{desc: "entities", filter: entities.EventFilter{EntityType: "book", EntityIDs: []int{3, 7}},
+			query: "select event_id,event_type,entity_type,entity_id,actor,payload,created_at from outbox " +
+				"where event_id>? and entity_type=? and entity_id in (?,?) order by event_id limit ?",
+			args: []driver.Value{4, "book", 3, 7, 2}},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectQuery(tc.query).WithArgs(tc.args...).WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(5, "BookUpdated", "book", 3, "asha", `{"bookID":3}`, "2022-08-01 10:00:00"))
+
+		events, err := New(db).GetSince(context.TODO(), 4, tc.filter, 2)
+
+		expected := []entities.Event{{EventID: 5, Type: "BookUpdated", EntityType: "book", EntityID: 3, Actor: "asha",
+			Payload: json.RawMessage(`{"bookID":3}`), CreatedAt: "2022-08-01 10:00:00"}}
+
+		if err != nil || !reflect.DeepEqual(events, expected) {
+			t.Errorf("%v: unexpected events %v %v", tc.desc, events, err)
+		}
+
+		if err = mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%v: unmet expectations: %v", tc.desc, err)
+		}
+
+		db.Close()
+	}
+}
+
+// TestLastID : to test the id of the latest event is read, 0 for an empty outbox
+func TestLastID(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		rows     *sqlmock.Rows
+		queryErr error
+
+		expected    int
+		expectedErr error
+	}{
+		{desc: "events written", rows: sqlmock.NewRows([]string{"id"}).AddRow(42), expected: 42},
+		{desc: "empty outbox", rows: sqlmock.NewRows([]string{"id"}).AddRow(0)},
+		{desc: "query error", rows: sqlmock.NewRows([]string{"id"}), queryErr: errors.New("connection lost"),
+			expectedErr: errors.New("connection lost")},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectQuery("select coalesce(max(event_id),0) from outbox").WillReturnRows(tc.rows).
+			WillReturnError(tc.queryErr)
+
+		id, err := New(db).LastID(context.TODO())
+		if id != tc.expected || !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %s, got: %v %v", tc.desc, id, err)
+		}
+
+		db.Close()
+	}
+}
+
+// TestMarkPublished : to test the events are marked together
+func TestMarkPublished(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("update outbox set published_at=CURRENT_TIMESTAMP where event_id in (?,?)").WithArgs(5, 7).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	if err = New(db).MarkPublished(context.TODO(), []int{5, 7}); err != nil {
+		t.Errorf("failed to mark the events: %v", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// TestDeletePublished : to test only the events published before the retention are deleted
+func TestDeletePublished(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("delete from outbox where published_at<=CURRENT_TIMESTAMP - interval ? second").WithArgs(3600).
+		WillReturnResult(sqlmock.NewResult(0, 6))
+
+	if deleted, err := New(db).DeletePublished(context.TODO(), 3600); deleted != 6 || err != nil {
+		t.Errorf("unexpected result %v %v", deleted, err)
+	}
+}
+
+// TestLock : to test the relay lock is taken without waiting and released on the same connection
+func TestLock(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		acquired interface{}
+		err      error
+
+		expectedOK bool
+	}{
+		{desc: "free", acquired: 1, expectedOK: true},
+		{desc: "held by another relay", acquired: 0},
+		{desc: "connection lost", err: errors.New("connection lost")},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		query := mock.ExpectQuery("select get_lock(?,0)").WithArgs(lockName)
+		if tc.err != nil {
+			query.WillReturnError(tc.err)
+		} else {
+			query.WillReturnRows(sqlmock.NewRows([]string{"acquired"}).AddRow(tc.acquired))
+		}
+
+		if tc.expectedOK {
+			mock.ExpectExec("select release_lock(?)").WithArgs(lockName).WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+
+		release, ok, err := New(db).Lock(context.TODO())
+		if ok != tc.expectedOK || !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("failed for %v, got: %v %v", tc.desc, ok, err)
+		}
+
+		if ok {
+			release()
+		}
+
+		if err = mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet expectations for %v: %v", tc.desc, err)
+		}
+
+		db.Close()
+	}
+}
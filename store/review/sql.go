@@ -0,0 +1,177 @@
+package review
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+type Store struct {
+	DB *sql.DB
+}
+
+// New : factory function
+func New(db *sql.DB) Store {
+	return Store{db}
+}
+
+const columns = "review_id,book_id,reviewer,rating,text,status,helpful_count,created_at"
+
+// Post : inserts a review
+func (s Store) Post(ctx context.Context, review entities.Review) (int, error) {
+	res, err := s.DB.ExecContext(ctx, "insert into review(book_id,reviewer,rating,text,status)values(?,?,?,?,?)",
+		review.BookID, review.Reviewer, review.Rating, review.Text, review.Status)
+	if err != nil {
+		log.Print(err)
+		return -1, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(id), nil
+}
+
+// GetByID : gives the review with particular id
+func (s Store) GetByID(ctx context.Context, id int) (entities.Review, error) {
+	var review entities.Review
+
+	row := s.DB.QueryRowContext(ctx, "select "+columns+" from review where review_id=?", id)
+
+	err := row.Scan(&review.ReviewID, &review.BookID, &review.Reviewer, &review.Rating, &review.Text, &review.Status,
+		&review.HelpfulCount, &review.CreatedAt)
+	if err != nil {
+		log.Print(err)
+		return entities.Review{}, err
+	}
+
+	return review, nil
+}
+
+// Exists : checks whether the reviewer already reviewed the book
+func (s Store) Exists(ctx context.Context, bookID int, reviewer string) (bool, error) {
+	var count int
+
+	row := s.DB.QueryRowContext(ctx, "select count(*) from review where book_id=? and reviewer=?", bookID, reviewer)
+
+	if err := row.Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// GetByBook : gives a page of the reviews of the book in the requested state and order
+func (s Store) GetByBook(ctx context.Context, bookID int, filter entities.ReviewFilter) ([]entities.Review, error) {
+	var reviews []entities.Review
+
+	order := " order by created_at desc,review_id desc"
+	if filter.Sort == "helpful" {
+		order = " order by helpful_count desc,created_at desc,review_id desc"
+	}
+
+	rows, err := s.DB.QueryContext(ctx, "select "+columns+" from review where book_id=? and status=?"+order+
+		" limit ? offset ?", bookID, filter.Status, filter.Limit, (filter.Page-1)*filter.Limit)
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var review entities.Review
+
+		err = rows.Scan(&review.ReviewID, &review.BookID, &review.Reviewer, &review.Rating, &review.Text, &review.Status,
+			&review.HelpfulCount, &review.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		reviews = append(reviews, review)
+	}
+
+	return reviews, rows.Err()
+}
+
+// Count : gives the number of reviews of the book in the state
+func (s Store) Count(ctx context.Context, bookID int, status string) (int, error) {
+	var count int
+
+	row := s.DB.QueryRowContext(ctx, "select count(*) from review where book_id=? and status=?", bookID, status)
+
+	if err := row.Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// UpdateStatus : moves the review to another moderation state
+func (s Store) UpdateStatus(ctx context.Context, id int, status string) (int, error) {
+	res, err := s.DB.ExecContext(ctx, "update review set status=? where review_id=?", status, id)
+	if err != nil {
+		log.Print(err)
+		return -1, err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(ra), nil
+}
+
+// MarkHelpful : records the vote of the voter and counts it on the review, a second vote of the same voter is not
+// counted and gives 0
+func (s Store) MarkHelpful(ctx context.Context, id int, voter string) (int, error) {
+	var counted int64
+
+	err := store.NewTransaction(s.DB).WithTx(ctx, func(ctx context.Context) error {
+		res, err := store.Executor(ctx, s.DB).ExecContext(ctx,
+			"insert ignore into review_vote(review_id,voter)values(?,?)", id, voter)
+		if err != nil {
+			return err
+		}
+
+		if counted, err = res.RowsAffected(); err != nil || counted == 0 {
+			return err
+		}
+
+		res, err = store.Executor(ctx, s.DB).ExecContext(ctx,
+			"update review set helpful_count=helpful_count+1 where review_id=?", id)
+		if err != nil {
+			return err
+		}
+
+		counted, err = res.RowsAffected()
+
+		return err
+	})
+	if err != nil {
+		log.Print(err)
+		return -1, err
+	}
+
+	return int(counted), nil
+}
+
+// Delete : deletes the review with particular id
+func (s Store) Delete(ctx context.Context, id int) (int, error) {
+	res, err := s.DB.ExecContext(ctx, "delete from review where review_id=?", id)
+	if err != nil {
+		return -1, err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(ra), nil
+}
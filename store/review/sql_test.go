@@ -0,0 +1,168 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+// TestPost : to test posting a review
+func TestPost(t *testing.T) {
+	testcases := []struct {
+		desc string
+		body entities.Review
+
+		execErr    error
+		expectedID int
+	}{
+		{desc: "new review", body: entities.Review{BookID: 1, Reviewer: "asha", Rating: 4, Text: "good",
+			Status: entities.ReviewPending}, expectedID: 8},
+		{desc: "second review of the reviewer", body: entities.Review{BookID: 1, Reviewer: "asha", Rating: 2,
+			Status: entities.ReviewPending}, execErr: errors.New("duplicate entry"), expectedID: -1},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectExec("insert into review(book_id,reviewer,rating,text,status)values(?,?,?,?,?)").
+			WithArgs(tc.body.BookID, tc.body.Reviewer, tc.body.Rating, tc.body.Text, tc.body.Status).
+			WillReturnResult(sqlmock.NewResult(8, 1)).WillReturnError(tc.execErr)
+
+		id, _ := New(db).Post(context.TODO(), tc.body)
+		if id != tc.expectedID {
+			t.Errorf("failed for %s, expected: %v, got: %v", tc.desc, tc.expectedID, id)
+		}
+
+		db.Close()
+	}
+}
+
+// TestGetByBook : to test the order and the page of the listed reviews
+func TestGetByBook(t *testing.T) {
+	cols := []string{"review_id", "book_id", "reviewer", "rating", "text", "status", "helpful_count", "created_at"}
+	review := entities.Review{ReviewID: 3, BookID: 1, Reviewer: "asha", Rating: 5, Text: "great",
+		Status: entities.ReviewApproved, HelpfulCount: 7, CreatedAt: "2022-07-01 10:00:00"}
+
+	testcases := []struct {
+		desc   string
+		filter entities.ReviewFilter
+
+		query  string
+		offset int
+	}{
+		{desc: "newest first", filter: entities.ReviewFilter{Status: entities.ReviewApproved, Sort: "date", Page: 1,
+			Limit: 10}, query: "select " + columns + " from review where book_id=? and status=? " +
+			"order by created_at desc,review_id desc limit ? offset ?", offset: 0},
+		{desc: "most helpful on second page", filter: entities.ReviewFilter{Status: entities.ReviewApproved,
+			Sort: "helpful", Page: 2, Limit: 5}, query: "select " + columns + " from review where book_id=? and " +
+			"status=? order by helpful_count desc,created_at desc,review_id desc limit ? offset ?", offset: 5},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectQuery(tc.query).WithArgs(1, tc.filter.Status, tc.filter.Limit, tc.offset).
+			WillReturnRows(sqlmock.NewRows(cols).AddRow(review.ReviewID, review.BookID, review.Reviewer, review.Rating,
+				review.Text, review.Status, review.HelpfulCount, review.CreatedAt))
+
+		reviews, err := New(db).GetByBook(context.TODO(), 1, tc.filter)
+		if err != nil || !reflect.DeepEqual(reviews, []entities.Review{review}) {
+			t.Errorf("failed for %s, got: %v %v", tc.desc, reviews, err)
+		}
+
+		db.Close()
+	}
+}
+
+// TestUpdateStatus : to test moderating a review
+func TestUpdateStatus(t *testing.T) {
+	testcases := []struct {
+		desc         string
+		target       int
+		rowsAffected int64
+
+		expected int
+	}{
+		{"existing review", 3, 1, 1},
+		{"not existing", 1000, 0, 0},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectExec("update review set status=? where review_id=?").WithArgs(entities.ReviewApproved, tc.target).
+			WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+
+		count, err := New(db).UpdateStatus(context.TODO(), tc.target, entities.ReviewApproved)
+		if err != nil || count != tc.expected {
+			t.Errorf("failed for %s, expected: %v, got: %v", tc.desc, tc.expected, count)
+		}
+
+		db.Close()
+	}
+}
+
+// TestMarkHelpful : to test a vote is counted once per voter
+func TestMarkHelpful(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		inserted int64
+		execErr  error
+
+		expected int
+	}{
+		{desc: "first vote", inserted: 1, expected: 1},
+		{desc: "repeated vote", inserted: 0, expected: 0},
+		{desc: "server error", execErr: errors.New("server error"), expected: -1},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectBegin()
+
+		insert := mock.ExpectExec("insert ignore into review_vote(review_id,voter)values(?,?)").WithArgs(3, "asha")
+
+		switch {
+		case tc.execErr != nil:
+			insert.WillReturnError(tc.execErr)
+			mock.ExpectRollback()
+		case tc.inserted == 0:
+			insert.WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectCommit()
+		default:
+			insert.WillReturnResult(sqlmock.NewResult(0, tc.inserted))
+			mock.ExpectExec("update review set helpful_count=helpful_count+1 where review_id=?").WithArgs(3).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}
+
+		count, err := New(db).MarkHelpful(context.TODO(), 3, "asha")
+		if count != tc.expected || !reflect.DeepEqual(err, tc.execErr) {
+			t.Errorf("failed for %s, expected: %v, got: %v %v", tc.desc, tc.expected, count, err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("failed for %s: %v", tc.desc, err)
+		}
+
+		db.Close()
+	}
+}
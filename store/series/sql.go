@@ -0,0 +1,132 @@
+package series
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+type Store struct {
+	DB *sql.DB
+}
+
+// New : factory function
+func New(db *sql.DB) Store {
+	return Store{db}
+}
+
+// Post : inserts a series
+func (s Store) Post(ctx context.Context, series entities.Series) (int, error) {
+	res, err := s.DB.ExecContext(ctx, "insert into series(name,description)values(?,?)", series.Name, series.Description)
+	if err != nil {
+		log.Print(err)
+		return -1, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(id), nil
+}
+
+// Put : updates the series with particular id
+func (s Store) Put(ctx context.Context, series entities.Series, id int) (int, error) {
+	res, err := s.DB.ExecContext(ctx, "update series set name=?,description=? where series_id=?",
+		series.Name, series.Description, id)
+	if err != nil {
+		log.Print(err)
+		return -1, err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(ra), nil
+}
+
+// Delete : deletes the series with particular id, the books themselves are kept
+func (s Store) Delete(ctx context.Context, id int) (int, error) {
+	res, err := s.DB.ExecContext(ctx, "delete from series where series_id=?", id)
+	if err != nil {
+		return -1, err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(ra), nil
+}
+
+// GetByID : gives the series with particular id without its books
+func (s Store) GetByID(ctx context.Context, id int) (entities.Series, error) {
+	var series entities.Series
+
+	row := s.DB.QueryRowContext(ctx, "select series_id,name,description from series where series_id=?", id)
+
+	if err := row.Scan(&series.SeriesID, &series.Name, &series.Description); err != nil {
+		log.Print(err)
+		return entities.Series{}, err
+	}
+
+	return series, nil
+}
+
+// GetBooks : gives the books of the series in reading order
+func (s Store) GetBooks(ctx context.Context, id int) ([]entities.SeriesBook, error) {
+	var books []entities.SeriesBook
+
+	rows, err := s.DB.QueryContext(ctx, "select sb.book_id,b.title,sb.position from series_book sb "+
+		"join book b on b.id=sb.book_id where sb.series_id=? order by sb.position", id)
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var book entities.SeriesBook
+
+		if err = rows.Scan(&book.BookID, &book.Title, &book.Position); err != nil {
+			return nil, err
+		}
+
+		books = append(books, book)
+	}
+
+	return books, rows.Err()
+}
+
+// AddBook : places the book at the position in the series, a book already in the series is moved
+func (s Store) AddBook(ctx context.Context, id int, book entities.SeriesBook) error {
+	_, err := s.DB.ExecContext(ctx, "insert into series_book(series_id,book_id,position)values(?,?,?) "+
+		"on duplicate key update position=values(position)", id, book.BookID, book.Position)
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	return nil
+}
+
+// RemoveBook : takes the book out of the series
+func (s Store) RemoveBook(ctx context.Context, id, bookID int) (int, error) {
+	res, err := s.DB.ExecContext(ctx, "delete from series_book where series_id=? and book_id=?", id, bookID)
+	if err != nil {
+		return -1, err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(ra), nil
+}
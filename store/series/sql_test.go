@@ -0,0 +1,129 @@
+package series
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+// TestPost : to test posting a series
+func TestPost(t *testing.T) {
+	testcases := []struct {
+		desc string
+		body entities.Series
+
+		execErr    error
+		expectedID int
+	}{
+		{desc: "valid series", body: entities.Series{Name: "Discworld"}, expectedID: 3},
+		{desc: "database error", body: entities.Series{Name: "Discworld"}, execErr: errors.New("error"),
+			expectedID: -1},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectExec("insert into series(name,description)values(?,?)").WithArgs(tc.body.Name, tc.body.Description).
+			WillReturnResult(sqlmock.NewResult(3, 1)).WillReturnError(tc.execErr)
+
+		id, _ := New(db).Post(context.TODO(), tc.body)
+		if id != tc.expectedID {
+			t.Errorf("failed for %s, expected: %v, got: %v", tc.desc, tc.expectedID, id)
+		}
+
+		db.Close()
+	}
+}
+
+// TestGetBooks : to test the books come back in reading order
+func TestGetBooks(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("select sb.book_id,b.title,sb.position from series_book sb " +
+		"join book b on b.id=sb.book_id where sb.series_id=? order by sb.position").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "title", "position"}).
+			AddRow(4, "book one", 1).AddRow(9, "novella", 1.5).AddRow(5, "book two", 2))
+
+	expected := []entities.SeriesBook{{BookID: 4, Title: "book one", Position: 1},
+		{BookID: 9, Title: "novella", Position: 1.5}, {BookID: 5, Title: "book two", Position: 2}}
+
+	books, err := New(db).GetBooks(context.TODO(), 1)
+	if err != nil || !reflect.DeepEqual(books, expected) {
+		t.Errorf("expected: %v, got: %v %v", expected, books, err)
+	}
+}
+
+// TestAddBook : to test placing a book in a series
+func TestAddBook(t *testing.T) {
+	testcases := []struct {
+		desc string
+		book entities.SeriesBook
+
+		expectedErr error
+	}{
+		{desc: "new position", book: entities.SeriesBook{BookID: 9, Position: 2.5}},
+		{desc: "taken position", book: entities.SeriesBook{BookID: 9, Position: 2},
+			expectedErr: errors.New("duplicate entry")},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectExec("insert into series_book(series_id,book_id,position)values(?,?,?) "+
+			"on duplicate key update position=values(position)").WithArgs(1, tc.book.BookID, tc.book.Position).
+			WillReturnResult(sqlmock.NewResult(0, 1)).WillReturnError(tc.expectedErr)
+
+		err = New(db).AddBook(context.TODO(), 1, tc.book)
+		if !reflect.DeepEqual(err, tc.expectedErr) {
+			t.Errorf("failed for %s, expected: %v, got: %v", tc.desc, tc.expectedErr, err)
+		}
+
+		db.Close()
+	}
+}
+
+// TestRemoveBook : to test taking a book out of a series
+func TestRemoveBook(t *testing.T) {
+	testcases := []struct {
+		desc         string
+		bookID       int
+		rowsAffected int64
+
+		expected int
+	}{
+		{"member book", 4, 1, 1},
+		{"not a member", 7, 0, 0},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectExec("delete from series_book where series_id=? and book_id=?").WithArgs(1, tc.bookID).
+			WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+
+		count, err := New(db).RemoveBook(context.TODO(), 1, tc.bookID)
+		if err != nil || count != tc.expected {
+			t.Errorf("failed for %s, expected: %v, got: %v", tc.desc, tc.expected, count)
+		}
+
+		db.Close()
+	}
+}
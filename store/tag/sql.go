@@ -0,0 +1,96 @@
+package tag
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+type Store struct {
+	DB *sql.DB
+}
+
+// New : factory function
+func New(db *sql.DB) Store {
+	return Store{db}
+}
+
+// Post : inserts a tag
+func (s Store) Post(ctx context.Context, tag entities.Tag) (int, error) {
+	res, err := s.DB.ExecContext(ctx, "insert into tag(name)values(?)", tag.Name)
+	if err != nil {
+		log.Print(err)
+		return -1, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(id), nil
+}
+
+// GetAll : gives every tag ordered by name
+func (s Store) GetAll(ctx context.Context) ([]entities.Tag, error) {
+	var tags []entities.Tag
+
+	rows, err := s.DB.QueryContext(ctx, "select tag_id,name from tag order by name")
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag entities.Tag
+
+		if err = rows.Scan(&tag.TagID, &tag.Name); err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// Delete : deletes the tag with particular id
+func (s Store) Delete(ctx context.Context, id int) (int, error) {
+	res, err := s.DB.ExecContext(ctx, "delete from tag where tag_id=?", id)
+	if err != nil {
+		return -1, err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(ra), nil
+}
+
+// GetBookIDs : gives the ids of the books the tag is attached to
+func (s Store) GetBookIDs(ctx context.Context, id int) ([]int, error) {
+	rows, err := s.DB.QueryContext(ctx, "select book_id from book_tag where tag_id=? order by book_id", id)
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+
+	for rows.Next() {
+		var bookID int
+		if err = rows.Scan(&bookID); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, bookID)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,111 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+// TestPost : to test posting a tag
+func TestPost(t *testing.T) {
+	testcases := []struct {
+		desc string
+		body entities.Tag
+
+		execErr    error
+		expectedID int
+	}{
+		{desc: "new tag", body: entities.Tag{Name: "dragons"}, expectedID: 5},
+		{desc: "existing tag", body: entities.Tag{Name: "dragons"}, execErr: errors.New("duplicate entry"),
+			expectedID: -1},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectExec("insert into tag(name)values(?)").WithArgs(tc.body.Name).
+			WillReturnResult(sqlmock.NewResult(5, 1)).WillReturnError(tc.execErr)
+
+		id, _ := New(db).Post(context.TODO(), tc.body)
+		if id != tc.expectedID {
+			t.Errorf("failed for %s, expected: %v, got: %v", tc.desc, tc.expectedID, id)
+		}
+
+		db.Close()
+	}
+}
+
+// TestGetAll : to test listing the tags
+func TestGetAll(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("select tag_id,name from tag order by name").
+		WillReturnRows(sqlmock.NewRows([]string{"tag_id", "name"}).AddRow(2, "dragons").AddRow(1, "magic"))
+
+	expected := []entities.Tag{{TagID: 2, Name: "dragons"}, {TagID: 1, Name: "magic"}}
+
+	tags, err := New(db).GetAll(context.TODO())
+	if err != nil || !reflect.DeepEqual(tags, expected) {
+		t.Errorf("expected: %v, got: %v %v", expected, tags, err)
+	}
+}
+
+// TestDelete : to test deleting a tag
+func TestDelete(t *testing.T) {
+	testcases := []struct {
+		desc         string
+		target       int
+		rowsAffected int64
+
+		expected int
+	}{
+		{"existing tag", 4, 1, 1},
+		{"not existing", 1000, 0, 0},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectExec("delete from tag where tag_id=?").WithArgs(tc.target).
+			WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+
+		count, err := New(db).Delete(context.TODO(), tc.target)
+		if err != nil || count != tc.expected {
+			t.Errorf("failed for %s, expected: %v, got: %v", tc.desc, tc.expected, count)
+		}
+
+		db.Close()
+	}
+}
+
+// TestGetBookIDs : to test fetching the books the tag is attached to
+func TestGetBookIDs(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("select book_id from book_tag where tag_id=? order by book_id").WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id"}).AddRow(4).AddRow(7))
+
+	ids, err := New(db).GetBookIDs(context.TODO(), 2)
+	if err != nil || !reflect.DeepEqual(ids, []int{4, 7}) {
+		t.Errorf("expected: [4 7], got: %v %v", ids, err)
+	}
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Conn : the statements shared by a database and a transaction
+type Conn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+type hooksKey struct{}
+
+// Transaction : runs store calls in one database transaction carried by the context
+type Transaction struct {
+	DB *sql.DB
+}
+
+// NewTransaction : factory function
+func NewTransaction(db *sql.DB) Transaction {
+	return Transaction{db}
+}
+
+// WithTx : runs fn in a transaction which is committed when fn succeeds and rolled back otherwise,
+// when the context already carries a transaction fn joins it
+func (t Transaction) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var hooks []func()
+
+	ctx = context.WithValue(context.WithValue(ctx, txKey{}, tx), hooksKey{}, &hooks)
+
+	if err = fn(ctx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, hook := range hooks {
+		hook()
+	}
+
+	return nil
+}
+
+// InTx : whether the context carries a transaction, what is read in one may still be rolled back
+func InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*sql.Tx)
+
+	return ok
+}
+
+// AfterCommit : runs fn once the transaction of the context is committed, right away outside of one;
+// fn is dropped when the transaction is rolled back
+func AfterCommit(ctx context.Context, fn func()) {
+	if hooks, ok := ctx.Value(hooksKey{}).(*[]func()); ok {
+		*hooks = append(*hooks, fn)
+		return
+	}
+
+	fn()
+}
+
+// Executor : gives the transaction of the context, or the database outside of one
+func Executor(ctx context.Context, db *sql.DB) Conn {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+
+	return db
+}
@@ -0,0 +1,75 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestAfterCommit : to test the hooks run once the transaction is committed and are dropped on a rollback
+func TestAfterCommit(t *testing.T) {
+	testcases := []struct {
+		desc  string
+		fnErr error
+
+		expectedRuns int
+	}{
+		{desc: "committed", expectedRuns: 2},
+		{desc: "rolled back", fnErr: errors.New("invalid id")},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectBegin()
+
+		if tc.fnErr == nil {
+			mock.ExpectCommit()
+		} else {
+			mock.ExpectRollback()
+		}
+
+		runs := 0
+
+		err = NewTransaction(db).WithTx(context.TODO(), func(ctx context.Context) error {
+			AfterCommit(ctx, func() { runs++ })
+
+			// a nested transaction joins the outer one and so do its hooks
+			return NewTransaction(db).WithTx(ctx, func(ctx context.Context) error {
+				if !InTx(ctx) {
+					t.Errorf("%v: no transaction in the context", tc.desc)
+				}
+
+				AfterCommit(ctx, func() { runs++ })
+
+				if runs != 0 {
+					t.Errorf("%v: hook ran before the commit", tc.desc)
+				}
+
+				return tc.fnErr
+			})
+		})
+
+		if err != tc.fnErr || runs != tc.expectedRuns {
+			t.Errorf("%v: unexpected result %v after %v runs", tc.desc, err, runs)
+		}
+
+		if err = mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%v: unmet expectations: %v", tc.desc, err)
+		}
+
+		db.Close()
+	}
+
+	runs := 0
+	AfterCommit(context.TODO(), func() { runs++ })
+
+	if runs != 1 || InTx(context.TODO()) {
+		t.Errorf("a hook outside of a transaction did not run right away")
+	}
+}
@@ -0,0 +1,313 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+type Store struct {
+	DB *sql.DB
+}
+
+// New : factory function
+func New(db *sql.DB) Store {
+	return Store{db}
+}
+
+const (
+	columns         = "webhook_id,url,event_types,entity_ids,secret,actor,created_at"
+	deliveryColumns = "delivery_id,webhook_id,event_id,event_type,payload,status,attempts,next_attempt_at," +
+		"response_status,last_error,created_at,delivered_at"
+)
+
+// Post : inserts a webhook
+func (s Store) Post(ctx context.Context, w entities.Webhook) (int, error) {
+	eventTypes, entityIDs, err := lists(w)
+	if err != nil {
+		return -1, err
+	}
+
+	res, err := s.DB.ExecContext(ctx, "insert into webhook(url,event_types,entity_ids,secret,actor)values(?,?,?,?,?)",
+		w.URL, eventTypes, entityIDs, w.Secret, w.Actor)
+	if err != nil {
+		log.Print(err)
+		return -1, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(id), nil
+}
+
+// GetAll : gives every webhook
+func (s Store) GetAll(ctx context.Context) ([]entities.Webhook, error) {
+	var webhooks []entities.Webhook
+
+	rows, err := s.DB.QueryContext(ctx, "select "+columns+" from webhook order by webhook_id")
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		w, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		webhooks = append(webhooks, w)
+	}
+
+	return webhooks, rows.Err()
+}
+
+// GetByID : gives the webhook with particular id
+func (s Store) GetByID(ctx context.Context, id int) (entities.Webhook, error) {
+	return scan(s.DB.QueryRowContext(ctx, "select "+columns+" from webhook where webhook_id=?", id))
+}
+
+// Put : updates the url and the subscribed events of a webhook, an empty secret keeps the current one
+func (s Store) Put(ctx context.Context, w entities.Webhook, id int) (int, error) {
+	eventTypes, entityIDs, err := lists(w)
+	if err != nil {
+		return -1, err
+	}
+
+	res, err := s.DB.ExecContext(ctx, "update webhook set url=?,event_types=?,entity_ids=?,"+
+		"secret=coalesce(nullif(?,''),secret) where webhook_id=?", w.URL, eventTypes, entityIDs, w.Secret, id)
+	if err != nil {
+		log.Print(err)
+		return -1, err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(ra), nil
+}
+
+// Delete : deletes a webhook, its delivery log goes with it
+func (s Store) Delete(ctx context.Context, id int) (int, error) {
+	res, err := s.DB.ExecContext(ctx, "delete from webhook where webhook_id=?", id)
+	if err != nil {
+		log.Print(err)
+		return -1, err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(ra), nil
+}
+
+// PostDelivery : queues the delivery of an event to a webhook, an event the relay publishes again is queued once
+func (s Store) PostDelivery(ctx context.Context, d entities.Delivery) error {
+	_, err := s.DB.ExecContext(ctx, "insert ignore into webhook_delivery(webhook_id,event_id,event_type,payload)"+
+		"values(?,?,?,?)", d.WebhookID, d.EventID, d.EventType, string(d.Payload))
+	if err != nil {
+		log.Print(err)
+	}
+
+	return err
+}
+
+// GetDeliveries : gives the delivery log of a webhook, newest first
+func (s Store) GetDeliveries(ctx context.Context, webhookID int, filter entities.DeliveryFilter) (
+	[]entities.Delivery, error) {
+	query := "select " + deliveryColumns + " from webhook_delivery where webhook_id=?"
+	args := []interface{}{webhookID}
+
+	if filter.Status != "" {
+		query += " and status=?"
+		args = append(args, filter.Status)
+	}
+
+	query += " order by delivery_id desc limit ?"
+	args = append(args, filter.Limit)
+
+	return s.deliveries(ctx, query, args...)
+}
+
+// GetDelivery : gives the delivery with particular id of a webhook
+func (s Store) GetDelivery(ctx context.Context, webhookID, id int) (entities.Delivery, error) {
+	return scanDelivery(s.DB.QueryRowContext(ctx, "select "+deliveryColumns+" from webhook_delivery "+
+		"where delivery_id=? and webhook_id=?", id, webhookID))
+}
+
+// GetDue : gives up to limit deliveries whose next attempt is due, oldest first
+func (s Store) GetDue(ctx context.Context, limit int) ([]entities.Delivery, error) {
+	return s.deliveries(ctx, "select "+deliveryColumns+" from webhook_delivery where status in ('pending','retrying') "+
+		"and next_attempt_at<=CURRENT_TIMESTAMP order by delivery_id limit ?", limit)
+}
+
+// Claim : counts an attempt of a due delivery and pushes its next attempt lease seconds ahead, so a dispatcher
+// that dies while sending leaves it for a later one; false when another dispatcher claimed it first
+func (s Store) Claim(ctx context.Context, d entities.Delivery, lease int) (bool, error) {
+	res, err := s.DB.ExecContext(ctx, "update webhook_delivery set attempts=attempts+1,"+
+		"next_attempt_at=CURRENT_TIMESTAMP+interval ? second where delivery_id=? and attempts=? and "+
+		"status in ('pending','retrying') and next_attempt_at<=CURRENT_TIMESTAMP", lease, d.DeliveryID, d.Attempts)
+	if err != nil {
+		log.Print(err)
+		return false, err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return ra == 1, nil
+}
+
+// Record : stores the outcome of an attempt, a delivery which is retried is due again in retryIn seconds
+func (s Store) Record(ctx context.Context, d entities.Delivery, retryIn int) error {
+	var err error
+
+	if d.Status == entities.DeliveryDelivered {
+		_, err = s.DB.ExecContext(ctx, "update webhook_delivery set status=?,response_status=?,last_error=null,"+
+			"delivered_at=CURRENT_TIMESTAMP where delivery_id=?", d.Status, nullInt(d.ResponseStatus), d.DeliveryID)
+	} else {
+		_, err = s.DB.ExecContext(ctx, "update webhook_delivery set status=?,response_status=?,last_error=?,"+
+			"next_attempt_at=CURRENT_TIMESTAMP+interval ? second where delivery_id=?", d.Status,
+			nullInt(d.ResponseStatus), d.LastError, retryIn, d.DeliveryID)
+	}
+
+	if err != nil {
+		log.Print(err)
+	}
+
+	return err
+}
+
+// Redeliver : queues a delivery of a webhook again with fresh retries
+func (s Store) Redeliver(ctx context.Context, webhookID, id int) (int, error) {
+	res, err := s.DB.ExecContext(ctx, "update webhook_delivery set status='pending',attempts=0,"+
+		"next_attempt_at=CURRENT_TIMESTAMP where delivery_id=? and webhook_id=?", id, webhookID)
+	if err != nil {
+		log.Print(err)
+		return -1, err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(ra), nil
+}
+
+// deliveries : reads the delivery rows of a query
+func (s Store) deliveries(ctx context.Context, query string, args ...interface{}) ([]entities.Delivery, error) {
+	var deliveries []entities.Delivery
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Print(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// lists : the event types and entity ids of a webhook as JSON documents, no entity ids are stored as NULL
+func lists(w entities.Webhook) (eventTypes string, entityIDs interface{}, err error) {
+	doc, err := json.Marshal(w.EventTypes)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(w.EntityIDs) == 0 {
+		return string(doc), nil, nil
+	}
+
+	ids, err := json.Marshal(w.EntityIDs)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return string(doc), string(ids), nil
+}
+
+// nullInt : stores a missing response status as NULL
+func nullInt(n int) interface{} {
+	if n == 0 {
+		return nil
+	}
+
+	return n
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scan : reads a webhook row
+func scan(row scanner) (entities.Webhook, error) {
+	var (
+		w          entities.Webhook
+		eventTypes []byte
+		entityIDs  []byte
+	)
+
+	err := row.Scan(&w.WebhookID, &w.URL, &eventTypes, &entityIDs, &w.Secret, &w.Actor, &w.CreatedAt)
+	if err != nil {
+		return entities.Webhook{}, err
+	}
+
+	if err = json.Unmarshal(eventTypes, &w.EventTypes); err != nil {
+		return entities.Webhook{}, err
+	}
+
+	if len(entityIDs) > 0 {
+		if err = json.Unmarshal(entityIDs, &w.EntityIDs); err != nil {
+			return entities.Webhook{}, err
+		}
+	}
+
+	return w, nil
+}
+
+// scanDelivery : reads a delivery row
+func scanDelivery(row scanner) (entities.Delivery, error) {
+	var (
+		d                      entities.Delivery
+		payload                []byte
+		responseStatus         sql.NullInt64
+		lastError, deliveredAt sql.NullString
+	)
+
+	err := row.Scan(&d.DeliveryID, &d.WebhookID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &responseStatus, &lastError, &d.CreatedAt, &deliveredAt)
+	if err != nil {
+		return entities.Delivery{}, err
+	}
+
+	d.Payload = payload
+	d.ResponseStatus = int(responseStatus.Int64)
+	d.LastError = lastError.String
+	d.DeliveredAt = deliveredAt.String
+
+	return d, nil
+}
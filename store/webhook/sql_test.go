@@ -0,0 +1,144 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+// TestPost : to test the subscribed events are stored as JSON documents
+func TestPost(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("insert into webhook(url,event_types,entity_ids,secret,actor)values(?,?,?,?,?)").
+		WithArgs("https://partner.example/hook", `["BookUpdated","BookDeleted"]`, nil, "s3cret", "asha").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	id, err := New(db).Post(context.TODO(), entities.Webhook{URL: "https://partner.example/hook",
+		EventTypes: []string{"BookUpdated", "BookDeleted"}, Secret: "s3cret", Actor: "asha"})
+
+	if id != 3 || err != nil {
+		t.Errorf("unexpected insert %v %v", id, err)
+	}
+}
+
+// TestGetByID : to test the JSON documents are read back
+func TestGetByID(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("select " + columns + " from webhook where webhook_id=?").WithArgs(3).WillReturnRows(
+		sqlmock.NewRows([]string{"webhook_id", "url", "event_types", "entity_ids", "secret", "actor", "created_at"}).
+			AddRow(3, "https://partner.example/hook", `["BookUpdated"]`, `[4, 9]`, "s3cret", "asha",
+				"2022-08-01 10:00:00"))
+
+	w, err := New(db).GetByID(context.TODO(), 3)
+
+	expected := entities.Webhook{WebhookID: 3, URL: "https://partner.example/hook", EventTypes: []string{"BookUpdated"},
+		EntityIDs: []int{4, 9}, Secret: "s3cret", Actor: "asha", CreatedAt: "2022-08-01 10:00:00"}
+
+	if err != nil || !reflect.DeepEqual(w, expected) {
+		t.Errorf("unexpected webhook %v %v", w, err)
+	}
+}
+
+// TestGetDue : to test the due deliveries are read oldest first
+func TestGetDue(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("select " + deliveryColumns + " from webhook_delivery where status in ('pending','retrying') " +
+		"and next_attempt_at<=CURRENT_TIMESTAMP order by delivery_id limit ?").WithArgs(10).WillReturnRows(
+		sqlmock.NewRows([]string{"delivery_id", "webhook_id", "event_id", "event_type", "payload", "status",
+			"attempts", "next_attempt_at", "response_status", "last_error", "created_at", "delivered_at"}).
+			AddRow(7, 3, 21, "BookDeleted", `{"eventID":21}`, "retrying", 2, "2022-08-01 10:02:00", 503,
+				"503 Service Unavailable", "2022-08-01 10:00:00", nil))
+
+	deliveries, err := New(db).GetDue(context.TODO(), 10)
+
+	expected := []entities.Delivery{{DeliveryID: 7, WebhookID: 3, EventID: 21, EventType: "BookDeleted",
+		Payload: json.RawMessage(`{"eventID":21}`), Status: "retrying", Attempts: 2,
+		NextAttemptAt: "2022-08-01 10:02:00", ResponseStatus: 503, LastError: "503 Service Unavailable",
+		CreatedAt: "2022-08-01 10:00:00"}}
+
+	if err != nil || !reflect.DeepEqual(deliveries, expected) {
+		t.Errorf("unexpected deliveries %v %v", deliveries, err)
+	}
+}
+
+// TestClaim : to test a delivery is claimed only by the dispatcher which saw its current attempt count
+func TestClaim(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		affected int64
+		expected bool
+	}{
+		{desc: "claimed", affected: 1, expected: true},
+		{desc: "claimed by another dispatcher", affected: 0},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectExec("update webhook_delivery set attempts=attempts+1,next_attempt_at=CURRENT_TIMESTAMP+"+
+			"interval ? second where delivery_id=? and attempts=? and status in ('pending','retrying') and "+
+			"next_attempt_at<=CURRENT_TIMESTAMP").WithArgs(60, 7, 2).WillReturnResult(sqlmock.NewResult(0, tc.affected))
+
+		ok, err := New(db).Claim(context.TODO(), entities.Delivery{DeliveryID: 7, Attempts: 2}, 60)
+		if ok != tc.expected || err != nil {
+			t.Errorf("failed for %v, got: %v %v", tc.desc, ok, err)
+		}
+
+		db.Close()
+	}
+}
+
+// TestRecord : to test a delivered attempt is stamped and a failed one is scheduled again
+func TestRecord(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error during the opening of database:%v\n", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("update webhook_delivery set status=?,response_status=?,last_error=null,"+
+		"delivered_at=CURRENT_TIMESTAMP where delivery_id=?").WithArgs("delivered", 204, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("update webhook_delivery set status=?,response_status=?,last_error=?,"+
+		"next_attempt_at=CURRENT_TIMESTAMP+interval ? second where delivery_id=?").
+		WithArgs("retrying", nil, "connection refused", 120, 8).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s := New(db)
+
+	if err = s.Record(context.TODO(), entities.Delivery{DeliveryID: 7, Status: "delivered", ResponseStatus: 204},
+		0); err != nil {
+		t.Errorf("failed to record the delivery: %v", err)
+	}
+
+	if err = s.Record(context.TODO(), entities.Delivery{DeliveryID: 8, Status: "retrying",
+		LastError: "connection refused"}, 120); err != nil {
+		t.Errorf("failed to record the retry: %v", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
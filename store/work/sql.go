@@ -0,0 +1,94 @@
+package work
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+	"projects/GoLang-Interns-2022/authorbook/store"
+)
+
+type Store struct {
+	DB *sql.DB
+}
+
+// New : factory function
+func New(db *sql.DB) Store {
+	return Store{db}
+}
+
+// Post : inserts a work
+func (s Store) Post(ctx context.Context, work entities.Work) (int, error) {
+	res, err := store.Executor(ctx, s.DB).ExecContext(ctx, "insert into work(author_id,title)values(?,?)", work.AuthorID, work.Title)
+	if err != nil {
+		log.Print(err)
+		return -1, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(id), nil
+}
+
+// Put : updates the work with particular id
+func (s Store) Put(ctx context.Context, work entities.Work, id int) (int, error) {
+	res, err := store.Executor(ctx, s.DB).ExecContext(ctx, "update work set author_id=?,title=? where work_id=?", work.AuthorID, work.Title, id)
+	if err != nil {
+		log.Print(err)
+		return -1, err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(ra), nil
+}
+
+// Delete : deletes the work with particular id
+func (s Store) Delete(ctx context.Context, id int) (int, error) {
+	res, err := store.Executor(ctx, s.DB).ExecContext(ctx, "delete from work where work_id=?", id)
+	if err != nil {
+		return -1, err
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(ra), nil
+}
+
+// GetByID : gives the work with particular id without its editions
+func (s Store) GetByID(ctx context.Context, id int) (entities.Work, error) {
+	var work entities.Work
+
+	row := store.Executor(ctx, s.DB).QueryRowContext(ctx, "select work_id,author_id,title from work where work_id=?", id)
+
+	if err := row.Scan(&work.WorkID, &work.AuthorID, &work.Title); err != nil {
+		log.Print(err)
+		return entities.Work{}, err
+	}
+
+	return work, nil
+}
+
+// GetByTitle : gives the first work of the author with the title, the comparison follows the collation of the table
+func (s Store) GetByTitle(ctx context.Context, authorID int, title string) (entities.Work, error) {
+	var work entities.Work
+
+	row := store.Executor(ctx, s.DB).QueryRowContext(ctx, "select work_id,author_id,title from work where author_id=? "+
+		"and title=? order by work_id limit 1", authorID, title)
+
+	if err := row.Scan(&work.WorkID, &work.AuthorID, &work.Title); err != nil {
+		return entities.Work{}, err
+	}
+
+	return work, nil
+}
@@ -0,0 +1,143 @@
+package work
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"projects/GoLang-Interns-2022/authorbook/entities"
+)
+
+// TestPost : to test posting a work
+func TestPost(t *testing.T) {
+	testcases := []struct {
+		desc string
+		body entities.Work
+
+		execErr    error
+		expectedID int
+	}{
+		{desc: "valid work", body: entities.Work{AuthorID: 1, Title: "deciding decade"}, expectedID: 2},
+		{desc: "missing author", body: entities.Work{AuthorID: 9, Title: "deciding decade"},
+			execErr: errors.New("foreign key constraint fails"), expectedID: -1},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectExec("insert into work(author_id,title)values(?,?)").WithArgs(tc.body.AuthorID, tc.body.Title).
+			WillReturnResult(sqlmock.NewResult(2, 1)).WillReturnError(tc.execErr)
+
+		id, _ := New(db).Post(context.TODO(), tc.body)
+		if id != tc.expectedID {
+			t.Errorf("failed for %s, expected: %v, got: %v", tc.desc, tc.expectedID, id)
+		}
+
+		db.Close()
+	}
+}
+
+// TestGetByID : to test fetching a work
+func TestGetByID(t *testing.T) {
+	testcases := []struct {
+		desc string
+		id   int
+		rows *sqlmock.Rows
+
+		expected    entities.Work
+		expectedErr bool
+	}{
+		{desc: "existing work", id: 1, rows: sqlmock.NewRows([]string{"work_id", "author_id", "title"}).
+			AddRow(1, 3, "deciding decade"), expected: entities.Work{WorkID: 1, AuthorID: 3, Title: "deciding decade"}},
+		{desc: "not existing", id: 9, rows: sqlmock.NewRows([]string{"work_id", "author_id", "title"}),
+			expected: entities.Work{}, expectedErr: true},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectQuery("select work_id,author_id,title from work where work_id=?").WithArgs(tc.id).
+			WillReturnRows(tc.rows)
+
+		work, err := New(db).GetByID(context.TODO(), tc.id)
+
+		if !reflect.DeepEqual(work, tc.expected) || (err != nil) != tc.expectedErr {
+			t.Errorf("failed for %s, expected: %v, got: %v", tc.desc, tc.expected, work)
+		}
+
+		db.Close()
+	}
+}
+
+// TestDelete : to test deleting a work
+func TestDelete(t *testing.T) {
+	testcases := []struct {
+		desc         string
+		target       int
+		rowsAffected int64
+
+		expected int
+	}{
+		{"existing work", 4, 1, 1},
+		{"not existing", 1000, 0, 0},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectExec("delete from work where work_id=?").WithArgs(tc.target).
+			WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+
+		count, err := New(db).Delete(context.TODO(), tc.target)
+		if err != nil || count != tc.expected {
+			t.Errorf("failed for %s, expected: %v, got: %v", tc.desc, tc.expected, count)
+		}
+
+		db.Close()
+	}
+}
+
+// TestGetByTitle : test the lookup of a work by its author and title
+func TestGetByTitle(t *testing.T) {
+	testcases := []struct {
+		desc string
+		rows *sqlmock.Rows
+
+		expected    entities.Work
+		expectedErr bool
+	}{
+		{desc: "existing work", rows: sqlmock.NewRows([]string{"work_id", "author_id", "title"}).
+			AddRow(1, 3, "deciding decade"), expected: entities.Work{WorkID: 1, AuthorID: 3, Title: "deciding decade"}},
+		{desc: "not existing", rows: sqlmock.NewRows([]string{"work_id", "author_id", "title"}), expectedErr: true},
+	}
+
+	for _, tc := range testcases {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("error during the opening of database:%v\n", err)
+		}
+
+		mock.ExpectQuery("select work_id,author_id,title from work where author_id=? and title=? order by work_id limit 1").
+			WithArgs(3, "deciding decade").WillReturnRows(tc.rows)
+
+		work, err := New(db).GetByTitle(context.TODO(), 3, "deciding decade")
+
+		if !reflect.DeepEqual(work, tc.expected) || (err != nil) != tc.expectedErr {
+			t.Errorf("failed for %s, expected: %v, got: %v", tc.desc, tc.expected, work)
+		}
+
+		db.Close()
+	}
+}